@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"backend-golang/commons/models"
+	"backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	LoginSucceededEvent      = "LOGIN_SUCCEEDED"
+	LoginFailedEvent         = "LOGIN_FAILED"
+	LoginLockedEvent         = "LOGIN_LOCKED"
+	LoginDisabledEvent       = "LOGIN_DISABLED"
+	LoginUnverifiedEvent     = "LOGIN_UNVERIFIED"
+	TwoFactorChallengedEvent = "TWO_FACTOR_CHALLENGED"
+	TwoFactorFailedEvent     = "TWO_FACTOR_FAILED"
+)
+
+// authEventWriteTimeout bounds a single insert, a slow database must not hold up the events queued behind it forever
+const authEventWriteTimeout = 5 * time.Second
+
+// AuthEventHelper writes authentication events to auth_events without making the request wait for the insert
+type AuthEventHelper interface {
+	Record(pool *pgxpool.Pool, ctx context.Context, authEvent models.AuthEvent)
+}
+
+// AuthEventHelperImplementation hands every insert to its own background runner, so an event is dropped rather than slowing
+// the login down when the database falls behind
+type AuthEventHelperImplementation struct {
+	AuthEventRepository repositories.AuthEventRepository
+	BackgroundRunner    utils.BackgroundRunner
+}
+
+func NewAuthEventHelper(authEventRepository repositories.AuthEventRepository, backgroundRunner utils.BackgroundRunner) AuthEventHelper {
+	return &AuthEventHelperImplementation{
+		AuthEventRepository: authEventRepository,
+		BackgroundRunner:    backgroundRunner,
+	}
+}
+
+func (helper *AuthEventHelperImplementation) Record(pool *pgxpool.Pool, ctx context.Context, authEvent models.AuthEvent) {
+	helper.BackgroundRunner.Run(ctx, authEvent.EventType+" of "+authEvent.Email, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, authEventWriteTimeout)
+		defer cancel()
+		return helper.AuthEventRepository.Create(pool, ctx, authEvent)
+	})
+}
@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const SessionCookieName = "sessionId"
+
+// ToSessionCookie builds the session cookie, Expires and Max-Age both follow maxAge so the cookie ends with the redis ttl
+func ToSessionCookie(sessionId string, maxAge time.Duration) (cookie *http.Cookie, err error) {
+	secure, err := strconv.ParseBool(os.Getenv("ECOMMERCEV2_COOKIE_SECURE"))
+	if err != nil {
+		return
+	}
+	cookie = &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    sessionId,
+		Path:     "/",
+		Expires:  time.Now().Add(maxAge),
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+		Domain:   os.Getenv("ECOMMERCEV2_COOKIE_DOMAIN"),
+	}
+	return
+}
+
+// the browser only replaces the cookie when name, domain and path match the one set on login
+func ToExpiredSessionCookie() (cookie *http.Cookie, err error) {
+	cookie, err = ToSessionCookie("", 0)
+	if err != nil {
+		return
+	}
+	cookie.Expires = time.Unix(0, 0)
+	cookie.MaxAge = -1
+	return
+}
+
+// ToOidcStateCookie only goes to the oidc routes, Lax rather than Strict since the user arrives from the provider
+func ToOidcStateCookie(state string, maxAge time.Duration) (cookie *http.Cookie, err error) {
+	secure, err := strconv.ParseBool(os.Getenv("ECOMMERCEV2_COOKIE_SECURE"))
+	if err != nil {
+		return
+	}
+	cookie = &http.Cookie{
+		Name:     OidcStateCookieName,
+		Value:    state,
+		Path:     "/api/v1/users/oidc",
+		Expires:  time.Now().Add(maxAge),
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+		Domain:   os.Getenv("ECOMMERCEV2_COOKIE_DOMAIN"),
+	}
+	return
+}
+
+func ToExpiredOidcStateCookie() (cookie *http.Cookie, err error) {
+	cookie, err = ToOidcStateCookie("", 0)
+	if err != nil {
+		return
+	}
+	cookie.Expires = time.Unix(0, 0)
+	cookie.MaxAge = -1
+	return
+}
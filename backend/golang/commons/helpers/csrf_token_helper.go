@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const CsrfTokenHeaderName = "X-CSRF-Token"
+
+// CsrfTokenHelper keeps one csrf token per session in redis, the token lives next to the session
+// under its own key so the session value is never rewritten
+type CsrfTokenHelper interface {
+	FindOrCreate(client *redis.Client, ctx context.Context, sessionId string, token string, expiration time.Duration) (result string, err error)
+	Find(client *redis.Client, ctx context.Context, sessionId string) (result string, err error)
+}
+
+type CsrfTokenHelperImplementation struct {
+}
+
+func NewCsrfTokenHelper() CsrfTokenHelper {
+	return &CsrfTokenHelperImplementation{}
+}
+
+func ToCsrfTokenKey(sessionId string) string {
+	return "csrfToken:" + sessionId
+}
+
+// FindOrCreate stores token only when the session has none yet and returns the one stored,
+// so every tab of the same session gets the same csrf token
+func (helper *CsrfTokenHelperImplementation) FindOrCreate(client *redis.Client, ctx context.Context, sessionId string, token string, expiration time.Duration) (result string, err error) {
+	created, err := client.SetNX(ctx, ToCsrfTokenKey(sessionId), token, expiration).Result()
+	if err != nil {
+		return
+	}
+	if created {
+		return token, nil
+	}
+	return client.Get(ctx, ToCsrfTokenKey(sessionId)).Result()
+}
+
+// Find returns redis.Nil when no csrf token was issued for the session
+func (helper *CsrfTokenHelperImplementation) Find(client *redis.Client, ctx context.Context, sessionId string) (result string, err error) {
+	return client.Get(ctx, ToCsrfTokenKey(sessionId)).Result()
+}
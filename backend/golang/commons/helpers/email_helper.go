@@ -0,0 +1,9 @@
+package helpers
+
+import "strings"
+
+// NormalizeEmail is the form every email is stored, looked up and rate limited in, so the same address typed
+// with other capitals is still the same account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(email)
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"backend-golang/commons/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const EmailVerificationTokenLifetime = 24 * time.Hour
+
+const (
+	// EmailVerificationPolicyOff lets unverified accounts log in
+	EmailVerificationPolicyOff = "off"
+	// EmailVerificationPolicyGrace lets unverified accounts log in until the grace period after registering is over
+	EmailVerificationPolicyGrace = "grace"
+	// EmailVerificationPolicyRequired only lets verified accounts log in
+	EmailVerificationPolicyRequired = "required"
+)
+
+// EmailVerificationToken is stored in redis under the hash of the token, the email is kept so a token only verifies the email it was sent to
+type EmailVerificationToken struct {
+	UserId int32  `json:"userId"`
+	Email  string `json:"email"`
+}
+
+type EmailVerificationPolicy struct {
+	Mode        string
+	GracePeriod time.Duration
+}
+
+// GetEmailVerificationPolicy reads ECOMMERCEV2_EMAIL_VERIFICATION_POLICY (off, grace or required, default off)
+// and ECOMMERCEV2_EMAIL_VERIFICATION_GRACE_PERIOD in minutes, default 24 hours
+func GetEmailVerificationPolicy() (emailVerificationPolicy EmailVerificationPolicy, err error) {
+	emailVerificationPolicy.Mode = os.Getenv("ECOMMERCEV2_EMAIL_VERIFICATION_POLICY")
+	if emailVerificationPolicy.Mode == "" {
+		emailVerificationPolicy.Mode = EmailVerificationPolicyOff
+	}
+	if emailVerificationPolicy.Mode != EmailVerificationPolicyOff && emailVerificationPolicy.Mode != EmailVerificationPolicyGrace && emailVerificationPolicy.Mode != EmailVerificationPolicyRequired {
+		err = errors.New("ECOMMERCEV2_EMAIL_VERIFICATION_POLICY must be off, grace or required")
+		return
+	}
+	emailVerificationPolicy.GracePeriod, err = getMinutes("ECOMMERCEV2_EMAIL_VERIFICATION_GRACE_PERIOD", 24*60)
+	return
+}
+
+// AllowsLogin tells whether an account created at createdAt (unix millis) may log in, emailVerified is whether email_verified_at is set
+func (emailVerificationPolicy EmailVerificationPolicy) AllowsLogin(createdAt int64, emailVerified bool, now time.Time) bool {
+	if emailVerified || emailVerificationPolicy.Mode == EmailVerificationPolicyOff {
+		return true
+	}
+	if emailVerificationPolicy.Mode == EmailVerificationPolicyGrace {
+		return now.Before(time.UnixMilli(createdAt).Add(emailVerificationPolicy.GracePeriod))
+	}
+	return false
+}
+
+// EmailVerificationHelper sends the verification mail and consumes its token, it is shared by register and by resending
+type EmailVerificationHelper interface {
+	Send(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error)
+	FindAndDelete(client *redis.Client, ctx context.Context, token string) (emailVerificationToken EmailVerificationToken, err error)
+}
+
+type EmailVerificationHelperImplementation struct {
+	TokenHelper TokenHelper
+	Mailer      utils.Mailer
+}
+
+func NewEmailVerificationHelper(tokenHelper TokenHelper, mailer utils.Mailer) EmailVerificationHelper {
+	return &EmailVerificationHelperImplementation{
+		TokenHelper: tokenHelper,
+		Mailer:      mailer,
+	}
+}
+
+func ToEmailVerificationTokenKey(tokenHash string) string {
+	return "emailVerificationToken:" + tokenHash
+}
+
+func (helper *EmailVerificationHelperImplementation) Send(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error) {
+	token, err := helper.TokenHelper.Generate()
+	if err != nil {
+		return
+	}
+	emailVerificationTokenByte, err := json.Marshal(EmailVerificationToken{UserId: userId, Email: email})
+	if err != nil {
+		return
+	}
+	_, err = client.Set(ctx, ToEmailVerificationTokenKey(ToTokenHash(token)), string(emailVerificationTokenByte), EmailVerificationTokenLifetime).Result()
+	if err != nil {
+		return
+	}
+
+	mail := utils.Mail{
+		To:      email,
+		Subject: "Verify your email",
+		Body:    "Hi " + username + ",\n\nopen this link within " + EmailVerificationTokenLifetime.String() + " to verify your email:\n" + os.Getenv("ECOMMERCEV2_EMAIL_VERIFICATION_URL") + "?token=" + url.QueryEscape(token) + "\n\nIf you did not create an account, you can ignore this email.\n",
+	}
+	return helper.Mailer.Send(ctx, mail)
+}
+
+// FindAndDelete reads and deletes the token in one command so a token can only be used once, redis.Nil when it does not exist
+func (helper *EmailVerificationHelperImplementation) FindAndDelete(client *redis.Client, ctx context.Context, token string) (emailVerificationToken EmailVerificationToken, err error) {
+	value, err := client.GetDel(ctx, ToEmailVerificationTokenKey(ToTokenHash(token))).Result()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(value), &emailVerificationToken)
+	return
+}
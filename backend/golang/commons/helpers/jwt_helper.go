@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RefreshTokenHeaderName is where the refresh and revoke endpoints take the refresh token from
+const RefreshTokenHeaderName = "X-Refresh-Token"
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+	minJwtKeyLength  = 32
+)
+
+type JwtLifetime struct {
+	AccessTokenLifetime  time.Duration
+	RefreshTokenLifetime time.Duration
+}
+
+// GetJwtLifetime reads ECOMMERCEV2_JWT_ACCESS_TOKEN_LIFETIME and ECOMMERCEV2_JWT_REFRESH_TOKEN_LIFETIME in minutes, default 15 minutes and 24 hours
+func GetJwtLifetime() (jwtLifetime JwtLifetime, err error) {
+	jwtLifetime.AccessTokenLifetime, err = getMinutes("ECOMMERCEV2_JWT_ACCESS_TOKEN_LIFETIME", 15)
+	if err != nil {
+		return
+	}
+	jwtLifetime.RefreshTokenLifetime, err = getMinutes("ECOMMERCEV2_JWT_REFRESH_TOKEN_LIFETIME", 24*60)
+	return
+}
+
+type JwtKey struct {
+	Kid    string
+	Secret []byte
+}
+
+// GetJwtKeys reads ECOMMERCEV2_JWT_KEYS as comma separated kid:secret pairs, the first key signs and every key verifies,
+// so a key is rotated by putting the new one first and removing the old one once its tokens have expired
+func GetJwtKeys() (jwtKeys []JwtKey, err error) {
+	value := os.Getenv("ECOMMERCEV2_JWT_KEYS")
+	if value == "" {
+		err = errors.New("ECOMMERCEV2_JWT_KEYS is not set")
+		return
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kid, secret, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || kid == "" {
+			err = errors.New("ECOMMERCEV2_JWT_KEYS must be comma separated kid:secret pairs")
+			return nil, err
+		}
+		if len(secret) < minJwtKeyLength {
+			err = errors.New("ECOMMERCEV2_JWT_KEYS secret of kid " + kid + " must be at least 32 characters")
+			return nil, err
+		}
+		jwtKeys = append(jwtKeys, JwtKey{Kid: kid, Secret: []byte(secret)})
+	}
+	return
+}
+
+// AccessTokenClaims carries what a session carries so the bearer middleware can fill the same context keys,
+// FamilyId is the refresh token family the access token was issued from
+type AccessTokenClaims struct {
+	Type            string  `json:"typ"`
+	FamilyId        string  `json:"fid"`
+	Username        string  `json:"username"`
+	Email           string  `json:"email"`
+	IdPermissions   []int32 `json:"idPermissions"`
+	TwoFactor       bool    `json:"twoFactor"`
+	AuthenticatedAt int64   `json:"authenticatedAt"`
+	jwt.RegisteredClaims
+}
+
+type RefreshTokenClaims struct {
+	Type     string `json:"typ"`
+	FamilyId string `json:"fid"`
+	jwt.RegisteredClaims
+}
+
+// JwtHelper signs and parses hs256 tokens, the typ claim keeps a refresh token from being used as an access token and the other way around
+type JwtHelper interface {
+	SignAccessToken(claims AccessTokenClaims) (token string, err error)
+	SignRefreshToken(claims RefreshTokenClaims) (token string, err error)
+	ParseAccessToken(token string) (claims AccessTokenClaims, err error)
+	ParseRefreshToken(token string) (claims RefreshTokenClaims, err error)
+}
+
+type JwtHelperImplementation struct {
+}
+
+func NewJwtHelper() JwtHelper {
+	return &JwtHelperImplementation{}
+}
+
+func (helper *JwtHelperImplementation) SignAccessToken(claims AccessTokenClaims) (token string, err error) {
+	claims.Type = accessTokenType
+	return helper.sign(claims)
+}
+
+func (helper *JwtHelperImplementation) SignRefreshToken(claims RefreshTokenClaims) (token string, err error) {
+	claims.Type = refreshTokenType
+	return helper.sign(claims)
+}
+
+func (helper *JwtHelperImplementation) ParseAccessToken(token string) (claims AccessTokenClaims, err error) {
+	err = helper.parse(token, &claims)
+	if err != nil {
+		return
+	}
+	if claims.Type != accessTokenType {
+		return AccessTokenClaims{}, errors.New("token is not an access token")
+	}
+	return
+}
+
+func (helper *JwtHelperImplementation) ParseRefreshToken(token string) (claims RefreshTokenClaims, err error) {
+	err = helper.parse(token, &claims)
+	if err != nil {
+		return
+	}
+	if claims.Type != refreshTokenType {
+		return RefreshTokenClaims{}, errors.New("token is not a refresh token")
+	}
+	return
+}
+
+func (helper *JwtHelperImplementation) sign(claims jwt.Claims) (token string, err error) {
+	jwtKeys, err := GetJwtKeys()
+	if err != nil {
+		return
+	}
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	jwtToken.Header["kid"] = jwtKeys[0].Kid
+	return jwtToken.SignedString(jwtKeys[0].Secret)
+}
+
+// parse only accepts hs256 with a known kid, the algorithm is pinned so a token cannot pick its own
+func (helper *JwtHelperImplementation) parse(token string, claims jwt.Claims) (err error) {
+	jwtKeys, err := GetJwtKeys()
+	if err != nil {
+		return
+	}
+	_, err = jwt.ParseWithClaims(token, claims, func(jwtToken *jwt.Token) (interface{}, error) {
+		kid, _ := jwtToken.Header["kid"].(string)
+		for _, jwtKey := range jwtKeys {
+			if jwtKey.Kid == kid {
+				return jwtKey.Secret, nil
+			}
+		}
+		return nil, errors.New("unknown kid " + kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+	return
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
+
+// ToTokenClaims builds the claims of a token pair for session, the family ends RefreshTokenLifetime after the session was created
+// and rotating the refresh token never extends it, an access token never outlives its family
+func ToTokenClaims(session Session, familyId string, accessTokenId string, refreshTokenId string, now time.Time, jwtLifetime JwtLifetime) (accessTokenClaims AccessTokenClaims, refreshTokenClaims RefreshTokenClaims) {
+	familyExpiresAt := time.UnixMilli(session.CreatedAt).Add(jwtLifetime.RefreshTokenLifetime)
+	accessTokenExpiresAt := now.Add(jwtLifetime.AccessTokenLifetime)
+	if accessTokenExpiresAt.After(familyExpiresAt) {
+		accessTokenExpiresAt = familyExpiresAt
+	}
+	subject := strconv.Itoa(int(session.Id))
+	accessTokenClaims = AccessTokenClaims{
+		FamilyId:        familyId,
+		Username:        session.Username,
+		Email:           session.Email,
+		IdPermissions:   session.IdPermissions,
+		TwoFactor:       session.TwoFactor,
+		AuthenticatedAt: session.CreatedAt,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        accessTokenId,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(accessTokenExpiresAt),
+		},
+	}
+	refreshTokenClaims = RefreshTokenClaims{
+		FamilyId: familyId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshTokenId,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(familyExpiresAt),
+		},
+	}
+	return
+}
@@ -0,0 +1,302 @@
+package helpers
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OidcStateCookieName binds a pending sign in to the browser which started it, so a state cannot be replayed from another browser
+const OidcStateCookieName = "oidcState"
+
+// OidcStateLifetime is how long the user has to sign in at the provider and come back
+const OidcStateLifetime = 10 * time.Minute
+
+const (
+	oidcHttpTimeout     = 10 * time.Second
+	oidcClockSkew       = time.Minute
+	jwksRefreshInterval = time.Minute
+	maxOidcResponseSize = 1 << 20
+)
+
+// ErrOidcProviderNotFound is returned by GetOidcProvider for a name which is not in ECOMMERCEV2_OIDC_PROVIDERS
+var ErrOidcProviderNotFound = errors.New("oidc provider not found")
+
+// ErrOidcRejected wraps every error caused by what the provider or the user sent, a rejected code or an invalid id token,
+// any other error of OidcHelper means the provider could not be reached
+var ErrOidcRejected = errors.New("oidc sign in rejected")
+
+var oidcProviderNameRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+type OidcProvider struct {
+	Name             string
+	Issuer           string
+	ClientId         string
+	ClientSecret     string
+	AuthorizationUrl string
+	TokenUrl         string
+	JwksUrl          string
+	RedirectUrl      string
+}
+
+// GetOidcProvider reads ECOMMERCEV2_OIDC_PROVIDERS as comma separated lowercase names, every name has its own
+// ECOMMERCEV2_OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET, _AUTHORIZATION_URL, _TOKEN_URL, _JWKS_URL and _REDIRECT_URL
+func GetOidcProvider(name string) (oidcProvider OidcProvider, err error) {
+	found := false
+	for _, providerName := range strings.Split(os.Getenv("ECOMMERCEV2_OIDC_PROVIDERS"), ",") {
+		if strings.TrimSpace(providerName) == name && oidcProviderNameRegexp.MatchString(name) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err = ErrOidcProviderNotFound
+		return
+	}
+
+	prefix := "ECOMMERCEV2_OIDC_" + strings.ToUpper(name) + "_"
+	oidcProvider = OidcProvider{
+		Name:             name,
+		Issuer:           os.Getenv(prefix + "ISSUER"),
+		ClientId:         os.Getenv(prefix + "CLIENT_ID"),
+		ClientSecret:     os.Getenv(prefix + "CLIENT_SECRET"),
+		AuthorizationUrl: os.Getenv(prefix + "AUTHORIZATION_URL"),
+		TokenUrl:         os.Getenv(prefix + "TOKEN_URL"),
+		JwksUrl:          os.Getenv(prefix + "JWKS_URL"),
+		RedirectUrl:      os.Getenv(prefix + "REDIRECT_URL"),
+	}
+	if oidcProvider.Issuer == "" || oidcProvider.ClientId == "" || oidcProvider.AuthorizationUrl == "" || oidcProvider.TokenUrl == "" || oidcProvider.JwksUrl == "" || oidcProvider.RedirectUrl == "" {
+		err = errors.New(prefix + "ISSUER, CLIENT_ID, AUTHORIZATION_URL, TOKEN_URL, JWKS_URL and REDIRECT_URL must be set")
+		return OidcProvider{}, err
+	}
+	return
+}
+
+// ToOidcCodeChallenge is the S256 pkce challenge of codeVerifier
+func ToOidcCodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ToOidcAuthorizationUrl is where the browser is sent to sign in, the provider sends it back to RedirectUrl with the code and the state
+func ToOidcAuthorizationUrl(oidcProvider OidcProvider, state string, nonce string, codeVerifier string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", oidcProvider.ClientId)
+	query.Set("redirect_uri", oidcProvider.RedirectUrl)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", ToOidcCodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	separator := "?"
+	if strings.Contains(oidcProvider.AuthorizationUrl, "?") {
+		separator = "&"
+	}
+	return oidcProvider.AuthorizationUrl + separator + query.Encode()
+}
+
+// OidcClaims are the claims of an id token, aud may be a single string or an array and jwt.ClaimStrings takes either
+type OidcClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
+// OidcHelper is the client side of the authorization code flow, the provider is only trusted through the signature of the id token
+type OidcHelper interface {
+	ExchangeCode(ctx context.Context, oidcProvider OidcProvider, code string, codeVerifier string) (idToken string, err error)
+	VerifyIdToken(ctx context.Context, oidcProvider OidcProvider, idToken string, nonce string) (claims OidcClaims, err error)
+}
+
+type jwks struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// OidcHelperImplementation caches the signing keys of every provider, an unknown kid fetches them again at most once per jwksRefreshInterval
+type OidcHelperImplementation struct {
+	HttpClient *http.Client
+	mutex      sync.Mutex
+	jwksByUrl  map[string]jwks
+}
+
+func NewOidcHelper() OidcHelper {
+	return &OidcHelperImplementation{
+		HttpClient: &http.Client{Timeout: oidcHttpTimeout},
+		jwksByUrl:  map[string]jwks{},
+	}
+}
+
+type oidcTokenResponse struct {
+	IdToken string `json:"id_token"`
+}
+
+// ExchangeCode authenticates with client_secret_basic, a 4xx answer of the token endpoint means the code or the verifier was rejected
+func (helper *OidcHelperImplementation) ExchangeCode(ctx context.Context, oidcProvider OidcProvider, code string, codeVerifier string) (idToken string, err error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", oidcProvider.RedirectUrl)
+	form.Set("code_verifier", codeVerifier)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, oidcProvider.TokenUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(oidcProvider.ClientId), url.QueryEscape(oidcProvider.ClientSecret))
+
+	response, err := helper.HttpClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxOidcResponseSize))
+	if err != nil {
+		return
+	}
+	if response.StatusCode >= 400 && response.StatusCode < 500 {
+		err = errors.Join(ErrOidcRejected, errors.New("token endpoint of "+oidcProvider.Name+" answered "+strconv.Itoa(response.StatusCode)+": "+string(body)))
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = errors.New("token endpoint of " + oidcProvider.Name + " answered " + strconv.Itoa(response.StatusCode))
+		return
+	}
+
+	var tokenResponse oidcTokenResponse
+	err = json.Unmarshal(body, &tokenResponse)
+	if err != nil {
+		return
+	}
+	if tokenResponse.IdToken == "" {
+		err = errors.Join(ErrOidcRejected, errors.New("token endpoint of "+oidcProvider.Name+" did not return an id token"))
+		return
+	}
+	return tokenResponse.IdToken, nil
+}
+
+// VerifyIdToken only accepts rs256 signed by a key of the jwks of the provider, issued by it for this client and this nonce
+func (helper *OidcHelperImplementation) VerifyIdToken(ctx context.Context, oidcProvider OidcProvider, idToken string, nonce string) (claims OidcClaims, err error) {
+	unverifiedToken, _, err := new(jwt.Parser).ParseUnverified(idToken, &OidcClaims{})
+	if err != nil {
+		err = errors.Join(ErrOidcRejected, err)
+		return
+	}
+	kid, _ := unverifiedToken.Header["kid"].(string)
+	publicKey, err := helper.findKey(ctx, oidcProvider.JwksUrl, kid)
+	if err != nil {
+		return
+	}
+
+	// the times are checked with oidcClockSkew of leeway, the issuer and the audience by the parser too
+	_, err = jwt.ParseWithClaims(idToken, &claims, func(jwtToken *jwt.Token) (interface{}, error) {
+		if publicKey == nil {
+			return nil, errors.New("unknown kid " + kid)
+		}
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithLeeway(oidcClockSkew), jwt.WithExpirationRequired(), jwt.WithIssuedAt(),
+		jwt.WithIssuer(oidcProvider.Issuer), jwt.WithAudience(oidcProvider.ClientId))
+	if err != nil {
+		err = errors.Join(ErrOidcRejected, err)
+		return OidcClaims{}, err
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		err = errors.Join(ErrOidcRejected, errors.New("id token nonce does not match"))
+		return OidcClaims{}, err
+	}
+	if claims.Subject == "" {
+		err = errors.Join(ErrOidcRejected, errors.New("id token has no subject"))
+		return OidcClaims{}, err
+	}
+	return
+}
+
+// findKey returns a nil key without error when the kid is not in the jwks even after refreshing it
+func (helper *OidcHelperImplementation) findKey(ctx context.Context, jwksUrl string, kid string) (publicKey *rsa.PublicKey, err error) {
+	helper.mutex.Lock()
+	cachedJwks, found := helper.jwksByUrl[jwksUrl]
+	helper.mutex.Unlock()
+	if found {
+		publicKey = cachedJwks.keys[kid]
+		if publicKey != nil || time.Since(cachedJwks.fetchedAt) < jwksRefreshInterval {
+			return
+		}
+	}
+
+	keys, err := helper.fetchJwks(ctx, jwksUrl)
+	if err != nil {
+		return
+	}
+	helper.mutex.Lock()
+	helper.jwksByUrl[jwksUrl] = jwks{keys: keys, fetchedAt: time.Now()}
+	helper.mutex.Unlock()
+	return keys[kid], nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetchJwks keeps the rsa signing keys only, a key it cannot read is skipped rather than failing the others
+func (helper *OidcHelperImplementation) fetchJwks(ctx context.Context, jwksUrl string) (keys map[string]*rsa.PublicKey, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksUrl, nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := helper.HttpClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		err = errors.New("jwks endpoint " + jwksUrl + " answered " + strconv.Itoa(response.StatusCode))
+		return
+	}
+	var jsonWebKeySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err = json.NewDecoder(io.LimitReader(response.Body, maxOidcResponseSize)).Decode(&jsonWebKeySet)
+	if err != nil {
+		return
+	}
+
+	keys = map[string]*rsa.PublicKey{}
+	for _, key := range jsonWebKeySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		modulus, errModulus := base64.RawURLEncoding.DecodeString(key.N)
+		exponent, errExponent := base64.RawURLEncoding.DecodeString(key.E)
+		if errModulus != nil || errExponent != nil || len(exponent) == 0 || len(exponent) > 4 {
+			continue
+		}
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// likeReplacer escapes the wildcards of LIKE so a filter value only matches itself
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ToLikeContains is the LIKE pattern matching every value that contains value
+func ToLikeContains(value string) string {
+	return "%" + likeReplacer.Replace(value) + "%"
+}
+
+// ToCursor and FromCursor keep the cursor opaque, clients pass back what they got instead of building it. A page is read with
+// one row more than its limit and the cursor of the last row kept is only returned when that extra row exists
+func ToCursor(id int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(int(id))))
+}
+
+func FromCursor(cursor string) (id int32, err error) {
+	idByte, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return
+	}
+	parsedId, err := strconv.ParseInt(string(idByte), 10, 32)
+	if err != nil {
+		return
+	}
+	if parsedId <= 0 {
+		err = errors.New("cursor id must be positive")
+		return
+	}
+	return int32(parsedId), nil
+}
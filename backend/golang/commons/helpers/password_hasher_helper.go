@@ -0,0 +1,236 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordHashAlgorithmBcrypt   = "bcrypt"
+	PasswordHashAlgorithmArgon2id = "argon2id"
+)
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// ErrPasswordMismatch is returned by PasswordHasher.Compare for a wrong password, like bcrypt.ErrMismatchedHashAndPassword
+var ErrPasswordMismatch = errors.New("password does not match the hash")
+
+type PasswordHashConfig struct {
+	Algorithm           string
+	BcryptCost          int
+	Argon2idMemory      uint32
+	Argon2idIterations  uint32
+	Argon2idParallelism uint8
+}
+
+// GetPasswordHashConfig reads ECOMMERCEV2_PASSWORD_HASH_ALGORITHM (bcrypt or argon2id, default bcrypt), ECOMMERCEV2_BCRYPT_COST (default 10)
+// and ECOMMERCEV2_ARGON2ID_MEMORY in KiB, ECOMMERCEV2_ARGON2ID_ITERATIONS and ECOMMERCEV2_ARGON2ID_PARALLELISM, default 65536, 3 and 4
+func GetPasswordHashConfig() (passwordHashConfig PasswordHashConfig, err error) {
+	passwordHashConfig.Algorithm = os.Getenv("ECOMMERCEV2_PASSWORD_HASH_ALGORITHM")
+	if passwordHashConfig.Algorithm == "" {
+		passwordHashConfig.Algorithm = PasswordHashAlgorithmBcrypt
+	}
+	if passwordHashConfig.Algorithm != PasswordHashAlgorithmBcrypt && passwordHashConfig.Algorithm != PasswordHashAlgorithmArgon2id {
+		err = errors.New("ECOMMERCEV2_PASSWORD_HASH_ALGORITHM must be bcrypt or argon2id")
+		return
+	}
+	passwordHashConfig.BcryptCost, err = getPasswordHashParameter("ECOMMERCEV2_BCRYPT_COST", bcrypt.DefaultCost, bcrypt.MinCost, bcrypt.MaxCost)
+	if err != nil {
+		return
+	}
+	memory, err := getPasswordHashParameter("ECOMMERCEV2_ARGON2ID_MEMORY", 64*1024, 8*1024, 4*1024*1024)
+	if err != nil {
+		return
+	}
+	iterations, err := getPasswordHashParameter("ECOMMERCEV2_ARGON2ID_ITERATIONS", 3, 1, 100)
+	if err != nil {
+		return
+	}
+	parallelism, err := getPasswordHashParameter("ECOMMERCEV2_ARGON2ID_PARALLELISM", 4, 1, 255)
+	if err != nil {
+		return
+	}
+	passwordHashConfig.Argon2idMemory = uint32(memory)
+	passwordHashConfig.Argon2idIterations = uint32(iterations)
+	passwordHashConfig.Argon2idParallelism = uint8(parallelism)
+	return
+}
+
+func getPasswordHashParameter(key string, defaultValue int, minValue int, maxValue int) (value int, err error) {
+	rawValue := os.Getenv(key)
+	if rawValue == "" {
+		return defaultValue, nil
+	}
+	value, err = strconv.Atoi(rawValue)
+	if err != nil {
+		return
+	}
+	if value < minValue || value > maxValue {
+		err = errors.New(key + " must be between " + strconv.Itoa(minValue) + " and " + strconv.Itoa(maxValue))
+		return
+	}
+	return
+}
+
+// PasswordHasher hashes new passwords with the configured algorithm and cost and compares against any hash it recognises by prefix,
+// bcrypt ($2a$, $2b$, $2y$) or argon2id in the PHC string format, so stored hashes can be upgraded one login at a time
+type PasswordHasher interface {
+	Hash(password string) (hash string, err error)
+	Compare(hash string, password string) (err error)
+	NeedsRehash(hash string) (needsRehash bool, err error)
+	DummyHash() (dummyHash string, err error)
+}
+
+type PasswordHasherImplementation struct {
+	PasswordHashConfig PasswordHashConfig
+	mutex              sync.Mutex
+	dummyHash          string
+}
+
+func NewPasswordHasher(passwordHashConfig PasswordHashConfig) PasswordHasher {
+	return &PasswordHasherImplementation{
+		PasswordHashConfig: passwordHashConfig,
+	}
+}
+
+func (hasher *PasswordHasherImplementation) Hash(password string) (hash string, err error) {
+	passwordHashConfig := hasher.PasswordHashConfig
+	if passwordHashConfig.Algorithm == PasswordHashAlgorithmArgon2id {
+		salt := make([]byte, argon2idSaltLength)
+		_, err = rand.Read(salt)
+		if err != nil {
+			return
+		}
+		key := argon2.IDKey([]byte(password), salt, passwordHashConfig.Argon2idIterations, passwordHashConfig.Argon2idMemory, passwordHashConfig.Argon2idParallelism, argon2idKeyLength)
+		hash = fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, passwordHashConfig.Argon2idMemory, passwordHashConfig.Argon2idIterations, passwordHashConfig.Argon2idParallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+		return
+	}
+	hashByte, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashConfig.BcryptCost)
+	if err != nil {
+		return
+	}
+	return string(hashByte), nil
+}
+
+// Compare returns nil when password matches hash, ErrPasswordMismatch when it does not and another error for a hash it cannot read
+func (hasher *PasswordHasherImplementation) Compare(hash string, password string) (err error) {
+	if isBcryptHash(hash) {
+		err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return ErrPasswordMismatch
+		}
+		return
+	}
+	if strings.HasPrefix(hash, "$"+PasswordHashAlgorithmArgon2id+"$") {
+		argon2idHash, err := parseArgon2idHash(hash)
+		if err != nil {
+			return err
+		}
+		key := argon2.IDKey([]byte(password), argon2idHash.salt, argon2idHash.iterations, argon2idHash.memory, argon2idHash.parallelism, uint32(len(argon2idHash.key)))
+		if subtle.ConstantTimeCompare(key, argon2idHash.key) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+	}
+	return errors.New("unknown password hash algorithm")
+}
+
+// NeedsRehash tells whether hash was made with another algorithm or other parameters than the configured ones
+func (hasher *PasswordHasherImplementation) NeedsRehash(hash string) (needsRehash bool, err error) {
+	passwordHashConfig := hasher.PasswordHashConfig
+	if isBcryptHash(hash) {
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return false, err
+		}
+		return passwordHashConfig.Algorithm != PasswordHashAlgorithmBcrypt || cost != passwordHashConfig.BcryptCost, nil
+	}
+	if strings.HasPrefix(hash, "$"+PasswordHashAlgorithmArgon2id+"$") {
+		argon2idHash, err := parseArgon2idHash(hash)
+		if err != nil {
+			return false, err
+		}
+		return passwordHashConfig.Algorithm != PasswordHashAlgorithmArgon2id ||
+			argon2idHash.memory != passwordHashConfig.Argon2idMemory ||
+			argon2idHash.iterations != passwordHashConfig.Argon2idIterations ||
+			argon2idHash.parallelism != passwordHashConfig.Argon2idParallelism, nil
+	}
+	return false, errors.New("unknown password hash algorithm")
+}
+
+// DummyHash is one hash of a random password made with the configured algorithm and cost on first use, an unknown email is compared
+// against it so the answer takes as long as for a wrong password
+func (hasher *PasswordHasherImplementation) DummyHash() (dummyHash string, err error) {
+	hasher.mutex.Lock()
+	defer hasher.mutex.Unlock()
+	if hasher.dummyHash != "" {
+		return hasher.dummyHash, nil
+	}
+
+	dummyPassword := make([]byte, argon2idKeyLength)
+	_, err = rand.Read(dummyPassword)
+	if err != nil {
+		return
+	}
+	hasher.dummyHash, err = hasher.Hash(base64.RawStdEncoding.EncodeToString(dummyPassword))
+	return hasher.dummyHash, err
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// parseArgon2idHash reads $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key> with salt and key in unpadded base64
+func parseArgon2idHash(hash string) (result argon2idHash, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		err = errors.New("malformed argon2id hash")
+		return
+	}
+	var version int
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return
+	}
+	if version != argon2.Version {
+		err = errors.New("unsupported argon2id version " + strconv.Itoa(version))
+		return
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &result.memory, &result.iterations, &result.parallelism)
+	if err != nil {
+		return
+	}
+	result.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return
+	}
+	result.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return
+	}
+	if result.iterations == 0 || result.parallelism == 0 || len(result.key) == 0 {
+		err = errors.New("malformed argon2id hash")
+	}
+	return
+}
@@ -0,0 +1,229 @@
+package helpers
+
+import (
+	"backend-golang/commons/models"
+	"backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	DataExportStatusPending = "PENDING"
+	DataExportStatusReady   = "READY"
+	DataExportStatusFailed  = "FAILED"
+	DataExportStatusExpired = "EXPIRED"
+)
+
+// DataExportLifetime is how long a written archive can be downloaded
+const DataExportLifetime = 7 * 24 * time.Hour
+
+// personalDataInterval is how often the accounts past their grace period are anonymised, the expired archives dropped and the
+// exports which did not fit in the queue written
+const personalDataInterval = time.Hour
+
+// personalDataWriteTimeout bounds writing one archive or anonymising one account
+const personalDataWriteTimeout = time.Minute
+
+// GetAccountDeletionGracePeriod reads ECOMMERCEV2_ACCOUNT_DELETION_GRACE_PERIOD in minutes, default 30 days
+func GetAccountDeletionGracePeriod() (gracePeriod time.Duration, err error) {
+	return getMinutes("ECOMMERCEV2_ACCOUNT_DELETION_GRACE_PERIOD", 30*24*60)
+}
+
+// PersonalDataHelper writes the data export archives in the background so the request does not wait for them
+type PersonalDataHelper interface {
+	Export(ctx context.Context, dataExportId int32, userId int32)
+}
+
+// PersonalDataHelperImplementation writes the exports on its background runner, which also anonymises the accounts whose deletion grace
+// period is over, once at start and then every hour, so an export and an hourly run never overlap. The export rows are the real queue:
+// an export dropped because the queue is full stays PENDING and is written by the next hourly run
+type PersonalDataHelperImplementation struct {
+	PostgresUtil              utils.PostgresUtil
+	RedisUtil                 utils.RedisUtil
+	PersonalDataRepository    repositories.PersonalDataRepository
+	DataExportRepository      repositories.DataExportRepository
+	AccountDeletionRepository repositories.AccountDeletionRepository
+	SessionRegistryHelper     SessionRegistryHelper
+	BackgroundRunner          utils.BackgroundRunner
+	stop                      chan struct{}
+	waitGroup                 sync.WaitGroup
+}
+
+func NewPersonalDataHelper(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, personalDataRepository repositories.PersonalDataRepository, dataExportRepository repositories.DataExportRepository, accountDeletionRepository repositories.AccountDeletionRepository, sessionRegistryHelper SessionRegistryHelper, backgroundRunner utils.BackgroundRunner) *PersonalDataHelperImplementation {
+	helper := &PersonalDataHelperImplementation{
+		PostgresUtil:              postgresUtil,
+		RedisUtil:                 redisUtil,
+		PersonalDataRepository:    personalDataRepository,
+		DataExportRepository:      dataExportRepository,
+		AccountDeletionRepository: accountDeletionRepository,
+		SessionRegistryHelper:     sessionRegistryHelper,
+		BackgroundRunner:          backgroundRunner,
+		stop:                      make(chan struct{}),
+	}
+	helper.waitGroup.Add(1)
+	go helper.schedule()
+	return helper
+}
+
+func (helper *PersonalDataHelperImplementation) Export(ctx context.Context, dataExportId int32, userId int32) {
+	helper.BackgroundRunner.Run(ctx, "data export "+strconv.Itoa(int(dataExportId)), func(ctx context.Context) error {
+		return helper.WriteDataExport(ctx, models.DataExport{Id: dataExportId, UserId: userId})
+	})
+}
+
+func (helper *PersonalDataHelperImplementation) schedule() {
+	defer helper.waitGroup.Done()
+	ticker := time.NewTicker(personalDataInterval)
+	defer ticker.Stop()
+	helper.queueScheduled(time.Now())
+	for {
+		select {
+		case <-helper.stop:
+			return
+		case now := <-ticker.C:
+			helper.queueScheduled(now)
+		}
+	}
+}
+
+func (helper *PersonalDataHelperImplementation) queueScheduled(now time.Time) {
+	helper.BackgroundRunner.Run(context.Background(), "personal data run", func(ctx context.Context) error {
+		return helper.RunScheduled(ctx, now)
+	})
+}
+
+// RunScheduled anonymises the accounts due at now, expires the old archives and writes the exports left PENDING,
+// a failing account or export does not stop the others and every error is returned together
+func (helper *PersonalDataHelperImplementation) RunScheduled(ctx context.Context, now time.Time) (err error) {
+	errs := []error{helper.DeleteAccounts(ctx, now)}
+	errs = append(errs, helper.DataExportRepository.UpdateExpired(helper.PostgresUtil.GetPool(), ctx, now.UnixMilli()))
+	dataExports, err := helper.DataExportRepository.FindAllPending(helper.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, dataExport := range dataExports {
+		errs = append(errs, helper.WriteDataExport(ctx, dataExport))
+	}
+	return errors.Join(errs...)
+}
+
+// WriteDataExport marks the export READY with its archive, or FAILED when the personal data cannot be read
+func (helper *PersonalDataHelperImplementation) WriteDataExport(ctx context.Context, dataExport models.DataExport) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, personalDataWriteTimeout)
+	defer cancel()
+	archive, err := helper.findPersonalData(ctx, dataExport.UserId)
+	if err != nil {
+		return errors.Join(err, helper.DataExportRepository.UpdateFailed(helper.PostgresUtil.GetPool(), ctx, dataExport.Id, time.Now().UnixMilli()))
+	}
+	now := time.Now()
+	return helper.DataExportRepository.UpdateReady(helper.PostgresUtil.GetPool(), ctx, dataExport.Id, archive, now.UnixMilli(), now.Add(DataExportLifetime).UnixMilli())
+}
+
+func (helper *PersonalDataHelperImplementation) findPersonalData(ctx context.Context, userId int32) (archive []byte, err error) {
+	pool := helper.PostgresUtil.GetPool()
+	personalData := models.PersonalData{ExportedAt: time.Now().UnixMilli()}
+	personalData.User, err = helper.PersonalDataRepository.FindUserById(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.Permissions, err = helper.PersonalDataRepository.FindPermissionsByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.Roles, err = helper.PersonalDataRepository.FindRolesByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.RecoveryCodesRemaining, err = helper.PersonalDataRepository.CountUnusedRecoveryCodesByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.Identities, err = helper.PersonalDataRepository.FindIdentitiesByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.ApiKeys, err = helper.PersonalDataRepository.FindApiKeysByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.AuthEvents, err = helper.PersonalDataRepository.FindAuthEventsByUserId(pool, ctx, userId, personalData.User.Email)
+	if err != nil {
+		return
+	}
+	personalData.PermissionAudits, err = helper.PersonalDataRepository.FindPermissionAuditsByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.RoleAudits, err = helper.PersonalDataRepository.FindRoleAuditsByUserId(pool, ctx, userId)
+	if err != nil {
+		return
+	}
+
+	sessionInfos, err := helper.SessionRegistryHelper.FindAllByUserId(helper.RedisUtil.GetClient(), ctx, userId)
+	if err != nil {
+		return
+	}
+	personalData.Sessions = []models.PersonalDataSession{}
+	for _, sessionInfo := range sessionInfos {
+		personalData.Sessions = append(personalData.Sessions, models.PersonalDataSession{
+			CreatedAt: sessionInfo.CreatedAt,
+			UserAgent: sessionInfo.UserAgent,
+			Ip:        sessionInfo.Ip,
+		})
+	}
+	return json.Marshal(personalData)
+}
+
+// DeleteAccounts anonymises every account whose deletion was requested longer than the grace period ago and deletes its sessions,
+// an account that fails is tried again on the next run
+func (helper *PersonalDataHelperImplementation) DeleteAccounts(ctx context.Context, now time.Time) (err error) {
+	gracePeriod, err := GetAccountDeletionGracePeriod()
+	if err != nil {
+		return
+	}
+	deletionRequestedBefore := now.Add(-gracePeriod).UnixMilli()
+	userIds, err := helper.AccountDeletionRepository.FindUserIdsDue(helper.PostgresUtil.GetPool(), ctx, deletionRequestedBefore)
+	if err != nil {
+		return
+	}
+	var errs []error
+	for _, userId := range userIds {
+		errs = append(errs, helper.deleteAccount(ctx, userId, deletionRequestedBefore, now.UnixMilli()))
+	}
+	return errors.Join(errs...)
+}
+
+// deleteAccount deletes the sessions before the anonymisation is committed, when redis fails the anonymisation is rolled back
+// and the account is tried again on the next run rather than left anonymised with its sessions still logged in
+func (helper *PersonalDataHelperImplementation) deleteAccount(ctx context.Context, userId int32, deletionRequestedBefore int64, deletedAt int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, personalDataWriteTimeout)
+	defer cancel()
+	tx, err := helper.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return
+	}
+	defer func() {
+		errCommitOrRollback := helper.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			err = errCommitOrRollback
+		}
+	}()
+	anonymised, err := helper.AccountDeletionRepository.Anonymise(tx, ctx, userId, deletionRequestedBefore, deletedAt)
+	if err != nil || !anonymised {
+		return
+	}
+	return helper.SessionRegistryHelper.DeleteAllByUserId(helper.RedisUtil.GetClient(), ctx, userId, "")
+}
+
+// Close stops the hourly runs, it is called on shutdown before the background runner is closed
+func (helper *PersonalDataHelperImplementation) Close() {
+	close(helper.stop)
+	helper.waitGroup.Wait()
+}
@@ -8,8 +8,10 @@ import (
 )
 
 type RedisHelper interface {
+	Get(client *redis.Client, ctx context.Context, key string) (result string, err error)
 	Set(client *redis.Client, ctx context.Context, key string, value interface{}, expiration time.Duration) (result string, err error)
 	Del(client *redis.Client, ctx context.Context, key string) (result int64, err error)
+	Expire(client *redis.Client, ctx context.Context, key string, expiration time.Duration) (result bool, err error)
 }
 
 type RedisHelperImplementation struct {
@@ -19,6 +21,10 @@ func NewRedisHelper() RedisHelper {
 	return &RedisHelperImplementation{}
 }
 
+func (helper *RedisHelperImplementation) Get(client *redis.Client, ctx context.Context, key string) (result string, err error) {
+	return client.Get(ctx, key).Result()
+}
+
 func (helper *RedisHelperImplementation) Set(client *redis.Client, ctx context.Context, key string, value interface{}, expiration time.Duration) (result string, err error) {
 	return client.Set(ctx, key, value, expiration).Result()
 }
@@ -26,3 +32,7 @@ func (helper *RedisHelperImplementation) Set(client *redis.Client, ctx context.C
 func (helper *RedisHelperImplementation) Del(client *redis.Client, ctx context.Context, key string) (result int64, err error) {
 	return client.Del(ctx, key).Result()
 }
+
+func (helper *RedisHelperImplementation) Expire(client *redis.Client, ctx context.Context, key string, expiration time.Duration) (result bool, err error) {
+	return client.Expire(ctx, key, expiration).Result()
+}
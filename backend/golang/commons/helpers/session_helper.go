@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Session is the value stored in redis under the session id by LoginService.Login
+type Session struct {
+	Id            int32   `json:"id"`
+	Username      string  `json:"username"`
+	Email         string  `json:"email"`
+	IdPermissions []int32 `json:"idPermissions"`
+	CreatedAt     int64   `json:"createdAt"`
+	TwoFactor     bool    `json:"twoFactor"`
+}
+
+type SessionLifetime struct {
+	IdleTimeout      time.Duration
+	AbsoluteLifetime time.Duration
+}
+
+// GetSessionLifetime reads ECOMMERCEV2_SESSION_IDLE_TIMEOUT and ECOMMERCEV2_SESSION_ABSOLUTE_LIFETIME in minutes, default 30 minutes and 24 hours
+func GetSessionLifetime() (sessionLifetime SessionLifetime, err error) {
+	sessionLifetime.IdleTimeout, err = getMinutes("ECOMMERCEV2_SESSION_IDLE_TIMEOUT", 30)
+	if err != nil {
+		return
+	}
+	sessionLifetime.AbsoluteLifetime, err = getMinutes("ECOMMERCEV2_SESSION_ABSOLUTE_LIFETIME", 24*60)
+	return
+}
+
+// Ttl is how long a session created at createdAt may still live at now, zero or less means the session is over
+func (sessionLifetime SessionLifetime) Ttl(createdAt time.Time, now time.Time) time.Duration {
+	ttl := createdAt.Add(sessionLifetime.AbsoluteLifetime).Sub(now)
+	if ttl > sessionLifetime.IdleTimeout {
+		ttl = sessionLifetime.IdleTimeout
+	}
+	return ttl
+}
+
+func getMinutes(key string, defaultMinutes int) (duration time.Duration, err error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return time.Duration(defaultMinutes) * time.Minute, nil
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+	if minutes <= 0 {
+		err = errors.New(key + " must be greater than 0")
+		return
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
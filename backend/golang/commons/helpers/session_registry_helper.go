@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionInfo is kept per session in the user's session registry, the session id itself is the hash field
+type SessionInfo struct {
+	SessionId string `json:"-"`
+	CreatedAt int64  `json:"createdAt"`
+	UserAgent string `json:"userAgent"`
+	Ip        string `json:"ip"`
+}
+
+// SessionRegistryHelper keeps a redis hash per user so every session of a user can be listed and revoked
+type SessionRegistryHelper interface {
+	Register(client *redis.Client, ctx context.Context, userId int32, sessionInfo SessionInfo, expiration time.Duration) (err error)
+	Unregister(client *redis.Client, ctx context.Context, userId int32, sessionId string) (err error)
+	FindAllByUserId(client *redis.Client, ctx context.Context, userId int32) (sessionInfos []SessionInfo, err error)
+	DeleteAllByUserId(client *redis.Client, ctx context.Context, userId int32, exceptSessionId string) (err error)
+	UpdateProfileByUserId(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error)
+}
+
+// updateTokenFamilySessionScript only writes into a family that still exists, an HSET on an expired family would bring it back without a ttl
+var updateTokenFamilySessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'session', ARGV[1])
+return 1
+`)
+
+type SessionRegistryHelperImplementation struct {
+}
+
+func NewSessionRegistryHelper() SessionRegistryHelper {
+	return &SessionRegistryHelperImplementation{}
+}
+
+func ToUserSessionsKey(userId int32) string {
+	return "userSessions:" + strconv.Itoa(int(userId))
+}
+
+// ToSessionPublicId is what the api shows instead of the session id, the session id is a bearer secret
+func ToSessionPublicId(sessionId string) string {
+	sum := sha256.Sum256([]byte(sessionId))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Register adds the session to the registry, the registry lives as long as its longest living session can,
+// a session with a shorter expiration never shortens it
+func (helper *SessionRegistryHelperImplementation) Register(client *redis.Client, ctx context.Context, userId int32, sessionInfo SessionInfo, expiration time.Duration) (err error) {
+	sessionInfoByte, err := json.Marshal(sessionInfo)
+	if err != nil {
+		return
+	}
+	key := ToUserSessionsKey(userId)
+	_, err = client.HSet(ctx, key, sessionInfo.SessionId, string(sessionInfoByte)).Result()
+	if err != nil {
+		return
+	}
+	ttl, err := client.TTL(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	if ttl < expiration {
+		_, err = client.Expire(ctx, key, expiration).Result()
+	}
+	return
+}
+
+func (helper *SessionRegistryHelperImplementation) Unregister(client *redis.Client, ctx context.Context, userId int32, sessionId string) (err error) {
+	_, err = client.HDel(ctx, ToUserSessionsKey(userId), sessionId).Result()
+	return
+}
+
+// FindAllByUserId returns the live sessions of the user, entries whose session already expired are removed on the way
+func (helper *SessionRegistryHelperImplementation) FindAllByUserId(client *redis.Client, ctx context.Context, userId int32) (sessionInfos []SessionInfo, err error) {
+	key := ToUserSessionsKey(userId)
+	values, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	for sessionId, value := range values {
+		var exists int64
+		exists, err = client.Exists(ctx, sessionId).Result()
+		if err != nil {
+			return
+		}
+		var sessionInfo SessionInfo
+		if exists == 0 || json.Unmarshal([]byte(value), &sessionInfo) != nil {
+			_, err = client.HDel(ctx, key, sessionId).Result()
+			if err != nil {
+				return
+			}
+			continue
+		}
+		sessionInfo.SessionId = sessionId
+		sessionInfos = append(sessionInfos, sessionInfo)
+	}
+	return
+}
+
+// DeleteAllByUserId deletes every session of the user except exceptSessionId, pass "" to delete all of them
+func (helper *SessionRegistryHelperImplementation) DeleteAllByUserId(client *redis.Client, ctx context.Context, userId int32, exceptSessionId string) (err error) {
+	key := ToUserSessionsKey(userId)
+	sessionIds, err := client.HKeys(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	for _, sessionId := range sessionIds {
+		if sessionId == exceptSessionId {
+			continue
+		}
+		_, err = client.Del(ctx, sessionId).Result()
+		if err != nil {
+			return
+		}
+		_, err = client.HDel(ctx, key, sessionId).Result()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UpdateProfileByUserId rewrites username and email in every session and token family of the user and keeps their ttl,
+// access tokens already issued keep the old values until they expire since they are not stored anywhere
+func (helper *SessionRegistryHelperImplementation) UpdateProfileByUserId(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error) {
+	sessionIds, err := client.HKeys(ctx, ToUserSessionsKey(userId)).Result()
+	if err != nil {
+		return
+	}
+	for _, sessionId := range sessionIds {
+		// token families are registered under their key, their session is a field of the family hash
+		isTokenFamily := strings.HasPrefix(sessionId, ToTokenFamilyKey(""))
+		var value string
+		if isTokenFamily {
+			value, err = client.HGet(ctx, sessionId, "session").Result()
+		} else {
+			value, err = client.Get(ctx, sessionId).Result()
+		}
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return
+		}
+
+		var session Session
+		err = json.Unmarshal([]byte(value), &session)
+		if err != nil {
+			return
+		}
+		session.Username = username
+		session.Email = email
+		var sessionByte []byte
+		sessionByte, err = json.Marshal(session)
+		if err != nil {
+			return
+		}
+
+		if isTokenFamily {
+			err = updateTokenFamilySessionScript.Run(ctx, client, []string{sessionId}, string(sessionByte)).Err()
+		} else {
+			// XX leaves a session that expired in the meantime deleted
+			err = client.SetArgs(ctx, sessionId, string(sessionByte), redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+		}
+		if err != nil && err != redis.Nil {
+			return
+		}
+		err = nil
+	}
+	return
+}
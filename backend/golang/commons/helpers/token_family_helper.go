@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rotateTokenFamilyScript swaps the current refresh token id only when the presented one is current, a presented id which is
+// not current was already rotated away so it is a reuse and the whole family is deleted
+var rotateTokenFamilyScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'tokenId')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'tokenId', ARGV[2])
+return 1
+`)
+
+// rotate results of TokenFamilyHelper.Rotate
+const (
+	TokenFamilyRotated  int64 = 1
+	TokenFamilyNotFound int64 = 0
+	TokenFamilyReused   int64 = -1
+)
+
+// TokenFamilyHelper keeps a redis hash per refresh token family with the current refresh token id and the session the tokens are issued for,
+// the family key is registered in the session registry like a session id so revoking sessions revokes families too
+type TokenFamilyHelper interface {
+	Create(client *redis.Client, ctx context.Context, familyId string, tokenId string, session Session, expiration time.Duration) (err error)
+	FindSession(client *redis.Client, ctx context.Context, familyId string) (session Session, err error)
+	Rotate(client *redis.Client, ctx context.Context, familyId string, tokenId string, newTokenId string) (result int64, err error)
+	Exists(client *redis.Client, ctx context.Context, familyId string) (exists bool, err error)
+	Delete(client *redis.Client, ctx context.Context, familyId string) (err error)
+}
+
+type TokenFamilyHelperImplementation struct {
+}
+
+func NewTokenFamilyHelper() TokenFamilyHelper {
+	return &TokenFamilyHelperImplementation{}
+}
+
+func ToTokenFamilyKey(familyId string) string {
+	return "tokenFamily:" + familyId
+}
+
+func (helper *TokenFamilyHelperImplementation) Create(client *redis.Client, ctx context.Context, familyId string, tokenId string, session Session, expiration time.Duration) (err error) {
+	sessionByte, err := json.Marshal(session)
+	if err != nil {
+		return
+	}
+	key := ToTokenFamilyKey(familyId)
+	_, err = client.TxPipelined(ctx, func(pipeliner redis.Pipeliner) error {
+		pipeliner.HSet(ctx, key, "tokenId", tokenId, "session", string(sessionByte))
+		pipeliner.Expire(ctx, key, expiration)
+		return nil
+	})
+	return
+}
+
+// FindSession returns redis.Nil when the family does not exist, it expired or it was revoked
+func (helper *TokenFamilyHelperImplementation) FindSession(client *redis.Client, ctx context.Context, familyId string) (session Session, err error) {
+	value, err := client.HGet(ctx, ToTokenFamilyKey(familyId), "session").Result()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(value), &session)
+	return
+}
+
+func (helper *TokenFamilyHelperImplementation) Rotate(client *redis.Client, ctx context.Context, familyId string, tokenId string, newTokenId string) (result int64, err error) {
+	return rotateTokenFamilyScript.Run(ctx, client, []string{ToTokenFamilyKey(familyId)}, tokenId, newTokenId).Int64()
+}
+
+func (helper *TokenFamilyHelperImplementation) Exists(client *redis.Client, ctx context.Context, familyId string) (exists bool, err error) {
+	count, err := client.Exists(ctx, ToTokenFamilyKey(familyId)).Result()
+	if err != nil {
+		return
+	}
+	return count == 1, nil
+}
+
+func (helper *TokenFamilyHelperImplementation) Delete(client *redis.Client, ctx context.Context, familyId string) (err error) {
+	_, err = client.Del(ctx, ToTokenFamilyKey(familyId)).Result()
+	return
+}
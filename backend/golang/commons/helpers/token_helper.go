@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// TokenHelper generates the random secrets that are handed to users, only their hash is stored
+type TokenHelper interface {
+	Generate() (token string, err error)
+}
+
+type TokenHelperImplementation struct {
+}
+
+func NewTokenHelper() TokenHelper {
+	return &TokenHelperImplementation{}
+}
+
+func (helper *TokenHelperImplementation) Generate() (token string, err error) {
+	tokenByte := make([]byte, 32)
+	_, err = rand.Read(tokenByte)
+	if err != nil {
+		return
+	}
+	return hex.EncodeToString(tokenByte), nil
+}
+
+func ToTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
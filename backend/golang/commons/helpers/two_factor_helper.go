@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// totp follows RFC 6238 with the defaults authenticator apps expect, sha1, 6 digits and a 30 seconds step
+const (
+	totpDigits            = 6
+	totpStep              = 30 * time.Second
+	totpSkew              = 1
+	recoveryCodeCount     = 10
+	minTwoFactorKeyLength = 32
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GetTwoFactorKey reads ECOMMERCEV2_TWO_FACTOR_KEY, the key of at least 32 characters the totp secrets are encrypted
+// and the recovery codes are hashed with
+func GetTwoFactorKey() (twoFactorKey []byte, err error) {
+	value := os.Getenv("ECOMMERCEV2_TWO_FACTOR_KEY")
+	if len(value) < minTwoFactorKeyLength {
+		err = errors.New("ECOMMERCEV2_TWO_FACTOR_KEY must be at least 32 characters")
+		return
+	}
+	return []byte(value), nil
+}
+
+// TwoFactorHelper generates and checks totp secrets and recovery codes, a secret is stored encrypted with aes-gcm
+// and a recovery code as its hmac so neither can be used from a copy of the database
+type TwoFactorHelper interface {
+	GenerateSecret() (secret string, err error)
+	GenerateRecoveryCodes() (recoveryCodes []string, err error)
+	ValidateTotp(secret string, code string, now time.Time) (step int64, valid bool)
+	EncryptSecret(secret string) (encryptedSecret string, err error)
+	DecryptSecret(encryptedSecret string) (secret string, err error)
+	ToRecoveryCodeHash(recoveryCode string) (codeHash string)
+}
+
+type TwoFactorHelperImplementation struct {
+	secretKey       []byte
+	recoveryCodeKey []byte
+}
+
+// NewTwoFactorHelper derives one key for the secrets and another for the recovery codes from twoFactorKey
+func NewTwoFactorHelper(twoFactorKey []byte) TwoFactorHelper {
+	return &TwoFactorHelperImplementation{
+		secretKey:       toTwoFactorSubkey(twoFactorKey, "totp secret"),
+		recoveryCodeKey: toTwoFactorSubkey(twoFactorKey, "recovery code"),
+	}
+}
+
+func toTwoFactorSubkey(twoFactorKey []byte, label string) []byte {
+	mac := hmac.New(sha256.New, twoFactorKey)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+// ToTotpProvisioningUri is the otpauth uri authenticator apps read from a qr code
+func ToTotpProvisioningUri(issuer string, accountName string, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", strconv.Itoa(totpDigits))
+	values.Set("period", strconv.Itoa(int(totpStep.Seconds())))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+accountName) + "?" + values.Encode()
+}
+
+func GenerateTotpCode(secret string, step int64) (code string, err error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	code = strconv.Itoa(int(value % 1000000))
+	return strings.Repeat("0", totpDigits-len(code)) + code, nil
+}
+
+func (helper *TwoFactorHelperImplementation) GenerateSecret() (secret string, err error) {
+	secretByte := make([]byte, 20)
+	_, err = rand.Read(secretByte)
+	if err != nil {
+		return
+	}
+	return totpEncoding.EncodeToString(secretByte), nil
+}
+
+func (helper *TwoFactorHelperImplementation) GenerateRecoveryCodes() (recoveryCodes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		recoveryCodeByte := make([]byte, 5)
+		_, err = rand.Read(recoveryCodeByte)
+		if err != nil {
+			return
+		}
+		recoveryCode := strings.ToLower(totpEncoding.EncodeToString(recoveryCodeByte))
+		recoveryCodes = append(recoveryCodes, recoveryCode[:4]+"-"+recoveryCode[4:])
+	}
+	return
+}
+
+// ValidateTotp accepts the code of the current step and of one step before or after for clock drift, it returns the matched step
+func (helper *TwoFactorHelperImplementation) ValidateTotp(secret string, code string, now time.Time) (step int64, valid bool) {
+	if len(code) != totpDigits {
+		return
+	}
+	currentStep := now.Unix() / int64(totpStep.Seconds())
+	for step = currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		expectedCode, err := GenerateTotpCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expectedCode), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// EncryptSecret returns the nonce followed by the sealed secret in unpadded base64
+func (helper *TwoFactorHelperImplementation) EncryptSecret(secret string) (encryptedSecret string, err error) {
+	aead, err := helper.newAead()
+	if err != nil {
+		return
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return
+	}
+	return base64.RawStdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func (helper *TwoFactorHelperImplementation) DecryptSecret(encryptedSecret string) (secret string, err error) {
+	aead, err := helper.newAead()
+	if err != nil {
+		return
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encryptedSecret)
+	if err != nil {
+		return
+	}
+	if len(sealed) < aead.NonceSize() {
+		err = errors.New("encrypted totp secret is too short")
+		return
+	}
+	secretByte, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return
+	}
+	return string(secretByte), nil
+}
+
+// ToRecoveryCodeHash normalizes a recovery code the way users type it before hashing it
+func (helper *TwoFactorHelperImplementation) ToRecoveryCodeHash(recoveryCode string) (codeHash string) {
+	mac := hmac.New(sha256.New, helper.recoveryCodeKey)
+	mac.Write([]byte(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(recoveryCode), "-", ""))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (helper *TwoFactorHelperImplementation) newAead() (aead cipher.AEAD, err error) {
+	block, err := aes.NewCipher(helper.secretKey)
+	if err != nil {
+		return
+	}
+	return cipher.NewGCM(block)
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -25,10 +26,22 @@ func GetValidatorError(validatorError error, structRequest interface{}) (errorMe
 			errorMessage.Message = "please use only uppercase, lowercase, number and must have 1 uppercase. lowercase, number, @, _, -, min 8 and max 20"
 		} else if fieldError.Tag() == "telephonevalidator" {
 			errorMessage.Message = "please use only number and + "
+		} else if fieldError.Tag() == "slugvalidator" {
+			errorMessage.Message = "please use only lowercase letter and number separated by single -"
+		} else if fieldError.Tag() == "iso4217" {
+			errorMessage.Message = "please input an iso 4217 currency code"
 		} else if fieldError.Tag() == "email" {
 			errorMessage.Message = "please input a correct email format "
+		} else if fieldError.Tag() == "eqfield" {
+			errorMessage.Message = "please input the same value as " + strings.ToLower(fieldError.Param())
+		} else if fieldError.Tag() == "nefield" {
+			errorMessage.Message = "please input a different value from " + strings.ToLower(fieldError.Param())
 		} else if fieldError.Tag() == "gte" {
 			errorMessage.Message = "please input greater than equal to " + fieldError.Param()
+		} else if fieldError.Tag() == "lte" {
+			errorMessage.Message = "please input less than equal to " + fieldError.Param()
+		} else if fieldError.Tag() == "oneof" {
+			errorMessage.Message = "please input one of " + fieldError.Param()
 		} else {
 			errorMessage.Message = "is " + fieldError.Tag()
 		}
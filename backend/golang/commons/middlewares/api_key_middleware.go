@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const ApiKeyHeader = "X-API-Key"
+
+// apiKeyLastUsedInterval keeps a busy key from writing last_used_at on every request, it is only moved forward once a minute
+const apiKeyLastUsedInterval = time.Minute
+
+type ApiKeyMiddleware interface {
+	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type ApiKeyMiddlewareImplementation struct {
+	PostgresUtil     utils.PostgresUtil
+	ApiKeyRepository repositories.ApiKeyRepository
+}
+
+func NewApiKeyMiddleware(postgresUtil utils.PostgresUtil, apiKeyRepository repositories.ApiKeyRepository) ApiKeyMiddleware {
+	return &ApiKeyMiddlewareImplementation{
+		PostgresUtil:     postgresUtil,
+		ApiKeyRepository: apiKeyRepository,
+	}
+}
+
+// Authenticate takes the api key from X-API-Key and puts the same keys as the session middleware into the request context as the user
+// of the key with the permissions of the key, sessionId is apiKey:<id> and twoFactor is always false so administrator never applies
+func (middleware *ApiKeyMiddlewareImplementation) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestId := c.Request().Context().Value(RequestIdKey).(string)
+
+		key := c.Request().Header.Get(ApiKeyHeader)
+		if key == "" {
+			err := errors.New("cannot find api key")
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+
+		now := time.Now().UnixMilli()
+		apiKey, err := middleware.ApiKeyRepository.FindActiveByKeyHash(middleware.PostgresUtil.GetPool(), c.Request().Context(), helpers.ToTokenHash(key), now)
+		if err != nil && err != pgx.ErrNoRows {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		} else if err != nil && err == pgx.ErrNoRows {
+			err = errors.New("api key is unknown, revoked or expired, or its user is disabled")
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+
+		if !apiKey.LastUsedAt.Valid || now-apiKey.LastUsedAt.Int64 >= apiKeyLastUsedInterval.Milliseconds() {
+			err = middleware.ApiKeyRepository.UpdateLastUsedAt(middleware.PostgresUtil.GetPool(), c.Request().Context(), apiKey.Id.Int32, now)
+			if err != nil {
+				httpCode, response := helpers.ToResponseCheckError(err, requestId)
+				return c.JSON(httpCode, response)
+			}
+		}
+
+		ctx := context.WithValue(c.Request().Context(), IdKey, apiKey.UserId.Int32)
+		ctx = context.WithValue(ctx, UsernameKey, apiKey.Username.String)
+		ctx = context.WithValue(ctx, EmailKey, apiKey.Email.String)
+		ctx = context.WithValue(ctx, PermissionKey, apiKey.IdPermissions)
+		ctx = context.WithValue(ctx, SessionIdKey, "apiKey:"+strconv.Itoa(int(apiKey.Id.Int32)))
+		ctx = context.WithValue(ctx, ApiKeyIdKey, apiKey.Id.Int32)
+		ctx = context.WithValue(ctx, TwoFactorKey, false)
+		ctx = context.WithValue(ctx, AuthenticatedAtKey, int64(0))
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
@@ -3,11 +3,15 @@ package middlewares
 type StringCustomType string
 
 const (
-	RequestIdKey     StringCustomType = "requestId"
-	IdKey            StringCustomType = "id"
-	PermissionKey    StringCustomType = "permission"
-	UsernameKey      StringCustomType = "username"
-	XRefreshTokenKey StringCustomType = "xRefreshToken"
-	TokenIdKey       StringCustomType = "tokenId"
-	SessionIdKey     StringCustomType = "sessionId"
+	RequestIdKey       StringCustomType = "requestId"
+	IdKey              StringCustomType = "id"
+	PermissionKey      StringCustomType = "permission"
+	UsernameKey        StringCustomType = "username"
+	EmailKey           StringCustomType = "email"
+	XRefreshTokenKey   StringCustomType = "xRefreshToken"
+	TokenIdKey         StringCustomType = "tokenId"
+	SessionIdKey       StringCustomType = "sessionId"
+	TwoFactorKey       StringCustomType = "twoFactor"
+	ApiKeyIdKey        StringCustomType = "apiKeyId"
+	AuthenticatedAtKey StringCustomType = "authenticatedAt"
 )
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/utils"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// CsrfMiddlewareImplementation wraps the cookie session middleware, every request which is not GET, HEAD or OPTIONS
+// must send the csrf token of its session in X-CSRF-Token, bearer token requests never reach it since browsers do not send them on their own
+type CsrfMiddlewareImplementation struct {
+	RedisUtil         utils.RedisUtil
+	CsrfTokenHelper   helpers.CsrfTokenHelper
+	SessionMiddleware SessionMiddleware
+}
+
+func NewCsrfMiddleware(redisUtil utils.RedisUtil, csrfTokenHelper helpers.CsrfTokenHelper, sessionMiddleware SessionMiddleware) SessionMiddleware {
+	return &CsrfMiddlewareImplementation{
+		RedisUtil:         redisUtil,
+		CsrfTokenHelper:   csrfTokenHelper,
+		SessionMiddleware: sessionMiddleware,
+	}
+}
+
+func (middleware *CsrfMiddlewareImplementation) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return middleware.SessionMiddleware.Authenticate(middleware.protect(next))
+}
+
+func (middleware *CsrfMiddlewareImplementation) protect(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			return next(c)
+		}
+
+		// an optional session middleware lets a request without a session through, there is no session to forge a request for
+		sessionId, ok := c.Request().Context().Value(SessionIdKey).(string)
+		if !ok {
+			return next(c)
+		}
+		requestId := c.Request().Context().Value(RequestIdKey).(string)
+		csrfToken := c.Request().Header.Get(helpers.CsrfTokenHeaderName)
+		if csrfToken == "" {
+			err := errors.New("cannot find csrf token header")
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusForbidden, "csrf token is invalid")
+			return c.JSON(httpCode, response)
+		}
+
+		sessionCsrfToken, err := middleware.CsrfTokenHelper.Find(middleware.RedisUtil.GetClient(), c.Request().Context(), sessionId)
+		if err != nil && err != redis.Nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+		if err == redis.Nil || subtle.ConstantTimeCompare([]byte(csrfToken), []byte(sessionCsrfToken)) != 1 {
+			err = errors.New("csrf token does not match the session")
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusForbidden, "csrf token is invalid")
+			return c.JSON(httpCode, response)
+		}
+		return next(c)
+	}
+}
@@ -29,6 +29,22 @@ func (w *responseBodyWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
 
+// every request body field that holds a secret, whatever the route is
+var redactedFields = []string{"password", "currentpassword", "confirmpassword", "code", "token", "refreshToken", "challengeId"}
+
+// NoStore marks a response that hands out a credential, it is neither cached nor logged
+func NoStore(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		return next(c)
+	}
+}
+
+// a downloaded file like a data export archive is personal data and a no-store response holds a credential, both are left out of the log
+func isResponseBodyLogged(c echo.Context) bool {
+	return c.Response().Header().Get(echo.HeaderContentDisposition) == "" && c.Response().Header().Get(echo.HeaderCacheControl) != "no-store"
+}
+
 func PrintRequestResponseLog(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		datetimeNowRequest := time.Now()
@@ -75,11 +91,8 @@ func PrintRequestResponseLog(next echo.HandlerFunc) echo.HandlerFunc {
 		c.Request().Body = io.NopCloser(bytes.NewBuffer(body))
 
 		if jsonRequestBodyMap != nil {
-			if c.Request().URL.Path == "/api/v1/users/register" {
-				delete(jsonRequestBodyMap, "password")
-				delete(jsonRequestBodyMap, "confirmpassword")
-			} else if c.Request().URL.Path == "/api/v1/users/login" {
-				delete(jsonRequestBodyMap, "password")
+			for _, field := range redactedFields {
+				delete(jsonRequestBodyMap, field)
 			}
 
 			jsonRequestBodyByte, errJsonRequestBodyByte := json.Marshal(jsonRequestBodyMap)
@@ -125,6 +138,9 @@ func PrintRequestResponseLog(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 
 		responseBody := resBody.String()
+		if !isResponseBodyLogged(c) {
+			responseBody = `""`
+		}
 		responseStatus := writer.status
 		log := `{"responseTime": "` + time.Now().String() + `", "app": "project-backend", "requestId": "` + requestId + `", "responseStatus": ` + strconv.Itoa(responseStatus) + `, "response": ` + responseBody + `}`
 		fmt.Println(log)
@@ -185,6 +201,9 @@ func PrintRequestResponseLogWithNoRequestBody(next echo.HandlerFunc) echo.Handle
 		}
 
 		responseBody := resBody.String()
+		if !isResponseBodyLogged(c) {
+			responseBody = `""`
+		}
 		log := `{"responseTime": "` + time.Now().String() + `", "app": "project-backend", "requestId": "` + requestId + `", "response": ` + responseBody + `}`
 		fmt.Println(log)
 		return nil
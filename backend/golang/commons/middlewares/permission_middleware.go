@@ -0,0 +1,149 @@
+package middlewares
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	AdministratorPermission    = "ADMINISTRATOR"
+	CreatePermissionPermission = "CREATE_PERMISSION"
+	ReadPermissionPermission   = "READ_PERMISSION"
+	UpdatePermissionPermission = "UPDATE_PERMISSION"
+	DeletePermissionPermission = "DELETE_PERMISSION"
+)
+
+// PermissionIdsTtl bounds how long a renamed or deleted permission keeps its old id in the cached mapping
+const PermissionIdsTtl = time.Minute
+
+type PermissionMiddleware interface {
+	RequirePermissions(permissions ...string) echo.MiddlewareFunc
+	RequireAny(permissions ...string) echo.MiddlewareFunc
+}
+
+type PermissionMiddlewareImplementation struct {
+	PostgresUtil         utils.PostgresUtil
+	PermissionRepository repositories.PermissionRepository
+	PermissionIdsTtl     time.Duration
+	mutex                sync.Mutex
+	permissionIds        map[string]int32
+	loadedAt             time.Time
+}
+
+func NewPermissionMiddleware(postgresUtil utils.PostgresUtil, permissionRepository repositories.PermissionRepository) PermissionMiddleware {
+	return &PermissionMiddlewareImplementation{
+		PostgresUtil:         postgresUtil,
+		PermissionRepository: permissionRepository,
+		PermissionIdsTtl:     PermissionIdsTtl,
+	}
+}
+
+// RequirePermissions lets the request through when the session has every one of the permissions, it must be used after SessionMiddleware.Authenticate
+func (middleware *PermissionMiddlewareImplementation) RequirePermissions(permissions ...string) echo.MiddlewareFunc {
+	return middleware.require(permissions, true)
+}
+
+// RequireAny lets the request through when the session has at least one of the permissions, it must be used after SessionMiddleware.Authenticate
+func (middleware *PermissionMiddlewareImplementation) RequireAny(permissions ...string) echo.MiddlewareFunc {
+	return middleware.require(permissions, false)
+}
+
+func (middleware *PermissionMiddlewareImplementation) require(permissions []string, requireAll bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			requestId := c.Request().Context().Value(RequestIdKey).(string)
+
+			idPermissions, ok := c.Request().Context().Value(PermissionKey).([]int32)
+			if !ok {
+				err := errors.New("cannot find permissions in context, use SessionMiddleware.Authenticate before checking permissions")
+				httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+				return c.JSON(httpCode, response)
+			}
+
+			permissionIds, err := middleware.getPermissionIds(c.Request().Context(), permissions)
+			if err != nil {
+				httpCode, response := helpers.ToResponseCheckError(err, requestId)
+				return c.JSON(httpCode, response)
+			}
+
+			owned := make(map[int32]bool)
+			for _, idPermission := range idPermissions {
+				owned[idPermission] = true
+			}
+			// the administrator permission only counts for sessions that passed two-factor authentication
+			twoFactor, _ := c.Request().Context().Value(TwoFactorKey).(bool)
+			administrator := owned[permissionIds[AdministratorPermission]]
+			if administrator && twoFactor {
+				return next(c)
+			}
+			if administrator {
+				delete(owned, permissionIds[AdministratorPermission])
+			}
+
+			matched := 0
+			for _, permission := range permissions {
+				idPermission, ok := permissionIds[permission]
+				if !ok {
+					err = errors.New("unknown permission: " + permission)
+					httpCode, response := helpers.ToResponseCheckError(err, requestId)
+					return c.JSON(httpCode, response)
+				}
+				if owned[idPermission] {
+					matched++
+				}
+			}
+
+			if (requireAll && matched != len(permissions)) || (!requireAll && matched == 0) {
+				if administrator {
+					err = errors.New("administrator session without two-factor authentication")
+					httpCode, response := helpers.ToResponseError(err, requestId, http.StatusForbidden, "please log in with two-factor authentication to use administrator permissions")
+					return c.JSON(httpCode, response)
+				}
+				err = errors.New("user doesn't have the required permissions")
+				httpCode, response := helpers.ToResponseError(err, requestId, http.StatusForbidden, "forbidden")
+				return c.JSON(httpCode, response)
+			}
+			return next(c)
+		}
+	}
+}
+
+// getPermissionIds caches the permission name to id mapping for PermissionIdsTtl, a failed load is retried on the next request
+// and so is a mapping without one of the required permissions, which may have been created since
+func (middleware *PermissionMiddlewareImplementation) getPermissionIds(ctx context.Context, required []string) (permissionIds map[string]int32, err error) {
+	middleware.mutex.Lock()
+	defer middleware.mutex.Unlock()
+	fresh := time.Since(middleware.loadedAt) < middleware.PermissionIdsTtl
+	if middleware.permissionIds != nil && fresh && containsAll(middleware.permissionIds, required) {
+		return middleware.permissionIds, nil
+	}
+
+	permissions, err := middleware.PermissionRepository.FindAll(middleware.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		return
+	}
+	permissionIds = make(map[string]int32)
+	for _, permission := range permissions {
+		permissionIds[permission.Permission.String] = permission.Id.Int32
+	}
+	middleware.permissionIds = permissionIds
+	middleware.loadedAt = time.Now()
+	return
+}
+
+func containsAll(permissionIds map[string]int32, permissions []string) bool {
+	for _, permission := range permissions {
+		if _, ok := permissionIds[permission]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package middlewares
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+type SessionMiddleware interface {
+	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type SessionMiddlewareImplementation struct {
+	RedisUtil   utils.RedisUtil
+	RedisHelper helpers.RedisHelper
+	Optional    bool
+}
+
+func NewSessionMiddleware(redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper) SessionMiddleware {
+	return &SessionMiddlewareImplementation{
+		RedisUtil:   redisUtil,
+		RedisHelper: redisHelper,
+	}
+}
+
+// NewOptionalSessionMiddleware lets a request without a live session reach the handler with no session in the context instead of answering 401,
+// logout uses it so logging out of a session that is already gone is not an error
+func NewOptionalSessionMiddleware(redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper) SessionMiddleware {
+	return &SessionMiddlewareImplementation{
+		RedisUtil:   redisUtil,
+		RedisHelper: redisHelper,
+		Optional:    true,
+	}
+}
+
+// Authenticate loads the session from redis and puts id, username, email, idPermissions, sessionId and twoFactor into the request context,
+// every authenticated request slides the redis ttl and the cookie expiry forward up to the absolute lifetime
+func (middleware *SessionMiddlewareImplementation) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestId := c.Request().Context().Value(RequestIdKey).(string)
+
+		cookie, err := c.Cookie(helpers.SessionCookieName)
+		if err != nil || cookie.Value == "" {
+			return middleware.unauthenticated(c, next, requestId, errors.New("cannot find session id cookie"))
+		}
+		sessionId := cookie.Value
+
+		sessionValue, err := middleware.RedisHelper.Get(middleware.RedisUtil.GetClient(), c.Request().Context(), sessionId)
+		if err == redis.Nil {
+			return middleware.unauthenticated(c, next, requestId, errors.New("cannot find session: "+helpers.ToSessionPublicId(sessionId)))
+		} else if err != nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+
+		var session helpers.Session
+		err = json.Unmarshal([]byte(sessionValue), &session)
+		if err != nil || session.Id == 0 {
+			return middleware.unauthenticated(c, next, requestId, errors.New("malformed session: "+helpers.ToSessionPublicId(sessionId)))
+		}
+
+		sessionLifetime, err := helpers.GetSessionLifetime()
+		if err != nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+		// sessions without createdAt were written before sessions had a lifetime, they end up here as expired too
+		ttl := sessionLifetime.Ttl(time.UnixMilli(session.CreatedAt), time.Now())
+		if ttl <= 0 {
+			_, err = middleware.RedisHelper.Del(middleware.RedisUtil.GetClient(), c.Request().Context(), sessionId)
+			if err != nil && err != redis.Nil {
+				httpCode, response := helpers.ToResponseCheckError(err, requestId)
+				return c.JSON(httpCode, response)
+			}
+			return middleware.unauthenticated(c, next, requestId, errors.New("session reached its absolute lifetime: "+helpers.ToSessionPublicId(sessionId)))
+		}
+
+		_, err = middleware.RedisHelper.Expire(middleware.RedisUtil.GetClient(), c.Request().Context(), sessionId, ttl)
+		if err != nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+		sessionCookie, err := helpers.ToSessionCookie(sessionId, ttl)
+		if err != nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+		c.SetCookie(sessionCookie)
+
+		ctx := context.WithValue(c.Request().Context(), IdKey, session.Id)
+		ctx = context.WithValue(ctx, UsernameKey, session.Username)
+		ctx = context.WithValue(ctx, EmailKey, session.Email)
+		ctx = context.WithValue(ctx, PermissionKey, session.IdPermissions)
+		ctx = context.WithValue(ctx, SessionIdKey, sessionId)
+		ctx = context.WithValue(ctx, TwoFactorKey, session.TwoFactor)
+		ctx = context.WithValue(ctx, AuthenticatedAtKey, session.CreatedAt)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+func (middleware *SessionMiddlewareImplementation) unauthenticated(c echo.Context, next echo.HandlerFunc, requestId string, err error) error {
+	if middleware.Optional {
+		return next(c)
+	}
+	httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+	return c.JSON(httpCode, response)
+}
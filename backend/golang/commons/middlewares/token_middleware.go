@@ -0,0 +1,138 @@
+package middlewares
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/utils"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TokenMiddleware interface {
+	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type TokenMiddlewareImplementation struct {
+	RedisUtil         utils.RedisUtil
+	JwtHelper         helpers.JwtHelper
+	TokenFamilyHelper helpers.TokenFamilyHelper
+}
+
+func NewTokenMiddleware(redisUtil utils.RedisUtil, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper) TokenMiddleware {
+	return &TokenMiddlewareImplementation{
+		RedisUtil:         redisUtil,
+		JwtHelper:         jwtHelper,
+		TokenFamilyHelper: tokenFamilyHelper,
+	}
+}
+
+// Authenticate takes the access token from Authorization: Bearer and puts the same keys as the session middleware into the request context,
+// sessionId is the key of the token family and tokenId the id of the access token, a revoked family rejects its access tokens right away
+func (middleware *TokenMiddlewareImplementation) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestId := c.Request().Context().Value(RequestIdKey).(string)
+
+		token, found := toBearerToken(c)
+		if !found {
+			err := errors.New("cannot find bearer token")
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+
+		claims, err := middleware.JwtHelper.ParseAccessToken(token)
+		if err != nil {
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+		userId, err := strconv.ParseInt(claims.Subject, 10, 32)
+		if err != nil || userId == 0 {
+			err = errors.New("malformed access token subject: " + claims.Subject)
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+
+		exists, err := middleware.TokenFamilyHelper.Exists(middleware.RedisUtil.GetClient(), c.Request().Context(), claims.FamilyId)
+		if err != nil {
+			httpCode, response := helpers.ToResponseCheckError(err, requestId)
+			return c.JSON(httpCode, response)
+		}
+		if !exists {
+			err = errors.New("token family is revoked or expired: " + claims.FamilyId)
+			httpCode, response := helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "unauthorized")
+			return c.JSON(httpCode, response)
+		}
+
+		ctx := context.WithValue(c.Request().Context(), IdKey, int32(userId))
+		ctx = context.WithValue(ctx, UsernameKey, claims.Username)
+		ctx = context.WithValue(ctx, EmailKey, claims.Email)
+		ctx = context.WithValue(ctx, PermissionKey, claims.IdPermissions)
+		ctx = context.WithValue(ctx, SessionIdKey, helpers.ToTokenFamilyKey(claims.FamilyId))
+		ctx = context.WithValue(ctx, TokenIdKey, claims.ID)
+		ctx = context.WithValue(ctx, TwoFactorKey, claims.TwoFactor)
+		ctx = context.WithValue(ctx, AuthenticatedAtKey, claims.AuthenticatedAt)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+func toBearerToken(c echo.Context) (token string, found bool) {
+	scheme, token, found := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// SessionOrTokenMiddlewareImplementation lets a route take the session cookie, a bearer token and, only when ApiKeyMiddleware is set,
+// an api key. A request with an X-API-Key header is always authenticated by the key and one with an Authorization header by the token
+type SessionOrTokenMiddlewareImplementation struct {
+	SessionMiddleware SessionMiddleware
+	TokenMiddleware   TokenMiddleware
+	ApiKeyMiddleware  ApiKeyMiddleware
+}
+
+// NewSessionOrTokenMiddleware is for the routes of the user itself, like the profile, sessions, two-factor and personal data,
+// an api key only carries the permissions of its scope so it gets 403 on a route that does not check any
+func NewSessionOrTokenMiddleware(sessionMiddleware SessionMiddleware, tokenMiddleware TokenMiddleware) SessionMiddleware {
+	return &SessionOrTokenMiddlewareImplementation{
+		SessionMiddleware: sessionMiddleware,
+		TokenMiddleware:   tokenMiddleware,
+	}
+}
+
+// NewSessionTokenOrApiKeyMiddleware also takes an api key, it is only for routes guarded by PermissionMiddleware.RequirePermissions
+func NewSessionTokenOrApiKeyMiddleware(sessionMiddleware SessionMiddleware, tokenMiddleware TokenMiddleware, apiKeyMiddleware ApiKeyMiddleware) SessionMiddleware {
+	return &SessionOrTokenMiddlewareImplementation{
+		SessionMiddleware: sessionMiddleware,
+		TokenMiddleware:   tokenMiddleware,
+		ApiKeyMiddleware:  apiKeyMiddleware,
+	}
+}
+
+func (middleware *SessionOrTokenMiddlewareImplementation) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	sessionNext := middleware.SessionMiddleware.Authenticate(next)
+	tokenNext := middleware.TokenMiddleware.Authenticate(next)
+	var apiKeyNext echo.HandlerFunc
+	if middleware.ApiKeyMiddleware != nil {
+		apiKeyNext = middleware.ApiKeyMiddleware.Authenticate(next)
+	}
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(ApiKeyHeader) != "" {
+			if apiKeyNext == nil {
+				requestId := c.Request().Context().Value(RequestIdKey).(string)
+				err := errors.New("api key used on a route without a permission check")
+				httpCode, response := helpers.ToResponseError(err, requestId, http.StatusForbidden, "forbidden")
+				return c.JSON(httpCode, response)
+			}
+			return apiKeyNext(c)
+		}
+		if c.Request().Header.Get(echo.HeaderAuthorization) != "" {
+			return tokenNext(c)
+		}
+		return sessionNext(c)
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// ApiKey is what the api key middleware needs to act as the user of the key, IdPermissions are the permissions of the key
+// the user still holds
+type ApiKey struct {
+	Id            pgtype.Int4
+	UserId        pgtype.Int4
+	Username      pgtype.Text
+	Email         pgtype.Text
+	LastUsedAt    pgtype.Int8
+	IdPermissions []int32
+}
@@ -0,0 +1,12 @@
+package models
+
+// AuthEvent is one row of auth_events, UserId is 0 when the email does not belong to any user
+type AuthEvent struct {
+	EventType string
+	UserId    int32
+	Email     string
+	Ip        string
+	UserAgent string
+	RequestId string
+	CreatedAt int64
+}
@@ -0,0 +1,81 @@
+package models
+
+// PersonalData is the archive of a data export, everything kept about one users.id except secrets like password and key hashes
+type PersonalData struct {
+	ExportedAt             int64                         `json:"exportedAt"`
+	User                   PersonalDataUser              `json:"user"`
+	Permissions            []string                      `json:"permissions"`
+	Roles                  []string                      `json:"roles"`
+	RecoveryCodesRemaining int                           `json:"recoveryCodesRemaining"`
+	Identities             []PersonalDataIdentity        `json:"identities"`
+	ApiKeys                []PersonalDataApiKey          `json:"apiKeys"`
+	Sessions               []PersonalDataSession         `json:"sessions"`
+	AuthEvents             []PersonalDataAuthEvent       `json:"authEvents"`
+	PermissionAudits       []PersonalDataPermissionAudit `json:"permissionAudits"`
+	RoleAudits             []PersonalDataRoleAudit       `json:"roleAudits"`
+}
+
+type PersonalDataUser struct {
+	Id                  int32  `json:"id"`
+	Username            string `json:"username"`
+	Email               string `json:"email"`
+	CreatedAt           int64  `json:"createdAt"`
+	EmailVerifiedAt     *int64 `json:"emailVerifiedAt"`
+	TwoFactorEnabledAt  *int64 `json:"twoFactorEnabledAt"`
+	DisabledAt          *int64 `json:"disabledAt"`
+	DeletionRequestedAt *int64 `json:"deletionRequestedAt"`
+}
+
+type PersonalDataIdentity struct {
+	Provider  string `json:"provider"`
+	Subject   string `json:"subject"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+type PersonalDataApiKey struct {
+	Name        string   `json:"name"`
+	KeyPrefix   string   `json:"keyPrefix"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   int64    `json:"createdAt"`
+	ExpiresAt   *int64   `json:"expiresAt"`
+	LastUsedAt  *int64   `json:"lastUsedAt"`
+	RevokedAt   *int64   `json:"revokedAt"`
+}
+
+type PersonalDataSession struct {
+	CreatedAt int64  `json:"createdAt"`
+	UserAgent string `json:"userAgent"`
+	Ip        string `json:"ip"`
+}
+
+type PersonalDataAuthEvent struct {
+	EventType string `json:"eventType"`
+	Email     string `json:"email"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// PersonalDataPermissionAudit is a grant or revoke of a permission of the user or one the user made to someone else
+type PersonalDataPermissionAudit struct {
+	UserId     int32  `json:"userId"`
+	Permission string `json:"permission"`
+	Action     string `json:"action"`
+	ActorId    int32  `json:"actorId"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+// PersonalDataRoleAudit is an assign or unassign of a role of the user or one the user made to someone else
+type PersonalDataRoleAudit struct {
+	UserId    int32  `json:"userId"`
+	Role      string `json:"role"`
+	Action    string `json:"action"`
+	ActorId   int32  `json:"actorId"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// DataExport is a requested export the background writer still has to write
+type DataExport struct {
+	Id     int32
+	UserId int32
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AccountDeletionRepository anonymises the accounts whose deletion grace period is over
+type AccountDeletionRepository interface {
+	FindUserIdsDue(pool *pgxpool.Pool, ctx context.Context, deletionRequestedBefore int64) (userIds []int32, err error)
+	Anonymise(tx pgx.Tx, ctx context.Context, userId int32, deletionRequestedBefore int64, deletedAt int64) (anonymised bool, err error)
+}
+
+type AccountDeletionRepositoryImplementation struct {
+}
+
+func NewAccountDeletionRepository() AccountDeletionRepository {
+	return &AccountDeletionRepositoryImplementation{}
+}
+
+func (repository *AccountDeletionRepositoryImplementation) FindUserIdsDue(pool *pgxpool.Pool, ctx context.Context, deletionRequestedBefore int64) (userIds []int32, err error) {
+	rows, err := pool.Query(ctx, `SELECT id FROM users WHERE deletion_requested_at <= $1 AND deleted_at IS NULL ORDER BY id;`, deletionRequestedBefore)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			userIds = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var userId int32
+		err = rows.Scan(&userId)
+		if err != nil {
+			userIds = []int32{}
+			return
+		}
+		userIds = append(userIds, userId)
+	}
+	return
+}
+
+// Anonymise replaces the personal data of the user with placeholders which can never be registered or logged in with, the users row
+// and its permissions, roles and audits stay so everything referencing users.id keeps working. The row is locked and checked again
+// so a deletion cancelled after FindUserIdsDue is left alone, anonymised is false then
+func (repository *AccountDeletionRepositoryImplementation) Anonymise(tx pgx.Tx, ctx context.Context, userId int32, deletionRequestedBefore int64, deletedAt int64) (anonymised bool, err error) {
+	var email string
+	err = tx.QueryRow(ctx, `SELECT email FROM users WHERE id = $1 AND deletion_requested_at <= $2 AND deleted_at IS NULL FOR UPDATE;`, userId, deletionRequestedBefore).Scan(&email)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET username = 'deleted_' || id, email = 'deleted_' || id, password = '', email_verified_at = NULL,
+		totp_secret = NULL, totp_enabled_at = NULL, totp_last_used_step = NULL, disabled_at = COALESCE(disabled_at, $2), deleted_at = $2 WHERE id = $1;`, userId, deletedAt)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM user_identities WHERE user_id = $1;`, userId)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userId)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE user_id = $1;`, userId, deletedAt)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, `UPDATE auth_events SET email = '', ip = '', user_agent = '' WHERE user_id = $1 OR email = $2;`, userId, email)
+	if err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM data_exports WHERE user_id = $1;`, userId)
+	if err != nil {
+		return
+	}
+	return true, nil
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ApiKeyRepository interface {
+	FindActiveByKeyHash(pool *pgxpool.Pool, ctx context.Context, keyHash string, now int64) (apiKey models.ApiKey, err error)
+	UpdateLastUsedAt(pool *pgxpool.Pool, ctx context.Context, id int32, lastUsedAt int64) (err error)
+}
+
+type ApiKeyRepositoryImplementation struct {
+}
+
+func NewApiKeyRepository() ApiKeyRepository {
+	return &ApiKeyRepositoryImplementation{}
+}
+
+// FindActiveByKeyHash skips revoked and expired keys and keys of disabled users, the permissions of the key are narrowed to the
+// ones its user holds right now directly or through a role, so revoking a permission from the user revokes it from their keys too
+func (repository *ApiKeyRepositoryImplementation) FindActiveByKeyHash(pool *pgxpool.Pool, ctx context.Context, keyHash string, now int64) (apiKey models.ApiKey, err error) {
+	query := `WITH RECURSIVE active_api_key AS (
+		SELECT k.id, k.user_id, u.username, u.email, k.last_used_at FROM api_keys k JOIN users u ON u.id = k.user_id
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > $2) AND u.disabled_at IS NULL
+	), user_role_tree AS (
+		SELECT r.id, r.parent_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id JOIN active_api_key ak ON ak.user_id = ur.user_id
+		UNION
+		SELECT r.id, r.parent_id FROM roles r JOIN user_role_tree urt ON r.id = urt.parent_id
+	)
+	SELECT ak.id, ak.user_id, ak.username, ak.email, ak.last_used_at, ARRAY(
+		SELECT akp.permission_id FROM api_key_permissions akp WHERE akp.api_key_id = ak.id AND akp.permission_id IN (
+			SELECT permission_id FROM user_permissions WHERE user_id = ak.user_id
+			UNION
+			SELECT rp.permission_id FROM role_permissions rp JOIN user_role_tree urt ON urt.id = rp.role_id
+		) ORDER BY akp.permission_id
+	) FROM active_api_key ak;`
+	err = pool.QueryRow(ctx, query, keyHash, now).Scan(&apiKey.Id, &apiKey.UserId, &apiKey.Username, &apiKey.Email, &apiKey.LastUsedAt, &apiKey.IdPermissions)
+	return
+}
+
+func (repository *ApiKeyRepositoryImplementation) UpdateLastUsedAt(pool *pgxpool.Pool, ctx context.Context, id int32, lastUsedAt int64) (err error) {
+	_, err = pool.Exec(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1;`, id, lastUsedAt)
+	return
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuthEventRepository interface {
+	Create(pool *pgxpool.Pool, ctx context.Context, authEvent models.AuthEvent) (err error)
+}
+
+type AuthEventRepositoryImplementation struct {
+}
+
+func NewAuthEventRepository() AuthEventRepository {
+	return &AuthEventRepositoryImplementation{}
+}
+
+func (repository *AuthEventRepositoryImplementation) Create(pool *pgxpool.Pool, ctx context.Context, authEvent models.AuthEvent) (err error) {
+	_, err = pool.Exec(ctx, `INSERT INTO auth_events (event_type, user_id, email, ip, user_agent, request_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		authEvent.EventType, pgtype.Int4{Valid: authEvent.UserId > 0, Int32: authEvent.UserId}, authEvent.Email, authEvent.Ip, authEvent.UserAgent, authEvent.RequestId, authEvent.CreatedAt)
+	return
+}
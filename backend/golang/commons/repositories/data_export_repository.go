@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DataExportRepository is used by the background writer of the data exports, the feature creates and reads them with its own repository
+type DataExportRepository interface {
+	FindAllPending(pool *pgxpool.Pool, ctx context.Context) (dataExports []models.DataExport, err error)
+	UpdateReady(pool *pgxpool.Pool, ctx context.Context, id int32, archive []byte, completedAt int64, expiresAt int64) (err error)
+	UpdateFailed(pool *pgxpool.Pool, ctx context.Context, id int32, completedAt int64) (err error)
+	UpdateExpired(pool *pgxpool.Pool, ctx context.Context, now int64) (err error)
+}
+
+type DataExportRepositoryImplementation struct {
+}
+
+func NewDataExportRepository() DataExportRepository {
+	return &DataExportRepositoryImplementation{}
+}
+
+func (repository *DataExportRepositoryImplementation) FindAllPending(pool *pgxpool.Pool, ctx context.Context) (dataExports []models.DataExport, err error) {
+	rows, err := pool.Query(ctx, `SELECT id, user_id FROM data_exports WHERE status = 'PENDING' ORDER BY id;`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			dataExports = []models.DataExport{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var dataExport models.DataExport
+		err = rows.Scan(&dataExport.Id, &dataExport.UserId)
+		if err != nil {
+			dataExports = []models.DataExport{}
+			return
+		}
+		dataExports = append(dataExports, dataExport)
+	}
+	return
+}
+
+func (repository *DataExportRepositoryImplementation) UpdateReady(pool *pgxpool.Pool, ctx context.Context, id int32, archive []byte, completedAt int64, expiresAt int64) (err error) {
+	_, err = pool.Exec(ctx, `UPDATE data_exports SET status = 'READY', archive = $2, completed_at = $3, expires_at = $4 WHERE id = $1 AND status = 'PENDING';`, id, archive, completedAt, expiresAt)
+	return
+}
+
+func (repository *DataExportRepositoryImplementation) UpdateFailed(pool *pgxpool.Pool, ctx context.Context, id int32, completedAt int64) (err error) {
+	_, err = pool.Exec(ctx, `UPDATE data_exports SET status = 'FAILED', completed_at = $2 WHERE id = $1 AND status = 'PENDING';`, id, completedAt)
+	return
+}
+
+// UpdateExpired drops the archives nobody may download anymore, the rows stay so the user still sees the export expired
+func (repository *DataExportRepositoryImplementation) UpdateExpired(pool *pgxpool.Pool, ctx context.Context, now int64) (err error) {
+	_, err = pool.Exec(ctx, `UPDATE data_exports SET status = 'EXPIRED', archive = NULL WHERE status = 'READY' AND expires_at <= $1;`, now)
+	return
+}
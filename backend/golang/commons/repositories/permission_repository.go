@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error)
+}
+
+type PermissionRepositoryImplementation struct {
+}
+
+func NewPermissionRepository() PermissionRepository {
+	return &PermissionRepositoryImplementation{}
+}
+
+func (repository *PermissionRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error) {
+	rows, err := pool.Query(ctx, `SELECT id, permission FROM permissions;`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissions = []models.Permission{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permission models.Permission
+		err = rows.Scan(&permission.Id, &permission.Permission)
+		if err != nil {
+			permissions = []models.Permission{}
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+	return
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PersonalDataRepository reads everything kept about a user for a data export, the sessions live in redis and are not read here
+type PersonalDataRepository interface {
+	FindUserById(pool *pgxpool.Pool, ctx context.Context, userId int32) (user models.PersonalDataUser, err error)
+	FindPermissionsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissions []string, err error)
+	FindRolesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []string, err error)
+	CountUnusedRecoveryCodesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (count int, err error)
+	FindIdentitiesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (identities []models.PersonalDataIdentity, err error)
+	FindApiKeysByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (apiKeys []models.PersonalDataApiKey, err error)
+	FindAuthEventsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32, email string) (authEvents []models.PersonalDataAuthEvent, err error)
+	FindPermissionAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissionAudits []models.PersonalDataPermissionAudit, err error)
+	FindRoleAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roleAudits []models.PersonalDataRoleAudit, err error)
+}
+
+type PersonalDataRepositoryImplementation struct {
+}
+
+func NewPersonalDataRepository() PersonalDataRepository {
+	return &PersonalDataRepositoryImplementation{}
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindUserById(pool *pgxpool.Pool, ctx context.Context, userId int32) (user models.PersonalDataUser, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email, created_at, email_verified_at, totp_enabled_at, disabled_at, deletion_requested_at FROM users WHERE id = $1;`, userId).Scan(&user.Id, &user.Username, &user.Email, &user.CreatedAt, &user.EmailVerifiedAt, &user.TwoFactorEnabledAt, &user.DisabledAt, &user.DeletionRequestedAt)
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) CountUnusedRecoveryCodesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (count int, err error) {
+	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL;`, userId).Scan(&count)
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindPermissionsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissions []string, err error) {
+	permissions = []string{}
+	rows, err := pool.Query(ctx, `SELECT p.permission FROM user_permissions up JOIN permissions p ON p.id = up.permission_id WHERE up.user_id = $1 ORDER BY p.permission;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissions = []string{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permission string
+		err = rows.Scan(&permission)
+		if err != nil {
+			permissions = []string{}
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindRolesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []string, err error) {
+	roles = []string{}
+	rows, err := pool.Query(ctx, `SELECT r.role FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE ur.user_id = $1 ORDER BY r.role;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			roles = []string{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var role string
+		err = rows.Scan(&role)
+		if err != nil {
+			roles = []string{}
+			return
+		}
+		roles = append(roles, role)
+	}
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindIdentitiesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (identities []models.PersonalDataIdentity, err error) {
+	identities = []models.PersonalDataIdentity{}
+	rows, err := pool.Query(ctx, `SELECT provider, subject, created_at FROM user_identities WHERE user_id = $1 ORDER BY id;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			identities = []models.PersonalDataIdentity{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var identity models.PersonalDataIdentity
+		err = rows.Scan(&identity.Provider, &identity.Subject, &identity.CreatedAt)
+		if err != nil {
+			identities = []models.PersonalDataIdentity{}
+			return
+		}
+		identities = append(identities, identity)
+	}
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindApiKeysByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (apiKeys []models.PersonalDataApiKey, err error) {
+	apiKeys = []models.PersonalDataApiKey{}
+	rows, err := pool.Query(ctx, `SELECT k.name, k.key_prefix, ARRAY(
+		SELECT p.permission FROM api_key_permissions akp JOIN permissions p ON p.id = akp.permission_id WHERE akp.api_key_id = k.id ORDER BY p.permission
+	), k.created_at, k.expires_at, k.last_used_at, k.revoked_at FROM api_keys k WHERE k.user_id = $1 ORDER BY k.id;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			apiKeys = []models.PersonalDataApiKey{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var apiKey models.PersonalDataApiKey
+		err = rows.Scan(&apiKey.Name, &apiKey.KeyPrefix, &apiKey.Permissions, &apiKey.CreatedAt, &apiKey.ExpiresAt, &apiKey.LastUsedAt, &apiKey.RevokedAt)
+		if err != nil {
+			apiKeys = []models.PersonalDataApiKey{}
+			return
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+	return
+}
+
+// FindAuthEventsByUserId also finds the events with the email of the user and no user id, like failed logins before registering
+func (repository *PersonalDataRepositoryImplementation) FindAuthEventsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32, email string) (authEvents []models.PersonalDataAuthEvent, err error) {
+	authEvents = []models.PersonalDataAuthEvent{}
+	rows, err := pool.Query(ctx, `SELECT event_type, email, ip, user_agent, created_at FROM auth_events WHERE user_id = $1 OR email = $2 ORDER BY id;`, userId, email)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			authEvents = []models.PersonalDataAuthEvent{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var authEvent models.PersonalDataAuthEvent
+		err = rows.Scan(&authEvent.EventType, &authEvent.Email, &authEvent.Ip, &authEvent.UserAgent, &authEvent.CreatedAt)
+		if err != nil {
+			authEvents = []models.PersonalDataAuthEvent{}
+			return
+		}
+		authEvents = append(authEvents, authEvent)
+	}
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindPermissionAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissionAudits []models.PersonalDataPermissionAudit, err error) {
+	permissionAudits = []models.PersonalDataPermissionAudit{}
+	rows, err := pool.Query(ctx, `SELECT a.user_id, COALESCE(p.permission, ''), a.action, a.actor_id, a.created_at FROM user_permission_audits a
+		LEFT JOIN permissions p ON p.id = a.permission_id WHERE a.user_id = $1 OR a.actor_id = $1 ORDER BY a.id;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissionAudits = []models.PersonalDataPermissionAudit{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permissionAudit models.PersonalDataPermissionAudit
+		err = rows.Scan(&permissionAudit.UserId, &permissionAudit.Permission, &permissionAudit.Action, &permissionAudit.ActorId, &permissionAudit.CreatedAt)
+		if err != nil {
+			permissionAudits = []models.PersonalDataPermissionAudit{}
+			return
+		}
+		permissionAudits = append(permissionAudits, permissionAudit)
+	}
+	return
+}
+
+func (repository *PersonalDataRepositoryImplementation) FindRoleAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roleAudits []models.PersonalDataRoleAudit, err error) {
+	roleAudits = []models.PersonalDataRoleAudit{}
+	rows, err := pool.Query(ctx, `SELECT a.user_id, COALESCE(r.role, ''), a.action, a.actor_id, a.created_at FROM user_role_audits a
+		LEFT JOIN roles r ON r.id = a.role_id WHERE a.user_id = $1 OR a.actor_id = $1 ORDER BY a.id;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			roleAudits = []models.PersonalDataRoleAudit{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var roleAudit models.PersonalDataRoleAudit
+		err = rows.Scan(&roleAudit.UserId, &roleAudit.Role, &roleAudit.Action, &roleAudit.ActorId, &roleAudit.CreatedAt)
+		if err != nil {
+			roleAudits = []models.PersonalDataRoleAudit{}
+			return
+		}
+		roleAudits = append(roleAudits, roleAudit)
+	}
+	return
+}
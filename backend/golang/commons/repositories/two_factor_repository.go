@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TwoFactorRepository makes every two-factor code single use, rowsAffected is 0 when the code was already used
+type TwoFactorRepository interface {
+	UpdateTotpLastUsedStep(pool *pgxpool.Pool, ctx context.Context, userId int32, step int64) (rowsAffected int64, err error)
+	UseRecoveryCode(pool *pgxpool.Pool, ctx context.Context, userId int32, codeHash string, usedAt int64) (rowsAffected int64, err error)
+}
+
+type TwoFactorRepositoryImplementation struct {
+}
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &TwoFactorRepositoryImplementation{}
+}
+
+// UpdateTotpLastUsedStep only moves forward so a totp code cannot be replayed within its step
+func (repository *TwoFactorRepositoryImplementation) UpdateTotpLastUsedStep(pool *pgxpool.Pool, ctx context.Context, userId int32, step int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET totp_last_used_step = $1 WHERE id = $2 AND (totp_last_used_step IS NULL OR totp_last_used_step < $1);`, step, userId)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
+
+func (repository *TwoFactorRepositoryImplementation) UseRecoveryCode(pool *pgxpool.Pool, ctx context.Context, userId int32, codeHash string, usedAt int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE user_recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL;`, usedAt, userId, codeHash)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
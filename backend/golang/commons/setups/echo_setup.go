@@ -3,6 +3,7 @@ package setups
 import (
 	"backend-golang/commons/helpers"
 	"backend-golang/commons/middlewares"
+	"backend-golang/commons/repositories"
 	"backend-golang/commons/utils"
 	"context"
 	"errors"
@@ -10,19 +11,66 @@ import (
 	"os"
 	"time"
 
+	categoryroutes "backend-golang/features/categories/routes"
+	productroutes "backend-golang/features/products/routes"
+	adminuserroutes "backend-golang/features/users/adminusers/routes"
+	apikeyroutes "backend-golang/features/users/apikeys/routes"
+	autheventroutes "backend-golang/features/users/authevents/routes"
+	changepasswordroutes "backend-golang/features/users/changepassword/routes"
+	csrfroutes "backend-golang/features/users/csrf/routes"
+	emailverificationroutes "backend-golang/features/users/emailverification/routes"
 	loginroutes "backend-golang/features/users/login/routes"
+	logoutroutes "backend-golang/features/users/logout/routes"
+	passwordresetroutes "backend-golang/features/users/passwordreset/routes"
+	permissionroutes "backend-golang/features/users/permissions/routes"
+	personaldataroutes "backend-golang/features/users/personaldata/routes"
+	profileroutes "backend-golang/features/users/profile/routes"
+	registerroutes "backend-golang/features/users/register/routes"
+	roleroutes "backend-golang/features/users/roles/routes"
+	sessionroutes "backend-golang/features/users/sessions/routes"
+	tokenroutes "backend-golang/features/users/token/routes"
+	twofactorroutes "backend-golang/features/users/twofactor/routes"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
 )
 
-func SetEcho(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper) (e *echo.Echo) {
+func SetEcho(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, tokenHelper helpers.TokenHelper, mailer utils.Mailer, emailVerificationHelper helpers.EmailVerificationHelper, twoFactorHelper helpers.TwoFactorHelper, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper, csrfTokenHelper helpers.CsrfTokenHelper, authEventHelper helpers.AuthEventHelper, oidcHelper helpers.OidcHelper, personalDataHelper helpers.PersonalDataHelper) (e *echo.Echo) {
 	e = echo.New()
 	e.Use(echomiddleware.Recover())
 	e.Use(middlewares.SetRequestId)
 	e.HTTPErrorHandler = CustomHTTPErrorHandler
-	loginroutes.LoginRoute(e, postgresUtil, redisUtil, validate, uuidHelper, redisHelper)
+	// every authenticated route takes the session cookie or an access token as Authorization: Bearer, only the routes which require
+	// permissions also take an api key as X-API-Key, state-changing requests with the session cookie also need the csrf token of the session
+	cookieSessionMiddleware := middlewares.NewCsrfMiddleware(redisUtil, csrfTokenHelper, middlewares.NewSessionMiddleware(redisUtil, redisHelper))
+	tokenMiddleware := middlewares.NewTokenMiddleware(redisUtil, jwtHelper, tokenFamilyHelper)
+	apiKeyMiddleware := middlewares.NewApiKeyMiddleware(postgresUtil, repositories.NewApiKeyRepository())
+	sessionMiddleware := middlewares.NewSessionOrTokenMiddleware(cookieSessionMiddleware, tokenMiddleware)
+	sessionOrApiKeyMiddleware := middlewares.NewSessionTokenOrApiKeyMiddleware(cookieSessionMiddleware, tokenMiddleware, apiKeyMiddleware)
+	// logout takes only the session cookie and still answers when the session is already gone
+	optionalSessionMiddleware := middlewares.NewCsrfMiddleware(redisUtil, csrfTokenHelper, middlewares.NewOptionalSessionMiddleware(redisUtil, redisHelper))
+	permissionMiddleware := middlewares.NewPermissionMiddleware(postgresUtil, repositories.NewPermissionRepository())
+	twoFactorRepository := repositories.NewTwoFactorRepository()
+	loginroutes.LoginRoute(e, postgresUtil, redisUtil, validate, uuidHelper, redisHelper, sessionRegistryHelper, twoFactorRepository, twoFactorHelper, jwtHelper, tokenFamilyHelper, passwordHasher, authEventHelper, tokenHelper, oidcHelper)
+	registerroutes.RegisterRoute(e, postgresUtil, redisUtil, validate, passwordHasher, emailVerificationHelper, mailer)
+	changepasswordroutes.ChangePasswordRoute(e, postgresUtil, redisUtil, validate, passwordHasher, uuidHelper, redisHelper, sessionRegistryHelper, sessionMiddleware)
+	emailverificationroutes.EmailVerificationRoute(e, postgresUtil, redisUtil, validate, emailVerificationHelper)
+	logoutroutes.LogoutRoute(e, redisUtil, redisHelper, sessionRegistryHelper, optionalSessionMiddleware)
+	passwordresetroutes.PasswordResetRoute(e, postgresUtil, redisUtil, validate, passwordHasher, tokenHelper, sessionRegistryHelper, mailer)
+	sessionroutes.SessionRoute(e, redisUtil, redisHelper, sessionRegistryHelper, sessionMiddleware, sessionOrApiKeyMiddleware, permissionMiddleware)
+	twofactorroutes.TwoFactorRoute(e, postgresUtil, validate, twoFactorRepository, twoFactorHelper, passwordHasher, sessionMiddleware)
+	tokenroutes.TokenRoute(e, redisUtil, uuidHelper, sessionRegistryHelper, jwtHelper, tokenFamilyHelper)
+	csrfroutes.CsrfRoute(e, redisUtil, tokenHelper, csrfTokenHelper, sessionMiddleware)
+	adminuserroutes.AdminUserRoute(e, postgresUtil, redisUtil, validate, sessionRegistryHelper, sessionOrApiKeyMiddleware, permissionMiddleware)
+	permissionroutes.PermissionRoute(e, postgresUtil, redisUtil, validate, sessionRegistryHelper, sessionOrApiKeyMiddleware, permissionMiddleware)
+	roleroutes.RoleRoute(e, postgresUtil, redisUtil, validate, sessionRegistryHelper, sessionOrApiKeyMiddleware, permissionMiddleware)
+	autheventroutes.AuthEventRoute(e, postgresUtil, validate, sessionOrApiKeyMiddleware, permissionMiddleware)
+	apikeyroutes.ApiKeyRoute(e, postgresUtil, validate, tokenHelper, sessionOrApiKeyMiddleware, permissionMiddleware)
+	profileroutes.ProfileRoute(e, postgresUtil, redisUtil, validate, passwordHasher, tokenHelper, sessionRegistryHelper, mailer, sessionMiddleware)
+	personaldataroutes.PersonalDataRoute(e, postgresUtil, redisUtil, validate, passwordHasher, sessionRegistryHelper, personalDataHelper, sessionMiddleware)
+	productroutes.ProductRoute(e, postgresUtil, validate, sessionOrApiKeyMiddleware, permissionMiddleware)
+	categoryroutes.CategoryRoute(e, postgresUtil, validate, sessionOrApiKeyMiddleware, permissionMiddleware)
 	return
 }
 
@@ -59,10 +59,18 @@ func TelephoneValidator(validate *validator.Validate) {
 	})
 }
 
+func SlugValidator(validate *validator.Validate) {
+	validate.RegisterValidation("slugvalidator", func(fl validator.FieldLevel) bool {
+		slugRegex := `^[a-z\d]+(-[a-z\d]+)*$`
+		return regexp.MustCompile(slugRegex).MatchString(fl.Field().String())
+	})
+}
+
 func SetValidator() (validate *validator.Validate) {
 	validate = validator.New()
 	UsernameValidator(validate)
 	PasswordValidator(validate)
 	TelephoneValidator(validate)
+	SlugValidator(validate)
 	return
 }
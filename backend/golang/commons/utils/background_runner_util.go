@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// BackgroundRunner runs work the request should not wait for, like sending a mail or writing an auth event
+type BackgroundRunner interface {
+	Run(ctx context.Context, name string, job func(ctx context.Context) error)
+}
+
+type backgroundJob struct {
+	ctx  context.Context
+	name string
+	job  func(ctx context.Context) error
+}
+
+// BackgroundRunnerImplementation queues the jobs and runs them one by one in a goroutine, when the queue is full the job is dropped
+// rather than slowing the request down, OnError gets the dropped jobs and the errors of the jobs since nobody waits for them anymore
+type BackgroundRunnerImplementation struct {
+	OnError   func(ctx context.Context, err error)
+	jobs      chan backgroundJob
+	waitGroup sync.WaitGroup
+}
+
+func NewBackgroundRunner(queueSize int, onError func(ctx context.Context, err error)) *BackgroundRunnerImplementation {
+	runner := &BackgroundRunnerImplementation{
+		OnError: onError,
+		jobs:    make(chan backgroundJob, queueSize),
+	}
+	runner.waitGroup.Add(1)
+	go runner.run()
+	return runner
+}
+
+// Run queues job with ctx without its cancellation, the response is written before the job runs and finishing the request
+// must not cancel it, name tells OnError which job was dropped
+func (runner *BackgroundRunnerImplementation) Run(ctx context.Context, name string, job func(ctx context.Context) error) {
+	ctx = context.WithoutCancel(ctx)
+	select {
+	case runner.jobs <- backgroundJob{ctx: ctx, name: name, job: job}:
+	default:
+		runner.OnError(ctx, errors.New("background queue is full, dropped "+name))
+	}
+}
+
+func (runner *BackgroundRunnerImplementation) run() {
+	defer runner.waitGroup.Done()
+	for backgroundJob := range runner.jobs {
+		err := backgroundJob.job(backgroundJob.ctx)
+		if err != nil {
+			runner.OnError(backgroundJob.ctx, err)
+		}
+	}
+}
+
+// Close runs the jobs still queued and stops the goroutine, it is called on shutdown once nothing can queue a job anymore
+func (runner *BackgroundRunnerImplementation) Close() {
+	close(runner.jobs)
+	runner.waitGroup.Wait()
+}
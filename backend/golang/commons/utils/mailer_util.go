@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SmtpTimeout bounds a whole send from the dial to the quit, a stuck mail server must not hold the background runner forever
+const SmtpTimeout = 30 * time.Second
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) (err error)
+}
+
+type SmtpMailerImplementation struct {
+	Addr    string
+	Auth    smtp.Auth
+	From    string
+	Timeout time.Duration
+}
+
+func NewSmtpMailer() Mailer {
+	host := os.Getenv("ECOMMERCEV2_SMTP_HOST")
+	var auth smtp.Auth
+	if os.Getenv("ECOMMERCEV2_SMTP_USERNAME") != "" {
+		auth = smtp.PlainAuth("", os.Getenv("ECOMMERCEV2_SMTP_USERNAME"), os.Getenv("ECOMMERCEV2_SMTP_PASSWORD"), host)
+	}
+	return &SmtpMailerImplementation{
+		Addr:    net.JoinHostPort(host, os.Getenv("ECOMMERCEV2_SMTP_PORT")),
+		Auth:    auth,
+		From:    os.Getenv("ECOMMERCEV2_SMTP_FROM"),
+		Timeout: SmtpTimeout,
+	}
+}
+
+func (mailer *SmtpMailerImplementation) Send(ctx context.Context, mail Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	// the headers come from our own templates, newlines are dropped so a value can never start a new header
+	headerReplacer := strings.NewReplacer("\r", "", "\n", "")
+	message := "From: " + headerReplacer.Replace(mailer.From) + "\r\n" +
+		"To: " + headerReplacer.Replace(mail.To) + "\r\n" +
+		"Subject: " + headerReplacer.Replace(mail.Subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+		"\r\n" + mail.Body
+	ctx, cancel := context.WithTimeout(ctx, mailer.Timeout)
+	defer cancel()
+	err = mailer.send(ctx, mail.To, message)
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return
+}
+
+// send does what smtp.SendMail does on a connection which gives up at the deadline of ctx or as soon as ctx is canceled
+func (mailer *SmtpMailerImplementation) send(ctx context.Context, to string, message string) (err error) {
+	host, _, err := net.SplitHostPort(mailer.Addr)
+	if err != nil {
+		return
+	}
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", mailer.Addr)
+	if err != nil {
+		return
+	}
+	deadline, _ := ctx.Deadline()
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: host})
+		if err != nil {
+			return
+		}
+	}
+	if mailer.Auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			err = client.Auth(mailer.Auth)
+			if err != nil {
+				return
+			}
+		}
+	}
+	err = client.Mail(mailer.From)
+	if err != nil {
+		return
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return
+	}
+	_, err = writer.Write([]byte(message))
+	if err != nil {
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		return
+	}
+	return client.Quit()
+}
+
+// MemoryMailerImplementation keeps every mail instead of sending it, it is meant for tests and local development
+type MemoryMailerImplementation struct {
+	mutex sync.Mutex
+	mails []Mail
+}
+
+func NewMemoryMailer() *MemoryMailerImplementation {
+	return &MemoryMailerImplementation{}
+}
+
+func (mailer *MemoryMailerImplementation) Send(ctx context.Context, mail Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+	mailer.mails = append(mailer.mails, mail)
+	return
+}
+
+func (mailer *MemoryMailerImplementation) FindAll() (mails []Mail) {
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+	return append(mails, mailer.mails...)
+}
+
+// BackgroundMailerImplementation answers at once and sends the mail on a background runner, so a request which sends a mail only
+// for a registered email takes as long as one which sends none
+type BackgroundMailerImplementation struct {
+	Mailer           Mailer
+	BackgroundRunner BackgroundRunner
+}
+
+func NewBackgroundMailer(mailer Mailer, backgroundRunner BackgroundRunner) Mailer {
+	return &BackgroundMailerImplementation{
+		Mailer:           mailer,
+		BackgroundRunner: backgroundRunner,
+	}
+}
+
+func (mailer *BackgroundMailerImplementation) Send(ctx context.Context, mail Mail) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	mailer.BackgroundRunner.Run(ctx, "mail "+mail.Subject, func(ctx context.Context) error {
+		return mailer.Mailer.Send(ctx, mail)
+	})
+	return
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/categories/models"
+	"backend-golang/features/categories/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryController interface {
+	FindTree(c echo.Context) error
+	FindBreadcrumb(c echo.Context) error
+	FindAllProducts(c echo.Context) error
+	Create(c echo.Context) error
+	Move(c echo.Context) error
+	Reorder(c echo.Context) error
+	UpdateProductCategories(c echo.Context) error
+}
+
+type CategoryControllerImplementation struct {
+	CategoryService services.CategoryService
+}
+
+func NewCategoryController(categoryService services.CategoryService) CategoryController {
+	return &CategoryControllerImplementation{
+		CategoryService: categoryService,
+	}
+}
+
+func (controller *CategoryControllerImplementation) FindTree(c echo.Context) error {
+	httpCode, response := controller.CategoryService.FindTree(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) FindBreadcrumb(c echo.Context) error {
+	httpCode, response := controller.CategoryService.FindBreadcrumb(c.Request().Context(), c.Param("slug"))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) FindAllProducts(c echo.Context) error {
+	var findAllProductRequest models.FindAllProductRequest
+	err := c.Bind(&findAllProductRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.CategoryService.FindAllProducts(c.Request().Context(), c.Param("slug"), findAllProductRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) Create(c echo.Context) error {
+	var categoryRequest models.CategoryRequest
+	err := c.Bind(&categoryRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.CategoryService.Create(c.Request().Context(), categoryRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) Move(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var moveCategoryRequest models.MoveCategoryRequest
+	err = c.Bind(&moveCategoryRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.CategoryService.Move(c.Request().Context(), int32(id), moveCategoryRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) Reorder(c echo.Context) error {
+	var reorderCategoryRequest models.ReorderCategoryRequest
+	err := c.Bind(&reorderCategoryRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.CategoryService.Reorder(c.Request().Context(), reorderCategoryRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *CategoryControllerImplementation) UpdateProductCategories(c echo.Context) error {
+	productId, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var productCategoryRequest models.ProductCategoryRequest
+	err = c.Bind(&productCategoryRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.CategoryService.UpdateProductCategories(c.Request().Context(), int32(productId), productCategoryRequest)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+// Category has the materialised path of its ids from the root, like /1/4/9/ for category 9 under 4 under 1,
+// so the path of every descendant starts with it
+type Category struct {
+	Id       pgtype.Int4
+	ParentId pgtype.Int4
+	Name     pgtype.Text
+	Slug     pgtype.Text
+	Path     pgtype.Text
+	Position pgtype.Int4
+}
@@ -0,0 +1,8 @@
+package models
+
+// CategoryRequest leaves ParentId at 0 for a root category
+type CategoryRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Slug     string `json:"slug" validate:"required,max=120,slugvalidator"`
+	ParentId int32  `json:"parentId" validate:"gte=0"`
+}
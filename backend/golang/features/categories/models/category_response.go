@@ -0,0 +1,10 @@
+package models
+
+// CategoryResponse has a null parentId for a root category
+type CategoryResponse struct {
+	Id       int32  `json:"id"`
+	ParentId *int32 `json:"parentId"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Position int32  `json:"position"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type CategoryTreeResponse struct {
+	Id       int32                   `json:"id"`
+	Name     string                  `json:"name"`
+	Slug     string                  `json:"slug"`
+	Children []*CategoryTreeResponse `json:"children"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// FindAllProductRequest comes from the query string
+type FindAllProductRequest struct {
+	Cursor string `query:"cursor" json:"cursor"`
+	Limit  int    `query:"limit" json:"limit" validate:"gte=0,lte=100"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// MoveCategoryRequest leaves ParentId at 0 to make the category a root
+type MoveCategoryRequest struct {
+	ParentId int32 `json:"parentId" validate:"gte=0"`
+}
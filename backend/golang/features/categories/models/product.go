@@ -0,0 +1,14 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type Product struct {
+	Id          pgtype.Int4
+	Name        pgtype.Text
+	Slug        pgtype.Text
+	Description pgtype.Text
+	Price       pgtype.Int8
+	Currency    pgtype.Text
+	CreatedAt   pgtype.Int8
+	UpdatedAt   pgtype.Int8
+}
@@ -0,0 +1,6 @@
+package models
+
+// ProductCategoryRequest replaces every category of the product, an empty list unlinks them all
+type ProductCategoryRequest struct {
+	CategoryIds []int32 `json:"categoryIds" validate:"max=50,unique"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ProductFilter finds the active products linked to the category with Path or to any of its descendants,
+// BeforeId is the id of the last product of the previous page
+type ProductFilter struct {
+	Path     string
+	BeforeId int32
+	Limit    int
+}
@@ -0,0 +1,7 @@
+package models
+
+// ReorderCategoryRequest lists every child of ParentId in the new order, ParentId 0 orders the roots
+type ReorderCategoryRequest struct {
+	ParentId    int32   `json:"parentId" validate:"gte=0"`
+	CategoryIds []int32 `json:"categoryIds" validate:"required,unique"`
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"backend-golang/features/categories/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryRepository interface {
+	LockTree(tx pgx.Tx, ctx context.Context) (err error)
+	FindAll(pool *pgxpool.Pool, ctx context.Context) (categories []models.Category, err error)
+	FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (category models.Category, err error)
+	FindBreadcrumbBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (categories []models.Category, err error)
+	FindById(tx pgx.Tx, ctx context.Context, id int32) (category models.Category, err error)
+	FindIdsByParentId(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4) (ids []int32, err error)
+	Create(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4, name string, slug string, createdAt int64) (category models.Category, err error)
+	UpdatePaths(tx pgx.Tx, ctx context.Context, oldPath string, newPath string) (err error)
+	UpdateParent(tx pgx.Tx, ctx context.Context, id int32, parentId pgtype.Int4, updatedAt int64) (category models.Category, err error)
+	UpdatePositions(tx pgx.Tx, ctx context.Context, ids []int32, updatedAt int64) (err error)
+}
+
+type CategoryRepositoryImplementation struct {
+}
+
+func NewCategoryRepository() CategoryRepository {
+	return &CategoryRepositoryImplementation{}
+}
+
+// LockTree makes creating, moving and reordering categories wait for each other until the transaction ends, reading is not blocked.
+// Every path read after it is the committed one, so a move cannot put a category under its own descendant
+func (repository *CategoryRepositoryImplementation) LockTree(tx pgx.Tx, ctx context.Context) (err error) {
+	_, err = tx.Exec(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE;`)
+	return
+}
+
+// FindAll returns the siblings in their order, so a tree built in this order keeps it
+func (repository *CategoryRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context) (categories []models.Category, err error) {
+	categories = []models.Category{}
+	rows, err := pool.Query(ctx, `SELECT id, parent_id, name, slug, path, position FROM categories ORDER BY position, id;`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			categories = []models.Category{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var category models.Category
+		err = rows.Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+		if err != nil {
+			categories = []models.Category{}
+			return
+		}
+		categories = append(categories, category)
+	}
+	return
+}
+
+func (repository *CategoryRepositoryImplementation) FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (category models.Category, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, parent_id, name, slug, path, position FROM categories WHERE slug = $1;`, slug).Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+	return
+}
+
+// FindBreadcrumbBySlug returns the root first and the category with the slug last, nothing for an unknown slug
+func (repository *CategoryRepositoryImplementation) FindBreadcrumbBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (categories []models.Category, err error) {
+	categories = []models.Category{}
+	rows, err := pool.Query(ctx, `SELECT a.id, a.parent_id, a.name, a.slug, a.path, a.position FROM categories c
+		JOIN categories a ON c.path LIKE a.path || '%' WHERE c.slug = $1 ORDER BY length(a.path);`, slug)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			categories = []models.Category{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var category models.Category
+		err = rows.Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+		if err != nil {
+			categories = []models.Category{}
+			return
+		}
+		categories = append(categories, category)
+	}
+	return
+}
+
+func (repository *CategoryRepositoryImplementation) FindById(tx pgx.Tx, ctx context.Context, id int32) (category models.Category, err error) {
+	err = tx.QueryRow(ctx, `SELECT id, parent_id, name, slug, path, position FROM categories WHERE id = $1;`, id).Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+	return
+}
+
+// FindIdsByParentId finds the roots for a null parentId
+func (repository *CategoryRepositoryImplementation) FindIdsByParentId(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4) (ids []int32, err error) {
+	ids = []int32{}
+	rows, err := tx.Query(ctx, `SELECT id FROM categories WHERE parent_id IS NOT DISTINCT FROM $1 ORDER BY position, id;`, parentId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			ids = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var id int32
+		err = rows.Scan(&id)
+		if err != nil {
+			ids = []int32{}
+			return
+		}
+		ids = append(ids, id)
+	}
+	return
+}
+
+// Create puts the category after its last sibling, an unknown parentId fails with a foreign key violation
+func (repository *CategoryRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4, name string, slug string, createdAt int64) (category models.Category, err error) {
+	err = tx.QueryRow(ctx, `WITH new_category AS (SELECT nextval(pg_get_serial_sequence('categories', 'id'))::int AS id)
+		INSERT INTO categories (id, parent_id, name, slug, path, position, created_at, updated_at)
+		SELECT n.id, $1, $2, $3, COALESCE((SELECT path FROM categories WHERE id = $1), '/') || n.id || '/',
+			COALESCE((SELECT MAX(position) + 1 FROM categories WHERE parent_id IS NOT DISTINCT FROM $1), 0), $4, $4 FROM new_category n
+		RETURNING id, parent_id, name, slug, path, position;`, parentId, name, slug, createdAt).Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+	return
+}
+
+// UpdatePaths replaces the oldPath prefix of the category and of all of its descendants
+func (repository *CategoryRepositoryImplementation) UpdatePaths(tx pgx.Tx, ctx context.Context, oldPath string, newPath string) (err error) {
+	_, err = tx.Exec(ctx, `UPDATE categories SET path = $1 || substr(path, length($2) + 1) WHERE path LIKE $2 || '%';`, newPath, oldPath)
+	return
+}
+
+// UpdateParent puts the category after the last child of its new parent
+func (repository *CategoryRepositoryImplementation) UpdateParent(tx pgx.Tx, ctx context.Context, id int32, parentId pgtype.Int4, updatedAt int64) (category models.Category, err error) {
+	err = tx.QueryRow(ctx, `UPDATE categories SET parent_id = $1, updated_at = $3,
+		position = (SELECT COALESCE(MAX(position) + 1, 0) FROM categories WHERE parent_id IS NOT DISTINCT FROM $1 AND id <> $2) WHERE id = $2
+		RETURNING id, parent_id, name, slug, path, position;`, parentId, id, updatedAt).Scan(&category.Id, &category.ParentId, &category.Name, &category.Slug, &category.Path, &category.Position)
+	return
+}
+
+// UpdatePositions numbers the categories from 0 in the order of ids
+func (repository *CategoryRepositoryImplementation) UpdatePositions(tx pgx.Tx, ctx context.Context, ids []int32, updatedAt int64) (err error) {
+	_, err = tx.Exec(ctx, `UPDATE categories c SET position = o.position - 1, updated_at = $2 FROM unnest($1::int[]) WITH ORDINALITY AS o(id, position) WHERE c.id = o.id;`, ids, updatedAt)
+	return
+}
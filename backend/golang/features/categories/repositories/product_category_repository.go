@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ProductCategoryRepository interface {
+	DeleteAllByProductId(tx pgx.Tx, ctx context.Context, productId int32) (err error)
+	CreateAll(tx pgx.Tx, ctx context.Context, productId int32, categoryIds []int32) (err error)
+}
+
+type ProductCategoryRepositoryImplementation struct {
+}
+
+func NewProductCategoryRepository() ProductCategoryRepository {
+	return &ProductCategoryRepositoryImplementation{}
+}
+
+func (repository *ProductCategoryRepositoryImplementation) DeleteAllByProductId(tx pgx.Tx, ctx context.Context, productId int32) (err error) {
+	_, err = tx.Exec(ctx, `DELETE FROM product_categories WHERE product_id = $1;`, productId)
+	return
+}
+
+// CreateAll fails with a foreign key violation when one of the categories does not exist
+func (repository *ProductCategoryRepositoryImplementation) CreateAll(tx pgx.Tx, ctx context.Context, productId int32, categoryIds []int32) (err error) {
+	_, err = tx.Exec(ctx, `INSERT INTO product_categories (product_id, category_id) SELECT $1, unnest($2::int[]);`, productId, categoryIds)
+	return
+}
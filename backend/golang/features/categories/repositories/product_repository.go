@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"backend-golang/features/categories/models"
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProductRepository interface {
+	CountById(tx pgx.Tx, ctx context.Context, id int32) (count int, err error)
+	FindAllByCategoryPath(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error)
+}
+
+type ProductRepositoryImplementation struct {
+}
+
+func NewProductRepository() ProductRepository {
+	return &ProductRepositoryImplementation{}
+}
+
+func (repository *ProductRepositoryImplementation) CountById(tx pgx.Tx, ctx context.Context, id int32) (count int, err error) {
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM products WHERE id = $1;`, id).Scan(&count)
+	return
+}
+
+// FindAllByCategoryPath returns the newest active products first, a product linked to several of the categories is returned once
+func (repository *ProductRepositoryImplementation) FindAllByCategoryPath(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error) {
+	query := `SELECT p.id, p.name, p.slug, p.description, p.price, p.currency, p.created_at, p.updated_at FROM products p WHERE p.status = 'ACTIVE'
+		AND EXISTS (SELECT 1 FROM product_categories pc JOIN categories c ON c.id = pc.category_id WHERE pc.product_id = p.id AND c.path LIKE $1 || '%')`
+	arguments := []interface{}{productFilter.Path}
+	if productFilter.BeforeId > 0 {
+		arguments = append(arguments, productFilter.BeforeId)
+		query += ` AND p.id < $` + strconv.Itoa(len(arguments))
+	}
+	arguments = append(arguments, productFilter.Limit)
+	query += ` ORDER BY p.id DESC LIMIT $` + strconv.Itoa(len(arguments)) + `;`
+
+	products = []models.Product{}
+	rows, err := pool.Query(ctx, query, arguments...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			products = []models.Product{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var product models.Product
+		err = rows.Scan(&product.Id, &product.Name, &product.Slug, &product.Description, &product.Price, &product.Currency, &product.CreatedAt, &product.UpdatedAt)
+		if err != nil {
+			products = []models.Product{}
+			return
+		}
+		products = append(products, product)
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/categories/controllers"
+	"backend-golang/features/categories/repositories"
+	"backend-golang/features/categories/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func CategoryRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, validate *validator.Validate, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	categoryRepository := repositories.NewCategoryRepository()
+	productRepository := repositories.NewProductRepository()
+	productCategoryRepository := repositories.NewProductCategoryRepository()
+	categoryService := services.NewCategoryService(postgresUtil, validate, categoryRepository, productRepository, productCategoryRepository)
+	categoryController := controllers.NewCategoryController(categoryService)
+	requireCreate := permissionMiddleware.RequirePermissions(middlewares.CreatePermissionPermission)
+	requireUpdate := permissionMiddleware.RequirePermissions(middlewares.UpdatePermissionPermission)
+	e.GET("/api/v1/categories", categoryController.FindTree, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.GET("/api/v1/categories/:slug/breadcrumb", categoryController.FindBreadcrumb, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.GET("/api/v1/categories/:slug/products", categoryController.FindAllProducts, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.POST("/api/v1/admin/categories", categoryController.Create, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.PUT("/api/v1/admin/categories/order", categoryController.Reorder, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.PUT("/api/v1/admin/categories/:id/parent", categoryController.Move, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.PUT("/api/v1/admin/products/:id/categories", categoryController.UpdateProductCategories, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+}
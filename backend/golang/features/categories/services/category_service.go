@@ -0,0 +1,501 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/categories/models"
+	"backend-golang/features/categories/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultProductPageLimit = 20
+
+type CategoryService interface {
+	FindTree(ctx context.Context) (httpCode int, response helpers.Response)
+	FindBreadcrumb(ctx context.Context, slug string) (httpCode int, response helpers.Response)
+	FindAllProducts(ctx context.Context, slug string, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response)
+	Create(ctx context.Context, categoryRequest models.CategoryRequest) (httpCode int, response helpers.Response)
+	Move(ctx context.Context, id int32, moveCategoryRequest models.MoveCategoryRequest) (httpCode int, response helpers.Response)
+	Reorder(ctx context.Context, reorderCategoryRequest models.ReorderCategoryRequest) (httpCode int, response helpers.Response)
+	UpdateProductCategories(ctx context.Context, productId int32, productCategoryRequest models.ProductCategoryRequest) (httpCode int, response helpers.Response)
+}
+
+type CategoryServiceImplementation struct {
+	PostgresUtil              utils.PostgresUtil
+	Validate                  *validator.Validate
+	CategoryRepository        repositories.CategoryRepository
+	ProductRepository         repositories.ProductRepository
+	ProductCategoryRepository repositories.ProductCategoryRepository
+}
+
+func NewCategoryService(postgresUtil utils.PostgresUtil, validate *validator.Validate, categoryRepository repositories.CategoryRepository, productRepository repositories.ProductRepository, productCategoryRepository repositories.ProductCategoryRepository) CategoryService {
+	return &CategoryServiceImplementation{
+		PostgresUtil:              postgresUtil,
+		Validate:                  validate,
+		CategoryRepository:        categoryRepository,
+		ProductRepository:         productRepository,
+		ProductCategoryRepository: productCategoryRepository,
+	}
+}
+
+// FindTree reads every category in one query and answers the roots with their children nested in order
+func (service *CategoryServiceImplementation) FindTree(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	categories, err := service.CategoryRepository.FindAll(service.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	categoryTreeResponses := map[int32]*models.CategoryTreeResponse{}
+	for _, category := range categories {
+		categoryTreeResponses[category.Id.Int32] = &models.CategoryTreeResponse{
+			Id:       category.Id.Int32,
+			Name:     category.Name.String,
+			Slug:     category.Slug.String,
+			Children: []*models.CategoryTreeResponse{},
+		}
+	}
+	roots := []*models.CategoryTreeResponse{}
+	for _, category := range categories {
+		categoryTreeResponse := categoryTreeResponses[category.Id.Int32]
+		parent, ok := categoryTreeResponses[category.ParentId.Int32]
+		if category.ParentId.Valid && ok {
+			parent.Children = append(parent.Children, categoryTreeResponse)
+		} else {
+			roots = append(roots, categoryTreeResponse)
+		}
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   roots,
+		Errors: nil,
+	}
+	return
+}
+
+// FindBreadcrumb answers the root first and the category with the slug last
+func (service *CategoryServiceImplementation) FindBreadcrumb(ctx context.Context, slug string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	categories, err := service.CategoryRepository.FindBreadcrumbBySlug(service.PostgresUtil.GetPool(), ctx, slug)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if len(categories) == 0 {
+		httpCode, response = toResponseCategorySlugNotFound(requestId, slug)
+		return
+	}
+
+	categoryResponses := []models.CategoryResponse{}
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, toCategoryResponse(category))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   categoryResponses,
+		Errors: nil,
+	}
+	return
+}
+
+// FindAllProducts pages through the active products of the category and of all of its descendants, newest first
+func (service *CategoryServiceImplementation) FindAllProducts(ctx context.Context, slug string, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(findAllProductRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, findAllProductRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	productFilter := models.ProductFilter{
+		Limit: findAllProductRequest.Limit,
+	}
+	if productFilter.Limit == 0 {
+		productFilter.Limit = defaultProductPageLimit
+	}
+	if findAllProductRequest.Cursor != "" {
+		productFilter.BeforeId, err = helpers.FromCursor(findAllProductRequest.Cursor)
+		if err != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "cursor", Message: "cursor is invalid"}})
+			return
+		}
+	}
+
+	category, err := service.CategoryRepository.FindBySlug(service.PostgresUtil.GetPool(), ctx, slug)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseCategorySlugNotFound(requestId, slug)
+		return
+	}
+	productFilter.Path = category.Path.String
+
+	pageLimit := productFilter.Limit
+	productFilter.Limit++
+	products, err := service.ProductRepository.FindAllByCategoryPath(service.PostgresUtil.GetPool(), ctx, productFilter)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	findAllProductResponse := models.FindAllProductResponse{
+		Products: []models.ProductResponse{},
+	}
+	if len(products) > pageLimit {
+		products = products[:pageLimit]
+		findAllProductResponse.NextCursor = helpers.ToCursor(products[len(products)-1].Id.Int32)
+	}
+	for _, product := range products {
+		findAllProductResponse.Products = append(findAllProductResponse.Products, toProductResponse(product))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   findAllProductResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// Create puts the new category after the last child of its parent
+func (service *CategoryServiceImplementation) Create(ctx context.Context, categoryRequest models.CategoryRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(categoryRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, categoryRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	err = service.CategoryRepository.LockTree(tx, ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	category, err := service.CategoryRepository.Create(tx, ctx, toParentId(categoryRequest.ParentId), categoryRequest.Name, categoryRequest.Slug, time.Now().UnixMilli())
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "slug", Message: "slug already exists"}})
+			return
+		}
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "parent category not found"}})
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	response = helpers.Response{
+		Data:   toCategoryResponse(category),
+		Errors: nil,
+	}
+	return
+}
+
+// Move puts the category with all of its descendants after the last child of the new parent, moving it to the parent it already has
+// changes nothing
+func (service *CategoryServiceImplementation) Move(ctx context.Context, id int32, moveCategoryRequest models.MoveCategoryRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(moveCategoryRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, moveCategoryRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	err = service.CategoryRepository.LockTree(tx, ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	category, err := service.CategoryRepository.FindById(tx, ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseCategoryNotFound(requestId, id)
+		return
+	}
+
+	parentId := toParentId(moveCategoryRequest.ParentId)
+	if parentId == category.ParentId {
+		httpCode = http.StatusOK
+		response = helpers.Response{
+			Data:   toCategoryResponse(category),
+			Errors: nil,
+		}
+		return
+	}
+
+	newPath := "/" + strconv.Itoa(int(id)) + "/"
+	if parentId.Valid {
+		var parent models.Category
+		parent, err = service.CategoryRepository.FindById(tx, ctx, parentId.Int32)
+		if err != nil && err != pgx.ErrNoRows {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		} else if err != nil && err == pgx.ErrNoRows {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "parent category not found"}})
+			return
+		}
+		if strings.HasPrefix(parent.Path.String, category.Path.String) {
+			err = errors.New("cannot move category " + strconv.Itoa(int(id)) + " under its own subtree")
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "cannot move a category under itself or its descendants"}})
+			return
+		}
+		newPath = parent.Path.String + strconv.Itoa(int(id)) + "/"
+	}
+
+	err = service.CategoryRepository.UpdatePaths(tx, ctx, category.Path.String, newPath)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	category, err = service.CategoryRepository.UpdateParent(tx, ctx, id, parentId, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toCategoryResponse(category),
+		Errors: nil,
+	}
+	return
+}
+
+// Reorder needs every child of the parent exactly once, so a child created or moved in meanwhile is not left out of the order
+func (service *CategoryServiceImplementation) Reorder(ctx context.Context, reorderCategoryRequest models.ReorderCategoryRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(reorderCategoryRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, reorderCategoryRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	err = service.CategoryRepository.LockTree(tx, ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	childIds, err := service.CategoryRepository.FindIdsByParentId(tx, ctx, toParentId(reorderCategoryRequest.ParentId))
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !containsSameIds(childIds, reorderCategoryRequest.CategoryIds) {
+		err = errors.New("category ids are not the children of parent " + strconv.Itoa(int(reorderCategoryRequest.ParentId)))
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "categoryIds", Message: "please input every child category of the parent exactly once"}})
+		return
+	}
+
+	err = service.CategoryRepository.UpdatePositions(tx, ctx, reorderCategoryRequest.CategoryIds, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully reorder categories",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *CategoryServiceImplementation) UpdateProductCategories(ctx context.Context, productId int32, productCategoryRequest models.ProductCategoryRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(productCategoryRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, productCategoryRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	countProduct, err := service.ProductRepository.CountById(tx, ctx, productId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if countProduct == 0 {
+		err = errors.New("cannot find product with id: " + strconv.Itoa(int(productId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "product not found")
+		return
+	}
+
+	err = service.ProductCategoryRepository.DeleteAllByProductId(tx, ctx, productId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	err = service.ProductCategoryRepository.CreateAll(tx, ctx, productId, productCategoryRequest.CategoryIds)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "categoryIds", Message: "category not found"}})
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully update product categories",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// containsSameIds expects ids without duplicates, the request ones are validated unique
+func containsSameIds(ids []int32, otherIds []int32) bool {
+	if len(ids) != len(otherIds) {
+		return false
+	}
+	idSet := map[int32]bool{}
+	for _, id := range ids {
+		idSet[id] = true
+	}
+	for _, otherId := range otherIds {
+		if !idSet[otherId] {
+			return false
+		}
+	}
+	return true
+}
+
+func toParentId(parentId int32) pgtype.Int4 {
+	return pgtype.Int4{Valid: parentId > 0, Int32: parentId}
+}
+
+func toResponseCategoryNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find category with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "category not found")
+}
+
+func toResponseCategorySlugNotFound(requestId string, slug string) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find category with slug: " + slug)
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "category not found")
+}
+
+func toCategoryResponse(category models.Category) models.CategoryResponse {
+	categoryResponse := models.CategoryResponse{
+		Id:       category.Id.Int32,
+		Name:     category.Name.String,
+		Slug:     category.Slug.String,
+		Position: category.Position.Int32,
+	}
+	if category.ParentId.Valid {
+		parentId := category.ParentId.Int32
+		categoryResponse.ParentId = &parentId
+	}
+	return categoryResponse
+}
+
+func toProductResponse(product models.Product) models.ProductResponse {
+	return models.ProductResponse{
+		Id:          product.Id.Int32,
+		Name:        product.Name.String,
+		Slug:        product.Slug.String,
+		Description: product.Description.String,
+		Price:       product.Price.Int64,
+		Currency:    product.Currency.String,
+		CreatedAt:   product.CreatedAt.Int64,
+		UpdatedAt:   product.UpdatedAt.Int64,
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/products/models"
+	"backend-golang/features/products/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProductController interface {
+	FindAllActive(c echo.Context) error
+	FindActiveBySlug(c echo.Context) error
+	FindAll(c echo.Context) error
+	FindById(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Archive(c echo.Context) error
+}
+
+type ProductControllerImplementation struct {
+	ProductService services.ProductService
+}
+
+func NewProductController(productService services.ProductService) ProductController {
+	return &ProductControllerImplementation{
+		ProductService: productService,
+	}
+}
+
+func (controller *ProductControllerImplementation) FindAllActive(c echo.Context) error {
+	var findAllProductRequest models.FindAllProductRequest
+	err := c.Bind(&findAllProductRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.FindAllActive(c.Request().Context(), findAllProductRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) FindActiveBySlug(c echo.Context) error {
+	httpCode, response := controller.ProductService.FindActiveBySlug(c.Request().Context(), c.Param("slug"))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) FindAll(c echo.Context) error {
+	var findAllProductRequest models.FindAllProductRequest
+	err := c.Bind(&findAllProductRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.FindAll(c.Request().Context(), findAllProductRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) FindById(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.FindById(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) Create(c echo.Context) error {
+	var productRequest models.ProductRequest
+	err := c.Bind(&productRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.Create(c.Request().Context(), productRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var productRequest models.ProductRequest
+	err = c.Bind(&productRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.Update(c.Request().Context(), int32(id), productRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProductControllerImplementation) Archive(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProductService.Archive(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,8 @@
+package models
+
+// FindAllProductRequest comes from the query string, an empty status lists every status
+type FindAllProductRequest struct {
+	Status string `query:"status" json:"status" validate:"omitempty,oneof=DRAFT ACTIVE ARCHIVED"`
+	Cursor string `query:"cursor" json:"cursor"`
+	Limit  int    `query:"limit" json:"limit" validate:"gte=0,lte=100"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// FindAllProductResponse has an empty nextCursor on the last page
+type FindAllProductResponse struct {
+	Products   []ProductResponse `json:"products"`
+	NextCursor string            `json:"nextCursor"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	ProductStatusDraft    = "DRAFT"
+	ProductStatusActive   = "ACTIVE"
+	ProductStatusArchived = "ARCHIVED"
+)
+
+type Product struct {
+	Id          pgtype.Int4
+	Name        pgtype.Text
+	Slug        pgtype.Text
+	Description pgtype.Text
+	Price       pgtype.Int8
+	Currency    pgtype.Text
+	Status      pgtype.Text
+	CreatedAt   pgtype.Int8
+	UpdatedAt   pgtype.Int8
+	ArchivedAt  pgtype.Int8
+}
@@ -0,0 +1,9 @@
+package models
+
+// ProductFilter is what the repository searches by, a zero value leaves its condition out,
+// BeforeId is the id of the last product of the previous page
+type ProductFilter struct {
+	Status   string
+	BeforeId int32
+	Limit    int
+}
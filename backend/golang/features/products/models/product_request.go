@@ -0,0 +1,11 @@
+package models
+
+// ProductRequest has the price in the minor unit of the currency, like cents, archiving is done by deleting the product
+type ProductRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Slug        string `json:"slug" validate:"required,max=120,slugvalidator"`
+	Description string `json:"description" validate:"max=5000"`
+	Price       int64  `json:"price" validate:"gte=0"`
+	Currency    string `json:"currency" validate:"required,iso4217"`
+	Status      string `json:"status" validate:"required,oneof=DRAFT ACTIVE"`
+}
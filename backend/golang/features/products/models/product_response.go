@@ -0,0 +1,15 @@
+package models
+
+// ProductResponse has a null archivedAt unless the product is archived
+type ProductResponse struct {
+	Id          int32  `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"`
+	Currency    string `json:"currency"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"createdAt"`
+	UpdatedAt   int64  `json:"updatedAt"`
+	ArchivedAt  *int64 `json:"archivedAt"`
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"backend-golang/features/products/models"
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProductRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error)
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (product models.Product, err error)
+	FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string, status string) (product models.Product, err error)
+	Create(pool *pgxpool.Pool, ctx context.Context, productRequest models.ProductRequest, createdAt int64) (id int32, err error)
+	Update(pool *pgxpool.Pool, ctx context.Context, id int32, productRequest models.ProductRequest, updatedAt int64) (product models.Product, err error)
+	Archive(pool *pgxpool.Pool, ctx context.Context, id int32, archivedAt int64) (count int64, err error)
+}
+
+type ProductRepositoryImplementation struct {
+}
+
+func NewProductRepository() ProductRepository {
+	return &ProductRepositoryImplementation{}
+}
+
+// FindAll returns the newest products first, at most productFilter.Limit of them
+func (repository *ProductRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error) {
+	query := `SELECT id, name, slug, description, price, currency, status, created_at, updated_at, archived_at FROM products WHERE TRUE`
+	var arguments []interface{}
+	if productFilter.Status != "" {
+		arguments = append(arguments, productFilter.Status)
+		query += ` AND status = $` + strconv.Itoa(len(arguments))
+	}
+	if productFilter.BeforeId > 0 {
+		arguments = append(arguments, productFilter.BeforeId)
+		query += ` AND id < $` + strconv.Itoa(len(arguments))
+	}
+	arguments = append(arguments, productFilter.Limit)
+	query += ` ORDER BY id DESC LIMIT $` + strconv.Itoa(len(arguments)) + `;`
+
+	products = []models.Product{}
+	rows, err := pool.Query(ctx, query, arguments...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			products = []models.Product{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var product models.Product
+		err = rows.Scan(&product.Id, &product.Name, &product.Slug, &product.Description, &product.Price, &product.Currency, &product.Status, &product.CreatedAt, &product.UpdatedAt, &product.ArchivedAt)
+		if err != nil {
+			products = []models.Product{}
+			return
+		}
+		products = append(products, product)
+	}
+	return
+}
+
+func (repository *ProductRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (product models.Product, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, name, slug, description, price, currency, status, created_at, updated_at, archived_at FROM products WHERE id = $1;`, id).Scan(&product.Id, &product.Name, &product.Slug, &product.Description, &product.Price, &product.Currency, &product.Status, &product.CreatedAt, &product.UpdatedAt, &product.ArchivedAt)
+	return
+}
+
+func (repository *ProductRepositoryImplementation) FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string, status string) (product models.Product, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, name, slug, description, price, currency, status, created_at, updated_at, archived_at FROM products WHERE slug = $1 AND status = $2;`, slug, status).Scan(&product.Id, &product.Name, &product.Slug, &product.Description, &product.Price, &product.Currency, &product.Status, &product.CreatedAt, &product.UpdatedAt, &product.ArchivedAt)
+	return
+}
+
+func (repository *ProductRepositoryImplementation) Create(pool *pgxpool.Pool, ctx context.Context, productRequest models.ProductRequest, createdAt int64) (id int32, err error) {
+	err = pool.QueryRow(ctx, `INSERT INTO products (name, slug, description, price, currency, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $7) RETURNING id;`,
+		productRequest.Name, productRequest.Slug, productRequest.Description, productRequest.Price, productRequest.Currency, productRequest.Status, createdAt).Scan(&id)
+	return
+}
+
+// Update also restores an archived product, it returns pgx.ErrNoRows for an unknown id
+func (repository *ProductRepositoryImplementation) Update(pool *pgxpool.Pool, ctx context.Context, id int32, productRequest models.ProductRequest, updatedAt int64) (product models.Product, err error) {
+	err = pool.QueryRow(ctx, `UPDATE products SET name = $1, slug = $2, description = $3, price = $4, currency = $5, status = $6, updated_at = $7, archived_at = NULL WHERE id = $8
+		RETURNING id, name, slug, description, price, currency, status, created_at, updated_at, archived_at;`,
+		productRequest.Name, productRequest.Slug, productRequest.Description, productRequest.Price, productRequest.Currency, productRequest.Status, updatedAt, id).Scan(&product.Id, &product.Name, &product.Slug, &product.Description, &product.Price, &product.Currency, &product.Status, &product.CreatedAt, &product.UpdatedAt, &product.ArchivedAt)
+	return
+}
+
+// Archive leaves a product that is already archived untouched, count is 0 only for an unknown id
+func (repository *ProductRepositoryImplementation) Archive(pool *pgxpool.Pool, ctx context.Context, id int32, archivedAt int64) (count int64, err error) {
+	err = pool.QueryRow(ctx, `WITH archived AS (UPDATE products SET status = 'ARCHIVED', archived_at = $1, updated_at = $1 WHERE id = $2 AND archived_at IS NULL)
+		SELECT count(*) FROM products WHERE id = $2;`, archivedAt, id).Scan(&count)
+	return
+}
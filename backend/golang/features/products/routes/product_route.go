@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/products/controllers"
+	"backend-golang/features/products/repositories"
+	"backend-golang/features/products/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func ProductRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, validate *validator.Validate, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	productRepository := repositories.NewProductRepository()
+	productService := services.NewProductService(postgresUtil, validate, productRepository)
+	productController := controllers.NewProductController(productService)
+	requireCreate := permissionMiddleware.RequirePermissions(middlewares.CreatePermissionPermission)
+	requireRead := permissionMiddleware.RequirePermissions(middlewares.ReadPermissionPermission)
+	requireUpdate := permissionMiddleware.RequirePermissions(middlewares.UpdatePermissionPermission)
+	requireDelete := permissionMiddleware.RequirePermissions(middlewares.DeletePermissionPermission)
+	e.GET("/api/v1/products", productController.FindAllActive, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.GET("/api/v1/products/:slug", productController.FindActiveBySlug, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.GET("/api/v1/admin/products", productController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.GET("/api/v1/admin/products/:id", productController.FindById, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.POST("/api/v1/admin/products", productController.Create, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.PUT("/api/v1/admin/products/:id", productController.Update, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.DELETE("/api/v1/admin/products/:id", productController.Archive, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireDelete)
+}
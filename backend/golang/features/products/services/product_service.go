@@ -0,0 +1,271 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/products/models"
+	"backend-golang/features/products/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const defaultProductPageLimit = 20
+
+type ProductService interface {
+	FindAllActive(ctx context.Context, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response)
+	FindActiveBySlug(ctx context.Context, slug string) (httpCode int, response helpers.Response)
+	FindAll(ctx context.Context, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response)
+	FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	Create(ctx context.Context, productRequest models.ProductRequest) (httpCode int, response helpers.Response)
+	Update(ctx context.Context, id int32, productRequest models.ProductRequest) (httpCode int, response helpers.Response)
+	Archive(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+}
+
+type ProductServiceImplementation struct {
+	PostgresUtil      utils.PostgresUtil
+	Validate          *validator.Validate
+	ProductRepository repositories.ProductRepository
+}
+
+func NewProductService(postgresUtil utils.PostgresUtil, validate *validator.Validate, productRepository repositories.ProductRepository) ProductService {
+	return &ProductServiceImplementation{
+		PostgresUtil:      postgresUtil,
+		Validate:          validate,
+		ProductRepository: productRepository,
+	}
+}
+
+// FindAllActive is the public catalog, the status of the request is ignored so drafts and archived products stay hidden
+func (service *ProductServiceImplementation) FindAllActive(ctx context.Context, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response) {
+	findAllProductRequest.Status = models.ProductStatusActive
+	return service.FindAll(ctx, findAllProductRequest)
+}
+
+// FindActiveBySlug answers the same for an unknown slug, a draft and an archived product
+func (service *ProductServiceImplementation) FindActiveBySlug(ctx context.Context, slug string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	product, err := service.ProductRepository.FindBySlug(service.PostgresUtil.GetPool(), ctx, slug, models.ProductStatusActive)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		err = errors.New("cannot find active product with slug: " + slug)
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "product not found")
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toProductResponse(product),
+		Errors: nil,
+	}
+	return
+}
+
+// FindAll pages from the newest product to the oldest, the cursor of the next page is made from the id of the last product of this one
+func (service *ProductServiceImplementation) FindAll(ctx context.Context, findAllProductRequest models.FindAllProductRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(findAllProductRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, findAllProductRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	productFilter := models.ProductFilter{
+		Status: findAllProductRequest.Status,
+		Limit:  findAllProductRequest.Limit,
+	}
+	if productFilter.Limit == 0 {
+		productFilter.Limit = defaultProductPageLimit
+	}
+	if findAllProductRequest.Cursor != "" {
+		productFilter.BeforeId, err = helpers.FromCursor(findAllProductRequest.Cursor)
+		if err != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "cursor", Message: "cursor is invalid"}})
+			return
+		}
+	}
+
+	pageLimit := productFilter.Limit
+	productFilter.Limit++
+	products, err := service.ProductRepository.FindAll(service.PostgresUtil.GetPool(), ctx, productFilter)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	findAllProductResponse := models.FindAllProductResponse{
+		Products: []models.ProductResponse{},
+	}
+	if len(products) > pageLimit {
+		products = products[:pageLimit]
+		findAllProductResponse.NextCursor = helpers.ToCursor(products[len(products)-1].Id.Int32)
+	}
+	for _, product := range products {
+		findAllProductResponse.Products = append(findAllProductResponse.Products, toProductResponse(product))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   findAllProductResponse,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *ProductServiceImplementation) FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	product, err := service.ProductRepository.FindById(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseProductNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toProductResponse(product),
+		Errors: nil,
+	}
+	return
+}
+
+func (service *ProductServiceImplementation) Create(ctx context.Context, productRequest models.ProductRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(productRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, productRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	createdAt := time.Now().UnixMilli()
+	id, err := service.ProductRepository.Create(service.PostgresUtil.GetPool(), ctx, productRequest, createdAt)
+	if err != nil {
+		httpCode, response = toResponseSaveProductError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	response = helpers.Response{
+		Data: models.ProductResponse{
+			Id:          id,
+			Name:        productRequest.Name,
+			Slug:        productRequest.Slug,
+			Description: productRequest.Description,
+			Price:       productRequest.Price,
+			Currency:    productRequest.Currency,
+			Status:      productRequest.Status,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+		Errors: nil,
+	}
+	return
+}
+
+// Update replaces every field of the product, updating an archived product restores it with the status of the request
+func (service *ProductServiceImplementation) Update(ctx context.Context, id int32, productRequest models.ProductRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(productRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, productRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	product, err := service.ProductRepository.Update(service.PostgresUtil.GetPool(), ctx, id, productRequest, time.Now().UnixMilli())
+	if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseProductNotFound(requestId, id)
+		return
+	} else if err != nil {
+		httpCode, response = toResponseSaveProductError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toProductResponse(product),
+		Errors: nil,
+	}
+	return
+}
+
+// Archive hides the product from the catalog instead of deleting it so everything referencing it keeps working
+func (service *ProductServiceImplementation) Archive(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	count, err := service.ProductRepository.Archive(service.PostgresUtil.GetPool(), ctx, id, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if count != 1 {
+		httpCode, response = toResponseProductNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully archive product",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func toResponseSaveProductError(err error, requestId string) (httpCode int, response helpers.Response) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == "23505" {
+		return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "slug", Message: "slug already exists"}})
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+func toResponseProductNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find product with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "product not found")
+}
+
+func toProductResponse(product models.Product) models.ProductResponse {
+	productResponse := models.ProductResponse{
+		Id:          product.Id.Int32,
+		Name:        product.Name.String,
+		Slug:        product.Slug.String,
+		Description: product.Description.String,
+		Price:       product.Price.Int64,
+		Currency:    product.Currency.String,
+		Status:      product.Status.String,
+		CreatedAt:   product.CreatedAt.Int64,
+		UpdatedAt:   product.UpdatedAt.Int64,
+	}
+	if product.ArchivedAt.Valid {
+		archivedAt := product.ArchivedAt.Int64
+		productResponse.ArchivedAt = &archivedAt
+	}
+	return productResponse
+}
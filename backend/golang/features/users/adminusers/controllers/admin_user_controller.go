@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/adminusers/models"
+	"backend-golang/features/users/adminusers/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AdminUserController interface {
+	FindAll(c echo.Context) error
+	FindById(c echo.Context) error
+	Disable(c echo.Context) error
+	Enable(c echo.Context) error
+}
+
+type AdminUserControllerImplementation struct {
+	AdminUserService services.AdminUserService
+}
+
+func NewAdminUserController(adminUserService services.AdminUserService) AdminUserController {
+	return &AdminUserControllerImplementation{
+		AdminUserService: adminUserService,
+	}
+}
+
+func (controller *AdminUserControllerImplementation) FindAll(c echo.Context) error {
+	var findAllUserRequest models.FindAllUserRequest
+	err := c.Bind(&findAllUserRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.AdminUserService.FindAll(c.Request().Context(), findAllUserRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *AdminUserControllerImplementation) FindById(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.AdminUserService.FindById(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *AdminUserControllerImplementation) Disable(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.AdminUserService.Disable(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *AdminUserControllerImplementation) Enable(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.AdminUserService.Enable(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,12 @@
+package models
+
+// FindAllUserRequest comes from the query string, email and username match a part of the value,
+// createdFrom is inclusive and createdTo exclusive, both in unix millis
+type FindAllUserRequest struct {
+	Email       string `query:"email" json:"email" validate:"max=100"`
+	Username    string `query:"username" json:"username" validate:"max=50"`
+	CreatedFrom int64  `query:"createdFrom" json:"createdFrom" validate:"gte=0"`
+	CreatedTo   int64  `query:"createdTo" json:"createdTo" validate:"gte=0"`
+	Cursor      string `query:"cursor" json:"cursor"`
+	Limit       int    `query:"limit" json:"limit" validate:"gte=0,lte=100"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// FindAllUserResponse has an empty nextCursor on the last page
+type FindAllUserResponse struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"nextCursor"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type User struct {
+	Id              pgtype.Int4
+	Username        pgtype.Text
+	Email           pgtype.Text
+	CreatedAt       pgtype.Int8
+	EmailVerifiedAt pgtype.Int8
+	TotpEnabledAt   pgtype.Int8
+	DisabledAt      pgtype.Int8
+}
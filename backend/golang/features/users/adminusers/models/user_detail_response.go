@@ -0,0 +1,11 @@
+package models
+
+type UserDetailResponse struct {
+	UserResponse
+	Permissions []UserPermissionResponse `json:"permissions"`
+}
+
+type UserPermissionResponse struct {
+	Id         int32  `json:"id"`
+	Permission string `json:"permission"`
+}
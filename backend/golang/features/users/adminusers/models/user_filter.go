@@ -0,0 +1,12 @@
+package models
+
+// UserFilter is what the repository searches by, a zero value leaves its condition out,
+// BeforeId is the id of the last user of the previous page
+type UserFilter struct {
+	Email       string
+	Username    string
+	CreatedFrom int64
+	CreatedTo   int64
+	BeforeId    int32
+	Limit       int
+}
@@ -0,0 +1,12 @@
+package models
+
+type UserResponse struct {
+	Id               int32  `json:"id"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	CreatedAt        int64  `json:"createdAt"`
+	EmailVerified    bool   `json:"emailVerified"`
+	TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	Disabled         bool   `json:"disabled"`
+	DisabledAt       int64  `json:"disabledAt"`
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"backend-golang/features/users/adminusers/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserPermissionRepository interface {
+	FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error)
+}
+
+type UserPermissionRepositoryImplementation struct {
+}
+
+func NewUserPermissionRepository() UserPermissionRepository {
+	return &UserPermissionRepositoryImplementation{}
+}
+
+func (repository *UserPermissionRepositoryImplementation) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error) {
+	rows, err := pool.Query(ctx, `SELECT permissions.id, permissions.permission FROM user_permissions JOIN permissions ON permissions.id = user_permissions.permission_id WHERE user_permissions.user_id = $1 ORDER BY permissions.id;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			userPermissions = []models.UserPermission{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var userPermission models.UserPermission
+		err = rows.Scan(&userPermission.PermissionId, &userPermission.Permission)
+		if err != nil {
+			userPermissions = []models.UserPermission{}
+			return
+		}
+		userPermissions = append(userPermissions, userPermission)
+	}
+	return
+}
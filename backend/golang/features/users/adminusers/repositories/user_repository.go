@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/adminusers/models"
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context, userFilter models.UserFilter) (users []models.User, err error)
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error)
+	Disable(pool *pgxpool.Pool, ctx context.Context, id int32, disabledAt int64) (rowsAffected int64, err error)
+	Enable(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+// FindAll returns the newest users first, at most userFilter.Limit of them
+func (repository *UserRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context, userFilter models.UserFilter) (users []models.User, err error) {
+	query := `SELECT id, username, email, created_at, email_verified_at, totp_enabled_at, disabled_at FROM users WHERE TRUE`
+	var arguments []interface{}
+	if userFilter.Email != "" {
+		arguments = append(arguments, helpers.ToLikeContains(userFilter.Email))
+		query += ` AND email ILIKE $` + strconv.Itoa(len(arguments))
+	}
+	if userFilter.Username != "" {
+		arguments = append(arguments, helpers.ToLikeContains(userFilter.Username))
+		query += ` AND username ILIKE $` + strconv.Itoa(len(arguments))
+	}
+	if userFilter.CreatedFrom > 0 {
+		arguments = append(arguments, userFilter.CreatedFrom)
+		query += ` AND created_at >= $` + strconv.Itoa(len(arguments))
+	}
+	if userFilter.CreatedTo > 0 {
+		arguments = append(arguments, userFilter.CreatedTo)
+		query += ` AND created_at < $` + strconv.Itoa(len(arguments))
+	}
+	if userFilter.BeforeId > 0 {
+		arguments = append(arguments, userFilter.BeforeId)
+		query += ` AND id < $` + strconv.Itoa(len(arguments))
+	}
+	arguments = append(arguments, userFilter.Limit)
+	query += ` ORDER BY id DESC LIMIT $` + strconv.Itoa(len(arguments)) + `;`
+
+	rows, err := pool.Query(ctx, query, arguments...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			users = []models.User{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var user models.User
+		err = rows.Scan(&user.Id, &user.Username, &user.Email, &user.CreatedAt, &user.EmailVerifiedAt, &user.TotpEnabledAt, &user.DisabledAt)
+		if err != nil {
+			users = []models.User{}
+			return
+		}
+		users = append(users, user)
+	}
+	return
+}
+
+func (repository *UserRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email, created_at, email_verified_at, totp_enabled_at, disabled_at FROM users WHERE id = $1;`, id).Scan(&user.Id, &user.Username, &user.Email, &user.CreatedAt, &user.EmailVerifiedAt, &user.TotpEnabledAt, &user.DisabledAt)
+	return
+}
+
+// Disable keeps the first disabled_at of an account that is already disabled, rowsAffected is 0 only for an unknown id
+func (repository *UserRepositoryImplementation) Disable(pool *pgxpool.Pool, ctx context.Context, id int32, disabledAt int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET disabled_at = COALESCE(disabled_at, $1) WHERE id = $2;`, disabledAt, id)
+	rowsAffected = result.RowsAffected()
+	return
+}
+
+func (repository *UserRepositoryImplementation) Enable(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET disabled_at = NULL WHERE id = $1;`, id)
+	rowsAffected = result.RowsAffected()
+	return
+}
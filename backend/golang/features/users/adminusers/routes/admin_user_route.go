@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/adminusers/controllers"
+	"backend-golang/features/users/adminusers/repositories"
+	"backend-golang/features/users/adminusers/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func AdminUserRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, sessionRegistryHelper helpers.SessionRegistryHelper, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	userRepository := repositories.NewUserRepository()
+	userPermissionRepository := repositories.NewUserPermissionRepository()
+	adminUserService := services.NewAdminUserService(postgresUtil, redisUtil, validate, userRepository, userPermissionRepository, sessionRegistryHelper)
+	adminUserController := controllers.NewAdminUserController(adminUserService)
+	requireAdministrator := permissionMiddleware.RequirePermissions(middlewares.AdministratorPermission)
+	e.GET("/api/v1/admin/users", adminUserController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+	e.GET("/api/v1/admin/users/:id", adminUserController.FindById, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+	e.POST("/api/v1/admin/users/:id/disable", adminUserController.Disable, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+	e.POST("/api/v1/admin/users/:id/enable", adminUserController.Enable, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+}
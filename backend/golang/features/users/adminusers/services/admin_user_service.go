@@ -0,0 +1,220 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/adminusers/models"
+	"backend-golang/features/users/adminusers/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultUserPageLimit = 20
+
+type AdminUserService interface {
+	FindAll(ctx context.Context, findAllUserRequest models.FindAllUserRequest) (httpCode int, response helpers.Response)
+	FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	Disable(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	Enable(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+}
+
+type AdminUserServiceImplementation struct {
+	PostgresUtil             utils.PostgresUtil
+	RedisUtil                utils.RedisUtil
+	Validate                 *validator.Validate
+	UserRepository           repositories.UserRepository
+	UserPermissionRepository repositories.UserPermissionRepository
+	SessionRegistryHelper    helpers.SessionRegistryHelper
+}
+
+func NewAdminUserService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, userPermissionRepository repositories.UserPermissionRepository, sessionRegistryHelper helpers.SessionRegistryHelper) AdminUserService {
+	return &AdminUserServiceImplementation{
+		PostgresUtil:             postgresUtil,
+		RedisUtil:                redisUtil,
+		Validate:                 validate,
+		UserRepository:           userRepository,
+		UserPermissionRepository: userPermissionRepository,
+		SessionRegistryHelper:    sessionRegistryHelper,
+	}
+}
+
+// FindAll pages from the newest user to the oldest, the cursor of the next page is made from the id of the last user of this one
+func (service *AdminUserServiceImplementation) FindAll(ctx context.Context, findAllUserRequest models.FindAllUserRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(findAllUserRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, findAllUserRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	userFilter := models.UserFilter{
+		Email:       findAllUserRequest.Email,
+		Username:    findAllUserRequest.Username,
+		CreatedFrom: findAllUserRequest.CreatedFrom,
+		CreatedTo:   findAllUserRequest.CreatedTo,
+		Limit:       findAllUserRequest.Limit,
+	}
+	if userFilter.Limit == 0 {
+		userFilter.Limit = defaultUserPageLimit
+	}
+	if findAllUserRequest.Cursor != "" {
+		userFilter.BeforeId, err = helpers.FromCursor(findAllUserRequest.Cursor)
+		if err != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "cursor", Message: "cursor is invalid"}})
+			return
+		}
+	}
+
+	pageLimit := userFilter.Limit
+	userFilter.Limit++
+	users, err := service.UserRepository.FindAll(service.PostgresUtil.GetPool(), ctx, userFilter)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	findAllUserResponse := models.FindAllUserResponse{
+		Users: []models.UserResponse{},
+	}
+	if len(users) > pageLimit {
+		users = users[:pageLimit]
+		findAllUserResponse.NextCursor = helpers.ToCursor(users[len(users)-1].Id.Int32)
+	}
+	for _, user := range users {
+		findAllUserResponse.Users = append(findAllUserResponse.Users, toUserResponse(user))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   findAllUserResponse,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *AdminUserServiceImplementation) FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseUserNotFound(requestId, id)
+		return
+	}
+
+	userPermissions, err := service.UserPermissionRepository.FindByUserId(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	userDetailResponse := models.UserDetailResponse{
+		UserResponse: toUserResponse(user),
+		Permissions:  []models.UserPermissionResponse{},
+	}
+	for _, userPermission := range userPermissions {
+		userDetailResponse.Permissions = append(userDetailResponse.Permissions, models.UserPermissionResponse{
+			Id:         userPermission.PermissionId.Int32,
+			Permission: userPermission.Permission.String,
+		})
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   userDetailResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// Disable keeps the account from logging in and revokes its sessions and token families, disabling again
+// revokes whatever was created in between so a failed revoke can simply be retried
+func (service *AdminUserServiceImplementation) Disable(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	if id == ctx.Value(middlewares.IdKey).(int32) {
+		err := errors.New("user " + strconv.Itoa(int(id)) + " tried to disable their own account")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "cannot disable your own account")
+		return
+	}
+
+	rowsAffected, err := service.UserRepository.Disable(service.PostgresUtil.GetPool(), ctx, id, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponseUserNotFound(requestId, id)
+		return
+	}
+
+	err = service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, id, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully disable user",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *AdminUserServiceImplementation) Enable(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	rowsAffected, err := service.UserRepository.Enable(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponseUserNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully enable user",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func toUserResponse(user models.User) models.UserResponse {
+	return models.UserResponse{
+		Id:               user.Id.Int32,
+		Username:         user.Username.String,
+		Email:            user.Email.String,
+		CreatedAt:        user.CreatedAt.Int64,
+		EmailVerified:    user.EmailVerifiedAt.Valid,
+		TwoFactorEnabled: user.TotpEnabledAt.Valid,
+		Disabled:         user.DisabledAt.Valid,
+		DisabledAt:       user.DisabledAt.Int64,
+	}
+}
+
+func toResponseUserNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find user with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "user not found")
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/apikeys/models"
+	"backend-golang/features/users/apikeys/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ApiKeyController interface {
+	FindAll(c echo.Context) error
+	Create(c echo.Context) error
+	Revoke(c echo.Context) error
+}
+
+type ApiKeyControllerImplementation struct {
+	ApiKeyService services.ApiKeyService
+}
+
+func NewApiKeyController(apiKeyService services.ApiKeyService) ApiKeyController {
+	return &ApiKeyControllerImplementation{
+		ApiKeyService: apiKeyService,
+	}
+}
+
+func (controller *ApiKeyControllerImplementation) FindAll(c echo.Context) error {
+	httpCode, response := controller.ApiKeyService.FindAll(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ApiKeyControllerImplementation) Create(c echo.Context) error {
+	var apiKeyRequest models.ApiKeyRequest
+	err := c.Bind(&apiKeyRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ApiKeyService.Create(c.Request().Context(), apiKeyRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ApiKeyControllerImplementation) Revoke(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ApiKeyService.Revoke(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
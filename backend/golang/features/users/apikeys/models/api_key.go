@@ -0,0 +1,17 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type ApiKey struct {
+	Id            pgtype.Int4
+	UserId        pgtype.Int4
+	Name          pgtype.Text
+	KeyPrefix     pgtype.Text
+	KeyHash       pgtype.Text
+	PermissionIds []int32
+	CreatedBy     pgtype.Int4
+	CreatedAt     pgtype.Int8
+	ExpiresAt     pgtype.Int8
+	LastUsedAt    pgtype.Int8
+	RevokedAt     pgtype.Int8
+}
@@ -0,0 +1,9 @@
+package models
+
+// ApiKeyRequest leaves ExpiresAt at 0 for a key which never expires, otherwise it is in unix millis, UserId is the user the key acts as
+type ApiKeyRequest struct {
+	Name          string  `json:"name" validate:"required,max=100"`
+	UserId        int32   `json:"userId" validate:"required,gt=0"`
+	PermissionIds []int32 `json:"permissionIds" validate:"required,min=1,max=100,unique"`
+	ExpiresAt     int64   `json:"expiresAt" validate:"gte=0"`
+}
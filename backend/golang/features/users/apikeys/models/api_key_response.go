@@ -0,0 +1,14 @@
+package models
+
+type ApiKeyResponse struct {
+	Id            int32   `json:"id"`
+	UserId        int32   `json:"userId"`
+	Name          string  `json:"name"`
+	KeyPrefix     string  `json:"keyPrefix"`
+	PermissionIds []int32 `json:"permissionIds"`
+	CreatedBy     int32   `json:"createdBy"`
+	CreatedAt     int64   `json:"createdAt"`
+	ExpiresAt     *int64  `json:"expiresAt"`
+	LastUsedAt    *int64  `json:"lastUsedAt"`
+	RevokedAt     *int64  `json:"revokedAt"`
+}
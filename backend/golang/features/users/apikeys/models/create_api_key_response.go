@@ -0,0 +1,7 @@
+package models
+
+// CreateApiKeyResponse is the only response with the key itself, only its hash is stored
+type CreateApiKeyResponse struct {
+	ApiKey ApiKeyResponse `json:"apiKey"`
+	Key    string         `json:"key"`
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend-golang/features/users/apikeys/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ApiKeyRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context) (apiKeys []models.ApiKey, err error)
+	Create(tx pgx.Tx, ctx context.Context, apiKey models.ApiKey) (id int32, err error)
+	CreatePermissions(tx pgx.Tx, ctx context.Context, apiKeyId int32, permissionIds []int32) (err error)
+	Revoke(pool *pgxpool.Pool, ctx context.Context, id int32, revokedAt int64) (rowsAffected int64, err error)
+}
+
+type ApiKeyRepositoryImplementation struct {
+}
+
+func NewApiKeyRepository() ApiKeyRepository {
+	return &ApiKeyRepositoryImplementation{}
+}
+
+func (repository *ApiKeyRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context) (apiKeys []models.ApiKey, err error) {
+	query := `SELECT k.id, k.user_id, k.name, k.key_prefix, ARRAY(
+		SELECT akp.permission_id FROM api_key_permissions akp WHERE akp.api_key_id = k.id ORDER BY akp.permission_id
+	), k.created_by, k.created_at, k.expires_at, k.last_used_at, k.revoked_at FROM api_keys k ORDER BY k.id DESC;`
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			apiKeys = []models.ApiKey{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var apiKey models.ApiKey
+		err = rows.Scan(&apiKey.Id, &apiKey.UserId, &apiKey.Name, &apiKey.KeyPrefix, &apiKey.PermissionIds, &apiKey.CreatedBy, &apiKey.CreatedAt, &apiKey.ExpiresAt, &apiKey.LastUsedAt, &apiKey.RevokedAt)
+		if err != nil {
+			apiKeys = []models.ApiKey{}
+			return
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+	return
+}
+
+func (repository *ApiKeyRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, apiKey models.ApiKey) (id int32, err error) {
+	query := `INSERT INTO api_keys (user_id, name, key_prefix, key_hash, created_by, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+	err = tx.QueryRow(ctx, query, apiKey.UserId, apiKey.Name, apiKey.KeyPrefix, apiKey.KeyHash, apiKey.CreatedBy, apiKey.CreatedAt, apiKey.ExpiresAt).Scan(&id)
+	return
+}
+
+func (repository *ApiKeyRepositoryImplementation) CreatePermissions(tx pgx.Tx, ctx context.Context, apiKeyId int32, permissionIds []int32) (err error) {
+	query := `INSERT INTO api_key_permissions (api_key_id, permission_id) SELECT $1, unnest($2::int[]);`
+	_, err = tx.Exec(ctx, query, apiKeyId, permissionIds)
+	return
+}
+
+// Revoke keeps the row so the key still shows up in the list, a key which is already revoked is not found
+func (repository *ApiKeyRepositoryImplementation) Revoke(pool *pgxpool.Pool, ctx context.Context, id int32, revokedAt int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL;`, id, revokedAt)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"backend-golang/features/users/apikeys/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository interface {
+	FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error)
+}
+
+type PermissionRepositoryImplementation struct {
+}
+
+func NewPermissionRepository() PermissionRepository {
+	return &PermissionRepositoryImplementation{}
+}
+
+func (repository *PermissionRepositoryImplementation) FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, permission FROM permissions WHERE permission = $1;`, permission).Scan(&foundPermission.Id, &foundPermission.Permission)
+	return
+}
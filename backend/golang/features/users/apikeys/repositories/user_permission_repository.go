@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserPermissionRepository interface {
+	FindPermissionIdsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (idPermissions []int32, err error)
+}
+
+type UserPermissionRepositoryImplementation struct {
+}
+
+func NewUserPermissionRepository() UserPermissionRepository {
+	return &UserPermissionRepositoryImplementation{}
+}
+
+// FindPermissionIdsByUserId returns the direct grants of the user together with the permissions of their roles and of
+// every parent of those roles, UNION drops the duplicates and stops the recursion should the parents ever form a cycle
+func (repository *UserPermissionRepositoryImplementation) FindPermissionIdsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (idPermissions []int32, err error) {
+	query := `WITH RECURSIVE user_role_tree AS (
+		SELECT r.id, r.parent_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE ur.user_id = $1
+		UNION
+		SELECT r.id, r.parent_id FROM roles r JOIN user_role_tree urt ON r.id = urt.parent_id
+	)
+	SELECT permission_id FROM user_permissions WHERE user_id = $1
+	UNION
+	SELECT rp.permission_id FROM role_permissions rp JOIN user_role_tree urt ON urt.id = rp.role_id
+	ORDER BY permission_id;`
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			idPermissions = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var idPermission int32
+		err = rows.Scan(&idPermission)
+		if err != nil {
+			idPermissions = []int32{}
+			return
+		}
+		idPermissions = append(idPermissions, idPermission)
+	}
+	return
+}
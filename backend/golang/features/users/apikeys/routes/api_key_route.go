@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/apikeys/controllers"
+	"backend-golang/features/users/apikeys/repositories"
+	"backend-golang/features/users/apikeys/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func ApiKeyRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, validate *validator.Validate, tokenHelper helpers.TokenHelper, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	apiKeyRepository := repositories.NewApiKeyRepository()
+	userPermissionRepository := repositories.NewUserPermissionRepository()
+	permissionRepository := repositories.NewPermissionRepository()
+	apiKeyService := services.NewApiKeyService(postgresUtil, validate, apiKeyRepository, userPermissionRepository, permissionRepository, tokenHelper)
+	apiKeyController := controllers.NewApiKeyController(apiKeyService)
+	requireAdministrator := permissionMiddleware.RequirePermissions(middlewares.AdministratorPermission)
+	e.GET("/api/v1/admin/api-keys", apiKeyController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+	e.POST("/api/v1/admin/api-keys", apiKeyController.Create, middlewares.PrintRequestResponseLog, middlewares.NoStore, sessionMiddleware.Authenticate, requireAdministrator)
+	e.DELETE("/api/v1/admin/api-keys/:id", apiKeyController.Revoke, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+}
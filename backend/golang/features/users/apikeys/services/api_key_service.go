@@ -0,0 +1,239 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/apikeys/models"
+	"backend-golang/features/users/apikeys/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// apiKeyPrefix marks the keys so a leaked one is easy to recognize, the prefix and the first 8 characters of the secret
+// are kept as key_prefix to tell the keys apart in the list
+const apiKeyPrefix = "ek_"
+
+const apiKeyPrefixLength = len(apiKeyPrefix) + 8
+
+type ApiKeyService interface {
+	FindAll(ctx context.Context) (httpCode int, response helpers.Response)
+	Create(ctx context.Context, apiKeyRequest models.ApiKeyRequest) (httpCode int, response helpers.Response)
+	Revoke(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+}
+
+type ApiKeyServiceImplementation struct {
+	PostgresUtil             utils.PostgresUtil
+	Validate                 *validator.Validate
+	ApiKeyRepository         repositories.ApiKeyRepository
+	UserPermissionRepository repositories.UserPermissionRepository
+	PermissionRepository     repositories.PermissionRepository
+	TokenHelper              helpers.TokenHelper
+}
+
+func NewApiKeyService(postgresUtil utils.PostgresUtil, validate *validator.Validate, apiKeyRepository repositories.ApiKeyRepository, userPermissionRepository repositories.UserPermissionRepository, permissionRepository repositories.PermissionRepository, tokenHelper helpers.TokenHelper) ApiKeyService {
+	return &ApiKeyServiceImplementation{
+		PostgresUtil:             postgresUtil,
+		Validate:                 validate,
+		ApiKeyRepository:         apiKeyRepository,
+		UserPermissionRepository: userPermissionRepository,
+		PermissionRepository:     permissionRepository,
+		TokenHelper:              tokenHelper,
+	}
+}
+
+func (service *ApiKeyServiceImplementation) FindAll(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	apiKeys, err := service.ApiKeyRepository.FindAll(service.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	apiKeyResponses := []models.ApiKeyResponse{}
+	for _, apiKey := range apiKeys {
+		apiKeyResponses = append(apiKeyResponses, toApiKeyResponse(apiKey))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   apiKeyResponses,
+		Errors: nil,
+	}
+	return
+}
+
+// Create only gives the key permissions its user holds and never administrator, a key is never logged in with two-factor,
+// the key is in the response once and only its hash is stored
+func (service *ApiKeyServiceImplementation) Create(ctx context.Context, apiKeyRequest models.ApiKeyRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(apiKeyRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, apiKeyRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	if apiKeyRequest.ExpiresAt != 0 && apiKeyRequest.ExpiresAt <= now {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "expiresAt", Message: "please input a time in the future"}})
+		return
+	}
+
+	administrator, err := service.PermissionRepository.FindByPermission(service.PostgresUtil.GetPool(), ctx, middlewares.AdministratorPermission)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if err == nil && containsId(apiKeyRequest.PermissionIds, administrator.Id.Int32) {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "permissionIds", Message: "an api key cannot hold " + middlewares.AdministratorPermission}})
+		return
+	}
+
+	idPermissions, err := service.UserPermissionRepository.FindPermissionIdsByUserId(service.PostgresUtil.GetPool(), ctx, apiKeyRequest.UserId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	for _, permissionId := range apiKeyRequest.PermissionIds {
+		if !containsId(idPermissions, permissionId) {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "permissionIds", Message: "the user does not hold permission " + strconv.Itoa(int(permissionId))}})
+			return
+		}
+	}
+
+	token, err := service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	key := apiKeyPrefix + token
+	apiKey := models.ApiKey{
+		UserId:        pgtype.Int4{Valid: true, Int32: apiKeyRequest.UserId},
+		Name:          pgtype.Text{Valid: true, String: apiKeyRequest.Name},
+		KeyPrefix:     pgtype.Text{Valid: true, String: key[:apiKeyPrefixLength]},
+		KeyHash:       pgtype.Text{Valid: true, String: helpers.ToTokenHash(key)},
+		PermissionIds: apiKeyRequest.PermissionIds,
+		CreatedBy:     pgtype.Int4{Valid: true, Int32: ctx.Value(middlewares.IdKey).(int32)},
+		CreatedAt:     pgtype.Int8{Valid: true, Int64: now},
+		ExpiresAt:     pgtype.Int8{Valid: apiKeyRequest.ExpiresAt != 0, Int64: apiKeyRequest.ExpiresAt},
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	id, err := service.ApiKeyRepository.Create(tx, ctx, apiKey)
+	if err != nil {
+		httpCode, response = toResponseSaveApiKeyError(err, requestId, "userId", "user not found")
+		return
+	}
+	err = service.ApiKeyRepository.CreatePermissions(tx, ctx, id, apiKeyRequest.PermissionIds)
+	if err != nil {
+		httpCode, response = toResponseSaveApiKeyError(err, requestId, "permissionIds", "permission not found")
+		return
+	}
+
+	apiKey.Id = pgtype.Int4{Valid: true, Int32: id}
+	httpCode = http.StatusCreated
+	response = helpers.Response{
+		Data: models.CreateApiKeyResponse{
+			ApiKey: toApiKeyResponse(apiKey),
+			Key:    key,
+		},
+		Errors: nil,
+	}
+	return
+}
+
+func (service *ApiKeyServiceImplementation) Revoke(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	rowsAffected, err := service.ApiKeyRepository.Revoke(service.PostgresUtil.GetPool(), ctx, id, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("cannot find active api key with id: " + strconv.Itoa(int(id)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "api key not found")
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke api key",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func containsId(ids []int32, id int32) bool {
+	for _, element := range ids {
+		if element == id {
+			return true
+		}
+	}
+	return false
+}
+
+// toResponseSaveApiKeyError turns the foreign key violation of the insert into a validation error of field, the user or
+// a permission was deleted after the checks
+func toResponseSaveApiKeyError(err error, requestId string, field string, message string) (httpCode int, response helpers.Response) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == "23503" {
+		return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: field, Message: message}})
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+func toApiKeyResponse(apiKey models.ApiKey) models.ApiKeyResponse {
+	apiKeyResponse := models.ApiKeyResponse{
+		Id:            apiKey.Id.Int32,
+		UserId:        apiKey.UserId.Int32,
+		Name:          apiKey.Name.String,
+		KeyPrefix:     apiKey.KeyPrefix.String,
+		PermissionIds: apiKey.PermissionIds,
+		CreatedBy:     apiKey.CreatedBy.Int32,
+		CreatedAt:     apiKey.CreatedAt.Int64,
+	}
+	if apiKeyResponse.PermissionIds == nil {
+		apiKeyResponse.PermissionIds = []int32{}
+	}
+	if apiKey.ExpiresAt.Valid {
+		expiresAt := apiKey.ExpiresAt.Int64
+		apiKeyResponse.ExpiresAt = &expiresAt
+	}
+	if apiKey.LastUsedAt.Valid {
+		lastUsedAt := apiKey.LastUsedAt.Int64
+		apiKeyResponse.LastUsedAt = &lastUsedAt
+	}
+	if apiKey.RevokedAt.Valid {
+		revokedAt := apiKey.RevokedAt.Int64
+		apiKeyResponse.RevokedAt = &revokedAt
+	}
+	return apiKeyResponse
+}
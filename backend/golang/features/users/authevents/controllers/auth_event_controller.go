@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/authevents/models"
+	"backend-golang/features/users/authevents/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuthEventController interface {
+	FindAll(c echo.Context) error
+}
+
+type AuthEventControllerImplementation struct {
+	AuthEventService services.AuthEventService
+}
+
+func NewAuthEventController(authEventService services.AuthEventService) AuthEventController {
+	return &AuthEventControllerImplementation{
+		AuthEventService: authEventService,
+	}
+}
+
+func (controller *AuthEventControllerImplementation) FindAll(c echo.Context) error {
+	var findAllAuthEventRequest models.FindAllAuthEventRequest
+	err := c.Bind(&findAllAuthEventRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.AuthEventService.FindAll(c.Request().Context(), findAllAuthEventRequest)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type AuthEvent struct {
+	Id        pgtype.Int4
+	EventType pgtype.Text
+	UserId    pgtype.Int4
+	Email     pgtype.Text
+	Ip        pgtype.Text
+	UserAgent pgtype.Text
+	RequestId pgtype.Text
+	CreatedAt pgtype.Int8
+}
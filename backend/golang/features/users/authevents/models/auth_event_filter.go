@@ -0,0 +1,14 @@
+package models
+
+// AuthEventFilter is what the repository searches by, a zero value leaves its condition out,
+// BeforeId is the id of the last event of the previous page
+type AuthEventFilter struct {
+	EventType   string
+	UserId      int32
+	Email       string
+	Ip          string
+	CreatedFrom int64
+	CreatedTo   int64
+	BeforeId    int32
+	Limit       int
+}
@@ -0,0 +1,13 @@
+package models
+
+// AuthEventResponse has userId 0 when the attempted email did not belong to any user
+type AuthEventResponse struct {
+	Id        int32  `json:"id"`
+	EventType string `json:"eventType"`
+	UserId    int32  `json:"userId"`
+	Email     string `json:"email"`
+	Ip        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+	RequestId string `json:"requestId"`
+	CreatedAt int64  `json:"createdAt"`
+}
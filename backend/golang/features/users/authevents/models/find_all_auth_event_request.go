@@ -0,0 +1,14 @@
+package models
+
+// FindAllAuthEventRequest comes from the query string, email matches a part of the value while eventType, userId and ip
+// match exactly, createdFrom is inclusive and createdTo exclusive, both in unix millis
+type FindAllAuthEventRequest struct {
+	EventType   string `query:"eventType" json:"eventType" validate:"omitempty,oneof=LOGIN_SUCCEEDED LOGIN_FAILED LOGIN_LOCKED LOGIN_DISABLED LOGIN_UNVERIFIED TWO_FACTOR_CHALLENGED TWO_FACTOR_FAILED"`
+	UserId      int32  `query:"userId" json:"userId" validate:"gte=0"`
+	Email       string `query:"email" json:"email" validate:"max=100"`
+	Ip          string `query:"ip" json:"ip" validate:"max=45"`
+	CreatedFrom int64  `query:"createdFrom" json:"createdFrom" validate:"gte=0"`
+	CreatedTo   int64  `query:"createdTo" json:"createdTo" validate:"gte=0"`
+	Cursor      string `query:"cursor" json:"cursor"`
+	Limit       int    `query:"limit" json:"limit" validate:"gte=0,lte=100"`
+}
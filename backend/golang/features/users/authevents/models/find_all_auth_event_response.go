@@ -0,0 +1,7 @@
+package models
+
+// FindAllAuthEventResponse has an empty nextCursor on the last page
+type FindAllAuthEventResponse struct {
+	AuthEvents []AuthEventResponse `json:"authEvents"`
+	NextCursor string              `json:"nextCursor"`
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/authevents/models"
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuthEventRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context, authEventFilter models.AuthEventFilter) (authEvents []models.AuthEvent, err error)
+}
+
+type AuthEventRepositoryImplementation struct {
+}
+
+func NewAuthEventRepository() AuthEventRepository {
+	return &AuthEventRepositoryImplementation{}
+}
+
+// FindAll returns the newest events first, at most authEventFilter.Limit of them
+func (repository *AuthEventRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context, authEventFilter models.AuthEventFilter) (authEvents []models.AuthEvent, err error) {
+	query := `SELECT id, event_type, user_id, email, ip, user_agent, request_id, created_at FROM auth_events WHERE TRUE`
+	var arguments []interface{}
+	if authEventFilter.EventType != "" {
+		arguments = append(arguments, authEventFilter.EventType)
+		query += ` AND event_type = $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.UserId > 0 {
+		arguments = append(arguments, authEventFilter.UserId)
+		query += ` AND user_id = $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.Email != "" {
+		arguments = append(arguments, helpers.ToLikeContains(authEventFilter.Email))
+		query += ` AND email ILIKE $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.Ip != "" {
+		arguments = append(arguments, authEventFilter.Ip)
+		query += ` AND ip = $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.CreatedFrom > 0 {
+		arguments = append(arguments, authEventFilter.CreatedFrom)
+		query += ` AND created_at >= $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.CreatedTo > 0 {
+		arguments = append(arguments, authEventFilter.CreatedTo)
+		query += ` AND created_at < $` + strconv.Itoa(len(arguments))
+	}
+	if authEventFilter.BeforeId > 0 {
+		arguments = append(arguments, authEventFilter.BeforeId)
+		query += ` AND id < $` + strconv.Itoa(len(arguments))
+	}
+	arguments = append(arguments, authEventFilter.Limit)
+	query += ` ORDER BY id DESC LIMIT $` + strconv.Itoa(len(arguments)) + `;`
+
+	rows, err := pool.Query(ctx, query, arguments...)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			authEvents = []models.AuthEvent{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var authEvent models.AuthEvent
+		err = rows.Scan(&authEvent.Id, &authEvent.EventType, &authEvent.UserId, &authEvent.Email, &authEvent.Ip, &authEvent.UserAgent, &authEvent.RequestId, &authEvent.CreatedAt)
+		if err != nil {
+			authEvents = []models.AuthEvent{}
+			return
+		}
+		authEvents = append(authEvents, authEvent)
+	}
+	return
+}
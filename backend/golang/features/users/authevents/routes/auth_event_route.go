@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/authevents/controllers"
+	"backend-golang/features/users/authevents/repositories"
+	"backend-golang/features/users/authevents/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func AuthEventRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, validate *validator.Validate, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	authEventRepository := repositories.NewAuthEventRepository()
+	authEventService := services.NewAuthEventService(postgresUtil, validate, authEventRepository)
+	authEventController := controllers.NewAuthEventController(authEventService)
+	requireAdministrator := permissionMiddleware.RequirePermissions(middlewares.AdministratorPermission)
+	e.GET("/api/v1/admin/auth-events", authEventController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireAdministrator)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/authevents/models"
+	"backend-golang/features/users/authevents/repositories"
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const defaultAuthEventPageLimit = 20
+
+type AuthEventService interface {
+	FindAll(ctx context.Context, findAllAuthEventRequest models.FindAllAuthEventRequest) (httpCode int, response helpers.Response)
+}
+
+type AuthEventServiceImplementation struct {
+	PostgresUtil        utils.PostgresUtil
+	Validate            *validator.Validate
+	AuthEventRepository repositories.AuthEventRepository
+}
+
+func NewAuthEventService(postgresUtil utils.PostgresUtil, validate *validator.Validate, authEventRepository repositories.AuthEventRepository) AuthEventService {
+	return &AuthEventServiceImplementation{
+		PostgresUtil:        postgresUtil,
+		Validate:            validate,
+		AuthEventRepository: authEventRepository,
+	}
+}
+
+// FindAll pages from the newest event to the oldest, the cursor of the next page is made from the id of the last event of this one
+func (service *AuthEventServiceImplementation) FindAll(ctx context.Context, findAllAuthEventRequest models.FindAllAuthEventRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(findAllAuthEventRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, findAllAuthEventRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	authEventFilter := models.AuthEventFilter{
+		EventType:   findAllAuthEventRequest.EventType,
+		UserId:      findAllAuthEventRequest.UserId,
+		Email:       findAllAuthEventRequest.Email,
+		Ip:          findAllAuthEventRequest.Ip,
+		CreatedFrom: findAllAuthEventRequest.CreatedFrom,
+		CreatedTo:   findAllAuthEventRequest.CreatedTo,
+		Limit:       findAllAuthEventRequest.Limit,
+	}
+	if authEventFilter.Limit == 0 {
+		authEventFilter.Limit = defaultAuthEventPageLimit
+	}
+	if findAllAuthEventRequest.Cursor != "" {
+		authEventFilter.BeforeId, err = helpers.FromCursor(findAllAuthEventRequest.Cursor)
+		if err != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "cursor", Message: "cursor is invalid"}})
+			return
+		}
+	}
+
+	pageLimit := authEventFilter.Limit
+	authEventFilter.Limit++
+	authEvents, err := service.AuthEventRepository.FindAll(service.PostgresUtil.GetPool(), ctx, authEventFilter)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	findAllAuthEventResponse := models.FindAllAuthEventResponse{
+		AuthEvents: []models.AuthEventResponse{},
+	}
+	if len(authEvents) > pageLimit {
+		authEvents = authEvents[:pageLimit]
+		findAllAuthEventResponse.NextCursor = helpers.ToCursor(authEvents[len(authEvents)-1].Id.Int32)
+	}
+	for _, authEvent := range authEvents {
+		findAllAuthEventResponse.AuthEvents = append(findAllAuthEventResponse.AuthEvents, toAuthEventResponse(authEvent))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   findAllAuthEventResponse,
+		Errors: nil,
+	}
+	return
+}
+
+func toAuthEventResponse(authEvent models.AuthEvent) models.AuthEventResponse {
+	return models.AuthEventResponse{
+		Id:        authEvent.Id.Int32,
+		EventType: authEvent.EventType.String,
+		UserId:    authEvent.UserId.Int32,
+		Email:     authEvent.Email.String,
+		Ip:        authEvent.Ip.String,
+		UserAgent: authEvent.UserAgent.String,
+		RequestId: authEvent.RequestId.String,
+		CreatedAt: authEvent.CreatedAt.Int64,
+	}
+}
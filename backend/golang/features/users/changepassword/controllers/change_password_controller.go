@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/changepassword/models"
+	"backend-golang/features/users/changepassword/services"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ChangePasswordController interface {
+	ChangePassword(c echo.Context) error
+}
+
+type ChangePasswordControllerImplementation struct {
+	ChangePasswordService services.ChangePasswordService
+}
+
+func NewChangePasswordController(changePasswordService services.ChangePasswordService) ChangePasswordController {
+	return &ChangePasswordControllerImplementation{
+		ChangePasswordService: changePasswordService,
+	}
+}
+
+func (controller *ChangePasswordControllerImplementation) ChangePassword(c echo.Context) error {
+	var changePasswordRequest models.ChangePasswordRequest
+	err := c.Bind(&changePasswordRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	sessionId, httpCode, response := controller.ChangePasswordService.ChangePassword(c.Request().Context(), changePasswordRequest, c.Request().UserAgent(), c.RealIP())
+	if sessionId == "" {
+		return c.JSON(httpCode, response)
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	now := time.Now()
+	cookie, err := helpers.ToSessionCookie(sessionId, sessionLifetime.Ttl(now, now))
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	c.SetCookie(cookie)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,7 @@
+package models
+
+type ChangePasswordRequest struct {
+	Currentpassword string `json:"currentpassword" validate:"required"`
+	Password        string `json:"password" validate:"required,passwordvalidator,nefield=Currentpassword"`
+	Confirmpassword string `json:"confirmpassword" validate:"required,eqfield=Password"`
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserRepository interface {
+	FindPasswordByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (password string, err error)
+	UpdatePassword(tx pgx.Tx, ctx context.Context, id int32, password string) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+// FindPasswordByIdForUpdate locks the row so two concurrent changes cannot both pass the current password check
+func (repository *UserRepositoryImplementation) FindPasswordByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (password string, err error) {
+	err = tx.QueryRow(ctx, `SELECT password FROM users WHERE id = $1 FOR UPDATE;`, id).Scan(&password)
+	return
+}
+
+func (repository *UserRepositoryImplementation) UpdatePassword(tx pgx.Tx, ctx context.Context, id int32, password string) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `UPDATE users SET password = $1 WHERE id = $2;`, password, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/changepassword/controllers"
+	"backend-golang/features/users/changepassword/repositories"
+	"backend-golang/features/users/changepassword/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func ChangePasswordRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, sessionMiddleware middlewares.SessionMiddleware) {
+	userRepository := repositories.NewUserRepository()
+	changePasswordService := services.NewChangePasswordService(postgresUtil, redisUtil, validate, userRepository, passwordHasher, uuidHelper, redisHelper, sessionRegistryHelper)
+	changePasswordController := controllers.NewChangePasswordController(changePasswordService)
+	e.PUT("/api/v1/users/password", changePasswordController.ChangePassword, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/changepassword/models"
+	"backend-golang/features/users/changepassword/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+type ChangePasswordService interface {
+	ChangePassword(ctx context.Context, changePasswordRequest models.ChangePasswordRequest, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response)
+}
+
+type ChangePasswordServiceImplementation struct {
+	PostgresUtil          utils.PostgresUtil
+	RedisUtil             utils.RedisUtil
+	Validate              *validator.Validate
+	UserRepository        repositories.UserRepository
+	PasswordHasher        helpers.PasswordHasher
+	UuidHelper            helpers.UuidHelper
+	RedisHelper           helpers.RedisHelper
+	SessionRegistryHelper helpers.SessionRegistryHelper
+}
+
+func NewChangePasswordService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, passwordHasher helpers.PasswordHasher, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper) ChangePasswordService {
+	return &ChangePasswordServiceImplementation{
+		PostgresUtil:          postgresUtil,
+		RedisUtil:             redisUtil,
+		Validate:              validate,
+		UserRepository:        userRepository,
+		PasswordHasher:        passwordHasher,
+		UuidHelper:            uuidHelper,
+		RedisHelper:           redisHelper,
+		SessionRegistryHelper: sessionRegistryHelper,
+	}
+}
+
+// ChangePassword rotates the sessions once the new password is committed, the caller gets a new session
+// and every other session of the user, the current one included, is deleted
+func (service *ChangePasswordServiceImplementation) ChangePassword(ctx context.Context, changePasswordRequest models.ChangePasswordRequest, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	httpCode, response = service.updatePassword(ctx, changePasswordRequest)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	// a client authenticated by an access token has no cookie to rotate, it keeps its token family and everything else is deleted
+	_, byToken := ctx.Value(middlewares.TokenIdKey).(string)
+	if byToken {
+		err := service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, ctx.Value(middlewares.SessionIdKey).(string))
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		}
+		return
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	// a password change does not ask for a second factor, the new session keeps whatever the current one proved
+	twoFactor, _ := ctx.Value(middlewares.TwoFactorKey).(bool)
+	now := time.Now()
+	newSessionId := service.UuidHelper.String()
+	session := helpers.Session{
+		Id:            userId,
+		Username:      ctx.Value(middlewares.UsernameKey).(string),
+		Email:         ctx.Value(middlewares.EmailKey).(string),
+		IdPermissions: ctx.Value(middlewares.PermissionKey).([]int32),
+		CreatedAt:     now.UnixMilli(),
+		TwoFactor:     twoFactor,
+	}
+	sessionByte, err := json.Marshal(session)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	sessionInfo := helpers.SessionInfo{
+		SessionId: newSessionId,
+		CreatedAt: now.UnixMilli(),
+		UserAgent: userAgent,
+		Ip:        ip,
+	}
+	err = service.SessionRegistryHelper.Register(service.RedisUtil.GetClient(), ctx, userId, sessionInfo, sessionLifetime.AbsoluteLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	_, err = service.RedisHelper.Set(service.RedisUtil.GetClient(), ctx, newSessionId, string(sessionByte), sessionLifetime.Ttl(now, now))
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, newSessionId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	sessionId = newSessionId
+	return
+}
+
+func (service *ChangePasswordServiceImplementation) updatePassword(ctx context.Context, changePasswordRequest models.ChangePasswordRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	var err error
+	err = service.Validate.Struct(changePasswordRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, changePasswordRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	password, err := service.UserRepository.FindPasswordByIdForUpdate(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.PasswordHasher.Compare(password, changePasswordRequest.Currentpassword)
+	if err != nil {
+		err = errors.New("wrong current password")
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "currentpassword", Message: "wrong current password"}})
+		return
+	}
+
+	newPassword, err := service.PasswordHasher.Hash(changePasswordRequest.Password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	rowsAffected, err := service.UserRepository.UpdatePassword(tx, ctx, userId, newPassword)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("rows affected not one when updating password")
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully change password",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"backend-golang/features/users/csrf/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CsrfController interface {
+	Issue(c echo.Context) error
+}
+
+type CsrfControllerImplementation struct {
+	CsrfService services.CsrfService
+}
+
+func NewCsrfController(csrfService services.CsrfService) CsrfController {
+	return &CsrfControllerImplementation{
+		CsrfService: csrfService,
+	}
+}
+
+func (controller *CsrfControllerImplementation) Issue(c echo.Context) error {
+	httpCode, response := controller.CsrfService.Issue(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
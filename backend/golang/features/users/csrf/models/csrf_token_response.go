@@ -0,0 +1,5 @@
+package models
+
+type CsrfTokenResponse struct {
+	CsrfToken string `json:"csrfToken"`
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/csrf/controllers"
+	"backend-golang/features/users/csrf/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+func CsrfRoute(e *echo.Echo, redisUtil utils.RedisUtil, tokenHelper helpers.TokenHelper, csrfTokenHelper helpers.CsrfTokenHelper, sessionMiddleware middlewares.SessionMiddleware) {
+	csrfService := services.NewCsrfService(redisUtil, tokenHelper, csrfTokenHelper)
+	csrfController := controllers.NewCsrfController(csrfService)
+	e.GET("/api/v1/users/csrf", csrfController.Issue, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/csrf/models"
+	"context"
+	"net/http"
+)
+
+type CsrfService interface {
+	Issue(ctx context.Context) (httpCode int, response helpers.Response)
+}
+
+type CsrfServiceImplementation struct {
+	RedisUtil       utils.RedisUtil
+	TokenHelper     helpers.TokenHelper
+	CsrfTokenHelper helpers.CsrfTokenHelper
+}
+
+func NewCsrfService(redisUtil utils.RedisUtil, tokenHelper helpers.TokenHelper, csrfTokenHelper helpers.CsrfTokenHelper) CsrfService {
+	return &CsrfServiceImplementation{
+		RedisUtil:       redisUtil,
+		TokenHelper:     tokenHelper,
+		CsrfTokenHelper: csrfTokenHelper,
+	}
+}
+
+// Issue returns the csrf token of the current session and creates it on the first call, a session never outlives
+// its absolute lifetime so the token is kept that long, a new session after login or password change needs a new token
+func (service *CsrfServiceImplementation) Issue(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	sessionId := ctx.Value(middlewares.SessionIdKey).(string)
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	token, err := service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	csrfToken, err := service.CsrfTokenHelper.FindOrCreate(service.RedisUtil.GetClient(), ctx, sessionId, token, sessionLifetime.AbsoluteLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	csrfTokenResponse := models.CsrfTokenResponse{
+		CsrfToken: csrfToken,
+	}
+	response = helpers.Response{
+		Data:   csrfTokenResponse,
+		Errors: nil,
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/emailverification/models"
+	"backend-golang/features/users/emailverification/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EmailVerificationController interface {
+	Confirm(c echo.Context) error
+	Resend(c echo.Context) error
+}
+
+type EmailVerificationControllerImplementation struct {
+	EmailVerificationService services.EmailVerificationService
+}
+
+func NewEmailVerificationController(emailVerificationService services.EmailVerificationService) EmailVerificationController {
+	return &EmailVerificationControllerImplementation{
+		EmailVerificationService: emailVerificationService,
+	}
+}
+
+func (controller *EmailVerificationControllerImplementation) Confirm(c echo.Context) error {
+	var confirmEmailVerificationRequest models.ConfirmEmailVerificationRequest
+	err := c.Bind(&confirmEmailVerificationRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.EmailVerificationService.Confirm(c.Request().Context(), confirmEmailVerificationRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *EmailVerificationControllerImplementation) Resend(c echo.Context) error {
+	var resendEmailVerificationRequest models.ResendEmailVerificationRequest
+	err := c.Bind(&resendEmailVerificationRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	retryAfter, httpCode, response := controller.EmailVerificationService.Resend(c.Request().Context(), resendEmailVerificationRequest)
+	if retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	return c.JSON(httpCode, response)
+}
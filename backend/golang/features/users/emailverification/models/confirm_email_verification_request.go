@@ -0,0 +1,5 @@
+package models
+
+type ConfirmEmailVerificationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type ResendEmailVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
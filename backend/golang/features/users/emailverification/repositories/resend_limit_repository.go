@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ResendLimitRepository allows one verification mail per email per interval
+type ResendLimitRepository interface {
+	Acquire(client *redis.Client, ctx context.Context, email string, interval time.Duration) (retryAfter time.Duration, err error)
+}
+
+type ResendLimitRepositoryImplementation struct {
+}
+
+func NewResendLimitRepository() ResendLimitRepository {
+	return &ResendLimitRepositoryImplementation{}
+}
+
+func ToEmailVerificationResendKey(email string) string {
+	return "emailVerificationResend:" + email
+}
+
+// Acquire returns zero when a mail may be sent now, otherwise how long until the next one may be sent
+func (repository *ResendLimitRepositoryImplementation) Acquire(client *redis.Client, ctx context.Context, email string, interval time.Duration) (retryAfter time.Duration, err error) {
+	key := ToEmailVerificationResendKey(email)
+	acquired, err := client.SetNX(ctx, key, "1", interval).Result()
+	if err != nil || acquired {
+		return
+	}
+	retryAfter, err = client.TTL(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	if retryAfter <= 0 {
+		// the key expired between the two commands
+		retryAfter = time.Second
+	}
+	return
+}
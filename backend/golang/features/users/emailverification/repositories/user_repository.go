@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"backend-golang/features/users/emailverification/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error)
+	UpdateEmailVerifiedAt(pool *pgxpool.Pool, ctx context.Context, id int32, email string, emailVerifiedAt int64) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email, email_verified_at FROM users WHERE email = $1;`, email).Scan(&user.Id, &user.Username, &user.Email, &user.EmailVerifiedAt)
+	return
+}
+
+// UpdateEmailVerifiedAt only matches while the user still has the email the token was sent to, an email that is already verified keeps its first time
+func (repository *UserRepositoryImplementation) UpdateEmailVerifiedAt(pool *pgxpool.Pool, ctx context.Context, id int32, email string, emailVerifiedAt int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, $1) WHERE id = $2 AND email = $3;`, emailVerifiedAt, id, email)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/emailverification/controllers"
+	"backend-golang/features/users/emailverification/repositories"
+	"backend-golang/features/users/emailverification/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func EmailVerificationRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, emailVerificationHelper helpers.EmailVerificationHelper) {
+	userRepository := repositories.NewUserRepository()
+	resendLimitRepository := repositories.NewResendLimitRepository()
+	emailVerificationService := services.NewEmailVerificationService(postgresUtil, redisUtil, validate, userRepository, resendLimitRepository, emailVerificationHelper)
+	emailVerificationController := controllers.NewEmailVerificationController(emailVerificationService)
+	e.POST("/api/v1/users/email/verification/confirm", emailVerificationController.Confirm, middlewares.PrintRequestResponseLog)
+	e.POST("/api/v1/users/email/verification/resend", emailVerificationController.Resend, middlewares.PrintRequestResponseLog)
+}
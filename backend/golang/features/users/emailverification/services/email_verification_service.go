@@ -0,0 +1,141 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/emailverification/models"
+	"backend-golang/features/users/emailverification/repositories"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+const emailVerificationResendInterval = time.Minute
+
+type EmailVerificationService interface {
+	Confirm(ctx context.Context, confirmEmailVerificationRequest models.ConfirmEmailVerificationRequest) (httpCode int, response helpers.Response)
+	Resend(ctx context.Context, resendEmailVerificationRequest models.ResendEmailVerificationRequest) (retryAfter int, httpCode int, response helpers.Response)
+}
+
+type EmailVerificationServiceImplementation struct {
+	PostgresUtil            utils.PostgresUtil
+	RedisUtil               utils.RedisUtil
+	Validate                *validator.Validate
+	UserRepository          repositories.UserRepository
+	ResendLimitRepository   repositories.ResendLimitRepository
+	EmailVerificationHelper helpers.EmailVerificationHelper
+}
+
+func NewEmailVerificationService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, resendLimitRepository repositories.ResendLimitRepository, emailVerificationHelper helpers.EmailVerificationHelper) EmailVerificationService {
+	return &EmailVerificationServiceImplementation{
+		PostgresUtil:            postgresUtil,
+		RedisUtil:               redisUtil,
+		Validate:                validate,
+		UserRepository:          userRepository,
+		ResendLimitRepository:   resendLimitRepository,
+		EmailVerificationHelper: emailVerificationHelper,
+	}
+}
+
+func (service *EmailVerificationServiceImplementation) Confirm(ctx context.Context, confirmEmailVerificationRequest models.ConfirmEmailVerificationRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(confirmEmailVerificationRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, confirmEmailVerificationRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	emailVerificationToken, err := service.EmailVerificationHelper.FindAndDelete(service.RedisUtil.GetClient(), ctx, confirmEmailVerificationRequest.Token)
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+		return
+	}
+
+	rowsAffected, err := service.UserRepository.UpdateEmailVerifiedAt(service.PostgresUtil.GetPool(), ctx, emailVerificationToken.UserId, emailVerificationToken.Email, time.Now().UnixMilli())
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		// the email was changed after the token was sent
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully verify email",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// Resend answers the same whether the email is registered, unverified or not, the limit is kept for any email for the same reason
+func (service *EmailVerificationServiceImplementation) Resend(ctx context.Context, resendEmailVerificationRequest models.ResendEmailVerificationRequest) (retryAfter int, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(resendEmailVerificationRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, resendEmailVerificationRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	email := helpers.NormalizeEmail(resendEmailVerificationRequest.Email)
+	ttl, err := service.ResendLimitRepository.Acquire(service.RedisUtil.GetClient(), ctx, email, emailVerificationResendInterval)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if ttl > 0 {
+		retryAfter = int(math.Ceil(ttl.Seconds()))
+		err = errors.New("verification email for " + email + " was sent less than " + emailVerificationResendInterval.String() + " ago")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusTooManyRequests, "please wait before asking for another verification email")
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "if the email is registered and not verified yet, a verification link has been sent",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+
+	user, err := service.UserRepository.FindByEmail(service.PostgresUtil.GetPool(), ctx, email)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		return
+	}
+	if user.EmailVerifiedAt.Valid {
+		return
+	}
+
+	err = service.EmailVerificationHelper.Send(service.RedisUtil.GetClient(), ctx, user.Id.Int32, user.Username.String, user.Email.String)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+	return
+}
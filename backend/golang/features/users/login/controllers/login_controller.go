@@ -5,7 +5,6 @@ import (
 	"backend-golang/features/users/login/models"
 	"backend-golang/features/users/login/services"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
@@ -14,6 +13,11 @@ import (
 
 type LoginController interface {
 	Login(c echo.Context) error
+	VerifyTwoFactor(c echo.Context) error
+	LoginWithToken(c echo.Context) error
+	VerifyTwoFactorWithToken(c echo.Context) error
+	AuthorizeOidc(c echo.Context) error
+	LoginWithOidc(c echo.Context) error
 }
 
 type LoginControllerImplementation struct {
@@ -32,21 +36,129 @@ func (controller *LoginControllerImplementation) Login(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
 	}
-	sessionId, httpCode, response := controller.LoginService.Login(c.Request().Context(), loginRequest)
+	sessionId, retryAfter, httpCode, response := controller.LoginService.Login(c.Request().Context(), loginRequest, c.Request().UserAgent(), c.RealIP())
 
-	secure, err := strconv.ParseBool(os.Getenv("ECOMMERCEV2_COOKIE_SECURE"))
+	if retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	if sessionId == "" {
+		return c.JSON(httpCode, response)
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	now := time.Now()
+	cookie, err := helpers.ToSessionCookie(sessionId, sessionLifetime.Ttl(now, now))
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	c.SetCookie(cookie)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *LoginControllerImplementation) VerifyTwoFactor(c echo.Context) error {
+	var verifyTwoFactorRequest models.VerifyTwoFactorRequest
+	err := c.Bind(&verifyTwoFactorRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	sessionId, httpCode, response := controller.LoginService.VerifyTwoFactor(c.Request().Context(), verifyTwoFactorRequest, c.Request().UserAgent(), c.RealIP())
+	if sessionId == "" {
+		return c.JSON(httpCode, response)
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	now := time.Now()
+	cookie, err := helpers.ToSessionCookie(sessionId, sessionLifetime.Ttl(now, now))
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	c.SetCookie(cookie)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *LoginControllerImplementation) LoginWithToken(c echo.Context) error {
+	var loginRequest models.LoginRequest
+	err := c.Bind(&loginRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	retryAfter, httpCode, response := controller.LoginService.LoginWithToken(c.Request().Context(), loginRequest, c.Request().UserAgent(), c.RealIP())
+
+	if retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	return c.JSON(httpCode, response)
+}
+
+func (controller *LoginControllerImplementation) VerifyTwoFactorWithToken(c echo.Context) error {
+	var verifyTwoFactorRequest models.VerifyTwoFactorRequest
+	err := c.Bind(&verifyTwoFactorRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.LoginService.VerifyTwoFactorWithToken(c.Request().Context(), verifyTwoFactorRequest, c.Request().UserAgent(), c.RealIP())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *LoginControllerImplementation) AuthorizeOidc(c echo.Context) error {
+	state, httpCode, response := controller.LoginService.AuthorizeOidc(c.Request().Context(), c.Param("provider"))
+	if state == "" {
+		return c.JSON(httpCode, response)
+	}
+
+	cookie, err := helpers.ToOidcStateCookie(state, helpers.OidcStateLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	c.SetCookie(cookie)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *LoginControllerImplementation) LoginWithOidc(c echo.Context) error {
+	var oidcLoginRequest models.OidcLoginRequest
+	err := c.Bind(&oidcLoginRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	stateCookie := ""
+	cookie, err := c.Cookie(helpers.OidcStateCookieName)
+	if err == nil {
+		stateCookie = cookie.Value
+	}
+	sessionId, httpCode, response := controller.LoginService.LoginWithOidc(c.Request().Context(), c.Param("provider"), oidcLoginRequest, stateCookie, c.Request().UserAgent(), c.RealIP())
+
+	// the state is used up whatever the outcome, the cookie goes with it
+	expiredCookie, err := helpers.ToExpiredOidcStateCookie()
 	if err != nil {
 		httpCode, response = helpers.ToResponseInternalServerError()
 		return c.JSON(httpCode, response)
 	}
-	cookie := &http.Cookie{
-		Name:     "sessionId",
-		Value:    sessionId,
-		Expires:  time.Now().Add(24 * time.Hour),
-		HttpOnly: true,
-		Secure:   secure,
-		SameSite: http.SameSiteStrictMode,
-		Domain:   os.Getenv("ECOMMERCEV2_COOKIE_DOMAIN"),
+	c.SetCookie(expiredCookie)
+	if sessionId == "" {
+		return c.JSON(httpCode, response)
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	now := time.Now()
+	cookie, err = helpers.ToSessionCookie(sessionId, sessionLifetime.Ttl(now, now))
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
 	}
 	c.SetCookie(cookie)
 	return c.JSON(httpCode, response)
@@ -0,0 +1,5 @@
+package models
+
+type OidcAuthorizationResponse struct {
+	AuthorizationUrl string `json:"authorizationUrl"`
+}
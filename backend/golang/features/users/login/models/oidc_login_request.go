@@ -0,0 +1,7 @@
+package models
+
+// OidcLoginRequest is what the provider sent back to the redirect url of the frontend
+type OidcLoginRequest struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=128"`
+}
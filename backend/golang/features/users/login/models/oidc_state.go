@@ -0,0 +1,8 @@
+package models
+
+// OidcState is stored in redis under the hash of the state until the user comes back from the provider
+type OidcState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type TwoFactorChallengeResponse struct {
+	Message     string `json:"message"`
+	ChallengeId string `json:"challengeId"`
+}
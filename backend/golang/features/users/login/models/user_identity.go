@@ -0,0 +1,9 @@
+package models
+
+// UserIdentity links the subject of an identity provider to a user
+type UserIdentity struct {
+	UserId    int32
+	Provider  string
+	Subject   string
+	CreatedAt int64
+}
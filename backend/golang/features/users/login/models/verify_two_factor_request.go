@@ -0,0 +1,6 @@
+package models
+
+type VerifyTwoFactorRequest struct {
+	ChallengeId string `json:"challengeId" validate:"required"`
+	Code        string `json:"code" validate:"required"`
+}
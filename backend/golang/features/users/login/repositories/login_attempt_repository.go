@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptRepository interface {
+	FindLockTtl(client *redis.Client, ctx context.Context, key string) (ttl time.Duration, err error)
+	IncrementFailure(client *redis.Client, ctx context.Context, key string, window time.Duration) (failures int64, err error)
+	Lock(client *redis.Client, ctx context.Context, key string, duration time.Duration) (err error)
+	Reset(client *redis.Client, ctx context.Context, keys ...string) (err error)
+}
+
+type LoginAttemptRepositoryImplementation struct {
+}
+
+func NewLoginAttemptRepository() LoginAttemptRepository {
+	return &LoginAttemptRepositoryImplementation{}
+}
+
+func ToLoginFailureKey(kind string, value string) string {
+	return "loginFailures:" + kind + ":" + value
+}
+
+func ToLoginLockKey(kind string, value string) string {
+	return "loginLock:" + kind + ":" + value
+}
+
+// FindLockTtl returns how long the lock still holds, zero or less when there is no lock
+func (repository *LoginAttemptRepositoryImplementation) FindLockTtl(client *redis.Client, ctx context.Context, key string) (ttl time.Duration, err error) {
+	return client.TTL(ctx, key).Result()
+}
+
+// IncrementFailure counts a failure, the counter starts its window on the first failure
+func (repository *LoginAttemptRepositoryImplementation) IncrementFailure(client *redis.Client, ctx context.Context, key string, window time.Duration) (failures int64, err error) {
+	failures, err = client.Incr(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	if failures == 1 {
+		_, err = client.Expire(ctx, key, window).Result()
+	}
+	return
+}
+
+func (repository *LoginAttemptRepositoryImplementation) Lock(client *redis.Client, ctx context.Context, key string, duration time.Duration) (err error) {
+	_, err = client.Set(ctx, key, "1", duration).Result()
+	return
+}
+
+func (repository *LoginAttemptRepositoryImplementation) Reset(client *redis.Client, ctx context.Context, keys ...string) (err error) {
+	_, err = client.Del(ctx, keys...).Result()
+	return
+}
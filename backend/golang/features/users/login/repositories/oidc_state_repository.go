@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"backend-golang/features/users/login/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// OidcStateRepository keeps the nonce and the pkce verifier of a pending sign in under the hash of its state
+type OidcStateRepository interface {
+	Create(client *redis.Client, ctx context.Context, stateHash string, oidcState models.OidcState, expiration time.Duration) (err error)
+	FindAndDelete(client *redis.Client, ctx context.Context, stateHash string) (oidcState models.OidcState, err error)
+}
+
+type OidcStateRepositoryImplementation struct {
+}
+
+func NewOidcStateRepository() OidcStateRepository {
+	return &OidcStateRepositoryImplementation{}
+}
+
+func ToOidcStateKey(stateHash string) string {
+	return "oidcState:" + stateHash
+}
+
+func (repository *OidcStateRepositoryImplementation) Create(client *redis.Client, ctx context.Context, stateHash string, oidcState models.OidcState, expiration time.Duration) (err error) {
+	oidcStateByte, err := json.Marshal(oidcState)
+	if err != nil {
+		return
+	}
+	_, err = client.Set(ctx, ToOidcStateKey(stateHash), string(oidcStateByte), expiration).Result()
+	return
+}
+
+// FindAndDelete uses the state up so it cannot be used twice, it returns redis.Nil when the state does not exist or has expired
+func (repository *OidcStateRepositoryImplementation) FindAndDelete(client *redis.Client, ctx context.Context, stateHash string) (oidcState models.OidcState, err error) {
+	value, err := client.GetDel(ctx, ToOidcStateKey(stateHash)).Result()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(value), &oidcState)
+	return
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TwoFactorChallengeRepository stores the hash of a pending two-factor challenge with the user id it belongs to,
+// the attempts made against a challenge are counted under a separate key expiring with it
+type TwoFactorChallengeRepository interface {
+	Create(client *redis.Client, ctx context.Context, challengeHash string, userId int32, expiration time.Duration) (err error)
+	FindUserId(client *redis.Client, ctx context.Context, challengeHash string) (userId int32, err error)
+	IncrementAttempt(client *redis.Client, ctx context.Context, challengeHash string, expiration time.Duration) (attempts int64, err error)
+	Delete(client *redis.Client, ctx context.Context, challengeHash string) (deleted bool, err error)
+}
+
+type TwoFactorChallengeRepositoryImplementation struct {
+}
+
+func NewTwoFactorChallengeRepository() TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepositoryImplementation{}
+}
+
+func ToTwoFactorChallengeKey(challengeHash string) string {
+	return "twoFactorChallenge:" + challengeHash
+}
+
+func ToTwoFactorChallengeAttemptsKey(challengeHash string) string {
+	return "twoFactorChallengeAttempts:" + challengeHash
+}
+
+func (repository *TwoFactorChallengeRepositoryImplementation) Create(client *redis.Client, ctx context.Context, challengeHash string, userId int32, expiration time.Duration) (err error) {
+	_, err = client.Set(ctx, ToTwoFactorChallengeKey(challengeHash), strconv.Itoa(int(userId)), expiration).Result()
+	return
+}
+
+// FindUserId returns redis.Nil when the challenge does not exist or has expired
+func (repository *TwoFactorChallengeRepositoryImplementation) FindUserId(client *redis.Client, ctx context.Context, challengeHash string) (userId int32, err error) {
+	value, err := client.Get(ctx, ToTwoFactorChallengeKey(challengeHash)).Result()
+	if err != nil {
+		return
+	}
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return
+	}
+	return int32(id), nil
+}
+
+func (repository *TwoFactorChallengeRepositoryImplementation) IncrementAttempt(client *redis.Client, ctx context.Context, challengeHash string, expiration time.Duration) (attempts int64, err error) {
+	key := ToTwoFactorChallengeAttemptsKey(challengeHash)
+	attempts, err = client.Incr(ctx, key).Result()
+	if err != nil {
+		return
+	}
+	if attempts == 1 {
+		_, err = client.Expire(ctx, key, expiration).Result()
+	}
+	return
+}
+
+// Delete reports whether this call removed the challenge, so of two concurrent verifications only one can win
+func (repository *TwoFactorChallengeRepositoryImplementation) Delete(client *redis.Client, ctx context.Context, challengeHash string) (deleted bool, err error) {
+	count, err := client.Del(ctx, ToTwoFactorChallengeKey(challengeHash)).Result()
+	if err != nil {
+		return
+	}
+	return count == 1, nil
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"backend-golang/features/users/login/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserIdentityRepository interface {
+	FindUserId(pool *pgxpool.Pool, ctx context.Context, provider string, subject string) (userId int32, err error)
+	Create(tx pgx.Tx, ctx context.Context, userIdentity models.UserIdentity) (err error)
+}
+
+type UserIdentityRepositoryImplementation struct {
+}
+
+func NewUserIdentityRepository() UserIdentityRepository {
+	return &UserIdentityRepositoryImplementation{}
+}
+
+func (repository *UserIdentityRepositoryImplementation) FindUserId(pool *pgxpool.Pool, ctx context.Context, provider string, subject string) (userId int32, err error) {
+	err = pool.QueryRow(ctx, `SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2;`, provider, subject).Scan(&userId)
+	return
+}
+
+func (repository *UserIdentityRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, userIdentity models.UserIdentity) (err error) {
+	_, err = tx.Exec(ctx, `INSERT INTO user_identities (user_id, provider, subject, created_at) VALUES ($1, $2, $3, $4);`, userIdentity.UserId, userIdentity.Provider, userIdentity.Subject, userIdentity.CreatedAt)
+	return
+}
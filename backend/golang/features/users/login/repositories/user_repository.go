@@ -4,11 +4,15 @@ import (
 	"backend-golang/features/users/login/models"
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository interface {
 	FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error)
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error)
+	RehashPassword(pool *pgxpool.Pool, ctx context.Context, id int32, currentPassword string, password string) (rowsAffected int64, err error)
+	Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error)
 }
 
 type UserRepositoryImplementation struct {
@@ -19,6 +23,23 @@ func NewUserRepository() UserRepository {
 }
 
 func (repository *UserRepositoryImplementation) FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error) {
-	err = pool.QueryRow(ctx, `SELECT id, username, email, password, created_at FROM users WHERE email = $1;`, email).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.CreatedAt)
+	err = pool.QueryRow(ctx, `SELECT id, username, email, password, created_at, email_verified_at, totp_secret, totp_enabled_at, disabled_at FROM users WHERE email = $1;`, email).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.EmailVerifiedAt, &user.TotpSecret, &user.TotpEnabledAt, &user.DisabledAt)
+	return
+}
+
+func (repository *UserRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email, password, created_at, email_verified_at, totp_secret, totp_enabled_at, disabled_at FROM users WHERE id = $1;`, id).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.EmailVerifiedAt, &user.TotpSecret, &user.TotpEnabledAt, &user.DisabledAt)
+	return
+}
+
+// RehashPassword only replaces the hash it was computed from, a password changed in the meantime is left alone
+func (repository *UserRepositoryImplementation) RehashPassword(pool *pgxpool.Pool, ctx context.Context, id int32, currentPassword string, password string) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET password = $1 WHERE id = $2 AND password = $3;`, password, id, currentPassword)
+	rowsAffected = result.RowsAffected()
+	return
+}
+
+func (repository *UserRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error) {
+	err = tx.QueryRow(ctx, `INSERT INTO users (username, email, password, created_at, email_verified_at) VALUES ($1, $2, $3, $4, $5) RETURNING id;`, user.Username, user.Email, user.Password, user.CreatedAt, user.EmailVerifiedAt).Scan(&id)
 	return
 }
@@ -3,6 +3,7 @@ package routes
 import (
 	"backend-golang/commons/helpers"
 	"backend-golang/commons/middlewares"
+	commonrepositories "backend-golang/commons/repositories"
 	"backend-golang/commons/utils"
 	"backend-golang/features/users/login/controllers"
 	"backend-golang/features/users/login/repositories"
@@ -12,10 +13,19 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func LoginRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper) {
+func LoginRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, twoFactorRepository commonrepositories.TwoFactorRepository, twoFactorHelper helpers.TwoFactorHelper, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper, passwordHasher helpers.PasswordHasher, authEventHelper helpers.AuthEventHelper, tokenHelper helpers.TokenHelper, oidcHelper helpers.OidcHelper) {
 	userRepository := repositories.NewUserRepository()
 	userPermissionRepository := repositories.NewUserPermissinoRepository()
-	loginService := services.NewLoginService(postgresUtil, redisUtil, validate, userRepository, userPermissionRepository, uuidHelper, redisHelper)
+	loginAttemptRepository := repositories.NewLoginAttemptRepository()
+	twoFactorChallengeRepository := repositories.NewTwoFactorChallengeRepository()
+	oidcStateRepository := repositories.NewOidcStateRepository()
+	userIdentityRepository := repositories.NewUserIdentityRepository()
+	loginService := services.NewLoginService(postgresUtil, redisUtil, validate, userRepository, userPermissionRepository, loginAttemptRepository, twoFactorChallengeRepository, oidcStateRepository, userIdentityRepository, twoFactorRepository, uuidHelper, redisHelper, sessionRegistryHelper, twoFactorHelper, jwtHelper, tokenFamilyHelper, passwordHasher, authEventHelper, tokenHelper, oidcHelper)
 	loginController := controllers.NewLoginController(loginService)
-	e.POST("/api/v1/users/login", loginController.Login, middlewares.PrintRequestResponseLog)
+	e.POST("/api/v1/users/login", loginController.Login, middlewares.PrintRequestResponseLog, middlewares.NoStore)
+	e.POST("/api/v1/users/login/2fa", loginController.VerifyTwoFactor, middlewares.PrintRequestResponseLog, middlewares.NoStore)
+	e.POST("/api/v1/users/token", loginController.LoginWithToken, middlewares.PrintRequestResponseLog, middlewares.NoStore)
+	e.POST("/api/v1/users/token/2fa", loginController.VerifyTwoFactorWithToken, middlewares.PrintRequestResponseLog, middlewares.NoStore)
+	e.GET("/api/v1/users/oidc/:provider", loginController.AuthorizeOidc, middlewares.PrintRequestResponseLogWithNoRequestBody)
+	e.POST("/api/v1/users/oidc/:provider", loginController.LoginWithOidc, middlewares.PrintRequestResponseLog, middlewares.NoStore)
 }
@@ -2,49 +2,139 @@ package services
 
 import (
 	"backend-golang/commons/helpers"
+	commonmodels "backend-golang/commons/models"
+	commonrepositories "backend-golang/commons/repositories"
 	"backend-golang/commons/utils"
 	"backend-golang/features/users/login/models"
 	"backend-golang/features/users/login/repositories"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"backend-golang/commons/middlewares"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
+)
+
+// failed logins are counted per email and per ip, once a counter reaches its maximum the email or ip is locked
+// for baseLoginLockDuration, doubled for every further failure up to maxLoginLockDuration
+const (
+	maxLoginFailuresPerEmail = 5
+	maxLoginFailuresPerIp    = 20
+	loginFailureWindow       = time.Hour
+	baseLoginLockDuration    = 30 * time.Second
+	maxLoginLockDuration     = time.Hour
+)
+
+// a user with two-factor enabled gets a challenge instead of a session, it has to be verified within
+// twoFactorChallengeLifetime and maxTwoFactorAttempts tries
+const (
+	twoFactorChallengeLifetime = 5 * time.Minute
+	maxTwoFactorAttempts       = 5
 )
 
 type LoginService interface {
-	Login(ctx context.Context, loginRequest models.LoginRequest) (sessionId string, httpCode int, response helpers.Response)
+	Login(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (sessionId string, retryAfter int, httpCode int, response helpers.Response)
+	VerifyTwoFactor(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response)
+	LoginWithToken(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (retryAfter int, httpCode int, response helpers.Response)
+	VerifyTwoFactorWithToken(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (httpCode int, response helpers.Response)
+	AuthorizeOidc(ctx context.Context, provider string) (state string, httpCode int, response helpers.Response)
+	LoginWithOidc(ctx context.Context, provider string, oidcLoginRequest models.OidcLoginRequest, stateCookie string, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response)
 }
 
 type LoginServiceImplementation struct {
-	PostgresUtil             utils.PostgresUtil
-	RedisUtil                utils.RedisUtil
-	Validate                 *validator.Validate
-	UserRepository           repositories.UserRepository
-	UserPermissionRepository repositories.UserPermissionRepository
-	UuidHelper               helpers.UuidHelper
-	RedisHelper              helpers.RedisHelper
+	PostgresUtil                 utils.PostgresUtil
+	RedisUtil                    utils.RedisUtil
+	Validate                     *validator.Validate
+	UserRepository               repositories.UserRepository
+	UserPermissionRepository     repositories.UserPermissionRepository
+	LoginAttemptRepository       repositories.LoginAttemptRepository
+	TwoFactorChallengeRepository repositories.TwoFactorChallengeRepository
+	OidcStateRepository          repositories.OidcStateRepository
+	UserIdentityRepository       repositories.UserIdentityRepository
+	TwoFactorRepository          commonrepositories.TwoFactorRepository
+	UuidHelper                   helpers.UuidHelper
+	RedisHelper                  helpers.RedisHelper
+	SessionRegistryHelper        helpers.SessionRegistryHelper
+	TwoFactorHelper              helpers.TwoFactorHelper
+	JwtHelper                    helpers.JwtHelper
+	TokenFamilyHelper            helpers.TokenFamilyHelper
+	PasswordHasher               helpers.PasswordHasher
+	AuthEventHelper              helpers.AuthEventHelper
+	TokenHelper                  helpers.TokenHelper
+	OidcHelper                   helpers.OidcHelper
 }
 
-func NewLoginService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, userPermissionRepository repositories.UserPermissionRepository, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper) LoginService {
+func NewLoginService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, userPermissionRepository repositories.UserPermissionRepository, loginAttemptRepository repositories.LoginAttemptRepository, twoFactorChallengeRepository repositories.TwoFactorChallengeRepository, oidcStateRepository repositories.OidcStateRepository, userIdentityRepository repositories.UserIdentityRepository, twoFactorRepository commonrepositories.TwoFactorRepository, uuidHelper helpers.UuidHelper, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, twoFactorHelper helpers.TwoFactorHelper, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper, passwordHasher helpers.PasswordHasher, authEventHelper helpers.AuthEventHelper, tokenHelper helpers.TokenHelper, oidcHelper helpers.OidcHelper) LoginService {
 	return &LoginServiceImplementation{
-		PostgresUtil:             postgresUtil,
-		RedisUtil:                redisUtil,
-		Validate:                 validate,
-		UserRepository:           userRepository,
-		UserPermissionRepository: userPermissionRepository,
-		UuidHelper:               uuidHelper,
-		RedisHelper:              redisHelper,
+		PostgresUtil:                 postgresUtil,
+		RedisUtil:                    redisUtil,
+		Validate:                     validate,
+		UserRepository:               userRepository,
+		UserPermissionRepository:     userPermissionRepository,
+		LoginAttemptRepository:       loginAttemptRepository,
+		TwoFactorChallengeRepository: twoFactorChallengeRepository,
+		OidcStateRepository:          oidcStateRepository,
+		UserIdentityRepository:       userIdentityRepository,
+		TwoFactorRepository:          twoFactorRepository,
+		UuidHelper:                   uuidHelper,
+		RedisHelper:                  redisHelper,
+		SessionRegistryHelper:        sessionRegistryHelper,
+		TwoFactorHelper:              twoFactorHelper,
+		JwtHelper:                    jwtHelper,
+		TokenFamilyHelper:            tokenFamilyHelper,
+		PasswordHasher:               passwordHasher,
+		AuthEventHelper:              authEventHelper,
+		TokenHelper:                  tokenHelper,
+		OidcHelper:                   oidcHelper,
+	}
+}
+
+func (service *LoginServiceImplementation) Login(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (sessionId string, retryAfter int, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	user, retryAfter, httpCode, response := service.authenticate(ctx, loginRequest, userAgent, ip)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		httpCode, response = service.createTwoFactorChallenge(ctx, requestId, user, userAgent, ip)
+		return
 	}
+
+	sessionId, httpCode, response = service.createSession(ctx, requestId, user, false, userAgent, ip)
+	return
+}
+
+// LoginWithToken is Login for clients which cannot keep cookies, it answers with an access and refresh token pair instead of a session
+func (service *LoginServiceImplementation) LoginWithToken(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (retryAfter int, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	user, retryAfter, httpCode, response := service.authenticate(ctx, loginRequest, userAgent, ip)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		httpCode, response = service.createTwoFactorChallenge(ctx, requestId, user, userAgent, ip)
+		return
+	}
+
+	httpCode, response = service.createTokens(ctx, requestId, user, false, userAgent, ip)
+	return
 }
 
-func (service *LoginServiceImplementation) Login(ctx context.Context, loginRequest models.LoginRequest) (sessionId string, httpCode int, response helpers.Response) {
+// authenticate checks the lockout, the password, whether the account is disabled and the email verification policy, httpCode is 200 when the user may log in
+func (service *LoginServiceImplementation) authenticate(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (user models.User, retryAfter int, httpCode int, response helpers.Response) {
 	requestId := ctx.Value(middlewares.RequestIdKey).(string)
 	var err error
 	err = service.Validate.Struct(loginRequest)
@@ -56,51 +146,537 @@ func (service *LoginServiceImplementation) Login(ctx context.Context, loginReque
 		}
 	}
 
-	user, err := service.UserRepository.FindByEmail(service.PostgresUtil.GetPool(), ctx, loginRequest.Email)
+	email := helpers.NormalizeEmail(loginRequest.Email)
+	retryAfter, err = service.findRetryAfter(ctx, email, ip)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if retryAfter > 0 {
+		service.recordAuthEvent(ctx, helpers.LoginLockedEvent, 0, email, userAgent, ip)
+		err = errors.New("login is locked for email " + email + " or ip " + ip)
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusTooManyRequests, "too many failed login attempts, please try again later")
+		return
+	}
+
+	user, err = service.UserRepository.FindByEmail(service.PostgresUtil.GetPool(), ctx, email)
 	if err != nil && err != pgx.ErrNoRows {
 		httpCode, response = helpers.ToResponseCheckError(err, requestId)
 		return
 	} else if err != nil && err == pgx.ErrNoRows {
-		err = errors.New("wrong email or password")
-		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "wrong email or password")
+		// an unknown email still pays for a password comparison, otherwise the response time tells which emails are registered
+		service.compareDummyHash(requestId, loginRequest.Password)
+		service.recordAuthEvent(ctx, helpers.LoginFailedEvent, 0, email, userAgent, ip)
+		httpCode, response = service.toResponseWrongEmailOrPassword(ctx, requestId, email, ip)
 		return
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password.String), []byte(loginRequest.Password))
+	err = service.PasswordHasher.Compare(user.Password.String, loginRequest.Password)
 	if err != nil {
-		err = errors.New("wrong email or password")
-		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "wrong email or password")
+		service.recordAuthEvent(ctx, helpers.LoginFailedEvent, user.Id.Int32, email, userAgent, ip)
+		httpCode, response = service.toResponseWrongEmailOrPassword(ctx, requestId, email, ip)
+		return
+	}
+	// only told after the password matched, so it does not tell anyone else the email is registered
+	if user.DisabledAt.Valid {
+		service.recordAuthEvent(ctx, helpers.LoginDisabledEvent, user.Id.Int32, email, userAgent, ip)
+		err = errors.New("user " + email + " is disabled")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, "account is disabled")
 		return
 	}
 
-	userPermissions, err := service.UserPermissionRepository.FindByUserId(service.PostgresUtil.GetPool(), ctx, user.Id.Int32)
+	// the ip keeps its count until it expires, otherwise logging into an own account every few tries would let one ip guess the passwords of others
+	err = service.LoginAttemptRepository.Reset(service.RedisUtil.GetClient(), ctx, repositories.ToLoginFailureKey("email", email))
 	if err != nil {
 		httpCode, response = helpers.ToResponseCheckError(err, requestId)
 		return
 	}
-	var idPermissions []int32
-	for _, userPermission := range userPermissions {
-		idPermissions = append(idPermissions, userPermission.PermissionId.Int32)
+	service.rehashPassword(ctx, requestId, user, loginRequest.Password)
+
+	emailVerificationPolicy, err := helpers.GetEmailVerificationPolicy()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !emailVerificationPolicy.AllowsLogin(user.CreatedAt.Int64, user.EmailVerifiedAt.Valid, time.Now()) {
+		service.recordAuthEvent(ctx, helpers.LoginUnverifiedEvent, user.Id.Int32, email, userAgent, ip)
+		err = errors.New("email " + email + " is not verified")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, "please verify your email before logging in, check your inbox for the verification link")
+		return
+	}
+
+	httpCode = http.StatusOK
+	return
+}
+
+// compareDummyHash never matches, its error is only logged since the answer is a wrong email or password either way
+func (service *LoginServiceImplementation) compareDummyHash(requestId string, password string) {
+	dummyHash, err := service.PasswordHasher.DummyHash()
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	service.PasswordHasher.Compare(dummyHash, password)
+}
+
+// rehashPassword replaces a hash made with an outdated algorithm or cost while the plain password is at hand,
+// the old hash keeps working so a failed rehash is only logged and does not fail the login
+func (service *LoginServiceImplementation) rehashPassword(ctx context.Context, requestId string, user models.User, password string) {
+	needsRehash, err := service.PasswordHasher.NeedsRehash(user.Password.String)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	if !needsRehash {
+		return
+	}
+
+	hash, err := service.PasswordHasher.Hash(password)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	_, err = service.UserRepository.RehashPassword(service.PostgresUtil.GetPool(), ctx, user.Id.Int32, user.Password.String, hash)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+}
+
+// VerifyTwoFactor exchanges a pending challenge and a totp or recovery code for the session login would have given
+func (service *LoginServiceImplementation) VerifyTwoFactor(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	user, httpCode, response := service.verifyTwoFactor(ctx, verifyTwoFactorRequest, userAgent, ip)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	sessionId, httpCode, response = service.createSession(ctx, requestId, user, true, userAgent, ip)
+	return
+}
+
+// VerifyTwoFactorWithToken is VerifyTwoFactor answering with a token pair
+func (service *LoginServiceImplementation) VerifyTwoFactorWithToken(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	user, httpCode, response := service.verifyTwoFactor(ctx, verifyTwoFactorRequest, userAgent, ip)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	httpCode, response = service.createTokens(ctx, requestId, user, true, userAgent, ip)
+	return
+}
+
+// verifyTwoFactor uses up the challenge when the code is right, httpCode is 200 when the user may log in
+func (service *LoginServiceImplementation) verifyTwoFactor(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (user models.User, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(verifyTwoFactorRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, verifyTwoFactorRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	challengeHash := helpers.ToTokenHash(verifyTwoFactorRequest.ChallengeId)
+	userId, err := service.TwoFactorChallengeRepository.FindUserId(service.RedisUtil.GetClient(), ctx, challengeHash)
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		httpCode, response = service.toResponseInvalidChallenge(requestId)
+		return
+	}
+
+	attempts, err := service.TwoFactorChallengeRepository.IncrementAttempt(service.RedisUtil.GetClient(), ctx, challengeHash, twoFactorChallengeLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if attempts > maxTwoFactorAttempts {
+		service.recordAuthEvent(ctx, helpers.TwoFactorFailedEvent, userId, "", userAgent, ip)
+		_, err = service.TwoFactorChallengeRepository.Delete(service.RedisUtil.GetClient(), ctx, challengeHash)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+		httpCode, response = service.toResponseInvalidChallenge(requestId)
+		return
+	}
+
+	user, err = service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if (err != nil && err == pgx.ErrNoRows) || !user.TotpEnabledAt.Valid || user.DisabledAt.Valid {
+		httpCode, response = service.toResponseInvalidChallenge(requestId)
+		return
+	}
+
+	valid, err := service.verifyCode(ctx, user.Id.Int32, user.TotpSecret.String, verifyTwoFactorRequest.Code)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !valid {
+		service.recordAuthEvent(ctx, helpers.TwoFactorFailedEvent, user.Id.Int32, user.Email.String, userAgent, ip)
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "code", Message: "code is invalid"}})
+		return
+	}
+
+	deleted, err := service.TwoFactorChallengeRepository.Delete(service.RedisUtil.GetClient(), ctx, challengeHash)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !deleted {
+		httpCode, response = service.toResponseInvalidChallenge(requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	return
+}
+
+// verifyCode takes either a totp code, which cannot be used again once accepted, or an unused recovery code, which is used up
+func (service *LoginServiceImplementation) verifyCode(ctx context.Context, userId int32, encryptedSecret string, code string) (valid bool, err error) {
+	secret, err := service.TwoFactorHelper.DecryptSecret(encryptedSecret)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	var rowsAffected int64
+	step, valid := service.TwoFactorHelper.ValidateTotp(secret, code, now)
+	if valid {
+		rowsAffected, err = service.TwoFactorRepository.UpdateTotpLastUsedStep(service.PostgresUtil.GetPool(), ctx, userId, step)
+	} else {
+		rowsAffected, err = service.TwoFactorRepository.UseRecoveryCode(service.PostgresUtil.GetPool(), ctx, userId, service.TwoFactorHelper.ToRecoveryCodeHash(code), now.UnixMilli())
+	}
+	if err != nil {
+		return
+	}
+	return rowsAffected == 1, nil
+}
+
+// AuthorizeOidc starts a sign in at provider, the state it returns is also set as the oidcState cookie by the controller
+func (service *LoginServiceImplementation) AuthorizeOidc(ctx context.Context, provider string) (state string, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	oidcProvider, err := helpers.GetOidcProvider(provider)
+	if err != nil && err != helpers.ErrOidcProviderNotFound {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == helpers.ErrOidcProviderNotFound {
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "identity provider not found")
+		return
+	}
+
+	oidcState := models.OidcState{
+		Provider: provider,
+	}
+	state, err = service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return "", httpCode, response
+	}
+	oidcState.Nonce, err = service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return "", httpCode, response
+	}
+	oidcState.CodeVerifier, err = service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return "", httpCode, response
+	}
+
+	err = service.OidcStateRepository.Create(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(state), oidcState, helpers.OidcStateLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return "", httpCode, response
+	}
+
+	httpCode = http.StatusOK
+	oidcAuthorizationResponse := models.OidcAuthorizationResponse{
+		AuthorizationUrl: helpers.ToOidcAuthorizationUrl(oidcProvider, state, oidcState.Nonce, oidcState.CodeVerifier),
+	}
+	response = helpers.Response{
+		Data:   oidcAuthorizationResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// LoginWithOidc finishes a sign in at provider with the same session as Login, stateCookie is the oidcState cookie of the browser
+// which must match the state sent back by the provider
+func (service *LoginServiceImplementation) LoginWithOidc(ctx context.Context, provider string, oidcLoginRequest models.OidcLoginRequest, stateCookie string, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(oidcLoginRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, oidcLoginRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	oidcProvider, err := helpers.GetOidcProvider(provider)
+	if err != nil && err != helpers.ErrOidcProviderNotFound {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == helpers.ErrOidcProviderNotFound {
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "identity provider not found")
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stateCookie), []byte(oidcLoginRequest.State)) != 1 {
+		httpCode, response = service.toResponseInvalidState(requestId)
+		return
+	}
+	oidcState, err := service.OidcStateRepository.FindAndDelete(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(oidcLoginRequest.State))
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		httpCode, response = service.toResponseInvalidState(requestId)
+		return
+	}
+	if oidcState.Provider != provider {
+		httpCode, response = service.toResponseInvalidState(requestId)
+		return
+	}
+
+	idToken, err := service.OidcHelper.ExchangeCode(ctx, oidcProvider, oidcLoginRequest.Code, oidcState.CodeVerifier)
+	if err != nil {
+		httpCode, response = service.toResponseOidcError(err, requestId)
+		return
+	}
+	oidcClaims, err := service.OidcHelper.VerifyIdToken(ctx, oidcProvider, idToken, oidcState.Nonce)
+	if err != nil {
+		httpCode, response = service.toResponseOidcError(err, requestId)
+		return
+	}
+
+	user, httpCode, response := service.findOrCreateOidcUser(ctx, requestId, provider, oidcClaims)
+	if httpCode != http.StatusOK {
+		return
+	}
+	if user.DisabledAt.Valid {
+		service.recordAuthEvent(ctx, helpers.LoginDisabledEvent, user.Id.Int32, user.Email.String, userAgent, ip)
+		err = errors.New("user " + user.Email.String + " is disabled")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, "account is disabled")
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		httpCode, response = service.createTwoFactorChallenge(ctx, requestId, user, userAgent, ip)
+		return
+	}
+
+	sessionId, httpCode, response = service.createSession(ctx, requestId, user, false, userAgent, ip)
+	return
+}
+
+// findOrCreateOidcUser returns the user linked to the subject, a subject seen for the first time is linked to the user with the same email
+// or to a new user, only when the provider verified the email and, for an existing user, the user verified it too,
+// otherwise whoever registered the email first without owning it would share the account
+func (service *LoginServiceImplementation) findOrCreateOidcUser(ctx context.Context, requestId string, provider string, oidcClaims helpers.OidcClaims) (user models.User, httpCode int, response helpers.Response) {
+	userId, err := service.UserIdentityRepository.FindUserId(service.PostgresUtil.GetPool(), ctx, provider, oidcClaims.Subject)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err == nil {
+		user, err = service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+		httpCode = http.StatusOK
+		return
+	}
+
+	if oidcClaims.Email == "" || !oidcClaims.EmailVerified {
+		err = errors.New("email of subject " + oidcClaims.Subject + " of " + provider + " is missing or not verified")
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, "the identity provider did not share a verified email")
+		return
+	}
+	email := helpers.NormalizeEmail(oidcClaims.Email)
+	now := time.Now()
+	user, err = service.UserRepository.FindByEmail(service.PostgresUtil.GetPool(), ctx, email)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err == nil && !user.EmailVerifiedAt.Valid {
+		err = errors.New("user " + email + " has not verified the email, cannot link " + provider)
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusConflict, "an account with this email already exists, log in with its password and verify the email first")
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		user, httpCode, response = service.toOidcUser(requestId, email, now)
+		if httpCode != http.StatusOK {
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	if !user.Id.Valid {
+		user.Id.Int32, err = service.UserRepository.Create(tx, ctx, user)
+		if err != nil {
+			httpCode, response = service.toResponseOidcLinkError(err, requestId)
+			return
+		}
+		user.Id.Valid = true
+	}
+	err = service.UserIdentityRepository.Create(tx, ctx, models.UserIdentity{
+		UserId:    user.Id.Int32,
+		Provider:  provider,
+		Subject:   oidcClaims.Subject,
+		CreatedAt: now.UnixMilli(),
+	})
+	if err != nil {
+		httpCode, response = service.toResponseOidcLinkError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	return
+}
+
+// toOidcUser is a new user signing in with a provider, its password is a random one nobody knows until it is reset
+func (service *LoginServiceImplementation) toOidcUser(requestId string, email string, now time.Time) (user models.User, httpCode int, response helpers.Response) {
+	password, err := service.TokenHelper.Generate()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	hash, err := service.PasswordHasher.Hash(password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	user = models.User{
+		Username:        pgtype.Text{Valid: true, String: "user_" + strings.ReplaceAll(service.UuidHelper.String(), "-", "")},
+		Email:           pgtype.Text{Valid: true, String: email},
+		Password:        pgtype.Text{Valid: true, String: hash},
+		CreatedAt:       pgtype.Int8{Valid: true, Int64: now.UnixMilli()},
+		EmailVerifiedAt: pgtype.Int8{Valid: true, Int64: now.UnixMilli()},
+	}
+	httpCode = http.StatusOK
+	return
+}
+
+func (service *LoginServiceImplementation) toResponseInvalidState(requestId string) (httpCode int, response helpers.Response) {
+	return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "state", Message: "state is invalid or expired"}})
+}
+
+func (service *LoginServiceImplementation) toResponseOidcError(err error, requestId string) (httpCode int, response helpers.Response) {
+	if errors.Is(err, helpers.ErrOidcRejected) {
+		return helpers.ToResponseError(err, requestId, http.StatusBadRequest, "sign in with the identity provider failed, please try again")
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+// toResponseOidcLinkError answers a concurrent first sign in of the same subject or email, which lost on a unique constraint
+func (service *LoginServiceImplementation) toResponseOidcLinkError(err error, requestId string) (httpCode int, response helpers.Response) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == "23505" {
+		return helpers.ToResponseError(err, requestId, http.StatusConflict, "sign in is already in progress, please try again")
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+func (service *LoginServiceImplementation) createTwoFactorChallenge(ctx context.Context, requestId string, user models.User, userAgent string, ip string) (httpCode int, response helpers.Response) {
+	challengeId := service.UuidHelper.String()
+	err := service.TwoFactorChallengeRepository.Create(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(challengeId), user.Id.Int32, twoFactorChallengeLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	service.recordAuthEvent(ctx, helpers.TwoFactorChallengedEvent, user.Id.Int32, user.Email.String, userAgent, ip)
+
+	httpCode = http.StatusOK
+	twoFactorChallengeResponse := models.TwoFactorChallengeResponse{
+		Message:     "two-factor authentication required",
+		ChallengeId: challengeId,
+	}
+	response = helpers.Response{
+		Data:   twoFactorChallengeResponse,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *LoginServiceImplementation) toResponseInvalidChallenge(requestId string) (httpCode int, response helpers.Response) {
+	return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "challengeId", Message: "challenge is invalid or expired"}})
+}
+
+func (service *LoginServiceImplementation) toSession(ctx context.Context, user models.User, twoFactor bool, now time.Time) (session helpers.Session, err error) {
+	idPermissions, err := service.UserPermissionRepository.FindPermissionIdsByUserId(service.PostgresUtil.GetPool(), ctx, user.Id.Int32)
+	if err != nil {
+		return
+	}
+	session = helpers.Session{
+		Id:            user.Id.Int32,
+		Username:      user.Username.String,
+		Email:         user.Email.String,
+		IdPermissions: idPermissions,
+		CreatedAt:     now.UnixMilli(),
+		TwoFactor:     twoFactor,
+	}
+	return
+}
+
+func (service *LoginServiceImplementation) createSession(ctx context.Context, requestId string, user models.User, twoFactor bool, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	now := time.Now()
+	session, err := service.toSession(ctx, user, twoFactor, now)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	sessionLifetime, err := helpers.GetSessionLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
 	}
 
 	sessionId = service.UuidHelper.String()
-	sessionValue := make(map[string]interface{})
-	sessionValue["id"] = user.Id.Int32
-	sessionValue["username"] = user.Username.String
-	sessionValue["email"] = user.Email.String
-	sessionValue["idPermissions"] = idPermissions
-	sessionByte, err := json.Marshal(sessionValue)
+	sessionByte, err := json.Marshal(session)
 	if err != nil {
 		httpCode, response = helpers.ToResponseCheckError(err, requestId)
 		return
 	}
-	session := string(sessionByte)
 
-	_, err = service.RedisHelper.Set(service.RedisUtil.GetClient(), ctx, sessionId, session, 0)
+	// registering before storing the session is safe, entries without a session are pruned when the registry is read
+	sessionInfo := helpers.SessionInfo{
+		SessionId: sessionId,
+		CreatedAt: now.UnixMilli(),
+		UserAgent: userAgent,
+		Ip:        ip,
+	}
+	err = service.SessionRegistryHelper.Register(service.RedisUtil.GetClient(), ctx, user.Id.Int32, sessionInfo, sessionLifetime.AbsoluteLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	_, err = service.RedisHelper.Set(service.RedisUtil.GetClient(), ctx, sessionId, string(sessionByte), sessionLifetime.Ttl(now, now))
 	if err != nil && err != redis.Nil {
 		httpCode, response = helpers.ToResponseCheckError(err, requestId)
 		return
 	}
+	service.recordAuthEvent(ctx, helpers.LoginSucceededEvent, user.Id.Int32, user.Email.String, userAgent, ip)
 
 	httpCode = http.StatusOK
 	responseMessage := helpers.ResponseMessage{
@@ -112,3 +688,137 @@ func (service *LoginServiceImplementation) Login(ctx context.Context, loginReque
 	}
 	return
 }
+
+// createTokens starts a new token family, the family is registered like a session so it is listed and revoked with the sessions
+func (service *LoginServiceImplementation) createTokens(ctx context.Context, requestId string, user models.User, twoFactor bool, userAgent string, ip string) (httpCode int, response helpers.Response) {
+	now := time.Now()
+	session, err := service.toSession(ctx, user, twoFactor, now)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	jwtLifetime, err := helpers.GetJwtLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	familyId := service.UuidHelper.String()
+	refreshTokenId := service.UuidHelper.String()
+	accessTokenClaims, refreshTokenClaims := helpers.ToTokenClaims(session, familyId, service.UuidHelper.String(), refreshTokenId, now, jwtLifetime)
+	accessToken, err := service.JwtHelper.SignAccessToken(accessTokenClaims)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	refreshToken, err := service.JwtHelper.SignRefreshToken(refreshTokenClaims)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	sessionInfo := helpers.SessionInfo{
+		SessionId: helpers.ToTokenFamilyKey(familyId),
+		CreatedAt: now.UnixMilli(),
+		UserAgent: userAgent,
+		Ip:        ip,
+	}
+	err = service.SessionRegistryHelper.Register(service.RedisUtil.GetClient(), ctx, user.Id.Int32, sessionInfo, jwtLifetime.RefreshTokenLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.TokenFamilyHelper.Create(service.RedisUtil.GetClient(), ctx, familyId, refreshTokenId, session, jwtLifetime.RefreshTokenLifetime)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	service.recordAuthEvent(ctx, helpers.LoginSucceededEvent, user.Id.Int32, user.Email.String, userAgent, ip)
+
+	httpCode = http.StatusOK
+	tokenResponse := helpers.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    accessTokenClaims.ExpiresAt.Unix() - now.Unix(),
+	}
+	response = helpers.Response{
+		Data:   tokenResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// recordAuthEvent hands the event to the AuthEventHelper, which writes it after the response is sent
+func (service *LoginServiceImplementation) recordAuthEvent(ctx context.Context, eventType string, userId int32, email string, userAgent string, ip string) {
+	service.AuthEventHelper.Record(service.PostgresUtil.GetPool(), ctx, commonmodels.AuthEvent{
+		EventType: eventType,
+		UserId:    userId,
+		Email:     email,
+		Ip:        ip,
+		UserAgent: userAgent,
+		RequestId: ctx.Value(middlewares.RequestIdKey).(string),
+		CreatedAt: time.Now().UnixMilli(),
+	})
+}
+
+type loginAttemptLimit struct {
+	kind        string
+	value       string
+	maxFailures int64
+}
+
+func (service *LoginServiceImplementation) toLoginAttemptLimits(email string, ip string) (loginAttemptLimits []loginAttemptLimit) {
+	loginAttemptLimits = append(loginAttemptLimits, loginAttemptLimit{kind: "email", value: email, maxFailures: maxLoginFailuresPerEmail})
+	if ip != "" {
+		loginAttemptLimits = append(loginAttemptLimits, loginAttemptLimit{kind: "ip", value: ip, maxFailures: maxLoginFailuresPerIp})
+	}
+	return
+}
+
+// findRetryAfter returns the seconds left on the longest lock of the email or the ip, 0 when neither is locked
+func (service *LoginServiceImplementation) findRetryAfter(ctx context.Context, email string, ip string) (retryAfter int, err error) {
+	for _, loginAttemptLimit := range service.toLoginAttemptLimits(email, ip) {
+		var ttl time.Duration
+		ttl, err = service.LoginAttemptRepository.FindLockTtl(service.RedisUtil.GetClient(), ctx, repositories.ToLoginLockKey(loginAttemptLimit.kind, loginAttemptLimit.value))
+		if err != nil {
+			return
+		}
+		seconds := int(math.Ceil(ttl.Seconds()))
+		if seconds > retryAfter {
+			retryAfter = seconds
+		}
+	}
+	return
+}
+
+func (service *LoginServiceImplementation) toResponseWrongEmailOrPassword(ctx context.Context, requestId string, email string, ip string) (httpCode int, response helpers.Response) {
+	for _, loginAttemptLimit := range service.toLoginAttemptLimits(email, ip) {
+		failures, err := service.LoginAttemptRepository.IncrementFailure(service.RedisUtil.GetClient(), ctx, repositories.ToLoginFailureKey(loginAttemptLimit.kind, loginAttemptLimit.value), loginFailureWindow)
+		if err != nil {
+			return helpers.ToResponseCheckError(err, requestId)
+		}
+		if failures < loginAttemptLimit.maxFailures {
+			continue
+		}
+
+		lockDuration := baseLoginLockDuration
+		for i := loginAttemptLimit.maxFailures; i < failures && lockDuration < maxLoginLockDuration; i++ {
+			lockDuration *= 2
+		}
+		if lockDuration > maxLoginLockDuration {
+			lockDuration = maxLoginLockDuration
+		}
+		err = service.LoginAttemptRepository.Lock(service.RedisUtil.GetClient(), ctx, repositories.ToLoginLockKey(loginAttemptLimit.kind, loginAttemptLimit.value), lockDuration)
+		if err != nil {
+			return helpers.ToResponseCheckError(err, requestId)
+		}
+		err = errors.New("login locked for " + loginAttemptLimit.kind + " " + loginAttemptLimit.value + " for " + lockDuration.String() + " after " + strconv.FormatInt(failures, 10) + " failed attempts")
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+
+	err := errors.New("wrong email or password")
+	return helpers.ToResponseError(err, requestId, http.StatusBadRequest, "wrong email or password")
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/logout/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LogoutController interface {
+	Logout(c echo.Context) error
+}
+
+type LogoutControllerImplementation struct {
+	LogoutService services.LogoutService
+}
+
+func NewLogoutController(logoutService services.LogoutService) LogoutController {
+	return &LogoutControllerImplementation{
+		LogoutService: logoutService,
+	}
+}
+
+func (controller *LogoutControllerImplementation) Logout(c echo.Context) error {
+	httpCode, response := controller.LogoutService.Logout(c.Request().Context())
+	if httpCode != http.StatusOK {
+		return c.JSON(httpCode, response)
+	}
+
+	cookie, err := helpers.ToExpiredSessionCookie()
+	if err != nil {
+		httpCode, response = helpers.ToResponseInternalServerError()
+		return c.JSON(httpCode, response)
+	}
+	// the session middleware has already slid the cookie forward, the expired cookie replaces it
+	c.Response().Header().Del(echo.HeaderSetCookie)
+	c.SetCookie(cookie)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/logout/controllers"
+	"backend-golang/features/users/logout/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+func LogoutRoute(e *echo.Echo, redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, optionalSessionMiddleware middlewares.SessionMiddleware) {
+	logoutService := services.NewLogoutService(redisUtil, redisHelper, sessionRegistryHelper)
+	logoutController := controllers.NewLogoutController(logoutService)
+	e.POST("/api/v1/users/logout", logoutController.Logout, middlewares.PrintRequestResponseLogWithNoRequestBody, optionalSessionMiddleware.Authenticate)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"context"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LogoutService interface {
+	Logout(ctx context.Context) (httpCode int, response helpers.Response)
+}
+
+type LogoutServiceImplementation struct {
+	RedisUtil             utils.RedisUtil
+	RedisHelper           helpers.RedisHelper
+	SessionRegistryHelper helpers.SessionRegistryHelper
+}
+
+func NewLogoutService(redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper) LogoutService {
+	return &LogoutServiceImplementation{
+		RedisUtil:             redisUtil,
+		RedisHelper:           redisHelper,
+		SessionRegistryHelper: sessionRegistryHelper,
+	}
+}
+
+// Logout is behind the optional session middleware, the csrf middleware has already checked the token of a live session
+func (service *LogoutServiceImplementation) Logout(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	// logging out without a session or with an expired one is not an error, the cookie is cleared anyway
+	sessionId, ok := ctx.Value(middlewares.SessionIdKey).(string)
+	if ok {
+		_, err := service.RedisHelper.Del(service.RedisUtil.GetClient(), ctx, sessionId)
+		if err != nil && err != redis.Nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+
+		userId := ctx.Value(middlewares.IdKey).(int32)
+		err = service.SessionRegistryHelper.Unregister(service.RedisUtil.GetClient(), ctx, userId, sessionId)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully logout",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
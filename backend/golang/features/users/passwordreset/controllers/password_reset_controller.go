@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/passwordreset/models"
+	"backend-golang/features/users/passwordreset/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PasswordResetController interface {
+	Forgot(c echo.Context) error
+	Reset(c echo.Context) error
+}
+
+type PasswordResetControllerImplementation struct {
+	PasswordResetService services.PasswordResetService
+}
+
+func NewPasswordResetController(passwordResetService services.PasswordResetService) PasswordResetController {
+	return &PasswordResetControllerImplementation{
+		PasswordResetService: passwordResetService,
+	}
+}
+
+func (controller *PasswordResetControllerImplementation) Forgot(c echo.Context) error {
+	var forgotPasswordRequest models.ForgotPasswordRequest
+	err := c.Bind(&forgotPasswordRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PasswordResetService.Forgot(c.Request().Context(), forgotPasswordRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PasswordResetControllerImplementation) Reset(c echo.Context) error {
+	var resetPasswordRequest models.ResetPasswordRequest
+	err := c.Bind(&resetPasswordRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PasswordResetService.Reset(c.Request().Context(), resetPasswordRequest)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,5 @@
+package models
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type ResetPasswordRequest struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required,passwordvalidator"`
+	Confirmpassword string `json:"confirmpassword" validate:"required,eqfield=Password"`
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PasswordResetTokenRepository stores the hash of a reset token with the user id it belongs to
+type PasswordResetTokenRepository interface {
+	Create(client *redis.Client, ctx context.Context, tokenHash string, userId int32, expiration time.Duration) (err error)
+	FindAndDeleteUserId(client *redis.Client, ctx context.Context, tokenHash string) (userId int32, err error)
+}
+
+type PasswordResetTokenRepositoryImplementation struct {
+}
+
+func NewPasswordResetTokenRepository() PasswordResetTokenRepository {
+	return &PasswordResetTokenRepositoryImplementation{}
+}
+
+func ToPasswordResetTokenKey(tokenHash string) string {
+	return "passwordResetToken:" + tokenHash
+}
+
+func (repository *PasswordResetTokenRepositoryImplementation) Create(client *redis.Client, ctx context.Context, tokenHash string, userId int32, expiration time.Duration) (err error) {
+	_, err = client.Set(ctx, ToPasswordResetTokenKey(tokenHash), strconv.Itoa(int(userId)), expiration).Result()
+	return
+}
+
+// FindAndDeleteUserId reads and deletes the token in one command so a token can only be used once, redis.Nil when it does not exist
+func (repository *PasswordResetTokenRepositoryImplementation) FindAndDeleteUserId(client *redis.Client, ctx context.Context, tokenHash string) (userId int32, err error) {
+	value, err := client.GetDel(ctx, ToPasswordResetTokenKey(tokenHash)).Result()
+	if err != nil {
+		return
+	}
+	id, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"backend-golang/features/users/passwordreset/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error)
+	UpdatePassword(pool *pgxpool.Pool, ctx context.Context, id int32, password string) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) FindByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email FROM users WHERE email = $1;`, email).Scan(&user.Id, &user.Username, &user.Email)
+	return
+}
+
+func (repository *UserRepositoryImplementation) UpdatePassword(pool *pgxpool.Pool, ctx context.Context, id int32, password string) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET password = $1 WHERE id = $2;`, password, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/passwordreset/controllers"
+	"backend-golang/features/users/passwordreset/repositories"
+	"backend-golang/features/users/passwordreset/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func PasswordResetRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, tokenHelper helpers.TokenHelper, sessionRegistryHelper helpers.SessionRegistryHelper, mailer utils.Mailer) {
+	userRepository := repositories.NewUserRepository()
+	passwordResetTokenRepository := repositories.NewPasswordResetTokenRepository()
+	passwordResetService := services.NewPasswordResetService(postgresUtil, redisUtil, validate, userRepository, passwordResetTokenRepository, passwordHasher, tokenHelper, sessionRegistryHelper, mailer)
+	passwordResetController := controllers.NewPasswordResetController(passwordResetService)
+	e.POST("/api/v1/users/password/forgot", passwordResetController.Forgot, middlewares.PrintRequestResponseLog)
+	e.POST("/api/v1/users/password/reset", passwordResetController.Reset, middlewares.PrintRequestResponseLog)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/passwordreset/models"
+	"backend-golang/features/users/passwordreset/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+const passwordResetTokenLifetime = 30 * time.Minute
+
+type PasswordResetService interface {
+	Forgot(ctx context.Context, forgotPasswordRequest models.ForgotPasswordRequest) (httpCode int, response helpers.Response)
+	Reset(ctx context.Context, resetPasswordRequest models.ResetPasswordRequest) (httpCode int, response helpers.Response)
+}
+
+type PasswordResetServiceImplementation struct {
+	PostgresUtil                 utils.PostgresUtil
+	RedisUtil                    utils.RedisUtil
+	Validate                     *validator.Validate
+	UserRepository               repositories.UserRepository
+	PasswordResetTokenRepository repositories.PasswordResetTokenRepository
+	PasswordHasher               helpers.PasswordHasher
+	TokenHelper                  helpers.TokenHelper
+	SessionRegistryHelper        helpers.SessionRegistryHelper
+	Mailer                       utils.Mailer
+}
+
+func NewPasswordResetService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, passwordResetTokenRepository repositories.PasswordResetTokenRepository, passwordHasher helpers.PasswordHasher, tokenHelper helpers.TokenHelper, sessionRegistryHelper helpers.SessionRegistryHelper, mailer utils.Mailer) PasswordResetService {
+	return &PasswordResetServiceImplementation{
+		PostgresUtil:                 postgresUtil,
+		RedisUtil:                    redisUtil,
+		Validate:                     validate,
+		UserRepository:               userRepository,
+		PasswordResetTokenRepository: passwordResetTokenRepository,
+		PasswordHasher:               passwordHasher,
+		TokenHelper:                  tokenHelper,
+		SessionRegistryHelper:        sessionRegistryHelper,
+		Mailer:                       mailer,
+	}
+}
+
+// Forgot answers the same whether the email is registered or not, so it cannot be used to find registered emails
+func (service *PasswordResetServiceImplementation) Forgot(ctx context.Context, forgotPasswordRequest models.ForgotPasswordRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(forgotPasswordRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, forgotPasswordRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "if the email is registered, a password reset link has been sent",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+
+	user, err := service.UserRepository.FindByEmail(service.PostgresUtil.GetPool(), ctx, helpers.NormalizeEmail(forgotPasswordRequest.Email))
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		return
+	}
+
+	// from here on errors are only logged, an error answer would only ever be given for a registered email
+	token, err := service.TokenHelper.Generate()
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	err = service.PasswordResetTokenRepository.Create(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(token), user.Id.Int32, passwordResetTokenLifetime)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+
+	mail := utils.Mail{
+		To:      user.Email.String,
+		Subject: "Reset your password",
+		Body:    "Hi " + user.Username.String + ",\n\nopen this link within " + passwordResetTokenLifetime.String() + " to reset your password:\n" + toResetPasswordLink(token) + "\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+	}
+	err = service.Mailer.Send(ctx, mail)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+	return
+}
+
+func (service *PasswordResetServiceImplementation) Reset(ctx context.Context, resetPasswordRequest models.ResetPasswordRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(resetPasswordRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, resetPasswordRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	userId, err := service.PasswordResetTokenRepository.FindAndDeleteUserId(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(resetPasswordRequest.Token))
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+		return
+	}
+
+	password, err := service.PasswordHasher.Hash(resetPasswordRequest.Password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	rowsAffected, err := service.UserRepository.UpdatePassword(service.PostgresUtil.GetPool(), ctx, userId, password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("rows affected not one when updating password")
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully reset password",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func toResetPasswordLink(token string) string {
+	return os.Getenv("ECOMMERCEV2_PASSWORD_RESET_URL") + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PermissionController interface {
+	FindAll(c echo.Context) error
+	FindById(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+type PermissionControllerImplementation struct {
+	PermissionService services.PermissionService
+}
+
+func NewPermissionController(permissionService services.PermissionService) PermissionController {
+	return &PermissionControllerImplementation{
+		PermissionService: permissionService,
+	}
+}
+
+func (controller *PermissionControllerImplementation) FindAll(c echo.Context) error {
+	httpCode, response := controller.PermissionService.FindAll(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PermissionControllerImplementation) FindById(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PermissionService.FindById(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PermissionControllerImplementation) Create(c echo.Context) error {
+	var permissionRequest models.PermissionRequest
+	err := c.Bind(&permissionRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PermissionService.Create(c.Request().Context(), permissionRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PermissionControllerImplementation) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var permissionRequest models.PermissionRequest
+	err = c.Bind(&permissionRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PermissionService.Update(c.Request().Context(), int32(id), permissionRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PermissionControllerImplementation) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PermissionService.Delete(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
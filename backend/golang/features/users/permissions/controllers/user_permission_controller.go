@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UserPermissionController interface {
+	FindAllByUserId(c echo.Context) error
+	Grant(c echo.Context) error
+	Revoke(c echo.Context) error
+}
+
+type UserPermissionControllerImplementation struct {
+	UserPermissionService services.UserPermissionService
+}
+
+func NewUserPermissionController(userPermissionService services.UserPermissionService) UserPermissionController {
+	return &UserPermissionControllerImplementation{
+		UserPermissionService: userPermissionService,
+	}
+}
+
+func (controller *UserPermissionControllerImplementation) FindAllByUserId(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserPermissionService.FindAllByUserId(c.Request().Context(), int32(userId))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *UserPermissionControllerImplementation) Grant(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var grantPermissionRequest models.GrantPermissionRequest
+	err = c.Bind(&grantPermissionRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserPermissionService.Grant(c.Request().Context(), int32(userId), grantPermissionRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *UserPermissionControllerImplementation) Revoke(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	permissionId, err := strconv.ParseInt(c.Param("permissionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserPermissionService.Revoke(c.Request().Context(), int32(userId), int32(permissionId))
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,5 @@
+package models
+
+type PermissionRequest struct {
+	Permission string `json:"permission" validate:"required,max=50"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type PermissionResponse struct {
+	Id         int32  `json:"id"`
+	Permission string `json:"permission"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type UserPermission struct {
+	PermissionId pgtype.Int4
+	Permission   pgtype.Text
+}
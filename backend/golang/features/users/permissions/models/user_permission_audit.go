@@ -0,0 +1,18 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	GrantAction  = "GRANT"
+	RevokeAction = "REVOKE"
+)
+
+// UserPermissionAudit records who granted or revoked a permission of a user and in which request
+type UserPermissionAudit struct {
+	UserId       pgtype.Int4
+	PermissionId pgtype.Int4
+	Action       pgtype.Text
+	ActorId      pgtype.Int4
+	RequestId    pgtype.Text
+	CreatedAt    pgtype.Int8
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error)
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error)
+	FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error)
+	Create(pool *pgxpool.Pool, ctx context.Context, permission string) (id int32, err error)
+	Update(pool *pgxpool.Pool, ctx context.Context, id int32, permission string) (rowsAffected int64, err error)
+	Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error)
+}
+
+type PermissionRepositoryImplementation struct {
+}
+
+func NewPermissionRepository() PermissionRepository {
+	return &PermissionRepositoryImplementation{}
+}
+
+func (repository *PermissionRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error) {
+	rows, err := pool.Query(ctx, `SELECT id, permission FROM permissions ORDER BY id;`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissions = []models.Permission{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permission models.Permission
+		err = rows.Scan(&permission.Id, &permission.Permission)
+		if err != nil {
+			permissions = []models.Permission{}
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, permission FROM permissions WHERE id = $1;`, id).Scan(&permission.Id, &permission.Permission)
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, permission FROM permissions WHERE permission = $1;`, permission).Scan(&foundPermission.Id, &foundPermission.Permission)
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) Create(pool *pgxpool.Pool, ctx context.Context, permission string) (id int32, err error) {
+	err = pool.QueryRow(ctx, `INSERT INTO permissions (permission) VALUES ($1) RETURNING id;`, permission).Scan(&id)
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) Update(pool *pgxpool.Pool, ctx context.Context, id int32, permission string) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE permissions SET permission = $1 WHERE id = $2;`, permission, id)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `DELETE FROM permissions WHERE id = $1;`, id)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserPermissionAuditRepository interface {
+	Create(tx pgx.Tx, ctx context.Context, userPermissionAudit models.UserPermissionAudit) (err error)
+}
+
+type UserPermissionAuditRepositoryImplementation struct {
+}
+
+func NewUserPermissionAuditRepository() UserPermissionAuditRepository {
+	return &UserPermissionAuditRepositoryImplementation{}
+}
+
+func (repository *UserPermissionAuditRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, userPermissionAudit models.UserPermissionAudit) (err error) {
+	query := `INSERT INTO user_permission_audits (user_id, permission_id, action, actor_id, request_id, created_at) VALUES ($1, $2, $3, $4, $5, $6);`
+	_, err = tx.Exec(ctx, query, userPermissionAudit.UserId, userPermissionAudit.PermissionId, userPermissionAudit.Action, userPermissionAudit.ActorId, userPermissionAudit.RequestId, userPermissionAudit.CreatedAt)
+	return
+}
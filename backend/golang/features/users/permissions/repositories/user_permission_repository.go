@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserPermissionRepository interface {
+	FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error)
+	Create(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (id int32, err error)
+	Delete(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (rowsAffected int64, err error)
+}
+
+type UserPermissionRepositoryImplementation struct {
+}
+
+func NewUserPermissionRepository() UserPermissionRepository {
+	return &UserPermissionRepositoryImplementation{}
+}
+
+func (repository *UserPermissionRepositoryImplementation) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error) {
+	query := `SELECT p.id, p.permission FROM user_permissions up JOIN permissions p ON p.id = up.permission_id WHERE up.user_id = $1 ORDER BY p.id;`
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			userPermissions = []models.UserPermission{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var userPermission models.UserPermission
+		err = rows.Scan(&userPermission.PermissionId, &userPermission.Permission)
+		if err != nil {
+			userPermissions = []models.UserPermission{}
+			return
+		}
+		userPermissions = append(userPermissions, userPermission)
+	}
+	return
+}
+
+func (repository *UserPermissionRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (id int32, err error) {
+	err = tx.QueryRow(ctx, `INSERT INTO user_permissions (user_id, permission_id) VALUES ($1, $2) RETURNING id;`, userId, permissionId).Scan(&id)
+	return
+}
+
+func (repository *UserPermissionRepositoryImplementation) Delete(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `DELETE FROM user_permissions WHERE user_id = $1 AND permission_id = $2;`, userId, permissionId)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/permissions/controllers"
+	"backend-golang/features/users/permissions/repositories"
+	"backend-golang/features/users/permissions/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func PermissionRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, sessionRegistryHelper helpers.SessionRegistryHelper, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	permissionRepository := repositories.NewPermissionRepository()
+	userPermissionRepository := repositories.NewUserPermissionRepository()
+	userPermissionAuditRepository := repositories.NewUserPermissionAuditRepository()
+	permissionService := services.NewPermissionService(postgresUtil, validate, permissionRepository)
+	userPermissionService := services.NewUserPermissionService(postgresUtil, redisUtil, validate, permissionRepository, userPermissionRepository, userPermissionAuditRepository, sessionRegistryHelper)
+	permissionController := controllers.NewPermissionController(permissionService)
+	userPermissionController := controllers.NewUserPermissionController(userPermissionService)
+	requireCreate := permissionMiddleware.RequirePermissions(middlewares.CreatePermissionPermission)
+	requireRead := permissionMiddleware.RequirePermissions(middlewares.ReadPermissionPermission)
+	requireUpdate := permissionMiddleware.RequirePermissions(middlewares.UpdatePermissionPermission)
+	requireDelete := permissionMiddleware.RequirePermissions(middlewares.DeletePermissionPermission)
+	e.GET("/api/v1/permissions", permissionController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.GET("/api/v1/permissions/:id", permissionController.FindById, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.POST("/api/v1/permissions", permissionController.Create, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.PUT("/api/v1/permissions/:id", permissionController.Update, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.DELETE("/api/v1/permissions/:id", permissionController.Delete, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireDelete)
+	e.GET("/api/v1/users/:userId/permissions", userPermissionController.FindAllByUserId, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.POST("/api/v1/users/:userId/permissions", userPermissionController.Grant, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.DELETE("/api/v1/users/:userId/permissions/:permissionId", userPermissionController.Revoke, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireDelete)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PermissionService interface {
+	FindAll(ctx context.Context) (httpCode int, response helpers.Response)
+	FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	Create(ctx context.Context, permissionRequest models.PermissionRequest) (httpCode int, response helpers.Response)
+	Update(ctx context.Context, id int32, permissionRequest models.PermissionRequest) (httpCode int, response helpers.Response)
+	Delete(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+}
+
+type PermissionServiceImplementation struct {
+	PostgresUtil         utils.PostgresUtil
+	Validate             *validator.Validate
+	PermissionRepository repositories.PermissionRepository
+}
+
+func NewPermissionService(postgresUtil utils.PostgresUtil, validate *validator.Validate, permissionRepository repositories.PermissionRepository) PermissionService {
+	return &PermissionServiceImplementation{
+		PostgresUtil:         postgresUtil,
+		Validate:             validate,
+		PermissionRepository: permissionRepository,
+	}
+}
+
+func (service *PermissionServiceImplementation) FindAll(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	permissions, err := service.PermissionRepository.FindAll(service.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	permissionResponses := []models.PermissionResponse{}
+	for _, permission := range permissions {
+		permissionResponses = append(permissionResponses, toPermissionResponse(permission))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   permissionResponses,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PermissionServiceImplementation) FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	permission, err := service.PermissionRepository.FindById(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponsePermissionNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toPermissionResponse(permission),
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PermissionServiceImplementation) Create(ctx context.Context, permissionRequest models.PermissionRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(permissionRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, permissionRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	id, err := service.PermissionRepository.Create(service.PostgresUtil.GetPool(), ctx, permissionRequest.Permission)
+	if err != nil {
+		httpCode, response = toResponseSavePermissionError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	response = helpers.Response{
+		Data:   models.PermissionResponse{Id: id, Permission: permissionRequest.Permission},
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PermissionServiceImplementation) Update(ctx context.Context, id int32, permissionRequest models.PermissionRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(permissionRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, permissionRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	httpCode, response = service.checkChangeable(ctx, requestId, id)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	rowsAffected, err := service.PermissionRepository.Update(service.PostgresUtil.GetPool(), ctx, id, permissionRequest.Permission)
+	if err != nil {
+		httpCode, response = toResponseSavePermissionError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponsePermissionNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   models.PermissionResponse{Id: id, Permission: permissionRequest.Permission},
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PermissionServiceImplementation) Delete(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	httpCode, response = service.checkChangeable(ctx, requestId, id)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	rowsAffected, err := service.PermissionRepository.Delete(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "permission is still granted, revoke it first")
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponsePermissionNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully delete permission",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// checkChangeable answers 200 when the permission exists and is not one the routes require by name,
+// renaming or deleting those would lock everyone out of the endpoints they protect
+func (service *PermissionServiceImplementation) checkChangeable(ctx context.Context, requestId string, id int32) (httpCode int, response helpers.Response) {
+	permission, err := service.PermissionRepository.FindById(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponsePermissionNotFound(requestId, id)
+		return
+	}
+	if isBuiltInPermission(permission.Permission.String) {
+		err = errors.New("cannot change built-in permission " + permission.Permission.String)
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "built-in permission cannot be changed")
+		return
+	}
+	httpCode = http.StatusOK
+	return
+}
+
+func isBuiltInPermission(permission string) bool {
+	switch permission {
+	case middlewares.AdministratorPermission, middlewares.CreatePermissionPermission, middlewares.ReadPermissionPermission, middlewares.UpdatePermissionPermission, middlewares.DeletePermissionPermission:
+		return true
+	}
+	return false
+}
+
+func toResponseSavePermissionError(err error, requestId string) (httpCode int, response helpers.Response) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == "23505" {
+		return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "permission", Message: "permission already exists"}})
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+func toResponsePermissionNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find permission with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "permission not found")
+}
+
+func toPermissionResponse(permission models.Permission) models.PermissionResponse {
+	return models.PermissionResponse{
+		Id:         permission.Id.Int32,
+		Permission: permission.Permission.String,
+	}
+}
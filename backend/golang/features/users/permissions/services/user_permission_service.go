@@ -0,0 +1,252 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type UserPermissionService interface {
+	FindAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response)
+	Grant(ctx context.Context, userId int32, grantPermissionRequest models.GrantPermissionRequest) (httpCode int, response helpers.Response)
+	Revoke(ctx context.Context, userId int32, permissionId int32) (httpCode int, response helpers.Response)
+}
+
+type UserPermissionServiceImplementation struct {
+	PostgresUtil                  utils.PostgresUtil
+	RedisUtil                     utils.RedisUtil
+	Validate                      *validator.Validate
+	PermissionRepository          repositories.PermissionRepository
+	UserPermissionRepository      repositories.UserPermissionRepository
+	UserPermissionAuditRepository repositories.UserPermissionAuditRepository
+	SessionRegistryHelper         helpers.SessionRegistryHelper
+}
+
+func NewUserPermissionService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, permissionRepository repositories.PermissionRepository, userPermissionRepository repositories.UserPermissionRepository, userPermissionAuditRepository repositories.UserPermissionAuditRepository, sessionRegistryHelper helpers.SessionRegistryHelper) UserPermissionService {
+	return &UserPermissionServiceImplementation{
+		PostgresUtil:                  postgresUtil,
+		RedisUtil:                     redisUtil,
+		Validate:                      validate,
+		PermissionRepository:          permissionRepository,
+		UserPermissionRepository:      userPermissionRepository,
+		UserPermissionAuditRepository: userPermissionAuditRepository,
+		SessionRegistryHelper:         sessionRegistryHelper,
+	}
+}
+
+func (service *UserPermissionServiceImplementation) FindAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	userPermissions, err := service.UserPermissionRepository.FindByUserId(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	permissionResponses := []models.PermissionResponse{}
+	for _, userPermission := range userPermissions {
+		permissionResponses = append(permissionResponses, models.PermissionResponse{
+			Id:         userPermission.PermissionId.Int32,
+			Permission: userPermission.Permission.String,
+		})
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   permissionResponses,
+		Errors: nil,
+	}
+	return
+}
+
+// Grant takes effect at the next login of the user, sessions keep the permissions they were created with
+func (service *UserPermissionServiceImplementation) Grant(ctx context.Context, userId int32, grantPermissionRequest models.GrantPermissionRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(grantPermissionRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, grantPermissionRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	httpCode, response = service.checkManageable(ctx, requestId, grantPermissionRequest.PermissionId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	_, err = service.UserPermissionRepository.Create(tx, ctx, userId, grantPermissionRequest.PermissionId)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "user already has this permission")
+			return
+		}
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "user not found")
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.UserPermissionAuditRepository.Create(tx, ctx, toUserPermissionAudit(ctx, userId, grantPermissionRequest.PermissionId, models.GrantAction))
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully grant permission",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// Revoke also revokes every session and token family of the user, so the permission stops working right away
+func (service *UserPermissionServiceImplementation) Revoke(ctx context.Context, userId int32, permissionId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	httpCode, response = service.revoke(ctx, requestId, userId, permissionId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	err := service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	return
+}
+
+func (service *UserPermissionServiceImplementation) revoke(ctx context.Context, requestId string, userId int32, permissionId int32) (httpCode int, response helpers.Response) {
+	httpCode, response = service.checkManageable(ctx, requestId, permissionId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	rowsAffected, err := service.UserPermissionRepository.Delete(tx, ctx, userId, permissionId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected == 0 {
+		err = errors.New("user " + strconv.Itoa(int(userId)) + " does not have permission " + strconv.Itoa(int(permissionId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "user does not have this permission")
+		return
+	}
+
+	err = service.UserPermissionAuditRepository.Create(tx, ctx, toUserPermissionAudit(ctx, userId, permissionId, models.RevokeAction))
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke permission",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// checkManageable answers 200 when the permission exists and the current user may grant or revoke it, nobody grants
+// or revokes more than they have except an administrator logged in with two-factor, who is also the only one to grant
+// or revoke the administrator permission
+func (service *UserPermissionServiceImplementation) checkManageable(ctx context.Context, requestId string, permissionId int32) (httpCode int, response helpers.Response) {
+	permission, err := service.PermissionRepository.FindById(service.PostgresUtil.GetPool(), ctx, permissionId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponsePermissionNotFound(requestId, permissionId)
+		return
+	}
+
+	idPermissions := ctx.Value(middlewares.PermissionKey).([]int32)
+	if permission.Permission.String != middlewares.AdministratorPermission && containsPermission(idPermissions, permissionId) {
+		httpCode = http.StatusOK
+		return
+	}
+
+	administrator, err := service.PermissionRepository.FindByPermission(service.PostgresUtil.GetPool(), ctx, middlewares.AdministratorPermission)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	twoFactor, _ := ctx.Value(middlewares.TwoFactorKey).(bool)
+	if err == nil && twoFactor && containsPermission(idPermissions, administrator.Id.Int32) {
+		httpCode = http.StatusOK
+		return
+	}
+
+	err = errors.New("user " + strconv.Itoa(int(ctx.Value(middlewares.IdKey).(int32))) + " cannot manage permission " + permission.Permission.String)
+	httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, "cannot grant or revoke a permission you do not have")
+	return
+}
+
+func containsPermission(idPermissions []int32, permissionId int32) bool {
+	for _, idPermission := range idPermissions {
+		if idPermission == permissionId {
+			return true
+		}
+	}
+	return false
+}
+
+func toUserPermissionAudit(ctx context.Context, userId int32, permissionId int32, action string) models.UserPermissionAudit {
+	return models.UserPermissionAudit{
+		UserId:       pgtype.Int4{Valid: true, Int32: userId},
+		PermissionId: pgtype.Int4{Valid: true, Int32: permissionId},
+		Action:       pgtype.Text{Valid: true, String: action},
+		ActorId:      pgtype.Int4{Valid: true, Int32: ctx.Value(middlewares.IdKey).(int32)},
+		RequestId:    pgtype.Text{Valid: true, String: ctx.Value(middlewares.RequestIdKey).(string)},
+		CreatedAt:    pgtype.Int8{Valid: true, Int64: time.Now().UnixMilli()},
+	}
+}
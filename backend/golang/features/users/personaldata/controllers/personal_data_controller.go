@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/personaldata/models"
+	"backend-golang/features/users/personaldata/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PersonalDataController interface {
+	CreateDataExport(c echo.Context) error
+	FindAllDataExports(c echo.Context) error
+	FindDataExportArchive(c echo.Context) error
+	RequestAccountDeletion(c echo.Context) error
+	CancelAccountDeletion(c echo.Context) error
+}
+
+type PersonalDataControllerImplementation struct {
+	PersonalDataService services.PersonalDataService
+}
+
+func NewPersonalDataController(personalDataService services.PersonalDataService) PersonalDataController {
+	return &PersonalDataControllerImplementation{
+		PersonalDataService: personalDataService,
+	}
+}
+
+func (controller *PersonalDataControllerImplementation) CreateDataExport(c echo.Context) error {
+	httpCode, response := controller.PersonalDataService.CreateDataExport(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PersonalDataControllerImplementation) FindAllDataExports(c echo.Context) error {
+	httpCode, response := controller.PersonalDataService.FindAllDataExports(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+// FindDataExportArchive sends the archive as a json file to download instead of wrapping it in the usual response
+func (controller *PersonalDataControllerImplementation) FindDataExportArchive(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	archive, httpCode, response := controller.PersonalDataService.FindDataExportArchive(c.Request().Context(), int32(id))
+	if httpCode != http.StatusOK {
+		return c.JSON(httpCode, response)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="data-export-`+strconv.Itoa(int(id))+`.json"`)
+	return c.Blob(httpCode, echo.MIMEApplicationJSON, archive)
+}
+
+func (controller *PersonalDataControllerImplementation) RequestAccountDeletion(c echo.Context) error {
+	var deleteAccountRequest models.DeleteAccountRequest
+	err := c.Bind(&deleteAccountRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.PersonalDataService.RequestAccountDeletion(c.Request().Context(), deleteAccountRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *PersonalDataControllerImplementation) CancelAccountDeletion(c echo.Context) error {
+	httpCode, response := controller.PersonalDataService.CancelAccountDeletion(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
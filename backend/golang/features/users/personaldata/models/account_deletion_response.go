@@ -0,0 +1,6 @@
+package models
+
+// AccountDeletionResponse tells when the account is anonymised unless the deletion is cancelled before, in unix millis
+type AccountDeletionResponse struct {
+	DeleteAt int64 `json:"deleteAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type DataExport struct {
+	Id          pgtype.Int4
+	Status      pgtype.Text
+	CreatedAt   pgtype.Int8
+	CompletedAt pgtype.Int8
+	ExpiresAt   pgtype.Int8
+}
@@ -0,0 +1,10 @@
+package models
+
+// DataExportResponse has a null completedAt while the archive is being written and a null expiresAt unless it was written
+type DataExportResponse struct {
+	Id          int32  `json:"id"`
+	Status      string `json:"status"`
+	CreatedAt   int64  `json:"createdAt"`
+	CompletedAt *int64 `json:"completedAt"`
+	ExpiresAt   *int64 `json:"expiresAt"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// DeleteAccountRequest may leave the password empty right after logging in again
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type User struct {
+	Id                  pgtype.Int4
+	Password            pgtype.Text
+	DeletionRequestedAt pgtype.Int8
+}
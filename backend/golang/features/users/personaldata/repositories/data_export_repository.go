@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"backend-golang/features/users/personaldata/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DataExportRepository interface {
+	Create(pool *pgxpool.Pool, ctx context.Context, userId int32, createdAt int64) (id int32, err error)
+	FindAllByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (dataExports []models.DataExport, err error)
+	FindArchiveById(pool *pgxpool.Pool, ctx context.Context, id int32, userId int32, now int64) (archive []byte, err error)
+}
+
+type DataExportRepositoryImplementation struct {
+}
+
+func NewDataExportRepository() DataExportRepository {
+	return &DataExportRepositoryImplementation{}
+}
+
+// Create fails with a unique violation on data_export_pending_unique while the user has an export which is not written yet
+func (repository *DataExportRepositoryImplementation) Create(pool *pgxpool.Pool, ctx context.Context, userId int32, createdAt int64) (id int32, err error) {
+	err = pool.QueryRow(ctx, `INSERT INTO data_exports (user_id, status, created_at) VALUES ($1, 'PENDING', $2) RETURNING id;`, userId, createdAt).Scan(&id)
+	return
+}
+
+func (repository *DataExportRepositoryImplementation) FindAllByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (dataExports []models.DataExport, err error) {
+	dataExports = []models.DataExport{}
+	rows, err := pool.Query(ctx, `SELECT id, status, created_at, completed_at, expires_at FROM data_exports WHERE user_id = $1 ORDER BY id DESC;`, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			dataExports = []models.DataExport{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var dataExport models.DataExport
+		err = rows.Scan(&dataExport.Id, &dataExport.Status, &dataExport.CreatedAt, &dataExport.CompletedAt, &dataExport.ExpiresAt)
+		if err != nil {
+			dataExports = []models.DataExport{}
+			return
+		}
+		dataExports = append(dataExports, dataExport)
+	}
+	return
+}
+
+// FindArchiveById only finds a written archive of the user which did not expire yet, pgx.ErrNoRows otherwise
+func (repository *DataExportRepositoryImplementation) FindArchiveById(pool *pgxpool.Pool, ctx context.Context, id int32, userId int32, now int64) (archive []byte, err error) {
+	err = pool.QueryRow(ctx, `SELECT archive FROM data_exports WHERE id = $1 AND user_id = $2 AND status = 'READY' AND expires_at > $3;`, id, userId, now).Scan(&archive)
+	return
+}
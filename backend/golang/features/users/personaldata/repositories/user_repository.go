@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"backend-golang/features/users/personaldata/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error)
+	UpdateDeletionRequestedAt(pool *pgxpool.Pool, ctx context.Context, id int32, deletionRequestedAt int64) (rowsAffected int64, err error)
+	DeleteDeletionRequestedAt(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, password, deletion_requested_at FROM users WHERE id = $1 AND deleted_at IS NULL;`, id).Scan(&user.Id, &user.Password, &user.DeletionRequestedAt)
+	return
+}
+
+// UpdateDeletionRequestedAt keeps the first request of an account whose deletion is already requested, rowsAffected is 0 then
+func (repository *UserRepositoryImplementation) UpdateDeletionRequestedAt(pool *pgxpool.Pool, ctx context.Context, id int32, deletionRequestedAt int64) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET deletion_requested_at = $1 WHERE id = $2 AND deletion_requested_at IS NULL AND deleted_at IS NULL;`, deletionRequestedAt, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
+
+// DeleteDeletionRequestedAt cancels the deletion, rowsAffected is 0 when none was requested or the account is already anonymised
+func (repository *UserRepositoryImplementation) DeleteDeletionRequestedAt(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET deletion_requested_at = NULL WHERE id = $1 AND deletion_requested_at IS NOT NULL AND deleted_at IS NULL;`, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
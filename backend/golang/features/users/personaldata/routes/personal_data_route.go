@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/personaldata/controllers"
+	"backend-golang/features/users/personaldata/repositories"
+	"backend-golang/features/users/personaldata/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func PersonalDataRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, sessionRegistryHelper helpers.SessionRegistryHelper, personalDataHelper helpers.PersonalDataHelper, sessionMiddleware middlewares.SessionMiddleware) {
+	userRepository := repositories.NewUserRepository()
+	dataExportRepository := repositories.NewDataExportRepository()
+	personalDataService := services.NewPersonalDataService(postgresUtil, redisUtil, validate, userRepository, dataExportRepository, passwordHasher, sessionRegistryHelper, personalDataHelper)
+	personalDataController := controllers.NewPersonalDataController(personalDataService)
+	e.POST("/api/v1/users/me/data-exports", personalDataController.CreateDataExport, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.GET("/api/v1/users/me/data-exports", personalDataController.FindAllDataExports, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.GET("/api/v1/users/me/data-exports/:id/archive", personalDataController.FindDataExportArchive, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.POST("/api/v1/users/me/deletion", personalDataController.RequestAccountDeletion, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate)
+	e.DELETE("/api/v1/users/me/deletion", personalDataController.CancelAccountDeletion, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/personaldata/models"
+	"backend-golang/features/users/personaldata/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// reauthenticationWindow is how recent the login must be to request the account deletion without the password
+const reauthenticationWindow = 5 * time.Minute
+
+type PersonalDataService interface {
+	CreateDataExport(ctx context.Context) (httpCode int, response helpers.Response)
+	FindAllDataExports(ctx context.Context) (httpCode int, response helpers.Response)
+	FindDataExportArchive(ctx context.Context, id int32) (archive []byte, httpCode int, response helpers.Response)
+	RequestAccountDeletion(ctx context.Context, deleteAccountRequest models.DeleteAccountRequest) (httpCode int, response helpers.Response)
+	CancelAccountDeletion(ctx context.Context) (httpCode int, response helpers.Response)
+}
+
+type PersonalDataServiceImplementation struct {
+	PostgresUtil          utils.PostgresUtil
+	RedisUtil             utils.RedisUtil
+	Validate              *validator.Validate
+	UserRepository        repositories.UserRepository
+	DataExportRepository  repositories.DataExportRepository
+	PasswordHasher        helpers.PasswordHasher
+	SessionRegistryHelper helpers.SessionRegistryHelper
+	PersonalDataHelper    helpers.PersonalDataHelper
+}
+
+func NewPersonalDataService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, dataExportRepository repositories.DataExportRepository, passwordHasher helpers.PasswordHasher, sessionRegistryHelper helpers.SessionRegistryHelper, personalDataHelper helpers.PersonalDataHelper) PersonalDataService {
+	return &PersonalDataServiceImplementation{
+		PostgresUtil:          postgresUtil,
+		RedisUtil:             redisUtil,
+		Validate:              validate,
+		UserRepository:        userRepository,
+		DataExportRepository:  dataExportRepository,
+		PasswordHasher:        passwordHasher,
+		SessionRegistryHelper: sessionRegistryHelper,
+		PersonalDataHelper:    personalDataHelper,
+	}
+}
+
+// CreateDataExport answers before the archive is written, the client polls FindAllDataExports until the export is READY
+func (service *PersonalDataServiceImplementation) CreateDataExport(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+
+	createdAt := time.Now().UnixMilli()
+	id, err := service.DataExportRepository.Create(service.PostgresUtil.GetPool(), ctx, userId, createdAt)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusConflict, "a data export is already being written")
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	service.PersonalDataHelper.Export(ctx, id, userId)
+
+	httpCode = http.StatusAccepted
+	response = helpers.Response{
+		Data: models.DataExportResponse{
+			Id:        id,
+			Status:    helpers.DataExportStatusPending,
+			CreatedAt: createdAt,
+		},
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PersonalDataServiceImplementation) FindAllDataExports(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+
+	dataExports, err := service.DataExportRepository.FindAllByUserId(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	dataExportResponses := []models.DataExportResponse{}
+	for _, dataExport := range dataExports {
+		dataExportResponses = append(dataExportResponses, toDataExportResponse(dataExport))
+	}
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   dataExportResponses,
+		Errors: nil,
+	}
+	return
+}
+
+// FindDataExportArchive answers the same for an export of someone else, one still being written and an expired one
+func (service *PersonalDataServiceImplementation) FindDataExportArchive(ctx context.Context, id int32) (archive []byte, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+
+	archive, err := service.DataExportRepository.FindArchiveById(service.PostgresUtil.GetPool(), ctx, id, userId, time.Now().UnixMilli())
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		err = errors.New("cannot find ready data export with id: " + strconv.Itoa(int(id)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "data export not found")
+		return
+	}
+	httpCode = http.StatusOK
+	return
+}
+
+// RequestAccountDeletion logs every session of the user out right away, logging in again during the grace period is still possible
+// so the deletion can be cancelled, asking again keeps the first request. The user proves it is them with the password, or without it
+// by having logged in within reauthenticationWindow, which is the only way for a user who only ever signed in with a provider
+func (service *PersonalDataServiceImplementation) RequestAccountDeletion(ctx context.Context, deleteAccountRequest models.DeleteAccountRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	authenticatedAt, _ := ctx.Value(middlewares.AuthenticatedAtKey).(int64)
+	var err error
+	err = service.Validate.Struct(deleteAccountRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, deleteAccountRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	gracePeriod, err := helpers.GetAccountDeletionGracePeriod()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		err = errors.New("cannot find user with id: " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "user not found")
+		return
+	}
+
+	if deleteAccountRequest.Password != "" {
+		err = service.PasswordHasher.Compare(user.Password.String, deleteAccountRequest.Password)
+		if err != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "password", Message: "wrong password"}})
+			return
+		}
+	} else if time.Since(time.UnixMilli(authenticatedAt)) > reauthenticationWindow {
+		err = errors.New("no password and no login within " + reauthenticationWindow.String() + " for user with id: " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "password", Message: "please input the password or log in again"}})
+		return
+	}
+
+	deletionRequestedAt := time.Now().UnixMilli()
+	if user.DeletionRequestedAt.Valid {
+		deletionRequestedAt = user.DeletionRequestedAt.Int64
+	} else {
+		_, err = service.UserRepository.UpdateDeletionRequestedAt(service.PostgresUtil.GetPool(), ctx, userId, deletionRequestedAt)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+	}
+
+	err = service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusAccepted
+	response = helpers.Response{
+		Data:   models.AccountDeletionResponse{DeleteAt: time.UnixMilli(deletionRequestedAt).Add(gracePeriod).UnixMilli()},
+		Errors: nil,
+	}
+	return
+}
+
+func (service *PersonalDataServiceImplementation) CancelAccountDeletion(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+
+	rowsAffected, err := service.UserRepository.DeleteDeletionRequestedAt(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("no account deletion requested for user with id: " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "account deletion not found")
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully cancel account deletion",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func toDataExportResponse(dataExport models.DataExport) models.DataExportResponse {
+	dataExportResponse := models.DataExportResponse{
+		Id:        dataExport.Id.Int32,
+		Status:    dataExport.Status.String,
+		CreatedAt: dataExport.CreatedAt.Int64,
+	}
+	if dataExport.CompletedAt.Valid {
+		completedAt := dataExport.CompletedAt.Int64
+		dataExportResponse.CompletedAt = &completedAt
+	}
+	if dataExport.ExpiresAt.Valid {
+		expiresAt := dataExport.ExpiresAt.Int64
+		dataExportResponse.ExpiresAt = &expiresAt
+	}
+	return dataExportResponse
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/profile/models"
+	"backend-golang/features/users/profile/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProfileController interface {
+	Find(c echo.Context) error
+	Update(c echo.Context) error
+	ConfirmEmailChange(c echo.Context) error
+}
+
+type ProfileControllerImplementation struct {
+	ProfileService services.ProfileService
+}
+
+func NewProfileController(profileService services.ProfileService) ProfileController {
+	return &ProfileControllerImplementation{
+		ProfileService: profileService,
+	}
+}
+
+func (controller *ProfileControllerImplementation) Find(c echo.Context) error {
+	httpCode, response := controller.ProfileService.Find(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProfileControllerImplementation) Update(c echo.Context) error {
+	var updateProfileRequest models.UpdateProfileRequest
+	err := c.Bind(&updateProfileRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProfileService.Update(c.Request().Context(), updateProfileRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *ProfileControllerImplementation) ConfirmEmailChange(c echo.Context) error {
+	var confirmEmailChangeRequest models.ConfirmEmailChangeRequest
+	err := c.Bind(&confirmEmailChangeRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.ProfileService.ConfirmEmailChange(c.Request().Context(), confirmEmailChangeRequest)
+	return c.JSON(httpCode, response)
+}
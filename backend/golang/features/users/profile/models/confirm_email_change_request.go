@@ -0,0 +1,5 @@
+package models
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// EmailChangeToken is stored in redis under the hash of the token until the new email is confirmed
+type EmailChangeToken struct {
+	UserId int32  `json:"userId"`
+	Email  string `json:"email"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// ProfileResponse has the names of the permissions the current session or token is authenticated with
+type ProfileResponse struct {
+	Id          int32    `json:"id"`
+	Username    string   `json:"username"`
+	Email       string   `json:"email"`
+	CreatedAt   int64    `json:"createdAt"`
+	Permissions []string `json:"permissions"`
+}
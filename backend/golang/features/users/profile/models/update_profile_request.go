@@ -0,0 +1,8 @@
+package models
+
+// UpdateProfileRequest changes only the fields that are not empty, a new email is only set once it is confirmed and needs the current password
+type UpdateProfileRequest struct {
+	Username        string `json:"username" validate:"omitempty,usernamevalidator"`
+	Email           string `json:"email" validate:"omitempty,email"`
+	Currentpassword string `json:"currentpassword" validate:"required_with=Email"`
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"backend-golang/features/users/profile/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EmailChangeTokenRepository stores the hash of an email change token with the user and the email waiting for confirmation
+type EmailChangeTokenRepository interface {
+	Create(client *redis.Client, ctx context.Context, tokenHash string, emailChangeToken models.EmailChangeToken, expiration time.Duration) (err error)
+	FindAndDelete(client *redis.Client, ctx context.Context, tokenHash string) (emailChangeToken models.EmailChangeToken, err error)
+}
+
+type EmailChangeTokenRepositoryImplementation struct {
+}
+
+func NewEmailChangeTokenRepository() EmailChangeTokenRepository {
+	return &EmailChangeTokenRepositoryImplementation{}
+}
+
+func ToEmailChangeTokenKey(tokenHash string) string {
+	return "emailChangeToken:" + tokenHash
+}
+
+func (repository *EmailChangeTokenRepositoryImplementation) Create(client *redis.Client, ctx context.Context, tokenHash string, emailChangeToken models.EmailChangeToken, expiration time.Duration) (err error) {
+	emailChangeTokenByte, err := json.Marshal(emailChangeToken)
+	if err != nil {
+		return
+	}
+	_, err = client.Set(ctx, ToEmailChangeTokenKey(tokenHash), string(emailChangeTokenByte), expiration).Result()
+	return
+}
+
+// FindAndDelete reads and deletes the token in one command so a token can only be used once, redis.Nil when it does not exist
+func (repository *EmailChangeTokenRepositoryImplementation) FindAndDelete(client *redis.Client, ctx context.Context, tokenHash string) (emailChangeToken models.EmailChangeToken, err error) {
+	value, err := client.GetDel(ctx, ToEmailChangeTokenKey(tokenHash)).Result()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal([]byte(value), &emailChangeToken)
+	return
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository interface {
+	FindPermissionsByIds(pool *pgxpool.Pool, ctx context.Context, ids []int32) (permissions []string, err error)
+}
+
+type PermissionRepositoryImplementation struct {
+}
+
+func NewPermissionRepository() PermissionRepository {
+	return &PermissionRepositoryImplementation{}
+}
+
+func (repository *PermissionRepositoryImplementation) FindPermissionsByIds(pool *pgxpool.Pool, ctx context.Context, ids []int32) (permissions []string, err error) {
+	permissions = []string{}
+	rows, err := pool.Query(ctx, `SELECT permission FROM permissions WHERE id = ANY($1) ORDER BY permission;`, ids)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissions = []string{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permission string
+		err = rows.Scan(&permission)
+		if err != nil {
+			permissions = []string{}
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"backend-golang/features/users/profile/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error)
+	FindPasswordById(pool *pgxpool.Pool, ctx context.Context, id int32) (password string, err error)
+	CountByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (count int, err error)
+	UpdateUsername(pool *pgxpool.Pool, ctx context.Context, id int32, username string) (rowsAffected int64, err error)
+	UpdateEmail(pool *pgxpool.Pool, ctx context.Context, id int32, email string, emailVerifiedAt int64) (username string, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, username, email, created_at FROM users WHERE id = $1;`, id).Scan(&user.Id, &user.Username, &user.Email, &user.CreatedAt)
+	return
+}
+
+func (repository *UserRepositoryImplementation) FindPasswordById(pool *pgxpool.Pool, ctx context.Context, id int32) (password string, err error) {
+	err = pool.QueryRow(ctx, `SELECT password FROM users WHERE id = $1;`, id).Scan(&password)
+	return
+}
+
+func (repository *UserRepositoryImplementation) CountByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (count int, err error) {
+	err = pool.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE email = $1;`, email).Scan(&count)
+	return
+}
+
+func (repository *UserRepositoryImplementation) UpdateUsername(pool *pgxpool.Pool, ctx context.Context, id int32, username string) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `UPDATE users SET username = $1 WHERE id = $2;`, username, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
+
+// UpdateEmail marks the new email as verified since the link was opened from it, pgx.ErrNoRows when the user was deleted in the meantime
+func (repository *UserRepositoryImplementation) UpdateEmail(pool *pgxpool.Pool, ctx context.Context, id int32, email string, emailVerifiedAt int64) (username string, err error) {
+	err = pool.QueryRow(ctx, `UPDATE users SET email = $1, email_verified_at = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING username;`, email, emailVerifiedAt, id).Scan(&username)
+	return
+}
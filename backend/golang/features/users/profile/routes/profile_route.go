@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/profile/controllers"
+	"backend-golang/features/users/profile/repositories"
+	"backend-golang/features/users/profile/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func ProfileRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, tokenHelper helpers.TokenHelper, sessionRegistryHelper helpers.SessionRegistryHelper, mailer utils.Mailer, sessionMiddleware middlewares.SessionMiddleware) {
+	userRepository := repositories.NewUserRepository()
+	permissionRepository := repositories.NewPermissionRepository()
+	emailChangeTokenRepository := repositories.NewEmailChangeTokenRepository()
+	profileService := services.NewProfileService(postgresUtil, redisUtil, validate, userRepository, permissionRepository, emailChangeTokenRepository, passwordHasher, tokenHelper, sessionRegistryHelper, mailer)
+	profileController := controllers.NewProfileController(profileService)
+	e.GET("/api/v1/users/me", profileController.Find, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.PATCH("/api/v1/users/me", profileController.Update, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate)
+	e.POST("/api/v1/users/email/change/confirm", profileController.ConfirmEmailChange, middlewares.PrintRequestResponseLog)
+}
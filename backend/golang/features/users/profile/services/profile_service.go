@@ -0,0 +1,309 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/profile/models"
+	"backend-golang/features/users/profile/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+)
+
+const emailChangeTokenLifetime = time.Hour
+
+type ProfileService interface {
+	Find(ctx context.Context) (httpCode int, response helpers.Response)
+	Update(ctx context.Context, updateProfileRequest models.UpdateProfileRequest) (httpCode int, response helpers.Response)
+	ConfirmEmailChange(ctx context.Context, confirmEmailChangeRequest models.ConfirmEmailChangeRequest) (httpCode int, response helpers.Response)
+}
+
+type ProfileServiceImplementation struct {
+	PostgresUtil               utils.PostgresUtil
+	RedisUtil                  utils.RedisUtil
+	Validate                   *validator.Validate
+	UserRepository             repositories.UserRepository
+	PermissionRepository       repositories.PermissionRepository
+	EmailChangeTokenRepository repositories.EmailChangeTokenRepository
+	PasswordHasher             helpers.PasswordHasher
+	TokenHelper                helpers.TokenHelper
+	SessionRegistryHelper      helpers.SessionRegistryHelper
+	Mailer                     utils.Mailer
+}
+
+func NewProfileService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, permissionRepository repositories.PermissionRepository, emailChangeTokenRepository repositories.EmailChangeTokenRepository, passwordHasher helpers.PasswordHasher, tokenHelper helpers.TokenHelper, sessionRegistryHelper helpers.SessionRegistryHelper, mailer utils.Mailer) ProfileService {
+	return &ProfileServiceImplementation{
+		PostgresUtil:               postgresUtil,
+		RedisUtil:                  redisUtil,
+		Validate:                   validate,
+		UserRepository:             userRepository,
+		PermissionRepository:       permissionRepository,
+		EmailChangeTokenRepository: emailChangeTokenRepository,
+		PasswordHasher:             passwordHasher,
+		TokenHelper:                tokenHelper,
+		SessionRegistryHelper:      sessionRegistryHelper,
+		Mailer:                     mailer,
+	}
+}
+
+// Find reads the profile from the database and the permissions from the session, so it shows what the current request may do
+func (service *ProfileServiceImplementation) Find(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	idPermissions := ctx.Value(middlewares.PermissionKey).([]int32)
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseUserNotFound(requestId, userId)
+		return
+	}
+
+	permissions, err := service.PermissionRepository.FindPermissionsByIds(service.PostgresUtil.GetPool(), ctx, idPermissions)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data: models.ProfileResponse{
+			Id:          user.Id.Int32,
+			Username:    user.Username.String,
+			Email:       user.Email.String,
+			CreatedAt:   user.CreatedAt.Int64,
+			Permissions: permissions,
+		},
+		Errors: nil,
+	}
+	return
+}
+
+// Update sets the new username right away and the sessions of the user follow it, a new email needs the current password and is only mailed a confirmation link
+// while the current email is told about the change. The answer is the same whether the new email is already taken or not, so it cannot be used to find registered emails
+func (service *ProfileServiceImplementation) Update(ctx context.Context, updateProfileRequest models.UpdateProfileRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	var err error
+	err = service.Validate.Struct(updateProfileRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, updateProfileRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+	updateProfileRequest.Email = helpers.NormalizeEmail(updateProfileRequest.Email)
+	if updateProfileRequest.Username == "" && updateProfileRequest.Email == "" {
+		err = errors.New("nothing to update")
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "username", Message: "please input the username or the email"}, {Field: "email", Message: "please input the username or the email"}})
+		return
+	}
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseUserNotFound(requestId, userId)
+		return
+	}
+
+	// a stolen session must not be enough to move the account to another email
+	changeEmail := updateProfileRequest.Email != "" && updateProfileRequest.Email != user.Email.String
+	if changeEmail {
+		var password string
+		password, err = service.UserRepository.FindPasswordById(service.PostgresUtil.GetPool(), ctx, userId)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+		err = service.PasswordHasher.Compare(password, updateProfileRequest.Currentpassword)
+		if err != nil {
+			err = errors.New("wrong current password")
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "currentpassword", Message: "wrong current password"}})
+			return
+		}
+	}
+
+	if updateProfileRequest.Username != "" && updateProfileRequest.Username != user.Username.String {
+		var rowsAffected int64
+		rowsAffected, err = service.UserRepository.UpdateUsername(service.PostgresUtil.GetPool(), ctx, userId, updateProfileRequest.Username)
+		if err != nil {
+			var pgError *pgconn.PgError
+			if errors.As(err, &pgError) && pgError.Code == "23505" {
+				httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "username", Message: "username already exists"}})
+				return
+			}
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+		if rowsAffected != 1 {
+			httpCode, response = toResponseUserNotFound(requestId, userId)
+			return
+		}
+
+		err = service.SessionRegistryHelper.UpdateProfileByUserId(service.RedisUtil.GetClient(), ctx, userId, updateProfileRequest.Username, user.Email.String)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+		user.Username.String = updateProfileRequest.Username
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully update profile",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	if !changeEmail {
+		return
+	}
+	response.Data = helpers.ResponseMessage{
+		Message: "successfully update profile, open the link sent to the new email to change it",
+	}
+
+	// from here on errors are only logged, an error answer would only ever be given for an email that is not taken
+	noticeMail := utils.Mail{
+		To:      user.Email.String,
+		Subject: "Your email is about to change",
+		Body:    "Hi " + user.Username.String + ",\n\nsomeone asked to change the email of your account to " + updateProfileRequest.Email + ", it changes once the link sent there is opened.\n\nIf it was not you, change your password and log out of every session right away.\n",
+	}
+	err = service.Mailer.Send(ctx, noticeMail)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+
+	countEmail, err := service.UserRepository.CountByEmail(service.PostgresUtil.GetPool(), ctx, updateProfileRequest.Email)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	if countEmail > 0 {
+		return
+	}
+	token, err := service.TokenHelper.Generate()
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+	emailChangeToken := models.EmailChangeToken{
+		UserId: userId,
+		Email:  updateProfileRequest.Email,
+	}
+	err = service.EmailChangeTokenRepository.Create(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(token), emailChangeToken, emailChangeTokenLifetime)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+		return
+	}
+
+	mail := utils.Mail{
+		To:      updateProfileRequest.Email,
+		Subject: "Confirm your new email",
+		Body:    "Hi " + user.Username.String + ",\n\nopen this link within " + emailChangeTokenLifetime.String() + " to use this email for your account:\n" + toConfirmEmailChangeLink(token) + "\n\nIf you did not ask for this change, you can ignore this email.\n",
+	}
+	err = service.Mailer.Send(ctx, mail)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+	return
+}
+
+// ConfirmEmailChange does not need a session, the token alone proves the request came from the account and the link was opened from the new email,
+// the old email is told once the change is done
+func (service *ProfileServiceImplementation) ConfirmEmailChange(ctx context.Context, confirmEmailChangeRequest models.ConfirmEmailChangeRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(confirmEmailChangeRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, confirmEmailChangeRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	emailChangeToken, err := service.EmailChangeTokenRepository.FindAndDelete(service.RedisUtil.GetClient(), ctx, helpers.ToTokenHash(confirmEmailChangeRequest.Token))
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+		return
+	}
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, emailChangeToken.UserId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+		return
+	}
+
+	username, err := service.UserRepository.UpdateEmail(service.PostgresUtil.GetPool(), ctx, emailChangeToken.UserId, emailChangeToken.Email, time.Now().UnixMilli())
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			// another account took the email after the link was sent
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "email already exists"}})
+			return
+		}
+		if err == pgx.ErrNoRows {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "token", Message: "token is invalid or expired"}})
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.SessionRegistryHelper.UpdateProfileByUserId(service.RedisUtil.GetClient(), ctx, emailChangeToken.UserId, username, emailChangeToken.Email)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	mail := utils.Mail{
+		To:      user.Email.String,
+		Subject: "Your email was changed",
+		Body:    "Hi " + username + ",\n\nthe email of your account was changed to " + emailChangeToken.Email + ", this address will not get any more emails about it.\n\nIf it was not you, contact us right away.\n",
+	}
+	err = service.Mailer.Send(ctx, mail)
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully change email",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func toResponseUserNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find user with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "user not found")
+}
+
+func toConfirmEmailChangeLink(token string) string {
+	return os.Getenv("ECOMMERCEV2_EMAIL_CHANGE_URL") + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/register/models"
+	"backend-golang/features/users/register/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RegisterController interface {
+	Register(c echo.Context) error
+}
+
+type RegisterControllerImplementation struct {
+	RegisterService services.RegisterService
+}
+
+func NewRegisterController(registerService services.RegisterService) RegisterController {
+	return &RegisterControllerImplementation{
+		RegisterService: registerService,
+	}
+}
+
+func (controller *RegisterControllerImplementation) Register(c echo.Context) error {
+	var registerRequest models.RegisterRequest
+	err := c.Bind(&registerRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RegisterService.Register(c.Request().Context(), registerRequest)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,8 @@
+package models
+
+type RegisterRequest struct {
+	Username        string `json:"username" validate:"required,usernamevalidator"`
+	Email           string `json:"email" validate:"required,email"`
+	Password        string `json:"password" validate:"required,passwordvalidator"`
+	Confirmpassword string `json:"confirmpassword" validate:"required,eqfield=Password"`
+}
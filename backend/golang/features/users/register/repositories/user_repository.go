@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"backend-golang/features/users/register/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserRepository interface {
+	CountByUsername(tx pgx.Tx, ctx context.Context, username string) (count int, err error)
+	CountByEmail(tx pgx.Tx, ctx context.Context, email string) (count int, err error)
+	Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) CountByUsername(tx pgx.Tx, ctx context.Context, username string) (count int, err error) {
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE username = $1;`, username).Scan(&count)
+	return
+}
+
+func (repository *UserRepositoryImplementation) CountByEmail(tx pgx.Tx, ctx context.Context, email string) (count int, err error) {
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE email = $1;`, email).Scan(&count)
+	return
+}
+
+func (repository *UserRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error) {
+	err = tx.QueryRow(ctx, `INSERT INTO users (username, email, password, created_at) VALUES ($1, $2, $3, $4) RETURNING id;`, user.Username, user.Email, user.Password, user.CreatedAt).Scan(&id)
+	return
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/register/controllers"
+	"backend-golang/features/users/register/repositories"
+	"backend-golang/features/users/register/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, passwordHasher helpers.PasswordHasher, emailVerificationHelper helpers.EmailVerificationHelper, mailer utils.Mailer) {
+	userRepository := repositories.NewUserRepository()
+	registerService := services.NewRegisterService(postgresUtil, redisUtil, validate, userRepository, passwordHasher, emailVerificationHelper, mailer)
+	registerController := controllers.NewRegisterController(registerService)
+	e.POST("/api/v1/users/register", registerController.Register, middlewares.PrintRequestResponseLog)
+}
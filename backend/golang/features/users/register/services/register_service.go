@@ -0,0 +1,164 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/register/models"
+	"backend-golang/features/users/register/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RegisterService interface {
+	Register(ctx context.Context, registerRequest models.RegisterRequest) (httpCode int, response helpers.Response)
+}
+
+type RegisterServiceImplementation struct {
+	PostgresUtil            utils.PostgresUtil
+	RedisUtil               utils.RedisUtil
+	Validate                *validator.Validate
+	UserRepository          repositories.UserRepository
+	PasswordHasher          helpers.PasswordHasher
+	EmailVerificationHelper helpers.EmailVerificationHelper
+	Mailer                  utils.Mailer
+}
+
+func NewRegisterService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, userRepository repositories.UserRepository, passwordHasher helpers.PasswordHasher, emailVerificationHelper helpers.EmailVerificationHelper, mailer utils.Mailer) RegisterService {
+	return &RegisterServiceImplementation{
+		PostgresUtil:            postgresUtil,
+		RedisUtil:               redisUtil,
+		Validate:                validate,
+		UserRepository:          userRepository,
+		PasswordHasher:          passwordHasher,
+		EmailVerificationHelper: emailVerificationHelper,
+		Mailer:                  mailer,
+	}
+}
+
+// Register sends the verification mail once the user is committed, a mail that cannot be sent does not fail
+// the registration because the user can ask for it again. An email that is already registered gets the same answer
+// and its owner gets a mail instead, so registering cannot be used to find registered emails
+func (service *RegisterServiceImplementation) Register(ctx context.Context, registerRequest models.RegisterRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	registerRequest.Email = helpers.NormalizeEmail(registerRequest.Email)
+	userId, emailExists, httpCode, response := service.create(ctx, registerRequest)
+	if httpCode != http.StatusCreated {
+		return
+	}
+
+	var err error
+	if emailExists {
+		mail := utils.Mail{
+			To:      registerRequest.Email,
+			Subject: "Someone tried to register with your email",
+			Body:    "Hi,\n\nsomeone tried to create an account with this email, but it already has one. If it was you, log in or reset your password instead.\n\nIf it was not you, you can ignore this email.\n",
+		}
+		err = service.Mailer.Send(ctx, mail)
+	} else {
+		err = service.EmailVerificationHelper.Send(service.RedisUtil.GetClient(), ctx, userId, registerRequest.Username, registerRequest.Email)
+	}
+	if err != nil {
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+	return
+}
+
+// create answers 201 with emailExists when the email is taken, usernames are public so a taken username is still a validation error
+func (service *RegisterServiceImplementation) create(ctx context.Context, registerRequest models.RegisterRequest) (userId int32, emailExists bool, httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(registerRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, registerRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	countUsername, err := service.UserRepository.CountByUsername(tx, ctx, registerRequest.Username)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if countUsername > 0 {
+		err = errors.New("username already exists")
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "username", Message: "username already exists"}})
+		return
+	}
+
+	countEmail, err := service.UserRepository.CountByEmail(tx, ctx, registerRequest.Email)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	// the password is hashed for a taken email too, so both answers take as long
+	password, err := service.PasswordHasher.Hash(registerRequest.Password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if countEmail > 0 {
+		emailExists = true
+		httpCode, response = toResponseRegistered()
+		return
+	}
+
+	user := models.User{
+		Username:  pgtype.Text{Valid: true, String: registerRequest.Username},
+		Email:     pgtype.Text{Valid: true, String: registerRequest.Email},
+		Password:  pgtype.Text{Valid: true, String: password},
+		CreatedAt: pgtype.Int8{Valid: true, Int64: time.Now().UnixMilli()},
+	}
+	userId, err = service.UserRepository.Create(tx, ctx, user)
+	if err != nil {
+		// a concurrent registration can pass the count checks above, the unique constraints still catch it
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			if pgError.ConstraintName == "users_username_key" {
+				httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "username", Message: "username already exists"}})
+				return
+			}
+			emailExists = true
+			httpCode, response = toResponseRegistered()
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	httpCode, response = toResponseRegistered()
+	return
+}
+
+func toResponseRegistered() (httpCode int, response helpers.Response) {
+	httpCode = http.StatusCreated
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully register",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleController interface {
+	FindAll(c echo.Context) error
+	FindById(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+	GrantPermission(c echo.Context) error
+	RevokePermission(c echo.Context) error
+}
+
+type RoleControllerImplementation struct {
+	RoleService services.RoleService
+}
+
+func NewRoleController(roleService services.RoleService) RoleController {
+	return &RoleControllerImplementation{
+		RoleService: roleService,
+	}
+}
+
+func (controller *RoleControllerImplementation) FindAll(c echo.Context) error {
+	httpCode, response := controller.RoleService.FindAll(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) FindById(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.FindById(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) Create(c echo.Context) error {
+	var roleRequest models.RoleRequest
+	err := c.Bind(&roleRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.Create(c.Request().Context(), roleRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var roleRequest models.RoleRequest
+	err = c.Bind(&roleRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.Update(c.Request().Context(), int32(id), roleRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.Delete(c.Request().Context(), int32(id))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) GrantPermission(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var grantPermissionRequest models.GrantPermissionRequest
+	err = c.Bind(&grantPermissionRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.GrantPermission(c.Request().Context(), int32(id), grantPermissionRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *RoleControllerImplementation) RevokePermission(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	permissionId, err := strconv.ParseInt(c.Param("permissionId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.RoleService.RevokePermission(c.Request().Context(), int32(id), int32(permissionId))
+	return c.JSON(httpCode, response)
+}
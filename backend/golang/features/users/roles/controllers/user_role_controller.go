@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type UserRoleController interface {
+	FindAllByUserId(c echo.Context) error
+	Assign(c echo.Context) error
+	Unassign(c echo.Context) error
+}
+
+type UserRoleControllerImplementation struct {
+	UserRoleService services.UserRoleService
+}
+
+func NewUserRoleController(userRoleService services.UserRoleService) UserRoleController {
+	return &UserRoleControllerImplementation{
+		UserRoleService: userRoleService,
+	}
+}
+
+func (controller *UserRoleControllerImplementation) FindAllByUserId(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserRoleService.FindAllByUserId(c.Request().Context(), int32(userId))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *UserRoleControllerImplementation) Assign(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	var assignRoleRequest models.AssignRoleRequest
+	err = c.Bind(&assignRoleRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserRoleService.Assign(c.Request().Context(), int32(userId), assignRoleRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *UserRoleControllerImplementation) Unassign(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	roleId, err := strconv.ParseInt(c.Param("roleId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.UserRoleService.Unassign(c.Request().Context(), int32(userId), int32(roleId))
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,5 @@
+package models
+
+type AssignRoleRequest struct {
+	RoleId int32 `json:"roleId" validate:"required,gt=0"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type GrantPermissionRequest struct {
+	PermissionId int32 `json:"permissionId" validate:"required,gt=0"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type Role struct {
+	Id       pgtype.Int4
+	Role     pgtype.Text
+	ParentId pgtype.Int4
+}
@@ -0,0 +1,10 @@
+package models
+
+// RoleDetailResponse lists the permissions granted to the role itself, the ones inherited from its parents are not
+// repeated here
+type RoleDetailResponse struct {
+	Id          int32                `json:"id"`
+	Role        string               `json:"role"`
+	ParentId    *int32               `json:"parentId"`
+	Permissions []PermissionResponse `json:"permissions"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// RoleRequest leaves ParentId at 0 for a role without a parent
+type RoleRequest struct {
+	Role     string `json:"role" validate:"required,max=50"`
+	ParentId int32  `json:"parentId" validate:"gte=0"`
+}
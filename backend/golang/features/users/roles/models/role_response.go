@@ -0,0 +1,7 @@
+package models
+
+type RoleResponse struct {
+	Id       int32  `json:"id"`
+	Role     string `json:"role"`
+	ParentId *int32 `json:"parentId"`
+}
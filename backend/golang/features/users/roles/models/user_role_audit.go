@@ -0,0 +1,18 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+const (
+	AssignAction   = "ASSIGN"
+	UnassignAction = "UNASSIGN"
+)
+
+// UserRoleAudit records who assigned or unassigned a role of a user and in which request
+type UserRoleAudit struct {
+	UserId    pgtype.Int4
+	RoleId    pgtype.Int4
+	Action    pgtype.Text
+	ActorId   pgtype.Int4
+	RequestId pgtype.Text
+	CreatedAt pgtype.Int8
+}
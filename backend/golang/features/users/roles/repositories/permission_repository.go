@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PermissionRepository interface {
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error)
+	FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error)
+}
+
+type PermissionRepositoryImplementation struct {
+}
+
+func NewPermissionRepository() PermissionRepository {
+	return &PermissionRepositoryImplementation{}
+}
+
+func (repository *PermissionRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, permission FROM permissions WHERE id = $1;`, id).Scan(&permission.Id, &permission.Permission)
+	return
+}
+
+func (repository *PermissionRepositoryImplementation) FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, permission FROM permissions WHERE permission = $1;`, permission).Scan(&foundPermission.Id, &foundPermission.Permission)
+	return
+}
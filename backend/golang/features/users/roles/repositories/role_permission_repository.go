@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RolePermissionRepository interface {
+	FindByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (permissions []models.Permission, err error)
+	FindEffectivePermissionIdsByRoleId(tx pgx.Tx, ctx context.Context, roleId int32) (idPermissions []int32, err error)
+	Create(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (id int32, err error)
+	Delete(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (rowsAffected int64, err error)
+}
+
+type RolePermissionRepositoryImplementation struct {
+}
+
+func NewRolePermissionRepository() RolePermissionRepository {
+	return &RolePermissionRepositoryImplementation{}
+}
+
+func (repository *RolePermissionRepositoryImplementation) FindByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (permissions []models.Permission, err error) {
+	query := `SELECT p.id, p.permission FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id WHERE rp.role_id = $1 ORDER BY p.id;`
+	rows, err := pool.Query(ctx, query, roleId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			permissions = []models.Permission{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var permission models.Permission
+		err = rows.Scan(&permission.Id, &permission.Permission)
+		if err != nil {
+			permissions = []models.Permission{}
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+	return
+}
+
+// FindEffectivePermissionIdsByRoleId returns the permissions of the role together with the ones inherited from its parents
+func (repository *RolePermissionRepositoryImplementation) FindEffectivePermissionIdsByRoleId(tx pgx.Tx, ctx context.Context, roleId int32) (idPermissions []int32, err error) {
+	query := `WITH RECURSIVE role_tree AS (
+		SELECT id, parent_id FROM roles WHERE id = $1
+		UNION
+		SELECT r.id, r.parent_id FROM roles r JOIN role_tree rt ON r.id = rt.parent_id
+	)
+	SELECT DISTINCT rp.permission_id FROM role_permissions rp JOIN role_tree rt ON rt.id = rp.role_id ORDER BY rp.permission_id;`
+	rows, err := tx.Query(ctx, query, roleId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			idPermissions = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var idPermission int32
+		err = rows.Scan(&idPermission)
+		if err != nil {
+			idPermissions = []int32{}
+			return
+		}
+		idPermissions = append(idPermissions, idPermission)
+	}
+	return
+}
+
+func (repository *RolePermissionRepositoryImplementation) Create(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (id int32, err error) {
+	err = pool.QueryRow(ctx, `INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2) RETURNING id;`, roleId, permissionId).Scan(&id)
+	return
+}
+
+func (repository *RolePermissionRepositoryImplementation) Delete(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2;`, roleId, permissionId)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoleRepository interface {
+	FindAll(pool *pgxpool.Pool, ctx context.Context) (roles []models.Role, err error)
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (role models.Role, err error)
+	FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (role models.Role, err error)
+	LockTree(tx pgx.Tx, ctx context.Context) (err error)
+	FindAncestorIds(tx pgx.Tx, ctx context.Context, id int32) (ancestorIds []int32, err error)
+	Create(tx pgx.Tx, ctx context.Context, role string, parentId pgtype.Int4) (id int32, err error)
+	Update(tx pgx.Tx, ctx context.Context, id int32, role string, parentId pgtype.Int4) (rowsAffected int64, err error)
+	Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error)
+}
+
+type RoleRepositoryImplementation struct {
+}
+
+func NewRoleRepository() RoleRepository {
+	return &RoleRepositoryImplementation{}
+}
+
+// LockTree makes creating roles and changing their parents wait for each other until the transaction ends, reading is not blocked.
+// Every parent read after it is the committed one, so two updates cannot each make the other role its parent
+func (repository *RoleRepositoryImplementation) LockTree(tx pgx.Tx, ctx context.Context) (err error) {
+	_, err = tx.Exec(ctx, `LOCK TABLE roles IN SHARE ROW EXCLUSIVE MODE;`)
+	return
+}
+
+func (repository *RoleRepositoryImplementation) FindAll(pool *pgxpool.Pool, ctx context.Context) (roles []models.Role, err error) {
+	rows, err := pool.Query(ctx, `SELECT id, role, parent_id FROM roles ORDER BY id;`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			roles = []models.Role{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var role models.Role
+		err = rows.Scan(&role.Id, &role.Role, &role.ParentId)
+		if err != nil {
+			roles = []models.Role{}
+			return
+		}
+		roles = append(roles, role)
+	}
+	return
+}
+
+func (repository *RoleRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (role models.Role, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, role, parent_id FROM roles WHERE id = $1;`, id).Scan(&role.Id, &role.Role, &role.ParentId)
+	return
+}
+
+// FindByIdForUpdate locks the row so the role cannot change between the checks and the write of the same transaction
+func (repository *RoleRepositoryImplementation) FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (role models.Role, err error) {
+	err = tx.QueryRow(ctx, `SELECT id, role, parent_id FROM roles WHERE id = $1 FOR UPDATE;`, id).Scan(&role.Id, &role.Role, &role.ParentId)
+	return
+}
+
+// FindAncestorIds returns the id of the role followed by the ids of all its parents up to the root
+func (repository *RoleRepositoryImplementation) FindAncestorIds(tx pgx.Tx, ctx context.Context, id int32) (ancestorIds []int32, err error) {
+	query := `WITH RECURSIVE role_tree AS (
+		SELECT id, parent_id FROM roles WHERE id = $1
+		UNION
+		SELECT r.id, r.parent_id FROM roles r JOIN role_tree rt ON r.id = rt.parent_id
+	)
+	SELECT id FROM role_tree;`
+	rows, err := tx.Query(ctx, query, id)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			ancestorIds = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var ancestorId int32
+		err = rows.Scan(&ancestorId)
+		if err != nil {
+			ancestorIds = []int32{}
+			return
+		}
+		ancestorIds = append(ancestorIds, ancestorId)
+	}
+	return
+}
+
+func (repository *RoleRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, role string, parentId pgtype.Int4) (id int32, err error) {
+	err = tx.QueryRow(ctx, `INSERT INTO roles (role, parent_id) VALUES ($1, $2) RETURNING id;`, role, parentId).Scan(&id)
+	return
+}
+
+func (repository *RoleRepositoryImplementation) Update(tx pgx.Tx, ctx context.Context, id int32, role string, parentId pgtype.Int4) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `UPDATE roles SET role = $1, parent_id = $2 WHERE id = $3;`, role, parentId, id)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
+
+func (repository *RoleRepositoryImplementation) Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	result, err := pool.Exec(ctx, `DELETE FROM roles WHERE id = $1;`, id)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type UserRoleAuditRepository interface {
+	Create(tx pgx.Tx, ctx context.Context, userRoleAudit models.UserRoleAudit) (err error)
+}
+
+type UserRoleAuditRepositoryImplementation struct {
+}
+
+func NewUserRoleAuditRepository() UserRoleAuditRepository {
+	return &UserRoleAuditRepositoryImplementation{}
+}
+
+func (repository *UserRoleAuditRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, userRoleAudit models.UserRoleAudit) (err error) {
+	query := `INSERT INTO user_role_audits (user_id, role_id, action, actor_id, request_id, created_at) VALUES ($1, $2, $3, $4, $5, $6);`
+	_, err = tx.Exec(ctx, query, userRoleAudit.UserId, userRoleAudit.RoleId, userRoleAudit.Action, userRoleAudit.ActorId, userRoleAudit.RequestId, userRoleAudit.CreatedAt)
+	return
+}
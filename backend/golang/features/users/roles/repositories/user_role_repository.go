@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRoleRepository interface {
+	FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []models.Role, err error)
+	FindUserIdsByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (userIds []int32, err error)
+	Create(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (id int32, err error)
+	Delete(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (rowsAffected int64, err error)
+}
+
+type UserRoleRepositoryImplementation struct {
+}
+
+func NewUserRoleRepository() UserRoleRepository {
+	return &UserRoleRepositoryImplementation{}
+}
+
+func (repository *UserRoleRepositoryImplementation) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []models.Role, err error) {
+	query := `SELECT r.id, r.role, r.parent_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE ur.user_id = $1 ORDER BY r.id;`
+	rows, err := pool.Query(ctx, query, userId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			roles = []models.Role{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var role models.Role
+		err = rows.Scan(&role.Id, &role.Role, &role.ParentId)
+		if err != nil {
+			roles = []models.Role{}
+			return
+		}
+		roles = append(roles, role)
+	}
+	return
+}
+
+// FindUserIdsByRoleId returns the users holding the role or any role that inherits from it
+func (repository *UserRoleRepositoryImplementation) FindUserIdsByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (userIds []int32, err error) {
+	query := `WITH RECURSIVE role_tree AS (
+		SELECT id FROM roles WHERE id = $1
+		UNION
+		SELECT r.id FROM roles r JOIN role_tree rt ON r.parent_id = rt.id
+	)
+	SELECT DISTINCT ur.user_id FROM user_roles ur JOIN role_tree rt ON rt.id = ur.role_id ORDER BY ur.user_id;`
+	rows, err := pool.Query(ctx, query, roleId)
+	if err != nil {
+		return
+	}
+	defer func() {
+		rows.Close()
+		if rows.Err() != nil {
+			userIds = []int32{}
+			err = rows.Err()
+		}
+	}()
+
+	for rows.Next() {
+		var userId int32
+		err = rows.Scan(&userId)
+		if err != nil {
+			userIds = []int32{}
+			return
+		}
+		userIds = append(userIds, userId)
+	}
+	return
+}
+
+func (repository *UserRoleRepositoryImplementation) Create(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (id int32, err error) {
+	err = tx.QueryRow(ctx, `INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) RETURNING id;`, userId, roleId).Scan(&id)
+	return
+}
+
+func (repository *UserRoleRepositoryImplementation) Delete(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role_id = $2;`, userId, roleId)
+	if err != nil {
+		return
+	}
+	rowsAffected = result.RowsAffected()
+	return
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/roles/controllers"
+	"backend-golang/features/users/roles/repositories"
+	"backend-golang/features/users/roles/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func RoleRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, sessionRegistryHelper helpers.SessionRegistryHelper, sessionMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	roleRepository := repositories.NewRoleRepository()
+	rolePermissionRepository := repositories.NewRolePermissionRepository()
+	permissionRepository := repositories.NewPermissionRepository()
+	userRoleRepository := repositories.NewUserRoleRepository()
+	userRoleAuditRepository := repositories.NewUserRoleAuditRepository()
+	roleService := services.NewRoleService(postgresUtil, redisUtil, validate, roleRepository, rolePermissionRepository, permissionRepository, userRoleRepository, sessionRegistryHelper)
+	userRoleService := services.NewUserRoleService(postgresUtil, redisUtil, validate, roleRepository, rolePermissionRepository, permissionRepository, userRoleRepository, userRoleAuditRepository, sessionRegistryHelper)
+	roleController := controllers.NewRoleController(roleService)
+	userRoleController := controllers.NewUserRoleController(userRoleService)
+	requireCreate := permissionMiddleware.RequirePermissions(middlewares.CreatePermissionPermission)
+	requireRead := permissionMiddleware.RequirePermissions(middlewares.ReadPermissionPermission)
+	requireUpdate := permissionMiddleware.RequirePermissions(middlewares.UpdatePermissionPermission)
+	requireDelete := permissionMiddleware.RequirePermissions(middlewares.DeletePermissionPermission)
+	e.GET("/api/v1/roles", roleController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.GET("/api/v1/roles/:id", roleController.FindById, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.POST("/api/v1/roles", roleController.Create, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.PUT("/api/v1/roles/:id", roleController.Update, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.DELETE("/api/v1/roles/:id", roleController.Delete, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireDelete)
+	e.POST("/api/v1/roles/:id/permissions", roleController.GrantPermission, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireUpdate)
+	e.DELETE("/api/v1/roles/:id/permissions/:permissionId", roleController.RevokePermission, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireUpdate)
+	e.GET("/api/v1/users/:userId/roles", userRoleController.FindAllByUserId, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireRead)
+	e.POST("/api/v1/users/:userId/roles", userRoleController.Assign, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate, requireCreate)
+	e.DELETE("/api/v1/users/:userId/roles/:roleId", userRoleController.Unassign, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate, requireDelete)
+}
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoleService interface {
+	FindAll(ctx context.Context) (httpCode int, response helpers.Response)
+	FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	Create(ctx context.Context, roleRequest models.RoleRequest) (httpCode int, response helpers.Response)
+	Update(ctx context.Context, id int32, roleRequest models.RoleRequest) (httpCode int, response helpers.Response)
+	Delete(ctx context.Context, id int32) (httpCode int, response helpers.Response)
+	GrantPermission(ctx context.Context, id int32, grantPermissionRequest models.GrantPermissionRequest) (httpCode int, response helpers.Response)
+	RevokePermission(ctx context.Context, id int32, permissionId int32) (httpCode int, response helpers.Response)
+}
+
+type RoleServiceImplementation struct {
+	PostgresUtil             utils.PostgresUtil
+	RedisUtil                utils.RedisUtil
+	Validate                 *validator.Validate
+	RoleRepository           repositories.RoleRepository
+	RolePermissionRepository repositories.RolePermissionRepository
+	PermissionRepository     repositories.PermissionRepository
+	UserRoleRepository       repositories.UserRoleRepository
+	SessionRegistryHelper    helpers.SessionRegistryHelper
+}
+
+func NewRoleService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, roleRepository repositories.RoleRepository, rolePermissionRepository repositories.RolePermissionRepository, permissionRepository repositories.PermissionRepository, userRoleRepository repositories.UserRoleRepository, sessionRegistryHelper helpers.SessionRegistryHelper) RoleService {
+	return &RoleServiceImplementation{
+		PostgresUtil:             postgresUtil,
+		RedisUtil:                redisUtil,
+		Validate:                 validate,
+		RoleRepository:           roleRepository,
+		RolePermissionRepository: rolePermissionRepository,
+		PermissionRepository:     permissionRepository,
+		UserRoleRepository:       userRoleRepository,
+		SessionRegistryHelper:    sessionRegistryHelper,
+	}
+}
+
+func (service *RoleServiceImplementation) FindAll(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	roles, err := service.RoleRepository.FindAll(service.PostgresUtil.GetPool(), ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	roleResponses := []models.RoleResponse{}
+	for _, role := range roles {
+		roleResponses = append(roleResponses, toRoleResponse(role))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   roleResponses,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *RoleServiceImplementation) FindById(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	role, err := service.RoleRepository.FindById(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseRoleNotFound(requestId, id)
+		return
+	}
+
+	permissions, err := service.RolePermissionRepository.FindByRoleId(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	roleResponse := toRoleResponse(role)
+	roleDetailResponse := models.RoleDetailResponse{
+		Id:          roleResponse.Id,
+		Role:        roleResponse.Role,
+		ParentId:    roleResponse.ParentId,
+		Permissions: []models.PermissionResponse{},
+	}
+	for _, permission := range permissions {
+		roleDetailResponse.Permissions = append(roleDetailResponse.Permissions, models.PermissionResponse{
+			Id:         permission.Id.Int32,
+			Permission: permission.Permission.String,
+		})
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   roleDetailResponse,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *RoleServiceImplementation) Create(ctx context.Context, roleRequest models.RoleRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(roleRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, roleRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	err = service.RoleRepository.LockTree(tx, ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	if roleRequest.ParentId != 0 {
+		httpCode, response = service.checkParent(ctx, tx, requestId, 0, roleRequest.ParentId)
+		if httpCode != http.StatusOK {
+			return
+		}
+	}
+
+	id, err := service.RoleRepository.Create(tx, ctx, roleRequest.Role, toParentId(roleRequest.ParentId))
+	if err != nil {
+		httpCode, response = toResponseSaveRoleError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	response = helpers.Response{
+		Data:   toRoleResponse(models.Role{Id: pgtype.Int4{Valid: true, Int32: id}, Role: pgtype.Text{Valid: true, String: roleRequest.Role}, ParentId: toParentId(roleRequest.ParentId)}),
+		Errors: nil,
+	}
+	return
+}
+
+// Update logs out the users of the role and of the roles inheriting from it when the parent changes, they may lose
+// permissions their sessions still carry
+func (service *RoleServiceImplementation) Update(ctx context.Context, id int32, roleRequest models.RoleRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(roleRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, roleRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	err = service.RoleRepository.LockTree(tx, ctx)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	// the current parent is read under the lock, so whether the users are logged out follows the parent actually replaced
+	role, err := service.RoleRepository.FindByIdForUpdate(tx, ctx, id)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseRoleNotFound(requestId, id)
+		return
+	}
+
+	parentId := toParentId(roleRequest.ParentId)
+	parentChanged := parentId != role.ParentId
+	if parentChanged && parentId.Valid {
+		httpCode, response = service.checkParent(ctx, tx, requestId, id, parentId.Int32)
+		if httpCode != http.StatusOK {
+			return
+		}
+	}
+
+	rowsAffected, err := service.RoleRepository.Update(tx, ctx, id, roleRequest.Role, parentId)
+	if err != nil {
+		httpCode, response = toResponseSaveRoleError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponseRoleNotFound(requestId, id)
+		return
+	}
+
+	if parentChanged && role.ParentId.Valid {
+		httpCode, response = service.logoutUsers(ctx, requestId, id)
+		if httpCode != http.StatusOK {
+			return
+		}
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   toRoleResponse(models.Role{Id: pgtype.Int4{Valid: true, Int32: id}, Role: pgtype.Text{Valid: true, String: roleRequest.Role}, ParentId: parentId}),
+		Errors: nil,
+	}
+	return
+}
+
+func (service *RoleServiceImplementation) Delete(ctx context.Context, id int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	rowsAffected, err := service.RoleRepository.Delete(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "role is still assigned or inherited, unassign it first")
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		httpCode, response = toResponseRoleNotFound(requestId, id)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully delete role",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// GrantPermission takes effect at the next login of the users of the role, sessions keep the permissions they were
+// created with
+func (service *RoleServiceImplementation) GrantPermission(ctx context.Context, id int32, grantPermissionRequest models.GrantPermissionRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(grantPermissionRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, grantPermissionRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	httpCode, response = service.checkPermission(ctx, requestId, grantPermissionRequest.PermissionId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	_, err = service.RolePermissionRepository.Create(service.PostgresUtil.GetPool(), ctx, id, grantPermissionRequest.PermissionId)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "role already has this permission")
+			return
+		}
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = toResponseRoleNotFound(requestId, id)
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully grant permission",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// RevokePermission also logs out the users of the role and of the roles inheriting from it, so the permission stops
+// working right away
+func (service *RoleServiceImplementation) RevokePermission(ctx context.Context, id int32, permissionId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	httpCode, response = service.checkPermission(ctx, requestId, permissionId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	rowsAffected, err := service.RolePermissionRepository.Delete(service.PostgresUtil.GetPool(), ctx, id, permissionId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected == 0 {
+		err = errors.New("role " + strconv.Itoa(int(id)) + " does not have permission " + strconv.Itoa(int(permissionId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "role does not have this permission")
+		return
+	}
+
+	httpCode, response = service.logoutUsers(ctx, requestId, id)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke permission",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// checkPermission answers 200 when the permission exists and the current user may grant it to or revoke it from a role
+func (service *RoleServiceImplementation) checkPermission(ctx context.Context, requestId string, permissionId int32) (httpCode int, response helpers.Response) {
+	_, err := service.PermissionRepository.FindById(service.PostgresUtil.GetPool(), ctx, permissionId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		err = errors.New("cannot find permission with id: " + strconv.Itoa(int(permissionId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "permission not found")
+		return
+	}
+	return checkHoldsAll(ctx, requestId, service.PermissionRepository, service.PostgresUtil.GetPool(), []int32{permissionId}, "cannot grant or revoke a permission you do not have")
+}
+
+// checkParent answers 200 when the parent exists, is not the role itself nor one of its descendants, and the current
+// user holds every permission the role would inherit from it. It runs in the transaction holding LockTree so the
+// ancestors cannot change before the parent is saved, a new role passes id 0
+func (service *RoleServiceImplementation) checkParent(ctx context.Context, tx pgx.Tx, requestId string, id int32, parentId int32) (httpCode int, response helpers.Response) {
+	ancestorIds, err := service.RoleRepository.FindAncestorIds(tx, ctx, parentId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if len(ancestorIds) == 0 {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "parent role not found"}})
+		return
+	}
+	if containsId(ancestorIds, id) {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "parent role cannot inherit from this role"}})
+		return
+	}
+
+	idPermissions, err := service.RolePermissionRepository.FindEffectivePermissionIdsByRoleId(tx, ctx, parentId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	return checkHoldsAll(ctx, requestId, service.PermissionRepository, service.PostgresUtil.GetPool(), idPermissions, "cannot inherit from a role with permissions you do not have")
+}
+
+func (service *RoleServiceImplementation) logoutUsers(ctx context.Context, requestId string, id int32) (httpCode int, response helpers.Response) {
+	userIds, err := service.UserRoleRepository.FindUserIdsByRoleId(service.PostgresUtil.GetPool(), ctx, id)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	for _, userId := range userIds {
+		err = service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+	}
+	httpCode = http.StatusOK
+	return
+}
+
+// checkHoldsAll answers 200 when the current user holds every given permission and none of them is the administrator
+// permission, nobody hands out more than they have except an administrator logged in with two-factor
+func checkHoldsAll(ctx context.Context, requestId string, permissionRepository repositories.PermissionRepository, pool *pgxpool.Pool, permissionIds []int32, message string) (httpCode int, response helpers.Response) {
+	administrator, err := permissionRepository.FindByPermission(pool, ctx, middlewares.AdministratorPermission)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	idPermissions := ctx.Value(middlewares.PermissionKey).([]int32)
+	holdsAll := true
+	for _, permissionId := range permissionIds {
+		if permissionId == administrator.Id.Int32 || !containsId(idPermissions, permissionId) {
+			holdsAll = false
+			break
+		}
+	}
+	if holdsAll {
+		httpCode = http.StatusOK
+		return
+	}
+
+	twoFactor, _ := ctx.Value(middlewares.TwoFactorKey).(bool)
+	if err == nil && twoFactor && containsId(idPermissions, administrator.Id.Int32) {
+		httpCode = http.StatusOK
+		return
+	}
+
+	err = errors.New("user " + strconv.Itoa(int(ctx.Value(middlewares.IdKey).(int32))) + " does not hold every permission involved")
+	httpCode, response = helpers.ToResponseError(err, requestId, http.StatusForbidden, message)
+	return
+}
+
+func containsId(ids []int32, id int32) bool {
+	for _, element := range ids {
+		if element == id {
+			return true
+		}
+	}
+	return false
+}
+
+func toParentId(parentId int32) pgtype.Int4 {
+	return pgtype.Int4{Valid: parentId > 0, Int32: parentId}
+}
+
+func toResponseSaveRoleError(err error, requestId string) (httpCode int, response helpers.Response) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) && pgError.Code == "23505" {
+		return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "role", Message: "role already exists"}})
+	}
+	if errors.As(err, &pgError) && pgError.Code == "23503" {
+		return helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "parentId", Message: "parent role not found"}})
+	}
+	return helpers.ToResponseCheckError(err, requestId)
+}
+
+func toResponseRoleNotFound(requestId string, id int32) (httpCode int, response helpers.Response) {
+	err := errors.New("cannot find role with id: " + strconv.Itoa(int(id)))
+	return helpers.ToResponseError(err, requestId, http.StatusNotFound, "role not found")
+}
+
+func toRoleResponse(role models.Role) models.RoleResponse {
+	roleResponse := models.RoleResponse{
+		Id:   role.Id.Int32,
+		Role: role.Role.String,
+	}
+	if role.ParentId.Valid {
+		parentId := role.ParentId.Int32
+		roleResponse.ParentId = &parentId
+	}
+	return roleResponse
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type UserRoleService interface {
+	FindAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response)
+	Assign(ctx context.Context, userId int32, assignRoleRequest models.AssignRoleRequest) (httpCode int, response helpers.Response)
+	Unassign(ctx context.Context, userId int32, roleId int32) (httpCode int, response helpers.Response)
+}
+
+type UserRoleServiceImplementation struct {
+	PostgresUtil             utils.PostgresUtil
+	RedisUtil                utils.RedisUtil
+	Validate                 *validator.Validate
+	RoleRepository           repositories.RoleRepository
+	RolePermissionRepository repositories.RolePermissionRepository
+	PermissionRepository     repositories.PermissionRepository
+	UserRoleRepository       repositories.UserRoleRepository
+	UserRoleAuditRepository  repositories.UserRoleAuditRepository
+	SessionRegistryHelper    helpers.SessionRegistryHelper
+}
+
+func NewUserRoleService(postgresUtil utils.PostgresUtil, redisUtil utils.RedisUtil, validate *validator.Validate, roleRepository repositories.RoleRepository, rolePermissionRepository repositories.RolePermissionRepository, permissionRepository repositories.PermissionRepository, userRoleRepository repositories.UserRoleRepository, userRoleAuditRepository repositories.UserRoleAuditRepository, sessionRegistryHelper helpers.SessionRegistryHelper) UserRoleService {
+	return &UserRoleServiceImplementation{
+		PostgresUtil:             postgresUtil,
+		RedisUtil:                redisUtil,
+		Validate:                 validate,
+		RoleRepository:           roleRepository,
+		RolePermissionRepository: rolePermissionRepository,
+		PermissionRepository:     permissionRepository,
+		UserRoleRepository:       userRoleRepository,
+		UserRoleAuditRepository:  userRoleAuditRepository,
+		SessionRegistryHelper:    sessionRegistryHelper,
+	}
+}
+
+func (service *UserRoleServiceImplementation) FindAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	roles, err := service.UserRoleRepository.FindByUserId(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	roleResponses := []models.RoleResponse{}
+	for _, role := range roles {
+		roleResponses = append(roleResponses, toRoleResponse(role))
+	}
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   roleResponses,
+		Errors: nil,
+	}
+	return
+}
+
+// Assign takes effect at the next login of the user, sessions keep the permissions they were created with
+func (service *UserRoleServiceImplementation) Assign(ctx context.Context, userId int32, assignRoleRequest models.AssignRoleRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	var err error
+	err = service.Validate.Struct(assignRoleRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, assignRoleRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	httpCode, response = service.checkManageable(ctx, tx, requestId, assignRoleRequest.RoleId)
+	if httpCode != http.StatusOK {
+		err = errors.New("role is not manageable")
+		return
+	}
+
+	_, err = service.UserRoleRepository.Create(tx, ctx, userId, assignRoleRequest.RoleId)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == "23505" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "user already has this role")
+			return
+		}
+		if errors.As(err, &pgError) && pgError.Code == "23503" {
+			httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "user not found")
+			return
+		}
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	err = service.UserRoleAuditRepository.Create(tx, ctx, toUserRoleAudit(ctx, userId, assignRoleRequest.RoleId, models.AssignAction))
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusCreated
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully assign role",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// Unassign also revokes every session and token family of the user, so the permissions of the role stop working right away
+func (service *UserRoleServiceImplementation) Unassign(ctx context.Context, userId int32, roleId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	httpCode, response = service.unassign(ctx, requestId, userId, roleId)
+	if httpCode != http.StatusOK {
+		return
+	}
+
+	err := service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	return
+}
+
+func (service *UserRoleServiceImplementation) unassign(ctx context.Context, requestId string, userId int32, roleId int32) (httpCode int, response helpers.Response) {
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	httpCode, response = service.checkManageable(ctx, tx, requestId, roleId)
+	if httpCode != http.StatusOK {
+		err = errors.New("role is not manageable")
+		return
+	}
+
+	rowsAffected, err := service.UserRoleRepository.Delete(tx, ctx, userId, roleId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected == 0 {
+		err = errors.New("user " + strconv.Itoa(int(userId)) + " does not have role " + strconv.Itoa(int(roleId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "user does not have this role")
+		return
+	}
+
+	err = service.UserRoleAuditRepository.Create(tx, ctx, toUserRoleAudit(ctx, userId, roleId, models.UnassignAction))
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully unassign role",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+// checkManageable answers 200 when the role exists and the current user holds every permission the role grants,
+// inherited ones included, the role stays locked until the assignment is committed
+func (service *UserRoleServiceImplementation) checkManageable(ctx context.Context, tx pgx.Tx, requestId string, roleId int32) (httpCode int, response helpers.Response) {
+	_, err := service.RoleRepository.FindByIdForUpdate(tx, ctx, roleId)
+	if err != nil && err != pgx.ErrNoRows {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == pgx.ErrNoRows {
+		httpCode, response = toResponseRoleNotFound(requestId, roleId)
+		return
+	}
+
+	idPermissions, err := service.RolePermissionRepository.FindEffectivePermissionIdsByRoleId(tx, ctx, roleId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	return checkHoldsAll(ctx, requestId, service.PermissionRepository, service.PostgresUtil.GetPool(), idPermissions, "cannot assign or unassign a role with permissions you do not have")
+}
+
+func toUserRoleAudit(ctx context.Context, userId int32, roleId int32, action string) models.UserRoleAudit {
+	return models.UserRoleAudit{
+		UserId:    pgtype.Int4{Valid: true, Int32: userId},
+		RoleId:    pgtype.Int4{Valid: true, Int32: roleId},
+		Action:    pgtype.Text{Valid: true, String: action},
+		ActorId:   pgtype.Int4{Valid: true, Int32: ctx.Value(middlewares.IdKey).(int32)},
+		RequestId: pgtype.Text{Valid: true, String: ctx.Value(middlewares.RequestIdKey).(string)},
+		CreatedAt: pgtype.Int8{Valid: true, Int64: time.Now().UnixMilli()},
+	}
+}
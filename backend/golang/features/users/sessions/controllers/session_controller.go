@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/sessions/services"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SessionController interface {
+	FindAll(c echo.Context) error
+	Delete(c echo.Context) error
+	DeleteOthers(c echo.Context) error
+	DeleteAllByUserId(c echo.Context) error
+}
+
+type SessionControllerImplementation struct {
+	SessionService services.SessionService
+}
+
+func NewSessionController(sessionService services.SessionService) SessionController {
+	return &SessionControllerImplementation{
+		SessionService: sessionService,
+	}
+}
+
+func (controller *SessionControllerImplementation) FindAll(c echo.Context) error {
+	httpCode, response := controller.SessionService.FindAll(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *SessionControllerImplementation) Delete(c echo.Context) error {
+	httpCode, response := controller.SessionService.Delete(c.Request().Context(), c.Param("id"))
+	return c.JSON(httpCode, response)
+}
+
+func (controller *SessionControllerImplementation) DeleteOthers(c echo.Context) error {
+	httpCode, response := controller.SessionService.DeleteOthers(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *SessionControllerImplementation) DeleteAllByUserId(c echo.Context) error {
+	userId, err := strconv.ParseInt(c.Param("userId"), 10, 32)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.SessionService.DeleteAllByUserId(c.Request().Context(), int32(userId))
+	return c.JSON(httpCode, response)
+}
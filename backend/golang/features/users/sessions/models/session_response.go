@@ -0,0 +1,9 @@
+package models
+
+type SessionResponse struct {
+	Id        string `json:"id"`
+	CreatedAt int64  `json:"createdAt"`
+	UserAgent string `json:"userAgent"`
+	Ip        string `json:"ip"`
+	Current   bool   `json:"current"`
+}
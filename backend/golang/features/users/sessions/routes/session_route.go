@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/sessions/controllers"
+	"backend-golang/features/users/sessions/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+func SessionRoute(e *echo.Echo, redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper, sessionMiddleware middlewares.SessionMiddleware, sessionOrApiKeyMiddleware middlewares.SessionMiddleware, permissionMiddleware middlewares.PermissionMiddleware) {
+	sessionService := services.NewSessionService(redisUtil, redisHelper, sessionRegistryHelper)
+	sessionController := controllers.NewSessionController(sessionService)
+	e.GET("/api/v1/users/sessions", sessionController.FindAll, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.DELETE("/api/v1/users/sessions", sessionController.DeleteOthers, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.DELETE("/api/v1/users/sessions/:id", sessionController.Delete, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionMiddleware.Authenticate)
+	e.DELETE("/api/v1/users/:userId/sessions", sessionController.DeleteAllByUserId, middlewares.PrintRequestResponseLogWithNoRequestBody, sessionOrApiKeyMiddleware.Authenticate, permissionMiddleware.RequirePermissions(middlewares.AdministratorPermission))
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/sessions/models"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type SessionService interface {
+	FindAll(ctx context.Context) (httpCode int, response helpers.Response)
+	Delete(ctx context.Context, id string) (httpCode int, response helpers.Response)
+	DeleteOthers(ctx context.Context) (httpCode int, response helpers.Response)
+	DeleteAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response)
+}
+
+type SessionServiceImplementation struct {
+	RedisUtil             utils.RedisUtil
+	RedisHelper           helpers.RedisHelper
+	SessionRegistryHelper helpers.SessionRegistryHelper
+}
+
+func NewSessionService(redisUtil utils.RedisUtil, redisHelper helpers.RedisHelper, sessionRegistryHelper helpers.SessionRegistryHelper) SessionService {
+	return &SessionServiceImplementation{
+		RedisUtil:             redisUtil,
+		RedisHelper:           redisHelper,
+		SessionRegistryHelper: sessionRegistryHelper,
+	}
+}
+
+func (service *SessionServiceImplementation) FindAll(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	currentSessionId := ctx.Value(middlewares.SessionIdKey).(string)
+
+	sessionInfos, err := service.SessionRegistryHelper.FindAllByUserId(service.RedisUtil.GetClient(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	sessionResponses := []models.SessionResponse{}
+	for _, sessionInfo := range sessionInfos {
+		sessionResponses = append(sessionResponses, models.SessionResponse{
+			Id:        helpers.ToSessionPublicId(sessionInfo.SessionId),
+			CreatedAt: sessionInfo.CreatedAt,
+			UserAgent: sessionInfo.UserAgent,
+			Ip:        sessionInfo.Ip,
+			Current:   sessionInfo.SessionId == currentSessionId,
+		})
+	}
+	sort.Slice(sessionResponses, func(i, j int) bool {
+		return sessionResponses[i].CreatedAt > sessionResponses[j].CreatedAt
+	})
+
+	httpCode = http.StatusOK
+	response = helpers.Response{
+		Data:   sessionResponses,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *SessionServiceImplementation) Delete(ctx context.Context, id string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+
+	sessionInfos, err := service.SessionRegistryHelper.FindAllByUserId(service.RedisUtil.GetClient(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	var sessionId string
+	for _, sessionInfo := range sessionInfos {
+		if helpers.ToSessionPublicId(sessionInfo.SessionId) == id {
+			sessionId = sessionInfo.SessionId
+			break
+		}
+	}
+	if sessionId == "" {
+		err = errors.New("cannot find session with id: " + id)
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusNotFound, "session not found")
+		return
+	}
+
+	_, err = service.RedisHelper.Del(service.RedisUtil.GetClient(), ctx, sessionId)
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	err = service.SessionRegistryHelper.Unregister(service.RedisUtil.GetClient(), ctx, userId, sessionId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke session",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *SessionServiceImplementation) DeleteOthers(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	currentSessionId := ctx.Value(middlewares.SessionIdKey).(string)
+
+	err := service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, currentSessionId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke other sessions",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *SessionServiceImplementation) DeleteAllByUserId(ctx context.Context, userId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+
+	err := service.SessionRegistryHelper.DeleteAllByUserId(service.RedisUtil.GetClient(), ctx, userId, "")
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke user sessions",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
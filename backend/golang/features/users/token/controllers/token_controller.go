@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/token/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TokenController interface {
+	Refresh(c echo.Context) error
+	Revoke(c echo.Context) error
+}
+
+type TokenControllerImplementation struct {
+	TokenService services.TokenService
+}
+
+func NewTokenController(tokenService services.TokenService) TokenController {
+	return &TokenControllerImplementation{
+		TokenService: tokenService,
+	}
+}
+
+func (controller *TokenControllerImplementation) Refresh(c echo.Context) error {
+	refreshToken := c.Request().Header.Get(helpers.RefreshTokenHeaderName)
+	httpCode, response := controller.TokenService.Refresh(c.Request().Context(), refreshToken)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *TokenControllerImplementation) Revoke(c echo.Context) error {
+	refreshToken := c.Request().Header.Get(helpers.RefreshTokenHeaderName)
+	httpCode, response := controller.TokenService.Revoke(c.Request().Context(), refreshToken)
+	return c.JSON(httpCode, response)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/token/controllers"
+	"backend-golang/features/users/token/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TokenRoute(e *echo.Echo, redisUtil utils.RedisUtil, uuidHelper helpers.UuidHelper, sessionRegistryHelper helpers.SessionRegistryHelper, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper) {
+	tokenService := services.NewTokenService(redisUtil, uuidHelper, sessionRegistryHelper, jwtHelper, tokenFamilyHelper)
+	tokenController := controllers.NewTokenController(tokenService)
+	e.POST("/api/v1/users/token/refresh", tokenController.Refresh, middlewares.PrintRequestResponseLogWithNoRequestBody, middlewares.NoStore)
+	e.POST("/api/v1/users/token/revoke", tokenController.Revoke, middlewares.PrintRequestResponseLogWithNoRequestBody)
+}
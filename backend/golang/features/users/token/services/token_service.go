@@ -0,0 +1,143 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/utils"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type TokenService interface {
+	Refresh(ctx context.Context, refreshToken string) (httpCode int, response helpers.Response)
+	Revoke(ctx context.Context, refreshToken string) (httpCode int, response helpers.Response)
+}
+
+type TokenServiceImplementation struct {
+	RedisUtil             utils.RedisUtil
+	UuidHelper            helpers.UuidHelper
+	SessionRegistryHelper helpers.SessionRegistryHelper
+	JwtHelper             helpers.JwtHelper
+	TokenFamilyHelper     helpers.TokenFamilyHelper
+}
+
+func NewTokenService(redisUtil utils.RedisUtil, uuidHelper helpers.UuidHelper, sessionRegistryHelper helpers.SessionRegistryHelper, jwtHelper helpers.JwtHelper, tokenFamilyHelper helpers.TokenFamilyHelper) TokenService {
+	return &TokenServiceImplementation{
+		RedisUtil:             redisUtil,
+		UuidHelper:            uuidHelper,
+		SessionRegistryHelper: sessionRegistryHelper,
+		JwtHelper:             jwtHelper,
+		TokenFamilyHelper:     tokenFamilyHelper,
+	}
+}
+
+// Refresh rotates the refresh token, every refresh token works once and presenting one which was already rotated
+// revokes its whole family since either the client or whoever copied the token is not the legitimate holder anymore
+func (service *TokenServiceImplementation) Refresh(ctx context.Context, refreshToken string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	claims, err := service.JwtHelper.ParseRefreshToken(refreshToken)
+	if err != nil {
+		httpCode, response = service.toResponseInvalidRefreshToken(err, requestId)
+		return
+	}
+
+	newRefreshTokenId := service.UuidHelper.String()
+	result, err := service.TokenFamilyHelper.Rotate(service.RedisUtil.GetClient(), ctx, claims.FamilyId, claims.ID, newRefreshTokenId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if result == helpers.TokenFamilyNotFound {
+		err = errors.New("token family is revoked or expired: " + claims.FamilyId)
+		httpCode, response = service.toResponseInvalidRefreshToken(err, requestId)
+		return
+	}
+	if result == helpers.TokenFamilyReused {
+		err = errors.New("refresh token " + claims.ID + " was reused, token family " + claims.FamilyId + " of user " + claims.Subject + " is revoked")
+		httpCode, response = service.toResponseInvalidRefreshToken(err, requestId)
+		return
+	}
+
+	session, err := service.TokenFamilyHelper.FindSession(service.RedisUtil.GetClient(), ctx, claims.FamilyId)
+	if err != nil && err != redis.Nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	} else if err != nil && err == redis.Nil {
+		err = errors.New("token family is revoked while rotating: " + claims.FamilyId)
+		httpCode, response = service.toResponseInvalidRefreshToken(err, requestId)
+		return
+	}
+
+	jwtLifetime, err := helpers.GetJwtLifetime()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	now := time.Now()
+	accessTokenClaims, refreshTokenClaims := helpers.ToTokenClaims(session, claims.FamilyId, service.UuidHelper.String(), newRefreshTokenId, now, jwtLifetime)
+	accessToken, err := service.JwtHelper.SignAccessToken(accessTokenClaims)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	newRefreshToken, err := service.JwtHelper.SignRefreshToken(refreshTokenClaims)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	tokenResponse := helpers.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    accessTokenClaims.ExpiresAt.Unix() - now.Unix(),
+	}
+	response = helpers.Response{
+		Data:   tokenResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// Revoke ends the family of the refresh token, the access tokens of the family stop working with it,
+// revoking with a token which is invalid or whose family is already gone is not an error
+func (service *TokenServiceImplementation) Revoke(ctx context.Context, refreshToken string) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	claims, err := service.JwtHelper.ParseRefreshToken(refreshToken)
+	if err == nil {
+		err = service.TokenFamilyHelper.Delete(service.RedisUtil.GetClient(), ctx, claims.FamilyId)
+		if err != nil {
+			httpCode, response = helpers.ToResponseCheckError(err, requestId)
+			return
+		}
+
+		userId, errUserId := strconv.ParseInt(claims.Subject, 10, 32)
+		if errUserId == nil {
+			err = service.SessionRegistryHelper.Unregister(service.RedisUtil.GetClient(), ctx, int32(userId), helpers.ToTokenFamilyKey(claims.FamilyId))
+			if err != nil {
+				httpCode, response = helpers.ToResponseCheckError(err, requestId)
+				return
+			}
+		}
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully revoke token",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
+
+func (service *TokenServiceImplementation) toResponseInvalidRefreshToken(err error, requestId string) (httpCode int, response helpers.Response) {
+	return helpers.ToResponseError(err, requestId, http.StatusUnauthorized, "refresh token is invalid or expired")
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/twofactor/models"
+	"backend-golang/features/users/twofactor/services"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TwoFactorController interface {
+	Enroll(c echo.Context) error
+	Confirm(c echo.Context) error
+	Disable(c echo.Context) error
+}
+
+type TwoFactorControllerImplementation struct {
+	TwoFactorService services.TwoFactorService
+}
+
+func NewTwoFactorController(twoFactorService services.TwoFactorService) TwoFactorController {
+	return &TwoFactorControllerImplementation{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (controller *TwoFactorControllerImplementation) Enroll(c echo.Context) error {
+	httpCode, response := controller.TwoFactorService.Enroll(c.Request().Context())
+	return c.JSON(httpCode, response)
+}
+
+func (controller *TwoFactorControllerImplementation) Confirm(c echo.Context) error {
+	var confirmTwoFactorRequest models.ConfirmTwoFactorRequest
+	err := c.Bind(&confirmTwoFactorRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.TwoFactorService.Confirm(c.Request().Context(), confirmTwoFactorRequest)
+	return c.JSON(httpCode, response)
+}
+
+func (controller *TwoFactorControllerImplementation) Disable(c echo.Context) error {
+	var disableTwoFactorRequest models.DisableTwoFactorRequest
+	err := c.Bind(&disableTwoFactorRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, helpers.Response{Data: nil, Errors: helpers.ToErrorMessages(err.Error())})
+	}
+	httpCode, response := controller.TwoFactorService.Disable(c.Request().Context(), disableTwoFactorRequest)
+	return c.JSON(httpCode, response)
+}
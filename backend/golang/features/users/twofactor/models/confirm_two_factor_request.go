@@ -0,0 +1,5 @@
+package models
+
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type EnrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
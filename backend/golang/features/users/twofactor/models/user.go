@@ -0,0 +1,10 @@
+package models
+
+import "github.com/jackc/pgx/v5/pgtype"
+
+type User struct {
+	Id            pgtype.Int4
+	Password      pgtype.Text
+	TotpSecret    pgtype.Text
+	TotpEnabledAt pgtype.Int8
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecoveryCodeRepository stores only the hashes of the recovery codes, the codes themselves are shown once
+type RecoveryCodeRepository interface {
+	CreateMany(tx pgx.Tx, ctx context.Context, userId int32, codeHashes []string) (err error)
+	DeleteByUserId(tx pgx.Tx, ctx context.Context, userId int32) (err error)
+}
+
+type RecoveryCodeRepositoryImplementation struct {
+}
+
+func NewRecoveryCodeRepository() RecoveryCodeRepository {
+	return &RecoveryCodeRepositoryImplementation{}
+}
+
+func (repository *RecoveryCodeRepositoryImplementation) CreateMany(tx pgx.Tx, ctx context.Context, userId int32, codeHashes []string) (err error) {
+	_, err = tx.Exec(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) SELECT $1, unnest($2::varchar[]);`, userId, codeHashes)
+	return
+}
+
+func (repository *RecoveryCodeRepositoryImplementation) DeleteByUserId(tx pgx.Tx, ctx context.Context, userId int32) (err error) {
+	_, err = tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1;`, userId)
+	return
+}
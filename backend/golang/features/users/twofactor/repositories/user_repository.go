@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"backend-golang/features/users/twofactor/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserRepository interface {
+	FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error)
+	FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (user models.User, err error)
+	UpdateTotpSecret(tx pgx.Tx, ctx context.Context, id int32, totpSecret string) (rowsAffected int64, err error)
+	UpdateTotpEnabledAt(tx pgx.Tx, ctx context.Context, id int32, totpEnabledAt int64, totpLastUsedStep int64) (rowsAffected int64, err error)
+	DeleteTotp(tx pgx.Tx, ctx context.Context, id int32) (rowsAffected int64, err error)
+}
+
+type UserRepositoryImplementation struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImplementation{}
+}
+
+func (repository *UserRepositoryImplementation) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, password, totp_secret, totp_enabled_at FROM users WHERE id = $1;`, id).Scan(&user.Id, &user.Password, &user.TotpSecret, &user.TotpEnabledAt)
+	return
+}
+
+// FindByIdForUpdate locks the row so an enrollment and a confirmation cannot interleave
+func (repository *UserRepositoryImplementation) FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (user models.User, err error) {
+	err = tx.QueryRow(ctx, `SELECT id, password, totp_secret, totp_enabled_at FROM users WHERE id = $1 FOR UPDATE;`, id).Scan(&user.Id, &user.Password, &user.TotpSecret, &user.TotpEnabledAt)
+	return
+}
+
+func (repository *UserRepositoryImplementation) UpdateTotpSecret(tx pgx.Tx, ctx context.Context, id int32, totpSecret string) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `UPDATE users SET totp_secret = $1, totp_enabled_at = NULL, totp_last_used_step = NULL WHERE id = $2;`, totpSecret, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
+
+// UpdateTotpEnabledAt also stores the step of the confirming code so it cannot be replayed at login
+func (repository *UserRepositoryImplementation) UpdateTotpEnabledAt(tx pgx.Tx, ctx context.Context, id int32, totpEnabledAt int64, totpLastUsedStep int64) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `UPDATE users SET totp_enabled_at = $1, totp_last_used_step = $2 WHERE id = $3;`, totpEnabledAt, totpLastUsedStep, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
+
+func (repository *UserRepositoryImplementation) DeleteTotp(tx pgx.Tx, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	result, err := tx.Exec(ctx, `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_used_step = NULL WHERE id = $1;`, id)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	commonrepositories "backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/twofactor/controllers"
+	"backend-golang/features/users/twofactor/repositories"
+	"backend-golang/features/users/twofactor/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func TwoFactorRoute(e *echo.Echo, postgresUtil utils.PostgresUtil, validate *validator.Validate, twoFactorRepository commonrepositories.TwoFactorRepository, twoFactorHelper helpers.TwoFactorHelper, passwordHasher helpers.PasswordHasher, sessionMiddleware middlewares.SessionMiddleware) {
+	userRepository := repositories.NewUserRepository()
+	recoveryCodeRepository := repositories.NewRecoveryCodeRepository()
+	twoFactorService := services.NewTwoFactorService(postgresUtil, validate, userRepository, recoveryCodeRepository, twoFactorRepository, twoFactorHelper, passwordHasher)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService)
+	e.POST("/api/v1/users/2fa/enroll", twoFactorController.Enroll, middlewares.PrintRequestResponseLogWithNoRequestBody, middlewares.NoStore, sessionMiddleware.Authenticate)
+	e.POST("/api/v1/users/2fa/confirm", twoFactorController.Confirm, middlewares.PrintRequestResponseLog, middlewares.NoStore, sessionMiddleware.Authenticate)
+	e.POST("/api/v1/users/2fa/disable", twoFactorController.Disable, middlewares.PrintRequestResponseLog, sessionMiddleware.Authenticate)
+}
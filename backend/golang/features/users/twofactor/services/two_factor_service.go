@@ -0,0 +1,316 @@
+package services
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	commonrepositories "backend-golang/commons/repositories"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/twofactor/models"
+	"backend-golang/features/users/twofactor/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+)
+
+const totpIssuer = "EcommerceV2"
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context) (httpCode int, response helpers.Response)
+	Confirm(ctx context.Context, confirmTwoFactorRequest models.ConfirmTwoFactorRequest) (httpCode int, response helpers.Response)
+	Disable(ctx context.Context, disableTwoFactorRequest models.DisableTwoFactorRequest) (httpCode int, response helpers.Response)
+}
+
+type TwoFactorServiceImplementation struct {
+	PostgresUtil           utils.PostgresUtil
+	Validate               *validator.Validate
+	UserRepository         repositories.UserRepository
+	RecoveryCodeRepository repositories.RecoveryCodeRepository
+	TwoFactorRepository    commonrepositories.TwoFactorRepository
+	TwoFactorHelper        helpers.TwoFactorHelper
+	PasswordHasher         helpers.PasswordHasher
+}
+
+func NewTwoFactorService(postgresUtil utils.PostgresUtil, validate *validator.Validate, userRepository repositories.UserRepository, recoveryCodeRepository repositories.RecoveryCodeRepository, twoFactorRepository commonrepositories.TwoFactorRepository, twoFactorHelper helpers.TwoFactorHelper, passwordHasher helpers.PasswordHasher) TwoFactorService {
+	return &TwoFactorServiceImplementation{
+		PostgresUtil:           postgresUtil,
+		Validate:               validate,
+		UserRepository:         userRepository,
+		RecoveryCodeRepository: recoveryCodeRepository,
+		TwoFactorRepository:    twoFactorRepository,
+		TwoFactorHelper:        twoFactorHelper,
+		PasswordHasher:         passwordHasher,
+	}
+}
+
+// Enroll stores a new secret which stays inactive until a code generated from it is confirmed,
+// enrolling again before confirming replaces the secret
+func (service *TwoFactorServiceImplementation) Enroll(ctx context.Context) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	var err error
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	user, err := service.UserRepository.FindByIdForUpdate(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if user.TotpEnabledAt.Valid {
+		err = errors.New("two-factor authentication is already enabled for user " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := service.TwoFactorHelper.GenerateSecret()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	encryptedSecret, err := service.TwoFactorHelper.EncryptSecret(secret)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	rowsAffected, err := service.UserRepository.UpdateTotpSecret(tx, ctx, userId, encryptedSecret)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("rows affected not one when updating totp secret")
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	enrollTwoFactorResponse := models.EnrollTwoFactorResponse{
+		Secret: secret,
+		Uri:    helpers.ToTotpProvisioningUri(totpIssuer, ctx.Value(middlewares.EmailKey).(string), secret),
+	}
+	response = helpers.Response{
+		Data:   enrollTwoFactorResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// Confirm enables two-factor authentication once the user proves the authenticator app works,
+// the recovery codes are returned here and never again
+func (service *TwoFactorServiceImplementation) Confirm(ctx context.Context, confirmTwoFactorRequest models.ConfirmTwoFactorRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	var err error
+	err = service.Validate.Struct(confirmTwoFactorRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, confirmTwoFactorRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	user, err := service.UserRepository.FindByIdForUpdate(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if user.TotpEnabledAt.Valid {
+		err = errors.New("two-factor authentication is already enabled for user " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "two-factor authentication is already enabled")
+		return
+	}
+	if !user.TotpSecret.Valid {
+		err = errors.New("two-factor authentication is not enrolled for user " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "please enroll two-factor authentication first")
+		return
+	}
+
+	secret, err := service.TwoFactorHelper.DecryptSecret(user.TotpSecret.String)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	now := time.Now()
+	step, valid := service.TwoFactorHelper.ValidateTotp(secret, confirmTwoFactorRequest.Code, now)
+	if !valid {
+		err = errors.New("code is invalid")
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "code", Message: "code is invalid"}})
+		return
+	}
+
+	recoveryCodes, err := service.TwoFactorHelper.GenerateRecoveryCodes()
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	var codeHashes []string
+	for _, recoveryCode := range recoveryCodes {
+		codeHashes = append(codeHashes, service.TwoFactorHelper.ToRecoveryCodeHash(recoveryCode))
+	}
+
+	rowsAffected, err := service.UserRepository.UpdateTotpEnabledAt(tx, ctx, userId, now.UnixMilli(), step)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("rows affected not one when updating totp enabled at")
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	err = service.RecoveryCodeRepository.DeleteByUserId(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	err = service.RecoveryCodeRepository.CreateMany(tx, ctx, userId, codeHashes)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	recoveryCodesResponse := models.RecoveryCodesResponse{
+		Message:       "successfully enable two-factor authentication, store the recovery codes somewhere safe, they are shown only once",
+		RecoveryCodes: recoveryCodes,
+	}
+	response = helpers.Response{
+		Data:   recoveryCodesResponse,
+		Errors: nil,
+	}
+	return
+}
+
+// Disable asks for the password and a current code, the code is checked outside the transaction
+// because using it up writes the user row on its own connection
+func (service *TwoFactorServiceImplementation) Disable(ctx context.Context, disableTwoFactorRequest models.DisableTwoFactorRequest) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	userId := ctx.Value(middlewares.IdKey).(int32)
+	var err error
+	err = service.Validate.Struct(disableTwoFactorRequest)
+	if err != nil {
+		validationResult := helpers.GetValidatorError(err, disableTwoFactorRequest)
+		if validationResult != nil {
+			httpCode, response = helpers.ToResponseRequestValidation(requestId, validationResult)
+			return
+		}
+	}
+
+	user, err := service.UserRepository.FindById(service.PostgresUtil.GetPool(), ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !user.TotpEnabledAt.Valid {
+		err = errors.New("two-factor authentication is not enabled for user " + strconv.Itoa(int(userId)))
+		httpCode, response = helpers.ToResponseError(err, requestId, http.StatusBadRequest, "two-factor authentication is not enabled")
+		return
+	}
+
+	err = service.PasswordHasher.Compare(user.Password.String, disableTwoFactorRequest.Password)
+	if err != nil {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "password", Message: "wrong password"}})
+		return
+	}
+
+	valid, err := service.verifyCode(ctx, userId, user.TotpSecret.String, disableTwoFactorRequest.Code)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if !valid {
+		httpCode, response = helpers.ToResponseRequestValidation(requestId, []helpers.ErrorMessage{{Field: "code", Message: "code is invalid"}})
+		return
+	}
+
+	httpCode, response = service.deleteTotp(ctx, userId)
+	return
+}
+
+// verifyCode takes either a totp code, which cannot be used again once accepted, or an unused recovery code, which is used up
+func (service *TwoFactorServiceImplementation) verifyCode(ctx context.Context, userId int32, encryptedSecret string, code string) (valid bool, err error) {
+	secret, err := service.TwoFactorHelper.DecryptSecret(encryptedSecret)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	var rowsAffected int64
+	step, valid := service.TwoFactorHelper.ValidateTotp(secret, code, now)
+	if valid {
+		rowsAffected, err = service.TwoFactorRepository.UpdateTotpLastUsedStep(service.PostgresUtil.GetPool(), ctx, userId, step)
+	} else {
+		rowsAffected, err = service.TwoFactorRepository.UseRecoveryCode(service.PostgresUtil.GetPool(), ctx, userId, service.TwoFactorHelper.ToRecoveryCodeHash(code), now.UnixMilli())
+	}
+	if err != nil {
+		return
+	}
+	return rowsAffected == 1, nil
+}
+
+func (service *TwoFactorServiceImplementation) deleteTotp(ctx context.Context, userId int32) (httpCode int, response helpers.Response) {
+	requestId := ctx.Value(middlewares.RequestIdKey).(string)
+	tx, err := service.PostgresUtil.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	defer func() {
+		errCommitOrRollback := service.PostgresUtil.CommitOrRollback(tx, ctx, err)
+		if errCommitOrRollback != nil {
+			httpCode, response = helpers.ToResponseCheckError(errCommitOrRollback, requestId)
+		}
+	}()
+
+	rowsAffected, err := service.UserRepository.DeleteTotp(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	if rowsAffected != 1 {
+		err = errors.New("rows affected not one when deleting totp")
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+	err = service.RecoveryCodeRepository.DeleteByUserId(tx, ctx, userId)
+	if err != nil {
+		httpCode, response = helpers.ToResponseCheckError(err, requestId)
+		return
+	}
+
+	httpCode = http.StatusOK
+	responseMessage := helpers.ResponseMessage{
+		Message: "successfully disable two-factor authentication",
+	}
+	response = helpers.Response{
+		Data:   responseMessage,
+		Errors: nil,
+	}
+	return
+}
@@ -2,9 +2,12 @@ package main
 
 import (
 	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/repositories"
 	"backend-golang/commons/setups"
 	"backend-golang/commons/utils"
 	"context"
+	"log"
 	"os"
 	"os/signal"
 )
@@ -17,11 +20,42 @@ func main() {
 	defer redisUtil.Close()
 
 	validate := setups.SetValidator()
-	// bcryptHelper := helpers.NewBcryptHelper()
+	passwordHashConfig, err := helpers.GetPasswordHashConfig()
+	if err != nil {
+		log.Fatalln("error when reading password hash config: " + err.Error())
+	}
+	passwordHasher := helpers.NewPasswordHasher(passwordHashConfig)
 	uuidHelper := helpers.NewUuidHelper()
 	redisHelper := helpers.NewRedisHelper()
+	sessionRegistryHelper := helpers.NewSessionRegistryHelper()
+	tokenHelper := helpers.NewTokenHelper()
+	// every background runner logs the jobs it drops and the errors of its jobs with the request id they were queued with
+	onBackgroundError := func(ctx context.Context, err error) {
+		requestId, _ := ctx.Value(middlewares.RequestIdKey).(string)
+		helpers.PrintLogToTerminal(err, requestId)
+	}
+	mailRunner := utils.NewBackgroundRunner(1024, onBackgroundError)
+	defer mailRunner.Close()
+	mailer := utils.NewBackgroundMailer(utils.NewSmtpMailer(), mailRunner)
+	emailVerificationHelper := helpers.NewEmailVerificationHelper(tokenHelper, mailer)
+	twoFactorKey, err := helpers.GetTwoFactorKey()
+	if err != nil {
+		log.Fatalln("error when reading two-factor key: " + err.Error())
+	}
+	twoFactorHelper := helpers.NewTwoFactorHelper(twoFactorKey)
+	jwtHelper := helpers.NewJwtHelper()
+	tokenFamilyHelper := helpers.NewTokenFamilyHelper()
+	csrfTokenHelper := helpers.NewCsrfTokenHelper()
+	authEventRunner := utils.NewBackgroundRunner(1024, onBackgroundError)
+	defer authEventRunner.Close()
+	authEventHelper := helpers.NewAuthEventHelper(repositories.NewAuthEventRepository(), authEventRunner)
+	oidcHelper := helpers.NewOidcHelper()
+	personalDataRunner := utils.NewBackgroundRunner(64, onBackgroundError)
+	defer personalDataRunner.Close()
+	personalDataHelper := helpers.NewPersonalDataHelper(postgresUtil, redisUtil, repositories.NewPersonalDataRepository(), repositories.NewDataExportRepository(), repositories.NewAccountDeletionRepository(), sessionRegistryHelper, personalDataRunner)
+	defer personalDataHelper.Close()
 
-	e := setups.SetEcho(postgresUtil, redisUtil, validate, uuidHelper, redisHelper)
+	e := setups.SetEcho(postgresUtil, redisUtil, validate, passwordHasher, uuidHelper, redisHelper, sessionRegistryHelper, tokenHelper, mailer, emailVerificationHelper, twoFactorHelper, jwtHelper, tokenFamilyHelper, csrfTokenHelper, authEventHelper, oidcHelper, personalDataHelper)
 	setups.StartEcho(e)
 	defer setups.StopEcho(e)
 
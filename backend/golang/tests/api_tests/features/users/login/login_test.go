@@ -3,21 +3,30 @@ package login_test
 import (
 	"backend-golang/commons/helpers"
 	"backend-golang/commons/middlewares"
+	"backend-golang/commons/repositories"
 	"backend-golang/commons/setups"
 	"backend-golang/commons/utils"
 	"backend-golang/features/users/login/routes"
 	"backend-golang/tests/initialize"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/suite"
@@ -25,14 +34,20 @@ import (
 
 type LoginTestSuite struct {
 	suite.Suite
-	ctx          context.Context
-	postgresUtil utils.PostgresUtil
-	redisUtil    utils.RedisUtil
-	validate     *validator.Validate
-	requestBody  string
-	e            *echo.Echo
-	uuidHelper   helpers.UuidHelper
-	redisHelper  helpers.RedisHelper
+	ctx                   context.Context
+	postgresUtil          utils.PostgresUtil
+	redisUtil             utils.RedisUtil
+	validate              *validator.Validate
+	requestBody           string
+	e                     *echo.Echo
+	uuidHelper            helpers.UuidHelper
+	redisHelper           helpers.RedisHelper
+	sessionRegistryHelper helpers.SessionRegistryHelper
+	authEventRunner       *utils.BackgroundRunnerImplementation
+	authEventHelper       helpers.AuthEventHelper
+	oidcServer            *httptest.Server
+	oidcPrivateKey        *rsa.PrivateKey
+	oidcNonce             string
 }
 
 func TestLoginTestSuite(t *testing.T) {
@@ -46,11 +61,108 @@ func (sut *LoginTestSuite) SetupSuite() {
 	sut.validate = setups.SetValidator()
 	sut.uuidHelper = helpers.NewUuidHelper()
 	sut.redisHelper = helpers.NewRedisHelper()
+	sut.sessionRegistryHelper = helpers.NewSessionRegistryHelper()
+	sut.authEventRunner = utils.NewBackgroundRunner(1024, func(ctx context.Context, err error) {
+		log.Println(err)
+	})
+	sut.authEventHelper = helpers.NewAuthEventHelper(repositories.NewAuthEventRepository(), sut.authEventRunner)
+	sut.setOidcServer()
 	sut.e = echo.New()
 	sut.e.Use(echomiddleware.Recover())
 	sut.e.Use(middlewares.SetRequestId)
 	sut.e.HTTPErrorHandler = setups.CustomHTTPErrorHandler
-	routes.LoginRoute(sut.e, sut.postgresUtil, sut.redisUtil, sut.validate, sut.uuidHelper, sut.redisHelper)
+	passwordHashConfig, err := helpers.GetPasswordHashConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	routes.LoginRoute(sut.e, sut.postgresUtil, sut.redisUtil, sut.validate, sut.uuidHelper, sut.redisHelper, sut.sessionRegistryHelper, repositories.NewTwoFactorRepository(), helpers.NewTwoFactorHelper([]byte("twoFactorKeyOfAtLeastThirtyTwoCharacters")), helpers.NewJwtHelper(), helpers.NewTokenFamilyHelper(), helpers.NewPasswordHasher(passwordHashConfig), sut.authEventHelper, helpers.NewTokenHelper(), helpers.NewOidcHelper())
+}
+
+// setOidcServer starts a stub provider which signs an id token for the nonce of the last authorization and registers it as "stub"
+func (sut *LoginTestSuite) setOidcServer() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.oidcPrivateKey = privateKey
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"use": "sig",
+					"kid": "key",
+					"n":   base64.RawURLEncoding.EncodeToString(sut.oidcPrivateKey.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(sut.oidcPrivateKey.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("code") != "code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		now := time.Now()
+		jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, helpers.OidcClaims{
+			Nonce:         sut.oidcNonce,
+			Email:         "oidc@email.com",
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    sut.oidcServer.URL,
+				Subject:   "subject",
+				Audience:  jwt.ClaimStrings{"clientId"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		})
+		jwtToken.Header["kid"] = "key"
+		idToken, err := jwtToken.SignedString(sut.oidcPrivateKey)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+	sut.oidcServer = httptest.NewServer(mux)
+	os.Setenv("ECOMMERCEV2_OIDC_PROVIDERS", "stub")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_ISSUER", sut.oidcServer.URL)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_CLIENT_ID", "clientId")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_CLIENT_SECRET", "clientSecret")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_AUTHORIZATION_URL", sut.oidcServer.URL+"/authorize")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_TOKEN_URL", sut.oidcServer.URL+"/token")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_JWKS_URL", sut.oidcServer.URL+"/jwks")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_REDIRECT_URL", "https://shop.example.com/oidc/stub")
+}
+
+// authorizeOidc starts the sign in and returns the state cookie, the state and the nonce are read back from the authorization url
+func (sut *LoginTestSuite) authorizeOidc() (stateCookie *http.Cookie, state string) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/oidc/stub", nil)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusOK)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	data, _ := responseBody["data"].(map[string]interface{})
+	authorizationUrl, err := url.Parse(data["authorizationUrl"].(string))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.Equal(authorizationUrl.Query().Get("code_challenge_method"), "S256")
+	sut.oidcNonce = authorizationUrl.Query().Get("nonce")
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == helpers.OidcStateCookieName {
+			stateCookie = cookie
+		}
+	}
+	return stateCookie, authorizationUrl.Query().Get("state")
 }
 
 func (sut *LoginTestSuite) SetupTest() {
@@ -68,8 +180,13 @@ func (sut *LoginTestSuite) BeforeTest(suiteName, testName string) {
 
 func (sut *LoginTestSuite) Test1LoginValidationError() {
 	sut.T().Log("Test1LoginValidationError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	sut.requestBody = `{}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", strings.NewReader(sut.requestBody))
@@ -99,8 +216,13 @@ func (sut *LoginTestSuite) Test1LoginValidationError() {
 
 func (sut *LoginTestSuite) Test2LoginUserRepositoryFindByEmailInternalServerError() {
 	sut.T().Log("Test2LoginUserRepositoryFindByEmailInternalServerError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", strings.NewReader(sut.requestBody))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -123,8 +245,13 @@ func (sut *LoginTestSuite) Test2LoginUserRepositoryFindByEmailInternalServerErro
 
 func (sut *LoginTestSuite) Test3LoginUserRepositoryFindByEmailBadRequestWrongEmailOrPasswordError() {
 	sut.T().Log("Test3LoginUserRepositoryFindByEmailBadRequestWrongEmailOrPasswordError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", strings.NewReader(sut.requestBody))
@@ -148,8 +275,13 @@ func (sut *LoginTestSuite) Test3LoginUserRepositoryFindByEmailBadRequestWrongEma
 
 func (sut *LoginTestSuite) Test4LoginBcryptCompareHashAndPasswordBadRequestWrongEmailOrPasswordError() {
 	sut.T().Log("Test4LoginBcryptCompareHashAndPasswordBadRequestWrongEmailOrPasswordError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
@@ -178,8 +310,13 @@ func (sut *LoginTestSuite) Test4LoginBcryptCompareHashAndPasswordBadRequestWrong
 
 func (sut *LoginTestSuite) Test5LoginUserPermissionRepositoryFindByUserIdInternalServerError() {
 	sut.T().Log("Test5LoginUserPermissionRepositoryFindByUserIdInternalServerError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
@@ -204,12 +341,22 @@ func (sut *LoginTestSuite) Test5LoginUserPermissionRepositoryFindByUserIdInterna
 
 func (sut *LoginTestSuite) Test6LoginSuccess() {
 	sut.T().Log("Test6LoginSuccess")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
@@ -229,6 +376,75 @@ func (sut *LoginTestSuite) Test6LoginSuccess() {
 	sut.Equal(responseBody["errors"], nil)
 }
 
+func (sut *LoginTestSuite) Test7LoginWithOidcStateMismatchBadRequest() {
+	sut.T().Log("Test7LoginWithOidcStateMismatchBadRequest")
+	stateCookie, _ := sut.authorizeOidc()
+	sut.requestBody = `{"code": "code", "state": "otherState"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/oidc/stub", strings.NewReader(sut.requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.AddCookie(stateCookie)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusBadRequest)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.Equal(responseBody["data"], nil)
+	errorsResponseBody := responseBody["errors"].([]interface{})
+	errorMessage0, _ := errorsResponseBody[0].((map[string]interface{}))
+	sut.Equal(errorMessage0["field"], "state")
+	sut.Equal(errorMessage0["message"], "state is invalid or expired")
+}
+
+func (sut *LoginTestSuite) Test8LoginWithOidcNewUserSuccess() {
+	sut.T().Log("Test8LoginWithOidcNewUserSuccess")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	stateCookie, state := sut.authorizeOidc()
+	sut.requestBody = `{"code": "code", "state": "` + state + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/oidc/stub", strings.NewReader(sut.requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.AddCookie(stateCookie)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusOK)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.Equal(responseBody["errors"], nil)
+	sessionIdFound := false
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == helpers.SessionCookieName && cookie.Value != "" {
+			sessionIdFound = true
+		}
+	}
+	sut.True(sessionIdFound)
+	user := initialize.GetDataUserByEmail(sut.postgresUtil.GetPool(), sut.ctx, "oidc@email.com")
+	sut.True(user.EmailVerifiedAt.Valid)
+}
+
 func (sut *LoginTestSuite) AfterTest(suiteName, testName string) {
 	sut.T().Log("AfterTest: " + suiteName + " " + testName)
 }
@@ -239,4 +455,14 @@ func (sut *LoginTestSuite) TearDownTest() {
 
 func (sut *LoginTestSuite) TearDownSuite() {
 	sut.T().Log("TearDownSuite")
+	sut.authEventRunner.Close()
+	sut.oidcServer.Close()
+	os.Unsetenv("ECOMMERCEV2_OIDC_PROVIDERS")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_ISSUER")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_CLIENT_ID")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_CLIENT_SECRET")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_AUTHORIZATION_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_TOKEN_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_JWKS_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_REDIRECT_URL")
 }
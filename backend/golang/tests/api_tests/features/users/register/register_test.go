@@ -0,0 +1,171 @@
+package register_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/register/routes"
+	"backend-golang/tests/initialize"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegisterTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	postgresUtil   utils.PostgresUtil
+	redisUtil      utils.RedisUtil
+	validate       *validator.Validate
+	requestBody    string
+	e              *echo.Echo
+	passwordHasher helpers.PasswordHasher
+}
+
+func TestRegisterTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterTestSuite))
+}
+
+func (sut *RegisterTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.postgresUtil = utils.NewPostgresConnection()
+	sut.redisUtil = utils.NewRedisConnection()
+	sut.validate = setups.SetValidator()
+	passwordHashConfig, err := helpers.GetPasswordHashConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.passwordHasher = helpers.NewPasswordHasher(passwordHashConfig)
+	sut.e = echo.New()
+	sut.e.Use(echomiddleware.Recover())
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.HTTPErrorHandler = setups.CustomHTTPErrorHandler
+	routes.RegisterRoute(sut.e, sut.postgresUtil, sut.redisUtil, sut.validate, sut.passwordHasher, helpers.NewEmailVerificationHelper(helpers.NewTokenHelper(), utils.NewMemoryMailer()), utils.NewMemoryMailer())
+}
+
+func (sut *RegisterTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.ctx = context.Background()
+	sut.requestBody = `{
+		"username": "username",
+		"email": "email@email.com",
+		"password": "password@A1",
+		"confirmpassword": "password@A1"
+	}`
+}
+
+func (sut *RegisterTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterTestSuite) Test1RegisterValidationError() {
+	sut.T().Log("Test1RegisterValidationError")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	sut.requestBody = `{}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", strings.NewReader(sut.requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusBadRequest)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.Equal(responseBody["data"], nil)
+	errorsResponseBody := responseBody["errors"].([]interface{})
+	errorMessage0, _ := errorsResponseBody[0].((map[string]interface{}))
+	sut.Equal(errorMessage0["field"], "username")
+	sut.Equal(errorMessage0["message"], "is required")
+	errorMessage3, _ := errorsResponseBody[3].((map[string]interface{}))
+	sut.Equal(errorMessage3["field"], "confirmpassword")
+	sut.Equal(errorMessage3["message"], "is required")
+}
+
+func (sut *RegisterTestSuite) Test2RegisterUsernameAlreadyExistsBadRequest() {
+	sut.T().Log("Test2RegisterUsernameAlreadyExistsBadRequest")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", strings.NewReader(sut.requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusBadRequest)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.Equal(responseBody["data"], nil)
+	errorsResponseBody := responseBody["errors"].([]interface{})
+	errorMessage0, _ := errorsResponseBody[0].((map[string]interface{}))
+	sut.Equal(errorMessage0["field"], "username")
+	sut.Equal(errorMessage0["message"], "username already exists")
+	sut.Equal(len(errorsResponseBody), 1)
+}
+
+func (sut *RegisterTestSuite) Test3RegisterSuccess() {
+	sut.T().Log("Test3RegisterSuccess")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", strings.NewReader(sut.requestBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusCreated)
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.NotEqual(responseBody["data"], "")
+	sut.Equal(responseBody["errors"], nil)
+}
+
+func (sut *RegisterTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *RegisterTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,83 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"name": "Coffee", "slug": "coffee", "parentId": 0}' \
+    http://localhost:10001/api/v1/admin/categories
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"name": "Tea", "slug": "tea", "parentId": 0}' \
+    http://localhost:10001/api/v1/admin/categories
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"name": "Beans", "slug": "beans", "parentId": 1}' \
+    http://localhost:10001/api/v1/admin/categories
+
+echo ""
+
+curl -X PUT \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"parentId": 0, "categoryIds": [2, 1]}' \
+    http://localhost:10001/api/v1/admin/categories/order
+
+echo ""
+
+curl -X PUT \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"parentId": 2}' \
+    http://localhost:10001/api/v1/admin/categories/3/parent
+
+echo ""
+
+curl -X PUT \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"categoryIds": [3]}' \
+    http://localhost:10001/api/v1/admin/products/1/categories
+
+echo ""
+
+# the category tree does not need a session
+curl -X GET \
+    http://localhost:10001/api/v1/categories
+
+echo ""
+
+curl -X GET \
+    http://localhost:10001/api/v1/categories/beans/breadcrumb
+
+echo ""
+
+curl -X GET \
+    "http://localhost:10001/api/v1/categories/tea/products?limit=10"
@@ -0,0 +1,55 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"name": "Coffee Beans", "slug": "coffee-beans", "description": "roasted arabica", "price": 1299, "currency": "USD", "status": "ACTIVE"}' \
+    http://localhost:10001/api/v1/admin/products
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    "http://localhost:10001/api/v1/admin/products?status=ACTIVE&limit=10"
+
+echo ""
+
+curl -X PUT \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"name": "Coffee Beans", "slug": "coffee-beans", "description": "roasted arabica", "price": 1499, "currency": "USD", "status": "ACTIVE"}' \
+    http://localhost:10001/api/v1/admin/products/1
+
+echo ""
+
+# the catalog does not need a session
+curl -X GET \
+    "http://localhost:10001/api/v1/products?limit=10"
+
+echo ""
+
+curl -X GET \
+    http://localhost:10001/api/v1/products/coffee-beans
+
+echo ""
+
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/products/1
@@ -0,0 +1,48 @@
+#!/bin/bash
+
+# administrator only, log in with two-factor first
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"challengeId": "challengeId", "code": "123456"}' \
+    http://localhost:10001/api/v1/users/login/2fa
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# newest first, pass nextCursor of the response as cursor to get the next page
+curl -X GET \
+    -b cookie.txt \
+    "http://localhost:10001/api/v1/admin/users?email=email&createdFrom=1719496855216&limit=10"
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    "http://localhost:10001/api/v1/admin/users?email=email&createdFrom=1719496855216&limit=10&cursor=cursor"
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/users/2
+
+echo ""
+
+# disable user 2 and revoke every session of it
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/users/2/disable
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/users/2/enable
@@ -0,0 +1,44 @@
+#!/bin/bash
+
+# administrator only, log in with two-factor first
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"challengeId": "challengeId", "code": "123456"}' \
+    http://localhost:10001/api/v1/users/login/2fa
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# the key is only in this response, expiresAt is unix millis and can be left out for a key which never expires
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"name": "warehouse", "userId": 2, "permissionIds": [3], "expiresAt": 1893456000000}' \
+    http://localhost:10001/api/v1/admin/api-keys
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/api-keys
+
+echo ""
+
+# a route which requires permissions takes the key instead of the cookie, READ_PERMISSION
+curl -X GET \
+    -H "X-API-Key: ek_key" \
+    http://localhost:10001/api/v1/permissions
+
+echo ""
+
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/admin/api-keys/1
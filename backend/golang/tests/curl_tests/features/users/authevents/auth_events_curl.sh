@@ -0,0 +1,21 @@
+#!/bin/bash
+
+# administrator only, log in with two-factor first
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"challengeId": "challengeId", "code": "123456"}' \
+    http://localhost:10001/api/v1/users/login/2fa
+
+echo ""
+
+# newest first, pass nextCursor of the response as cursor to get the next page
+curl -X GET \
+    -b cookie.txt \
+    "http://localhost:10001/api/v1/admin/auth-events?eventType=LOGIN_FAILED&email=email&createdFrom=1719496855216&limit=10"
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    "http://localhost:10001/api/v1/admin/auth-events?userId=1&ip=127.0.0.1&limit=10&cursor=cursor"
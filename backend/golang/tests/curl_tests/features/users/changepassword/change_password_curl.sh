@@ -0,0 +1,25 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+# state-changing requests with the session cookie send the csrf token of the session in X-CSRF-Token
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# the response sets a new session cookie, every other session is logged out
+curl -X PUT \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -c cookie.txt \
+    -d '{"currentpassword": "password@A1", "password": "password@A2", "confirmpassword": "password@A2"}' \
+    http://localhost:10001/api/v1/users/password
@@ -0,0 +1,28 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+# the same session always gets the same csrf token
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# without X-CSRF-Token the request is rejected with 403
+curl -X DELETE \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/sessions
+
+echo ""
+
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/sessions
@@ -0,0 +1,14 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"email": "email@email.com"}' \
+    http://localhost:10001/api/v1/users/email/verification/resend
+
+echo ""
+
+# use the token from the verification link in the mail
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"token": "token"}' \
+    http://localhost:10001/api/v1/users/email/verification/confirm
@@ -0,0 +1,15 @@
+#!/bin/bash
+
+curl -X GET \
+    -H "X-REQUEST-ID: requestId" \
+    -c cookie.txt \
+    http://localhost:10001/api/v1/users/oidc/google
+
+echo ""
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-REQUEST-ID: requestId" \
+    -b cookie.txt \
+    -d '{"code": "code", "state": "state"}' \
+    http://localhost:10001/api/v1/users/oidc/google
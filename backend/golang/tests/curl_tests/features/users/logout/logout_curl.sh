@@ -0,0 +1,29 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+# state-changing requests with the session cookie send the csrf token of the session in X-CSRF-Token
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -i \
+    http://localhost:10001/api/v1/users/logout
+
+echo ""
+
+# logging out again with the same cookie is still successful, the session is gone so no csrf token is needed
+curl -X POST \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/logout
@@ -0,0 +1,14 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"email": "email@email.com"}' \
+    http://localhost:10001/api/v1/users/password/forgot
+
+echo ""
+
+# use the token from the reset link in the mail
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"token": "token", "password": "password@A2", "confirmpassword": "password@A2"}' \
+    http://localhost:10001/api/v1/users/password/reset
@@ -0,0 +1,73 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# READ_PERMISSION
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/permissions
+
+echo ""
+
+# CREATE_PERMISSION
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"permission": "READ_PRODUCT"}' \
+    http://localhost:10001/api/v1/permissions
+
+echo ""
+
+# UPDATE_PERMISSION, built-in permissions cannot be renamed
+curl -X PUT \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"permission": "VIEW_PRODUCT"}' \
+    http://localhost:10001/api/v1/permissions/6
+
+echo ""
+
+# CREATE_PERMISSION, only permissions you have can be granted
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"permissionId": 3}' \
+    http://localhost:10001/api/v1/users/2/permissions
+
+echo ""
+
+# READ_PERMISSION
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/2/permissions
+
+echo ""
+
+# DELETE_PERMISSION, also revokes every session of user 2
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/2/permissions/3
+
+echo ""
+
+# DELETE_PERMISSION, a permission still granted to someone cannot be deleted
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/permissions/6
@@ -0,0 +1,65 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/me/data-exports
+
+echo ""
+
+# wait until the export is READY
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/me/data-exports
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    -o data-export.json \
+    http://localhost:10001/api/v1/users/me/data-exports/1/archive
+
+echo ""
+
+# logs every session out, log in again to cancel
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/me/deletion
+
+echo ""
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/me/deletion
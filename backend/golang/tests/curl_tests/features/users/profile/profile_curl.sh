@@ -0,0 +1,37 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/me
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# the username changes right away, the new email needs the current password and gets a confirmation link
+curl -X PATCH \
+    -H "X-CSRF-Token: csrfToken" \
+    -H "Content-Type: application/json" \
+    -b cookie.txt \
+    -d '{"username": "username2", "email": "email2@email.com", "currentpassword": "password@A1"}' \
+    http://localhost:10001/api/v1/users/me
+
+echo ""
+
+# use the token from the link in the mail
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"token": "token"}' \
+    http://localhost:10001/api/v1/users/email/change/confirm
@@ -0,0 +1,13 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{}' \
+    http://localhost:10001/api/v1/users/register
+
+echo ""
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"username": "username", "email": "email@email.com", "password": "password@A1", "confirmpassword": "password@A1"}' \
+    http://localhost:10001/api/v1/users/register
@@ -0,0 +1,108 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# CREATE_PERMISSION
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"role": "SUPPORT"}' \
+    http://localhost:10001/api/v1/roles
+
+echo ""
+
+# CREATE_PERMISSION, CATALOG inherits every permission of SUPPORT
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"role": "CATALOG", "parentId": 1}' \
+    http://localhost:10001/api/v1/roles
+
+echo ""
+
+# UPDATE_PERMISSION, only permissions you have can be granted
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"permissionId": 3}' \
+    http://localhost:10001/api/v1/roles/1/permissions
+
+echo ""
+
+# READ_PERMISSION
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/roles
+
+echo ""
+
+# READ_PERMISSION
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/roles/1
+
+echo ""
+
+# UPDATE_PERMISSION, a role cannot inherit from itself or from a role inheriting from it
+curl -X PUT \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"role": "SUPPORT", "parentId": 2}' \
+    http://localhost:10001/api/v1/roles/1
+
+echo ""
+
+# CREATE_PERMISSION, applies from the next login of user 2
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"roleId": 2}' \
+    http://localhost:10001/api/v1/users/2/roles
+
+echo ""
+
+# READ_PERMISSION
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/2/roles
+
+echo ""
+
+# UPDATE_PERMISSION, also revokes every session of the users of SUPPORT and CATALOG
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/roles/1/permissions/3
+
+echo ""
+
+# DELETE_PERMISSION, also revokes every session of user 2
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/2/roles/2
+
+echo ""
+
+# DELETE_PERMISSION, a role still inherited by another role cannot be deleted
+curl -X DELETE \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/roles/1
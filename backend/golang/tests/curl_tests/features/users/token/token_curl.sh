@@ -0,0 +1,26 @@
+#!/bin/bash
+
+# the response has the access token and the refresh token
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/token
+
+echo ""
+
+curl -X GET \
+    -H "Authorization: Bearer accessToken" \
+    http://localhost:10001/api/v1/users/sessions
+
+echo ""
+
+# the refresh token works once, the response has a new pair
+curl -X POST \
+    -H "X-Refresh-Token: refreshToken" \
+    http://localhost:10001/api/v1/users/token/refresh
+
+echo ""
+
+curl -X POST \
+    -H "X-Refresh-Token: refreshToken" \
+    http://localhost:10001/api/v1/users/token/revoke
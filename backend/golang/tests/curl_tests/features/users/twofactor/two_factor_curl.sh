@@ -0,0 +1,65 @@
+#!/bin/bash
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+# state-changing requests with the session cookie send the csrf token of the session in X-CSRF-Token
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+# the response has the secret and the otpauth uri to show as a qr code
+curl -X POST \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/2fa/enroll
+
+echo ""
+
+# the response has the recovery codes, they are shown only once
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"code": "123456"}' \
+    http://localhost:10001/api/v1/users/2fa/confirm
+
+echo ""
+
+# with two-factor enabled login answers with a challengeId instead of a session cookie
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -d '{"email": "email@email.com", "password": "password@A1"}' \
+    http://localhost:10001/api/v1/users/login
+
+echo ""
+
+# a recovery code can be used instead of the totp code
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -c cookie.txt \
+    -d '{"challengeId": "challengeId", "code": "123456"}' \
+    http://localhost:10001/api/v1/users/login/2fa
+
+echo ""
+
+# the new session needs its own csrf token
+curl -X GET \
+    -b cookie.txt \
+    http://localhost:10001/api/v1/users/csrf
+
+echo ""
+
+curl -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-CSRF-Token: csrfToken" \
+    -b cookie.txt \
+    -d '{"password": "password@A1", "code": "123456"}' \
+    http://localhost:10001/api/v1/users/2fa/disable
@@ -0,0 +1,35 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableAuthEvent(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE auth_events (
+  		id SERIAL PRIMARY KEY,
+  		event_type varchar(30) NOT NULL,
+  		user_id int,
+  		email text NOT NULL,
+  		ip varchar(45) NOT NULL,
+  		user_agent text NOT NULL,
+  		request_id varchar(36) NOT NULL,
+  		created_at bigint NOT NULL
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table auth_events:", err.Error())
+	}
+	log.Println("create table auth_events succedded")
+}
+
+func DropTableAuthEvent(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS auth_events;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table auth_events:", err.Error())
+	}
+	log.Println("drop table auth_events succedded")
+}
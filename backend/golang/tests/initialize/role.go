@@ -0,0 +1,31 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableRole(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE roles (
+  		id SERIAL PRIMARY KEY,
+  		role varchar(50) NOT NULL UNIQUE,
+  		parent_id int,
+    	CONSTRAINT role_ibfk_1 FOREIGN KEY(parent_id) REFERENCES roles(id)
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table roles:", err.Error())
+	}
+	log.Println("create table roles succedded")
+}
+
+func DropTableRole(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS roles;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table roles:", err.Error())
+	}
+	log.Println("drop table roles succedded")
+}
@@ -0,0 +1,33 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableRolePermission(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE role_permissions (
+  		id SERIAL PRIMARY KEY,
+  		role_id int NOT NULL,
+  		permission_id int NOT NULL,
+    	CONSTRAINT role_permission_ibfk_1 FOREIGN KEY(role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    	CONSTRAINT role_permission_ibfk_2 FOREIGN KEY(permission_id) REFERENCES permissions(id),
+    	CONSTRAINT role_permission_unique UNIQUE(role_id, permission_id)
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table role_permissions:", err.Error())
+	}
+	log.Println("create table role_permissions succedded")
+}
+
+func DropTableRolePermission(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS role_permissions;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table role_permissions:", err.Error())
+	}
+	log.Println("drop table role_permissions succedded")
+}
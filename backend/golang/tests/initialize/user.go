@@ -13,8 +13,15 @@ func CreateTableUser(pool *pgxpool.Pool, ctx context.Context) {
   		id SERIAL PRIMARY KEY,
   		username varchar(50) NOT NULL UNIQUE,
   		email varchar(100) NOT NULL UNIQUE,
-  		password varchar(100) NOT NULL,
-  		created_at bigint NOT NULL
+  		password varchar(255) NOT NULL,
+  		created_at bigint NOT NULL,
+  		email_verified_at bigint,
+  		totp_secret varchar(255),
+  		totp_enabled_at bigint,
+  		totp_last_used_step bigint,
+  		disabled_at bigint,
+  		deletion_requested_at bigint,
+  		deleted_at bigint
 	);`
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
@@ -33,16 +40,17 @@ func CreateDataUser(pool *pgxpool.Pool, ctx context.Context) {
 }
 
 type User struct {
-	Id        pgtype.Int4
-	Username  pgtype.Text
-	Email     pgtype.Text
-	Password  pgtype.Text
-	CreatedAt pgtype.Int8
+	Id              pgtype.Int4
+	Username        pgtype.Text
+	Email           pgtype.Text
+	Password        pgtype.Text
+	CreatedAt       pgtype.Int8
+	EmailVerifiedAt pgtype.Int8
 }
 
 func GetDataUserByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (user User) {
-	query := `SELECT id, username, email, password, created_at FROM users WHERE email = $1;`
-	err := pool.QueryRow(ctx, query, email).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.CreatedAt)
+	query := `SELECT id, username, email, password, created_at, email_verified_at FROM users WHERE email = $1;`
+	err := pool.QueryRow(ctx, query, email).Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.EmailVerifiedAt)
 	if err != nil {
 		log.Fatalln("error when getting data users:", err.Error())
 	}
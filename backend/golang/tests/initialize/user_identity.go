@@ -0,0 +1,33 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableUserIdentity(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE user_identities (
+  		id SERIAL PRIMARY KEY,
+  		user_id int NOT NULL REFERENCES users(id),
+  		provider varchar(50) NOT NULL,
+  		subject varchar(255) NOT NULL,
+  		created_at bigint NOT NULL,
+  		CONSTRAINT user_identity_unique UNIQUE (provider, subject)
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table user_identities:", err.Error())
+	}
+	log.Println("create table user_identities succedded")
+}
+
+func DropTableUserIdentity(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS user_identities;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table user_identities:", err.Error())
+	}
+	log.Println("drop table user_identities succedded")
+}
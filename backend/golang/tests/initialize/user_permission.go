@@ -13,7 +13,8 @@ func CreateTableUserPermission(pool *pgxpool.Pool, ctx context.Context) {
   		user_id int NOT NULL,
   		permission_id int NOT NULL,
     	CONSTRAINT user_permission_ibfk_1 FOREIGN KEY(user_id) REFERENCES users(id),
-    	CONSTRAINT user_permission_ibfk_2 FOREIGN KEY(permission_id) REFERENCES permissions(id)
+    	CONSTRAINT user_permission_ibfk_2 FOREIGN KEY(permission_id) REFERENCES permissions(id),
+    	CONSTRAINT user_permission_unique UNIQUE(user_id, permission_id)
 	);`
 	_, err := pool.Exec(ctx, query)
 	if err != nil {
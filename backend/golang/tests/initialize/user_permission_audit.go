@@ -0,0 +1,34 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableUserPermissionAudit(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE user_permission_audits (
+  		id SERIAL PRIMARY KEY,
+  		user_id int NOT NULL,
+  		permission_id int NOT NULL,
+  		action varchar(10) NOT NULL,
+  		actor_id int NOT NULL,
+  		request_id varchar(36) NOT NULL,
+  		created_at bigint NOT NULL
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table user_permission_audits:", err.Error())
+	}
+	log.Println("create table user_permission_audits succedded")
+}
+
+func DropTableUserPermissionAudit(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS user_permission_audits;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table user_permission_audits:", err.Error())
+	}
+	log.Println("drop table user_permission_audits succedded")
+}
@@ -0,0 +1,32 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableUserRecoveryCode(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE user_recovery_codes (
+  		id SERIAL PRIMARY KEY,
+  		user_id int NOT NULL,
+  		code_hash varchar(64) NOT NULL,
+  		used_at bigint,
+    	CONSTRAINT user_recovery_code_ibfk_1 FOREIGN KEY(user_id) REFERENCES users(id)
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table user_recovery_codes:", err.Error())
+	}
+	log.Println("create table user_recovery_codes succedded")
+}
+
+func DropTableUserRecoveryCode(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS user_recovery_codes;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table user_recovery_codes:", err.Error())
+	}
+	log.Println("drop table user_recovery_codes succedded")
+}
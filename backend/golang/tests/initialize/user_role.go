@@ -0,0 +1,33 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableUserRole(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE user_roles (
+  		id SERIAL PRIMARY KEY,
+  		user_id int NOT NULL,
+  		role_id int NOT NULL,
+    	CONSTRAINT user_role_ibfk_1 FOREIGN KEY(user_id) REFERENCES users(id),
+    	CONSTRAINT user_role_ibfk_2 FOREIGN KEY(role_id) REFERENCES roles(id),
+    	CONSTRAINT user_role_unique UNIQUE(user_id, role_id)
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table user_roles:", err.Error())
+	}
+	log.Println("create table user_roles succedded")
+}
+
+func DropTableUserRole(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS user_roles;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table user_roles:", err.Error())
+	}
+	log.Println("drop table user_roles succedded")
+}
@@ -0,0 +1,34 @@
+package initialize
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func CreateTableUserRoleAudit(pool *pgxpool.Pool, ctx context.Context) {
+	query := `CREATE TABLE user_role_audits (
+  		id SERIAL PRIMARY KEY,
+  		user_id int NOT NULL,
+  		role_id int NOT NULL,
+  		action varchar(10) NOT NULL,
+  		actor_id int NOT NULL,
+  		request_id varchar(36) NOT NULL,
+  		created_at bigint NOT NULL
+	);`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when creating table user_role_audits:", err.Error())
+	}
+	log.Println("create table user_role_audits succedded")
+}
+
+func DropTableUserRoleAudit(pool *pgxpool.Pool, ctx context.Context) {
+	query := `DROP TABLE IF EXISTS user_role_audits;`
+	_, err := pool.Exec(ctx, query)
+	if err != nil {
+		log.Fatalln("error when dropping table user_role_audits:", err.Error())
+	}
+	log.Println("drop table user_role_audits succedded")
+}
@@ -3,6 +3,7 @@ package services_test
 import (
 	"backend-golang/commons/helpers"
 	"backend-golang/commons/middlewares"
+	commonrepositories "backend-golang/commons/repositories"
 	"backend-golang/commons/setups"
 	"backend-golang/commons/utils"
 	"backend-golang/features/users/login/models"
@@ -10,6 +11,7 @@ import (
 	"backend-golang/features/users/login/services"
 	"backend-golang/tests/initialize"
 	"context"
+	"log"
 	"net/http"
 	"testing"
 
@@ -20,16 +22,27 @@ import (
 
 type LoginServiceTestSuite struct {
 	suite.Suite
-	ctx                      context.Context
-	postgresUtil             utils.PostgresUtil
-	redisUtil                utils.RedisUtil
-	loginRequest             models.LoginRequest
-	validate                 *validator.Validate
-	userRepository           repositories.UserRepository
-	userPermissionRepository repositories.UserPermissionRepository
-	uuidHelper               helpers.UuidHelper
-	residHelper              helpers.RedisHelper
-	loginService             services.LoginService
+	ctx                          context.Context
+	postgresUtil                 utils.PostgresUtil
+	redisUtil                    utils.RedisUtil
+	loginRequest                 models.LoginRequest
+	validate                     *validator.Validate
+	userRepository               repositories.UserRepository
+	userPermissionRepository     repositories.UserPermissionRepository
+	loginAttemptRepository       repositories.LoginAttemptRepository
+	twoFactorChallengeRepository repositories.TwoFactorChallengeRepository
+	uuidHelper                   helpers.UuidHelper
+	residHelper                  helpers.RedisHelper
+	sessionRegistryHelper        helpers.SessionRegistryHelper
+	twoFactorHelper              helpers.TwoFactorHelper
+	jwtHelper                    helpers.JwtHelper
+	tokenFamilyHelper            helpers.TokenFamilyHelper
+	passwordHasher               helpers.PasswordHasher
+	authEventRunner              *utils.BackgroundRunnerImplementation
+	authEventHelper              helpers.AuthEventHelper
+	userAgent                    string
+	ip                           string
+	loginService                 services.LoginService
 }
 
 func TestLoginTestSuite(t *testing.T) {
@@ -46,9 +59,24 @@ func (sut *LoginServiceTestSuite) SetupSuite() {
 	setups.TelephoneValidator(sut.validate)
 	sut.userRepository = repositories.NewUserRepository()
 	sut.userPermissionRepository = repositories.NewUserPermissinoRepository()
+	sut.loginAttemptRepository = repositories.NewLoginAttemptRepository()
+	sut.twoFactorChallengeRepository = repositories.NewTwoFactorChallengeRepository()
 	sut.uuidHelper = helpers.NewUuidHelper()
 	sut.residHelper = helpers.NewRedisHelper()
-	sut.loginService = services.NewLoginService(sut.postgresUtil, sut.redisUtil, sut.validate, sut.userRepository, sut.userPermissionRepository, sut.uuidHelper, sut.residHelper)
+	sut.sessionRegistryHelper = helpers.NewSessionRegistryHelper()
+	sut.twoFactorHelper = helpers.NewTwoFactorHelper([]byte("twoFactorKeyOfAtLeastThirtyTwoCharacters"))
+	sut.jwtHelper = helpers.NewJwtHelper()
+	sut.tokenFamilyHelper = helpers.NewTokenFamilyHelper()
+	passwordHashConfig, err := helpers.GetPasswordHashConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.passwordHasher = helpers.NewPasswordHasher(passwordHashConfig)
+	sut.authEventRunner = utils.NewBackgroundRunner(1024, func(ctx context.Context, err error) {
+		log.Println(err)
+	})
+	sut.authEventHelper = helpers.NewAuthEventHelper(commonrepositories.NewAuthEventRepository(), sut.authEventRunner)
+	sut.loginService = services.NewLoginService(sut.postgresUtil, sut.redisUtil, sut.validate, sut.userRepository, sut.userPermissionRepository, sut.loginAttemptRepository, sut.twoFactorChallengeRepository, repositories.NewOidcStateRepository(), repositories.NewUserIdentityRepository(), commonrepositories.NewTwoFactorRepository(), sut.uuidHelper, sut.residHelper, sut.sessionRegistryHelper, sut.twoFactorHelper, sut.jwtHelper, sut.tokenFamilyHelper, sut.passwordHasher, sut.authEventHelper, helpers.NewTokenHelper(), helpers.NewOidcHelper())
 }
 
 func (sut *LoginServiceTestSuite) SetupTest() {
@@ -58,6 +86,8 @@ func (sut *LoginServiceTestSuite) SetupTest() {
 		Email:    "email@email.com",
 		Password: "password@A1",
 	}
+	sut.userAgent = "Mozilla/5.0"
+	sut.ip = "127.0.0.1"
 }
 
 func (sut *LoginServiceTestSuite) BeforeTest(suiteName, testName string) {
@@ -66,11 +96,16 @@ func (sut *LoginServiceTestSuite) BeforeTest(suiteName, testName string) {
 
 func (sut *LoginServiceTestSuite) Test1LoginValidationError() {
 	sut.T().Log("Test1LoginValidationError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	sut.loginRequest = models.LoginRequest{}
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
@@ -83,10 +118,15 @@ func (sut *LoginServiceTestSuite) Test1LoginValidationError() {
 
 func (sut *LoginServiceTestSuite) Test2LoginUserRepositoryFindByEmailInternalServerError() {
 	sut.T().Log("Test2LoginUserRepositoryFindByEmailInternalServerError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusInternalServerError)
 	sut.Equal(response.Data, nil)
@@ -97,11 +137,16 @@ func (sut *LoginServiceTestSuite) Test2LoginUserRepositoryFindByEmailInternalSer
 
 func (sut *LoginServiceTestSuite) Test3LoginUserRepositoryFindByEmailBadRequestWrongEmailOrPasswordError() {
 	sut.T().Log("Test3LoginUserRepositoryFindByEmailBadRequestWrongEmailOrPasswordError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
@@ -112,13 +157,18 @@ func (sut *LoginServiceTestSuite) Test3LoginUserRepositoryFindByEmailBadRequestW
 
 func (sut *LoginServiceTestSuite) Test4LoginBcryptCompareHashAndPasswordBadRequestWrongEmailOrPasswordError() {
 	sut.T().Log("Test4LoginBcryptCompareHashAndPasswordBadRequestWrongEmailOrPasswordError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
 	sut.loginRequest.Password = "password@A1-"
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
@@ -129,12 +179,17 @@ func (sut *LoginServiceTestSuite) Test4LoginBcryptCompareHashAndPasswordBadReque
 
 func (sut *LoginServiceTestSuite) Test5LoginUserPermissionRepositoryFindByUserIdInternalServerError() {
 	sut.T().Log("Test5LoginUserPermissionRepositoryFindByUserIdInternalServerError")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusInternalServerError)
 	sut.Equal(response.Data, nil)
@@ -145,16 +200,26 @@ func (sut *LoginServiceTestSuite) Test5LoginUserPermissionRepositoryFindByUserId
 
 func (sut *LoginServiceTestSuite) Test6LoginSuccess() {
 	sut.T().Log("Test6LoginSuccess")
+	initialize.DropTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableAuthEvent(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataPermission(sut.postgresUtil.GetPool(), sut.ctx)
 	initialize.CreateDataUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.NotEqual(sessionId, "")
 	sut.Equal(httpCode, http.StatusOK)
 	sut.Equal(response.Errors, nil)
@@ -172,4 +237,5 @@ func (sut *LoginServiceTestSuite) TearDownTest() {
 
 func (sut *LoginServiceTestSuite) TearDownSuite() {
 	sut.T().Log("TearDownSuite")
+	sut.authEventRunner.Close()
 }
@@ -0,0 +1,167 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/register/models"
+	"backend-golang/features/users/register/repositories"
+	"backend-golang/features/users/register/services"
+	"backend-golang/tests/initialize"
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegisterServiceTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	postgresUtil    utils.PostgresUtil
+	redisUtil       utils.RedisUtil
+	mailer          *utils.MemoryMailerImplementation
+	registerRequest models.RegisterRequest
+	validate        *validator.Validate
+	userRepository  repositories.UserRepository
+	passwordHasher  helpers.PasswordHasher
+	registerService services.RegisterService
+}
+
+func TestRegisterTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterServiceTestSuite))
+}
+
+func (sut *RegisterServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.postgresUtil = utils.NewPostgresConnection()
+	sut.redisUtil = utils.NewRedisConnection()
+	sut.mailer = utils.NewMemoryMailer()
+	sut.validate = validator.New()
+	setups.UsernameValidator(sut.validate)
+	setups.PasswordValidator(sut.validate)
+	setups.TelephoneValidator(sut.validate)
+	sut.userRepository = repositories.NewUserRepository()
+	passwordHashConfig, err := helpers.GetPasswordHashConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.passwordHasher = helpers.NewPasswordHasher(passwordHashConfig)
+	sut.registerService = services.NewRegisterService(sut.postgresUtil, sut.redisUtil, sut.validate, sut.userRepository, sut.passwordHasher, helpers.NewEmailVerificationHelper(helpers.NewTokenHelper(), sut.mailer), sut.mailer)
+}
+
+func (sut *RegisterServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.registerRequest = models.RegisterRequest{
+		Username:        "username",
+		Email:           "email@email.com",
+		Password:        "password@A1",
+		Confirmpassword: "password@A1",
+	}
+}
+
+func (sut *RegisterServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterServiceTestSuite) Test1RegisterValidationError() {
+	sut.T().Log("Test1RegisterValidationError")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	sut.registerRequest = models.RegisterRequest{}
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "is required")
+	sut.Equal(errorMessages[1].Field, "email")
+	sut.Equal(errorMessages[1].Message, "is required")
+	sut.Equal(errorMessages[2].Field, "password")
+	sut.Equal(errorMessages[2].Message, "is required")
+	sut.Equal(errorMessages[3].Field, "confirmpassword")
+	sut.Equal(errorMessages[3].Message, "is required")
+}
+
+func (sut *RegisterServiceTestSuite) Test2RegisterUserRepositoryCountByUsernameInternalServerError() {
+	sut.T().Log("Test2RegisterUserRepositoryCountByUsernameInternalServerError")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *RegisterServiceTestSuite) Test3RegisterUsernameAlreadyExistsBadRequest() {
+	sut.T().Log("Test3RegisterUsernameAlreadyExistsBadRequest")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateDataUser(sut.postgresUtil.GetPool(), sut.ctx)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "username already exists")
+	sut.Equal(len(errorMessages), 1)
+}
+
+func (sut *RegisterServiceTestSuite) Test4RegisterSuccess() {
+	sut.T().Log("Test4RegisterSuccess")
+	initialize.DropTableUserRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRolePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableRole(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserPermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTablePermission(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUserIdentity(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.DropTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	initialize.CreateTableUser(sut.postgresUtil.GetPool(), sut.ctx)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully register")
+	user := initialize.GetDataUserByEmail(sut.postgresUtil.GetPool(), sut.ctx, sut.registerRequest.Email)
+	sut.Equal(user.Username.String, sut.registerRequest.Username)
+	err := sut.passwordHasher.Compare(user.Password.String, sut.registerRequest.Password)
+	sut.Equal(err, nil)
+	sut.Equal(user.EmailVerifiedAt.Valid, false)
+	mails := sut.mailer.FindAll()
+	sut.Equal(mails[len(mails)-1].To, sut.registerRequest.Email)
+}
+
+func (sut *RegisterServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *RegisterServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,17 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuthEventHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *AuthEventHelperMock) Record(pool *pgxpool.Pool, ctx context.Context, authEvent models.AuthEvent) {
+	helper.Mock.Called(pool, ctx, authEvent)
+}
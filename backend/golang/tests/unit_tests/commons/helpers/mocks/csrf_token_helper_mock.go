@@ -0,0 +1,23 @@
+package mockhelpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type CsrfTokenHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *CsrfTokenHelperMock) FindOrCreate(client *redis.Client, ctx context.Context, sessionId string, token string, expiration time.Duration) (result string, err error) {
+	arguments := helper.Mock.Called(client, ctx, sessionId, token, expiration)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (helper *CsrfTokenHelperMock) Find(client *redis.Client, ctx context.Context, sessionId string) (result string, err error) {
+	arguments := helper.Mock.Called(client, ctx, sessionId)
+	return arguments.String(0), arguments.Error(1)
+}
@@ -0,0 +1,23 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/helpers"
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type EmailVerificationHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *EmailVerificationHelperMock) Send(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error) {
+	arguments := helper.Mock.Called(client, ctx, userId, username, email)
+	return arguments.Error(0)
+}
+
+func (helper *EmailVerificationHelperMock) FindAndDelete(client *redis.Client, ctx context.Context, token string) (emailVerificationToken helpers.EmailVerificationToken, err error) {
+	arguments := helper.Mock.Called(client, ctx, token)
+	return arguments.Get(0).(helpers.EmailVerificationToken), arguments.Error(1)
+}
@@ -0,0 +1,31 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/helpers"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type JwtHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *JwtHelperMock) SignAccessToken(claims helpers.AccessTokenClaims) (token string, err error) {
+	arguments := helper.Mock.Called(claims)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (helper *JwtHelperMock) SignRefreshToken(claims helpers.RefreshTokenClaims) (token string, err error) {
+	arguments := helper.Mock.Called(claims)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (helper *JwtHelperMock) ParseAccessToken(token string) (claims helpers.AccessTokenClaims, err error) {
+	arguments := helper.Mock.Called(token)
+	return arguments.Get(0).(helpers.AccessTokenClaims), arguments.Error(1)
+}
+
+func (helper *JwtHelperMock) ParseRefreshToken(token string) (claims helpers.RefreshTokenClaims, err error) {
+	arguments := helper.Mock.Called(token)
+	return arguments.Get(0).(helpers.RefreshTokenClaims), arguments.Error(1)
+}
@@ -0,0 +1,22 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/helpers"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OidcHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *OidcHelperMock) ExchangeCode(ctx context.Context, oidcProvider helpers.OidcProvider, code string, codeVerifier string) (idToken string, err error) {
+	arguments := helper.Mock.Called(ctx, oidcProvider, code, codeVerifier)
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
+func (helper *OidcHelperMock) VerifyIdToken(ctx context.Context, oidcProvider helpers.OidcProvider, idToken string, nonce string) (claims helpers.OidcClaims, err error) {
+	arguments := helper.Mock.Called(ctx, oidcProvider, idToken, nonce)
+	return arguments.Get(0).(helpers.OidcClaims), arguments.Error(1)
+}
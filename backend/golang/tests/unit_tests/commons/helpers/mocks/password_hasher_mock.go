@@ -0,0 +1,27 @@
+package mockhelpers
+
+import "github.com/stretchr/testify/mock"
+
+type PasswordHasherMock struct {
+	Mock mock.Mock
+}
+
+func (hasher *PasswordHasherMock) Hash(password string) (hash string, err error) {
+	arguments := hasher.Mock.Called(password)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (hasher *PasswordHasherMock) Compare(hash string, password string) (err error) {
+	arguments := hasher.Mock.Called(hash, password)
+	return arguments.Error(0)
+}
+
+func (hasher *PasswordHasherMock) NeedsRehash(hash string) (needsRehash bool, err error) {
+	arguments := hasher.Mock.Called(hash)
+	return arguments.Bool(0), arguments.Error(1)
+}
+
+func (hasher *PasswordHasherMock) DummyHash() (dummyHash string, err error) {
+	arguments := hasher.Mock.Called()
+	return arguments.String(0), arguments.Error(1)
+}
@@ -0,0 +1,15 @@
+package mockhelpers
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PersonalDataHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *PersonalDataHelperMock) Export(ctx context.Context, dataExportId int32, userId int32) {
+	helper.Mock.Called(ctx, dataExportId, userId)
+}
@@ -12,6 +12,11 @@ type RedisHelperMock struct {
 	Mock mock.Mock
 }
 
+func (helper *RedisHelperMock) Get(client *redis.Client, ctx context.Context, key string) (result string, err error) {
+	arguments := helper.Mock.Called(client, ctx, key)
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
 func (helper *RedisHelperMock) Set(client *redis.Client, ctx context.Context, key string, value interface{}, expiration time.Duration) (result string, err error) {
 	arguments := helper.Mock.Called(client, ctx, key, value, expiration)
 	return arguments.Get(0).(string), arguments.Error(1)
@@ -21,3 +26,8 @@ func (helper *RedisHelperMock) Del(client *redis.Client, ctx context.Context, ke
 	arguments := helper.Mock.Called(client, ctx, key)
 	return arguments.Get(0).(int64), arguments.Error(1)
 }
+
+func (helper *RedisHelperMock) Expire(client *redis.Client, ctx context.Context, key string, expiration time.Duration) (result bool, err error) {
+	arguments := helper.Mock.Called(client, ctx, key, expiration)
+	return arguments.Bool(0), arguments.Error(1)
+}
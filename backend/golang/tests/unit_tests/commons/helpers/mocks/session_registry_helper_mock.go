@@ -0,0 +1,39 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/helpers"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type SessionRegistryHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *SessionRegistryHelperMock) Register(client *redis.Client, ctx context.Context, userId int32, sessionInfo helpers.SessionInfo, expiration time.Duration) (err error) {
+	arguments := helper.Mock.Called(client, ctx, userId, sessionInfo, expiration)
+	return arguments.Error(0)
+}
+
+func (helper *SessionRegistryHelperMock) Unregister(client *redis.Client, ctx context.Context, userId int32, sessionId string) (err error) {
+	arguments := helper.Mock.Called(client, ctx, userId, sessionId)
+	return arguments.Error(0)
+}
+
+func (helper *SessionRegistryHelperMock) FindAllByUserId(client *redis.Client, ctx context.Context, userId int32) (sessionInfos []helpers.SessionInfo, err error) {
+	arguments := helper.Mock.Called(client, ctx, userId)
+	return arguments.Get(0).([]helpers.SessionInfo), arguments.Error(1)
+}
+
+func (helper *SessionRegistryHelperMock) DeleteAllByUserId(client *redis.Client, ctx context.Context, userId int32, exceptSessionId string) (err error) {
+	arguments := helper.Mock.Called(client, ctx, userId, exceptSessionId)
+	return arguments.Error(0)
+}
+
+func (helper *SessionRegistryHelperMock) UpdateProfileByUserId(client *redis.Client, ctx context.Context, userId int32, username string, email string) (err error) {
+	arguments := helper.Mock.Called(client, ctx, userId, username, email)
+	return arguments.Error(0)
+}
@@ -0,0 +1,39 @@
+package mockhelpers
+
+import (
+	"backend-golang/commons/helpers"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type TokenFamilyHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *TokenFamilyHelperMock) Create(client *redis.Client, ctx context.Context, familyId string, tokenId string, session helpers.Session, expiration time.Duration) (err error) {
+	arguments := helper.Mock.Called(client, ctx, familyId, tokenId, session, expiration)
+	return arguments.Error(0)
+}
+
+func (helper *TokenFamilyHelperMock) FindSession(client *redis.Client, ctx context.Context, familyId string) (session helpers.Session, err error) {
+	arguments := helper.Mock.Called(client, ctx, familyId)
+	return arguments.Get(0).(helpers.Session), arguments.Error(1)
+}
+
+func (helper *TokenFamilyHelperMock) Rotate(client *redis.Client, ctx context.Context, familyId string, tokenId string, newTokenId string) (result int64, err error) {
+	arguments := helper.Mock.Called(client, ctx, familyId, tokenId, newTokenId)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (helper *TokenFamilyHelperMock) Exists(client *redis.Client, ctx context.Context, familyId string) (exists bool, err error) {
+	arguments := helper.Mock.Called(client, ctx, familyId)
+	return arguments.Bool(0), arguments.Error(1)
+}
+
+func (helper *TokenFamilyHelperMock) Delete(client *redis.Client, ctx context.Context, familyId string) (err error) {
+	arguments := helper.Mock.Called(client, ctx, familyId)
+	return arguments.Error(0)
+}
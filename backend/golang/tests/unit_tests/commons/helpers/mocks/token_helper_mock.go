@@ -0,0 +1,12 @@
+package mockhelpers
+
+import "github.com/stretchr/testify/mock"
+
+type TokenHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *TokenHelperMock) Generate() (token string, err error) {
+	arguments := helper.Mock.Called()
+	return arguments.Get(0).(string), arguments.Error(1)
+}
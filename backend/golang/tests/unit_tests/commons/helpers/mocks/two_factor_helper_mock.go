@@ -0,0 +1,41 @@
+package mockhelpers
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TwoFactorHelperMock struct {
+	Mock mock.Mock
+}
+
+func (helper *TwoFactorHelperMock) GenerateSecret() (secret string, err error) {
+	arguments := helper.Mock.Called()
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
+func (helper *TwoFactorHelperMock) GenerateRecoveryCodes() (recoveryCodes []string, err error) {
+	arguments := helper.Mock.Called()
+	return arguments.Get(0).([]string), arguments.Error(1)
+}
+
+func (helper *TwoFactorHelperMock) ValidateTotp(secret string, code string, now time.Time) (step int64, valid bool) {
+	arguments := helper.Mock.Called(secret, code, now)
+	return arguments.Get(0).(int64), arguments.Bool(1)
+}
+
+func (helper *TwoFactorHelperMock) EncryptSecret(secret string) (encryptedSecret string, err error) {
+	arguments := helper.Mock.Called(secret)
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
+func (helper *TwoFactorHelperMock) DecryptSecret(encryptedSecret string) (secret string, err error) {
+	arguments := helper.Mock.Called(encryptedSecret)
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
+func (helper *TwoFactorHelperMock) ToRecoveryCodeHash(recoveryCode string) (codeHash string) {
+	arguments := helper.Mock.Called(recoveryCode)
+	return arguments.Get(0).(string)
+}
@@ -0,0 +1,241 @@
+package helpers_test
+
+import (
+	"backend-golang/commons/helpers"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type OidcHelperTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	privateKey       *rsa.PrivateKey
+	otherPrivateKey  *rsa.PrivateKey
+	server           *httptest.Server
+	oidcProvider     helpers.OidcProvider
+	oidcHelper       helpers.OidcHelper
+	claims           helpers.OidcClaims
+	tokenStatusCode  int
+	idToken          string
+	tokenRequestForm url.Values
+	jwksRequestCount int
+}
+
+func TestOidcHelperTestSuite(t *testing.T) {
+	suite.Run(t, new(OidcHelperTestSuite))
+}
+
+func (sut *OidcHelperTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	var err error
+	sut.privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sut.otherPrivateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// the stub provider serves the jwks with the kid "key" and answers the token endpoint with whatever the test set
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		sut.jwksRequestCount++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"use": "sig",
+					"kid": "key",
+					"n":   base64.RawURLEncoding.EncodeToString(sut.privateKey.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(sut.privateKey.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientId, clientSecret, ok := r.BasicAuth()
+		if !ok || clientId != "clientId" || clientSecret != "clientSecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		sut.tokenRequestForm = r.PostForm
+		w.WriteHeader(sut.tokenStatusCode)
+		if sut.tokenStatusCode == http.StatusOK {
+			json.NewEncoder(w).Encode(map[string]string{"id_token": sut.idToken})
+		} else {
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		}
+	})
+	sut.server = httptest.NewServer(mux)
+}
+
+func (sut *OidcHelperTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.ctx = context.Background()
+	sut.oidcProvider = helpers.OidcProvider{
+		Name:             "stub",
+		Issuer:           sut.server.URL,
+		ClientId:         "clientId",
+		ClientSecret:     "clientSecret",
+		AuthorizationUrl: sut.server.URL + "/authorize",
+		TokenUrl:         sut.server.URL + "/token",
+		JwksUrl:          sut.server.URL + "/jwks",
+		RedirectUrl:      "https://shop.example.com/oidc/stub",
+	}
+	sut.oidcHelper = helpers.NewOidcHelper()
+	now := time.Now()
+	sut.claims = helpers.OidcClaims{
+		Nonce:         "nonce",
+		Email:         "email@email.com",
+		EmailVerified: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    sut.server.URL,
+			Subject:   "subject",
+			Audience:  jwt.ClaimStrings{"clientId"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	sut.tokenStatusCode = http.StatusOK
+	sut.idToken = ""
+	sut.tokenRequestForm = nil
+	sut.jwksRequestCount = 0
+}
+
+func (sut *OidcHelperTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *OidcHelperTestSuite) sign(method jwt.SigningMethod, key interface{}, kid string) string {
+	jwtToken := jwt.NewWithClaims(method, sut.claims)
+	jwtToken.Header["kid"] = kid
+	signed, err := jwtToken.SignedString(key)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return signed
+}
+
+func (sut *OidcHelperTestSuite) Test01ExchangeCodeSuccess() {
+	sut.T().Log("Test01ExchangeCodeSuccess")
+	sut.idToken = "idToken"
+	idToken, err := sut.oidcHelper.ExchangeCode(sut.ctx, sut.oidcProvider, "code", "codeVerifier")
+	sut.Nil(err)
+	sut.Equal(idToken, "idToken")
+	sut.Equal(sut.tokenRequestForm.Get("grant_type"), "authorization_code")
+	sut.Equal(sut.tokenRequestForm.Get("code"), "code")
+	sut.Equal(sut.tokenRequestForm.Get("code_verifier"), "codeVerifier")
+	sut.Equal(sut.tokenRequestForm.Get("redirect_uri"), "https://shop.example.com/oidc/stub")
+}
+
+func (sut *OidcHelperTestSuite) Test02ExchangeCodeRejected() {
+	sut.T().Log("Test02ExchangeCodeRejected")
+	sut.tokenStatusCode = http.StatusBadRequest
+	idToken, err := sut.oidcHelper.ExchangeCode(sut.ctx, sut.oidcProvider, "code", "codeVerifier")
+	sut.Equal(idToken, "")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test03ExchangeCodeProviderError() {
+	sut.T().Log("Test03ExchangeCodeProviderError")
+	sut.tokenStatusCode = http.StatusBadGateway
+	idToken, err := sut.oidcHelper.ExchangeCode(sut.ctx, sut.oidcProvider, "code", "codeVerifier")
+	sut.Equal(idToken, "")
+	sut.NotNil(err)
+	sut.False(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test04VerifyIdTokenSuccess() {
+	sut.T().Log("Test04VerifyIdTokenSuccess")
+	claims, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.Nil(err)
+	sut.Equal(claims.Subject, "subject")
+	sut.Equal(claims.Email, "email@email.com")
+	sut.True(claims.EmailVerified)
+
+	_, err = sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.Nil(err)
+	sut.Equal(sut.jwksRequestCount, 1)
+}
+
+func (sut *OidcHelperTestSuite) Test05VerifyIdTokenWrongNonceRejected() {
+	sut.T().Log("Test05VerifyIdTokenWrongNonceRejected")
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "otherNonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test06VerifyIdTokenWrongAudienceRejected() {
+	sut.T().Log("Test06VerifyIdTokenWrongAudienceRejected")
+	sut.claims.Audience = jwt.ClaimStrings{"otherClientId"}
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test07VerifyIdTokenWrongIssuerRejected() {
+	sut.T().Log("Test07VerifyIdTokenWrongIssuerRejected")
+	sut.claims.Issuer = "https://other.example.com"
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test08VerifyIdTokenExpiredRejected() {
+	sut.T().Log("Test08VerifyIdTokenExpiredRejected")
+	sut.claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test09VerifyIdTokenWrongKeyRejected() {
+	sut.T().Log("Test09VerifyIdTokenWrongKeyRejected")
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.otherPrivateKey, "key"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test10VerifyIdTokenUnknownKidRejected() {
+	sut.T().Log("Test10VerifyIdTokenUnknownKidRejected")
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.otherPrivateKey, "otherKey"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test11VerifyIdTokenHs256Rejected() {
+	sut.T().Log("Test11VerifyIdTokenHs256Rejected")
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodHS256, []byte("clientSecret"), "key"), "nonce")
+	sut.True(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) Test12VerifyIdTokenJwksUnavailableError() {
+	sut.T().Log("Test12VerifyIdTokenJwksUnavailableError")
+	sut.oidcProvider.JwksUrl = sut.server.URL + "/missing"
+	_, err := sut.oidcHelper.VerifyIdToken(sut.ctx, sut.oidcProvider, sut.sign(jwt.SigningMethodRS256, sut.privateKey, "key"), "nonce")
+	sut.NotNil(err)
+	sut.False(errors.Is(err, helpers.ErrOidcRejected))
+}
+
+func (sut *OidcHelperTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *OidcHelperTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *OidcHelperTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+	sut.server.Close()
+}
@@ -0,0 +1,173 @@
+package helpers_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/models"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PersonalDataHelperTestSuite struct {
+	suite.Suite
+	ctx                           context.Context
+	now                           time.Time
+	pool                          *pgxpool.Pool
+	tx                            pgx.Tx
+	client                        *redis.Client
+	errInternalServer             error
+	postgresUtilMock              *mockutils.PostgresUtilMock
+	redisUtilMock                 *mockutils.RedisUtilMock
+	backgroundRunnerMock          *mockutils.BackgroundRunnerMock
+	personalDataRepositoryMock    *mockrepositories.PersonalDataRepositoryMock
+	dataExportRepositoryMock      *mockrepositories.DataExportRepositoryMock
+	accountDeletionRepositoryMock *mockrepositories.AccountDeletionRepositoryMock
+	sessionRegistryHelperMock     *mockhelpers.SessionRegistryHelperMock
+	personalDataHelper            *helpers.PersonalDataHelperImplementation
+}
+
+func TestPersonalDataHelperTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonalDataHelperTestSuite))
+}
+
+func (sut *PersonalDataHelperTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.Background()
+	sut.now = time.UnixMilli(1700000000000)
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *PersonalDataHelperTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.T().Setenv("ECOMMERCEV2_ACCOUNT_DELETION_GRACE_PERIOD", "60")
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.backgroundRunnerMock = new(mockutils.BackgroundRunnerMock)
+	sut.personalDataRepositoryMock = new(mockrepositories.PersonalDataRepositoryMock)
+	sut.dataExportRepositoryMock = new(mockrepositories.DataExportRepositoryMock)
+	sut.accountDeletionRepositoryMock = new(mockrepositories.AccountDeletionRepositoryMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	// the run queued at start is only recorded, every test calls the methods it checks itself
+	sut.backgroundRunnerMock.Mock.On("Run", mock.Anything, "personal data run", mock.Anything).Return()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.personalDataHelper = helpers.NewPersonalDataHelper(sut.postgresUtilMock, sut.redisUtilMock, sut.personalDataRepositoryMock, sut.dataExportRepositoryMock, sut.accountDeletionRepositoryMock, sut.sessionRegistryHelperMock, sut.backgroundRunnerMock)
+}
+
+func (sut *PersonalDataHelperTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *PersonalDataHelperTestSuite) Test1DeleteAccountsGracePeriodCutoff() {
+	sut.T().Log("Test1DeleteAccountsGracePeriodCutoff")
+	deletionRequestedBefore := sut.now.Add(-time.Hour).UnixMilli()
+	sut.accountDeletionRepositoryMock.Mock.On("FindUserIdsDue", sut.pool, mock.Anything, deletionRequestedBefore).Return([]int32{1, 2}, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", mock.Anything, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.accountDeletionRepositoryMock.Mock.On("Anonymise", sut.tx, mock.Anything, mock.Anything, deletionRequestedBefore, sut.now.UnixMilli()).Return(true, nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, mock.Anything, mock.Anything, "").Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	err := sut.personalDataHelper.DeleteAccounts(sut.ctx, sut.now)
+	sut.Nil(err)
+	sut.accountDeletionRepositoryMock.Mock.AssertCalled(sut.T(), "Anonymise", sut.tx, mock.Anything, int32(1), deletionRequestedBefore, sut.now.UnixMilli())
+	sut.accountDeletionRepositoryMock.Mock.AssertCalled(sut.T(), "Anonymise", sut.tx, mock.Anything, int32(2), deletionRequestedBefore, sut.now.UnixMilli())
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "DeleteAllByUserId", 2)
+	sut.postgresUtilMock.Mock.AssertNumberOfCalls(sut.T(), "CommitOrRollback", 2)
+}
+
+func (sut *PersonalDataHelperTestSuite) Test2DeleteAccountsRerunIdempotent() {
+	sut.T().Log("Test2DeleteAccountsRerunIdempotent")
+	deletionRequestedBefore := sut.now.Add(-time.Hour).UnixMilli()
+	sut.accountDeletionRepositoryMock.Mock.On("FindUserIdsDue", sut.pool, mock.Anything, deletionRequestedBefore).Return([]int32{1}, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", mock.Anything, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.accountDeletionRepositoryMock.Mock.On("Anonymise", sut.tx, mock.Anything, int32(1), deletionRequestedBefore, sut.now.UnixMilli()).Return(true, nil).Once()
+	sut.accountDeletionRepositoryMock.Mock.On("Anonymise", sut.tx, mock.Anything, int32(1), deletionRequestedBefore, sut.now.UnixMilli()).Return(false, nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, mock.Anything, int32(1), "").Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	err := sut.personalDataHelper.DeleteAccounts(sut.ctx, sut.now)
+	sut.Nil(err)
+	err = sut.personalDataHelper.DeleteAccounts(sut.ctx, sut.now)
+	sut.Nil(err)
+	sut.accountDeletionRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "Anonymise", 2)
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "DeleteAllByUserId", 1)
+}
+
+func (sut *PersonalDataHelperTestSuite) Test3DeleteAccountsSessionRegistryHelperDeleteAllByUserIdRollback() {
+	sut.T().Log("Test3DeleteAccountsSessionRegistryHelperDeleteAllByUserIdRollback")
+	deletionRequestedBefore := sut.now.Add(-time.Hour).UnixMilli()
+	sut.accountDeletionRepositoryMock.Mock.On("FindUserIdsDue", sut.pool, mock.Anything, deletionRequestedBefore).Return([]int32{1}, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", mock.Anything, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.accountDeletionRepositoryMock.Mock.On("Anonymise", sut.tx, mock.Anything, int32(1), deletionRequestedBefore, sut.now.UnixMilli()).Return(true, nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, mock.Anything, int32(1), "").Return(sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	err := sut.personalDataHelper.DeleteAccounts(sut.ctx, sut.now)
+	sut.True(errors.Is(err, sut.errInternalServer))
+	sut.postgresUtilMock.Mock.AssertCalled(sut.T(), "CommitOrRollback", sut.tx, sut.errInternalServer)
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "CommitOrRollback", sut.tx, nil)
+}
+
+func (sut *PersonalDataHelperTestSuite) Test4WriteDataExportSuccess() {
+	sut.T().Log("Test4WriteDataExportSuccess")
+	user := models.PersonalDataUser{Id: 1, Username: "username", Email: "email@email.com", CreatedAt: 1}
+	sut.personalDataRepositoryMock.Mock.On("FindUserById", sut.pool, mock.Anything, int32(1)).Return(user, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindPermissionsByUserId", sut.pool, mock.Anything, int32(1)).Return([]string{"READ_PERMISSION"}, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindRolesByUserId", sut.pool, mock.Anything, int32(1)).Return([]string{"customer"}, nil)
+	sut.personalDataRepositoryMock.Mock.On("CountUnusedRecoveryCodesByUserId", sut.pool, mock.Anything, int32(1)).Return(8, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindIdentitiesByUserId", sut.pool, mock.Anything, int32(1)).Return([]models.PersonalDataIdentity{}, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindApiKeysByUserId", sut.pool, mock.Anything, int32(1)).Return([]models.PersonalDataApiKey{}, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindAuthEventsByUserId", sut.pool, mock.Anything, int32(1), "email@email.com").Return([]models.PersonalDataAuthEvent{}, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindPermissionAuditsByUserId", sut.pool, mock.Anything, int32(1)).Return([]models.PersonalDataPermissionAudit{}, nil)
+	sut.personalDataRepositoryMock.Mock.On("FindRoleAuditsByUserId", sut.pool, mock.Anything, int32(1)).Return([]models.PersonalDataRoleAudit{}, nil)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, mock.Anything, int32(1)).Return([]helpers.SessionInfo{{SessionId: "sessionId", CreatedAt: 2, UserAgent: "userAgent", Ip: "127.0.0.1"}}, nil)
+	var personalData models.PersonalData
+	var archive []byte
+	sut.dataExportRepositoryMock.Mock.On("UpdateReady", sut.pool, mock.Anything, int32(10), mock.Anything, mock.Anything, mock.Anything).Run(func(arguments mock.Arguments) {
+		archive = arguments.Get(3).([]byte)
+	}).Return(nil)
+	err := sut.personalDataHelper.WriteDataExport(sut.ctx, models.DataExport{Id: 10, UserId: 1})
+	sut.Nil(err)
+	sut.Nil(json.Unmarshal(archive, &personalData))
+	sut.Equal(personalData.User, user)
+	sut.Equal(personalData.Permissions, []string{"READ_PERMISSION"})
+	sut.Equal(personalData.Roles, []string{"customer"})
+	sut.Equal(personalData.RecoveryCodesRemaining, 8)
+	sut.Equal(personalData.Sessions, []models.PersonalDataSession{{CreatedAt: 2, UserAgent: "userAgent", Ip: "127.0.0.1"}})
+	sut.NotContains(string(archive), "sessionId")
+	sut.dataExportRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataHelperTestSuite) Test5WriteDataExportPersonalDataRepositoryFindUserByIdFailed() {
+	sut.T().Log("Test5WriteDataExportPersonalDataRepositoryFindUserByIdFailed")
+	sut.personalDataRepositoryMock.Mock.On("FindUserById", sut.pool, mock.Anything, int32(1)).Return(models.PersonalDataUser{}, sut.errInternalServer)
+	sut.dataExportRepositoryMock.Mock.On("UpdateFailed", sut.pool, mock.Anything, int32(10), mock.Anything).Return(nil)
+	err := sut.personalDataHelper.WriteDataExport(sut.ctx, models.DataExport{Id: 10, UserId: 1})
+	sut.True(errors.Is(err, sut.errInternalServer))
+	sut.dataExportRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "UpdateFailed", 1)
+	sut.dataExportRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateReady", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataHelperTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *PersonalDataHelperTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+	sut.personalDataHelper.Close()
+}
+
+func (sut *PersonalDataHelperTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
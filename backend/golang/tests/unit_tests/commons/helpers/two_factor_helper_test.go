@@ -0,0 +1,93 @@
+package helpers_test
+
+import (
+	"backend-golang/commons/helpers"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorHelperTestSuite struct {
+	suite.Suite
+	twoFactorHelper helpers.TwoFactorHelper
+	secret          string
+}
+
+func TestTwoFactorHelperTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorHelperTestSuite))
+}
+
+func (sut *TwoFactorHelperTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+}
+
+func (sut *TwoFactorHelperTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.twoFactorHelper = helpers.NewTwoFactorHelper([]byte("twoFactorKeyOfAtLeastThirtyTwoCharacters"))
+}
+
+func (sut *TwoFactorHelperTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *TwoFactorHelperTestSuite) Test1EncryptSecretDecryptSecretSuccess() {
+	sut.T().Log("Test1EncryptSecretDecryptSecretSuccess")
+	encryptedSecret, err := sut.twoFactorHelper.EncryptSecret(sut.secret)
+	sut.Nil(err)
+	sut.False(strings.Contains(encryptedSecret, sut.secret))
+	sut.LessOrEqual(len(encryptedSecret), 255)
+	otherEncryptedSecret, err := sut.twoFactorHelper.EncryptSecret(sut.secret)
+	sut.Nil(err)
+	sut.NotEqual(encryptedSecret, otherEncryptedSecret)
+	secret, err := sut.twoFactorHelper.DecryptSecret(encryptedSecret)
+	sut.Nil(err)
+	sut.Equal(secret, sut.secret)
+}
+
+func (sut *TwoFactorHelperTestSuite) Test2DecryptSecretOtherKeyError() {
+	sut.T().Log("Test2DecryptSecretOtherKeyError")
+	encryptedSecret, err := sut.twoFactorHelper.EncryptSecret(sut.secret)
+	sut.Nil(err)
+	otherTwoFactorHelper := helpers.NewTwoFactorHelper([]byte("otherTwoFactorKeyOfAtLeastThirtyTwoCharacters"))
+	_, err = otherTwoFactorHelper.DecryptSecret(encryptedSecret)
+	sut.NotNil(err)
+	_, err = sut.twoFactorHelper.DecryptSecret(sut.secret)
+	sut.NotNil(err)
+}
+
+func (sut *TwoFactorHelperTestSuite) Test3ToRecoveryCodeHashKeyed() {
+	sut.T().Log("Test3ToRecoveryCodeHashKeyed")
+	codeHash := sut.twoFactorHelper.ToRecoveryCodeHash("abcd-efgh")
+	sut.Len(codeHash, 64)
+	sut.Equal(sut.twoFactorHelper.ToRecoveryCodeHash(" ABCDEFGH "), codeHash)
+	sut.NotEqual(helpers.ToTokenHash("abcdefgh"), codeHash)
+	otherTwoFactorHelper := helpers.NewTwoFactorHelper([]byte("otherTwoFactorKeyOfAtLeastThirtyTwoCharacters"))
+	sut.NotEqual(otherTwoFactorHelper.ToRecoveryCodeHash("abcd-efgh"), codeHash)
+}
+
+func (sut *TwoFactorHelperTestSuite) Test4ValidateTotpSuccess() {
+	sut.T().Log("Test4ValidateTotpSuccess")
+	now := time.Unix(1719496855, 0)
+	code, err := helpers.GenerateTotpCode(sut.secret, now.Unix()/30-1)
+	sut.Nil(err)
+	step, valid := sut.twoFactorHelper.ValidateTotp(sut.secret, code, now)
+	sut.True(valid)
+	sut.Equal(step, now.Unix()/30-1)
+	_, valid = sut.twoFactorHelper.ValidateTotp(sut.secret, code, now.Add(time.Minute))
+	sut.False(valid)
+}
+
+func (sut *TwoFactorHelperTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *TwoFactorHelperTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *TwoFactorHelperTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,183 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/models"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiKeyMiddlewareTestSuite struct {
+	suite.Suite
+	postgresUtilMock     *mockutils.PostgresUtilMock
+	apiKeyRepositoryMock *mockrepositories.ApiKeyRepositoryMock
+	redisUtilMock        *mockutils.RedisUtilMock
+	redisHelperMock      *mockhelpers.RedisHelperMock
+	jwtHelperMock        *mockhelpers.JwtHelperMock
+	pool                 *pgxpool.Pool
+	errInternalServer    error
+	key                  string
+	keyHash              string
+	apiKey               models.ApiKey
+	e                    *echo.Echo
+}
+
+func TestApiKeyMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyMiddlewareTestSuite))
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.pool = &pgxpool.Pool{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.key = "ek_key"
+	sut.keyHash = helpers.ToTokenHash(sut.key)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.apiKey = models.ApiKey{
+		Id:            pgtype.Int4{Valid: true, Int32: 7},
+		UserId:        pgtype.Int4{Valid: true, Int32: 1},
+		Username:      pgtype.Text{Valid: true, String: "username"},
+		Email:         pgtype.Text{Valid: true, String: "email@email.com"},
+		LastUsedAt:    pgtype.Int8{Valid: true, Int64: time.Now().UnixMilli()},
+		IdPermissions: []int32{2, 3},
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.apiKeyRepositoryMock = new(mockrepositories.ApiKeyRepositoryMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.jwtHelperMock = new(mockhelpers.JwtHelperMock)
+	sessionMiddleware := middlewares.NewSessionTokenOrApiKeyMiddleware(
+		middlewares.NewSessionMiddleware(sut.redisUtilMock, sut.redisHelperMock),
+		middlewares.NewTokenMiddleware(sut.redisUtilMock, sut.jwtHelperMock, new(mockhelpers.TokenFamilyHelperMock)),
+		middlewares.NewApiKeyMiddleware(sut.postgresUtilMock, sut.apiKeyRepositoryMock),
+	)
+	sut.e = echo.New()
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.GET("/api/v1/test", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id":            ctx.Value(middlewares.IdKey).(int32),
+			"username":      ctx.Value(middlewares.UsernameKey).(string),
+			"email":         ctx.Value(middlewares.EmailKey).(string),
+			"idPermissions": ctx.Value(middlewares.PermissionKey).([]int32),
+			"sessionId":     ctx.Value(middlewares.SessionIdKey).(string),
+			"apiKeyId":      ctx.Value(middlewares.ApiKeyIdKey).(int32),
+			"twoFactor":     ctx.Value(middlewares.TwoFactorKey).(bool),
+		})
+	}, sessionMiddleware.Authenticate)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) serve(header map[string]string) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) Test1AuthenticateApiKeyRepositoryFindActiveByKeyHashInternalServerError() {
+	sut.T().Log("Test1AuthenticateApiKeyRepositoryFindActiveByKeyHashInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, sut.keyHash, mock.Anything).Return(models.ApiKey{}, sut.errInternalServer)
+	statusCode, responseBody := sut.serve(map[string]string{middlewares.ApiKeyHeader: sut.key})
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) Test2AuthenticateUnknownApiKeyUnauthorized() {
+	sut.T().Log("Test2AuthenticateUnknownApiKeyUnauthorized")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, sut.keyHash, mock.Anything).Return(models.ApiKey{}, pgx.ErrNoRows)
+	statusCode, responseBody := sut.serve(map[string]string{middlewares.ApiKeyHeader: sut.key})
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["field"], "message")
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) Test3AuthenticateApiKeyRepositoryUpdateLastUsedAtInternalServerError() {
+	sut.T().Log("Test3AuthenticateApiKeyRepositoryUpdateLastUsedAtInternalServerError")
+	sut.apiKey.LastUsedAt = pgtype.Int8{}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, sut.keyHash, mock.Anything).Return(sut.apiKey, nil)
+	sut.apiKeyRepositoryMock.Mock.On("UpdateLastUsedAt", sut.pool, mock.Anything, int32(7), mock.Anything).Return(sut.errInternalServer)
+	statusCode, responseBody := sut.serve(map[string]string{middlewares.ApiKeyHeader: sut.key})
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) Test4AuthenticateRecentlyUsedSuccess() {
+	sut.T().Log("Test4AuthenticateRecentlyUsedSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, sut.keyHash, mock.Anything).Return(sut.apiKey, nil)
+	statusCode, responseBody := sut.serve(map[string]string{middlewares.ApiKeyHeader: sut.key, echo.HeaderAuthorization: "Bearer accessToken"})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.Equal(responseBody["username"], "username")
+	sut.Equal(responseBody["email"], "email@email.com")
+	sut.Equal(responseBody["idPermissions"], []interface{}{float64(2), float64(3)})
+	sut.Equal(responseBody["sessionId"], "apiKey:7")
+	sut.Equal(responseBody["apiKeyId"], float64(7))
+	sut.Equal(responseBody["twoFactor"], false)
+	sut.apiKeyRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateLastUsedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.jwtHelperMock.Mock.AssertNotCalled(sut.T(), "ParseAccessToken", mock.Anything)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) Test5AuthenticateUpdatesLastUsedAtSuccess() {
+	sut.T().Log("Test5AuthenticateUpdatesLastUsedAtSuccess")
+	sut.apiKey.LastUsedAt = pgtype.Int8{Valid: true, Int64: time.Now().Add(-2 * time.Minute).UnixMilli()}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, sut.keyHash, mock.Anything).Return(sut.apiKey, nil)
+	sut.apiKeyRepositoryMock.Mock.On("UpdateLastUsedAt", sut.pool, mock.Anything, int32(7), mock.Anything).Return(nil)
+	statusCode, responseBody := sut.serve(map[string]string{middlewares.ApiKeyHeader: sut.key})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.apiKeyRepositoryMock.Mock.AssertCalled(sut.T(), "UpdateLastUsedAt", sut.pool, mock.Anything, int32(7), mock.Anything)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *ApiKeyMiddlewareTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,255 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/models"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CsrfMiddlewareTestSuite struct {
+	suite.Suite
+	redisUtilMock         *mockutils.RedisUtilMock
+	redisHelperMock       *mockhelpers.RedisHelperMock
+	csrfTokenHelperMock   *mockhelpers.CsrfTokenHelperMock
+	jwtHelperMock         *mockhelpers.JwtHelperMock
+	tokenFamilyHelperMock *mockhelpers.TokenFamilyHelperMock
+	postgresUtilMock      *mockutils.PostgresUtilMock
+	apiKeyRepositoryMock  *mockrepositories.ApiKeyRepositoryMock
+	pool                  *pgxpool.Pool
+	client                *redis.Client
+	errInternalServer     error
+	sessionId             string
+	csrfToken             string
+	e                     *echo.Echo
+}
+
+func TestCsrfMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(CsrfMiddlewareTestSuite))
+}
+
+func (sut *CsrfMiddlewareTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.client = &redis.Client{}
+	sut.pool = &pgxpool.Pool{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.sessionId = "sessionId"
+	sut.csrfToken = "csrfToken"
+	os.Setenv("ECOMMERCEV2_COOKIE_SECURE", "false")
+}
+
+func (sut *CsrfMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.csrfTokenHelperMock = new(mockhelpers.CsrfTokenHelperMock)
+	sut.jwtHelperMock = new(mockhelpers.JwtHelperMock)
+	sut.tokenFamilyHelperMock = new(mockhelpers.TokenFamilyHelperMock)
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.apiKeyRepositoryMock = new(mockrepositories.ApiKeyRepositoryMock)
+	sessionMiddleware := middlewares.NewSessionTokenOrApiKeyMiddleware(
+		middlewares.NewCsrfMiddleware(sut.redisUtilMock, sut.csrfTokenHelperMock, middlewares.NewSessionMiddleware(sut.redisUtilMock, sut.redisHelperMock)),
+		middlewares.NewTokenMiddleware(sut.redisUtilMock, sut.jwtHelperMock, sut.tokenFamilyHelperMock),
+		middlewares.NewApiKeyMiddleware(sut.postgresUtilMock, sut.apiKeyRepositoryMock),
+	)
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id": c.Request().Context().Value(middlewares.IdKey).(int32),
+		})
+	}
+	sut.e = echo.New()
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.GET("/api/v1/test", handler, sessionMiddleware.Authenticate)
+	sut.e.POST("/api/v1/test", handler, sessionMiddleware.Authenticate)
+	optionalSessionMiddleware := middlewares.NewCsrfMiddleware(sut.redisUtilMock, sut.csrfTokenHelperMock, middlewares.NewOptionalSessionMiddleware(sut.redisUtilMock, sut.redisHelperMock))
+	sut.e.POST("/api/v1/optional", func(c echo.Context) error {
+		_, ok := c.Request().Context().Value(middlewares.SessionIdKey).(string)
+		return c.JSON(http.StatusOK, map[string]interface{}{"session": ok})
+	}, optionalSessionMiddleware.Authenticate)
+}
+
+func (sut *CsrfMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *CsrfMiddlewareTestSuite) mockSession() {
+	session := `{"email":"email@email.com","id":1,"idPermissions":[1,2],"username":"username","createdAt":` + strconv.FormatInt(time.Now().UnixMilli(), 10) + `}`
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return(session, nil)
+	sut.redisHelperMock.Mock.On("Expire", sut.client, mock.Anything, sut.sessionId, 30*time.Minute).Return(true, nil)
+}
+
+func (sut *CsrfMiddlewareTestSuite) serve(method string, header map[string]string) (statusCode int, responseBody map[string]interface{}) {
+	return sut.servePath(method, "/api/v1/test", header)
+}
+
+func (sut *CsrfMiddlewareTestSuite) servePath(method string, path string, header map[string]string) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(method, path, nil)
+	req.AddCookie(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test1GetWithoutCsrfTokenSuccess() {
+	sut.T().Log("Test1GetWithoutCsrfTokenSuccess")
+	sut.mockSession()
+	statusCode, responseBody := sut.serve(http.MethodGet, nil)
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.csrfTokenHelperMock.Mock.AssertNotCalled(sut.T(), "Find", sut.client, mock.Anything, sut.sessionId)
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test2PostWithoutCsrfTokenForbidden() {
+	sut.T().Log("Test2PostWithoutCsrfTokenForbidden")
+	sut.mockSession()
+	statusCode, responseBody := sut.serve(http.MethodPost, nil)
+	sut.Equal(statusCode, http.StatusForbidden)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["field"], "message")
+	sut.Equal(errorMessage0["message"], "csrf token is invalid")
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test3PostCsrfTokenHelperFindInternalServerError() {
+	sut.T().Log("Test3PostCsrfTokenHelperFindInternalServerError")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return("", sut.errInternalServer)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test4PostCsrfTokenNotIssuedForbidden() {
+	sut.T().Log("Test4PostCsrfTokenNotIssuedForbidden")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return("", redis.Nil)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusForbidden)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "csrf token is invalid")
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test5PostWrongCsrfTokenForbidden() {
+	sut.T().Log("Test5PostWrongCsrfTokenForbidden")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return("otherCsrfToken", nil)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusForbidden)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "csrf token is invalid")
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test6PostSuccess() {
+	sut.T().Log("Test6PostSuccess")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return(sut.csrfToken, nil)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test7PostWithBearerTokenWithoutCsrfTokenSuccess() {
+	sut.T().Log("Test7PostWithBearerTokenWithoutCsrfTokenSuccess")
+	accessTokenClaims := helpers.AccessTokenClaims{
+		Type:             "access",
+		FamilyId:         "familyId",
+		IdPermissions:    []int32{1},
+		RegisteredClaims: jwt.RegisteredClaims{ID: "accessTokenId", Subject: "1"},
+	}
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseAccessToken", "accessToken").Return(accessTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Exists", sut.client, mock.Anything, "familyId").Return(true, nil)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{echo.HeaderAuthorization: "Bearer accessToken"})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.csrfTokenHelperMock.Mock.AssertNotCalled(sut.T(), "Find", sut.client, mock.Anything, mock.Anything)
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test8PostWithApiKeyWithoutCsrfTokenSuccess() {
+	sut.T().Log("Test8PostWithApiKeyWithoutCsrfTokenSuccess")
+	apiKey := models.ApiKey{
+		Id:            pgtype.Int4{Valid: true, Int32: 1},
+		UserId:        pgtype.Int4{Valid: true, Int32: 1},
+		LastUsedAt:    pgtype.Int8{Valid: true, Int64: time.Now().UnixMilli()},
+		IdPermissions: []int32{1},
+	}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindActiveByKeyHash", sut.pool, mock.Anything, helpers.ToTokenHash("apiKey"), mock.Anything).Return(apiKey, nil)
+	statusCode, responseBody := sut.serve(http.MethodPost, map[string]string{middlewares.ApiKeyHeader: "apiKey"})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.csrfTokenHelperMock.Mock.AssertNotCalled(sut.T(), "Find", sut.client, mock.Anything, mock.Anything)
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Get", sut.client, mock.Anything, mock.Anything)
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test9OptionalSessionGoneWithoutCsrfTokenSuccess() {
+	sut.T().Log("Test9OptionalSessionGoneWithoutCsrfTokenSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return("", redis.Nil)
+	statusCode, responseBody := sut.servePath(http.MethodPost, "/api/v1/optional", nil)
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["session"], false)
+	sut.csrfTokenHelperMock.Mock.AssertNotCalled(sut.T(), "Find", sut.client, mock.Anything, mock.Anything)
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test10OptionalSessionWrongCsrfTokenForbidden() {
+	sut.T().Log("Test10OptionalSessionWrongCsrfTokenForbidden")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return("otherCsrfToken", nil)
+	statusCode, responseBody := sut.servePath(http.MethodPost, "/api/v1/optional", map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusForbidden)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "csrf token is invalid")
+}
+
+func (sut *CsrfMiddlewareTestSuite) Test11OptionalSessionSuccess() {
+	sut.T().Log("Test11OptionalSessionSuccess")
+	sut.mockSession()
+	sut.csrfTokenHelperMock.Mock.On("Find", sut.client, mock.Anything, sut.sessionId).Return(sut.csrfToken, nil)
+	statusCode, responseBody := sut.servePath(http.MethodPost, "/api/v1/optional", map[string]string{helpers.CsrfTokenHeaderName: sut.csrfToken})
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["session"], true)
+}
+
+func (sut *CsrfMiddlewareTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *CsrfMiddlewareTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *CsrfMiddlewareTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,109 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/middlewares"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type LogMiddlewareTestSuite struct {
+	suite.Suite
+	e *echo.Echo
+}
+
+func TestLogMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(LogMiddlewareTestSuite))
+}
+
+func (sut *LogMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"accessToken": "secretAccessToken"})
+	}
+	sut.e = echo.New()
+	sut.e.POST("/api/v1/users/token", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLog, middlewares.NoStore)
+	sut.e.POST("/api/v1/users/anything", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLog)
+	sut.e.POST("/api/v1/users/password/reset", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLog)
+	sut.e.POST("/api/v1/users/login/2fa", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLog)
+	sut.e.POST("/api/v1/users/password", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLog)
+	sut.e.POST("/api/v1/users/token/refresh", handler, middlewares.SetRequestId, middlewares.PrintRequestResponseLogWithNoRequestBody, middlewares.NoStore)
+}
+
+func (sut *LogMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+// serve returns the response and everything the middleware printed
+func (sut *LogMiddlewareTestSuite) serve(path string, body string) (*httptest.ResponseRecorder, string) {
+	stdout := os.Stdout
+	reader, writer, err := os.Pipe()
+	sut.Nil(err)
+	os.Stdout = writer
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	os.Stdout = stdout
+	writer.Close()
+	printed := new(bytes.Buffer)
+	_, err = io.Copy(printed, reader)
+	sut.Nil(err)
+	return rec, printed.String()
+}
+
+func (sut *LogMiddlewareTestSuite) Test01RedactSecretFieldsOnEveryRoute() {
+	sut.T().Log("Test01RedactSecretFieldsOnEveryRoute")
+	_, printed := sut.serve("/api/v1/users/anything", `{"email": "email@email.com", "password": "password@A1", "refreshToken": "refreshToken", "challengeId": "challengeId"}`)
+	sut.Contains(printed, "email@email.com")
+	for _, secret := range []string{"password@A1", "refreshToken", "challengeId"} {
+		sut.NotContains(printed, secret)
+	}
+	sut.Contains(printed, "secretAccessToken")
+}
+
+func (sut *LogMiddlewareTestSuite) Test02NoStoreResponseIsNotLogged() {
+	sut.T().Log("Test02NoStoreResponseIsNotLogged")
+	rec, printed := sut.serve("/api/v1/users/token", `{"email": "email@email.com", "password": "password@A1"}`)
+	sut.Equal(rec.Code, http.StatusOK)
+	sut.Equal(rec.Header().Get(echo.HeaderCacheControl), "no-store")
+	sut.Contains(rec.Body.String(), "secretAccessToken")
+	sut.NotContains(printed, "password@A1")
+	sut.NotContains(printed, "secretAccessToken")
+}
+
+func (sut *LogMiddlewareTestSuite) Test03NoStoreResponseWithNoRequestBodyIsNotLogged() {
+	sut.T().Log("Test03NoStoreResponseWithNoRequestBodyIsNotLogged")
+	rec, printed := sut.serve("/api/v1/users/token/refresh", "")
+	sut.Equal(rec.Code, http.StatusOK)
+	sut.Contains(rec.Body.String(), "secretAccessToken")
+	sut.NotContains(printed, "secretAccessToken")
+}
+
+func (sut *LogMiddlewareTestSuite) Test04RedactPasswordResetRequest() {
+	sut.T().Log("Test04RedactPasswordResetRequest")
+	_, printed := sut.serve("/api/v1/users/password/reset", `{"token": "resetToken", "password": "password@A2", "confirmpassword": "password@A2"}`)
+	sut.NotContains(printed, "resetToken")
+	sut.NotContains(printed, "password@A2")
+}
+
+func (sut *LogMiddlewareTestSuite) Test05RedactChangePasswordRequest() {
+	sut.T().Log("Test05RedactChangePasswordRequest")
+	_, printed := sut.serve("/api/v1/users/password", `{"currentpassword": "password@A1", "password": "password@A2", "confirmpassword": "password@A2"}`)
+	sut.NotContains(printed, "password@A1")
+	sut.NotContains(printed, "password@A2")
+}
+
+func (sut *LogMiddlewareTestSuite) Test06RedactTwoFactorRequest() {
+	sut.T().Log("Test06RedactTwoFactorRequest")
+	_, printed := sut.serve("/api/v1/users/login/2fa", `{"challengeId": "challengeValue", "code": "123456"}`)
+	sut.NotContains(printed, "challengeValue")
+	sut.NotContains(printed, "123456")
+}
@@ -0,0 +1,222 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/models"
+	mockrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionMiddlewareTestSuite struct {
+	suite.Suite
+	postgresUtilMock         *mockutils.PostgresUtilMock
+	permissionRepositoryMock *mockrepositories.PermissionRepositoryMock
+	pool                     *pgxpool.Pool
+	errInternalServer        error
+	permissions              []models.Permission
+	idPermissions            []int32
+	twoFactor                bool
+	permissionMiddleware     *middlewares.PermissionMiddlewareImplementation
+	e                        *echo.Echo
+}
+
+func TestPermissionMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionMiddlewareTestSuite))
+}
+
+func (sut *PermissionMiddlewareTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.pool = &pgxpool.Pool{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.permissions = []models.Permission{
+		{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 2}, Permission: pgtype.Text{Valid: true, String: "CREATE_PERMISSION"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 3}, Permission: pgtype.Text{Valid: true, String: "READ_PERMISSION"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 4}, Permission: pgtype.Text{Valid: true, String: "UPDATE_PERMISSION"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 5}, Permission: pgtype.Text{Valid: true, String: "DELETE_PERMISSION"}},
+	}
+}
+
+func (sut *PermissionMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.idPermissions = []int32{3}
+	sut.twoFactor = false
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	permissionMiddleware := middlewares.NewPermissionMiddleware(sut.postgresUtilMock, sut.permissionRepositoryMock)
+	sut.permissionMiddleware = permissionMiddleware.(*middlewares.PermissionMiddlewareImplementation)
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"data": "ok"})
+	}
+	setSession := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := context.WithValue(c.Request().Context(), middlewares.PermissionKey, sut.idPermissions)
+			ctx = context.WithValue(ctx, middlewares.TwoFactorKey, sut.twoFactor)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+	sut.e = echo.New()
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.GET("/api/v1/all", handler, setSession, permissionMiddleware.RequirePermissions("CREATE_PERMISSION", "READ_PERMISSION"))
+	sut.e.GET("/api/v1/any", handler, setSession, permissionMiddleware.RequireAny("CREATE_PERMISSION", "READ_PERMISSION"))
+	sut.e.GET("/api/v1/unknown", handler, setSession, permissionMiddleware.RequirePermissions("UNKNOWN_PERMISSION"))
+	sut.e.GET("/api/v1/nosession", handler, permissionMiddleware.RequirePermissions("READ_PERMISSION"))
+}
+
+func (sut *PermissionMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *PermissionMiddlewareTestSuite) serve(path string) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test1RequirePermissionsWithoutSessionUnauthorized() {
+	sut.T().Log("Test1RequirePermissionsWithoutSessionUnauthorized")
+	statusCode, responseBody := sut.serve("/api/v1/nosession")
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test2RequirePermissionsPermissionRepositoryFindAllInternalServerError() {
+	sut.T().Log("Test2RequirePermissionsPermissionRepositoryFindAllInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return([]models.Permission{}, sut.errInternalServer)
+	statusCode, responseBody := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test3RequirePermissionsForbidden() {
+	sut.T().Log("Test3RequirePermissionsForbidden")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, responseBody := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusForbidden)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["field"], "message")
+	sut.Equal(errorMessage0["message"], "forbidden")
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test4RequirePermissionsUnknownPermissionInternalServerError() {
+	sut.T().Log("Test4RequirePermissionsUnknownPermissionInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/unknown")
+	sut.Equal(statusCode, http.StatusInternalServerError)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test5RequireAnySuccess() {
+	sut.T().Log("Test5RequireAnySuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/any")
+	sut.Equal(statusCode, http.StatusOK)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test6RequirePermissionsAdministratorSuccess() {
+	sut.T().Log("Test6RequirePermissionsAdministratorSuccess")
+	sut.idPermissions = []int32{1}
+	sut.twoFactor = true
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test7RequirePermissionsLoadsPermissionsOnce() {
+	sut.T().Log("Test7RequirePermissionsLoadsPermissionsOnce")
+	sut.idPermissions = []int32{2, 3}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+	statusCode, _ = sut.serve("/api/v1/any")
+	sut.Equal(statusCode, http.StatusOK)
+	sut.permissionRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "FindAll", 1)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test8RequirePermissionsAdministratorWithoutTwoFactorForbidden() {
+	sut.T().Log("Test8RequirePermissionsAdministratorWithoutTwoFactorForbidden")
+	sut.idPermissions = []int32{1, 3}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, responseBody := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusForbidden)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "please log in with two-factor authentication to use administrator permissions")
+	statusCode, _ = sut.serve("/api/v1/any")
+	sut.Equal(statusCode, http.StatusOK)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test9RequirePermissionsReloadsPermissionsWhenOneIsMissing() {
+	sut.T().Log("Test9RequirePermissionsReloadsPermissionsWhenOneIsMissing")
+	sut.idPermissions = []int32{2, 3}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions[:2], nil).Once()
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/any")
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	statusCode, _ = sut.serve("/api/v1/any")
+	sut.Equal(statusCode, http.StatusOK)
+	statusCode, _ = sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+	sut.permissionRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "FindAll", 2)
+}
+
+func (sut *PermissionMiddlewareTestSuite) Test10RequirePermissionsReloadsPermissionsAfterTtl() {
+	sut.T().Log("Test10RequirePermissionsReloadsPermissionsAfterTtl")
+	sut.idPermissions = []int32{2, 3}
+	sut.permissionMiddleware.PermissionIdsTtl = 10 * time.Millisecond
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, mock.Anything).Return(sut.permissions, nil)
+	statusCode, _ := sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+	statusCode, _ = sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+	sut.permissionRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "FindAll", 1)
+	time.Sleep(20 * time.Millisecond)
+	statusCode, _ = sut.serve("/api/v1/all")
+	sut.Equal(statusCode, http.StatusOK)
+	sut.permissionRepositoryMock.Mock.AssertNumberOfCalls(sut.T(), "FindAll", 2)
+}
+
+func (sut *PermissionMiddlewareTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *PermissionMiddlewareTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *PermissionMiddlewareTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,196 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/middlewares"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionMiddlewareTestSuite struct {
+	suite.Suite
+	redisUtilMock     *mockutils.RedisUtilMock
+	redisHelperMock   *mockhelpers.RedisHelperMock
+	client            *redis.Client
+	errInternalServer error
+	sessionId         string
+	e                 *echo.Echo
+}
+
+func TestSessionMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionMiddlewareTestSuite))
+}
+
+func (sut *SessionMiddlewareTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.client = &redis.Client{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.sessionId = "sessionId"
+	os.Setenv("ECOMMERCEV2_COOKIE_SECURE", "false")
+}
+
+func (sut *SessionMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sessionMiddleware := middlewares.NewSessionMiddleware(sut.redisUtilMock, sut.redisHelperMock)
+	sut.e = echo.New()
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.GET("/api/v1/test", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id":            ctx.Value(middlewares.IdKey).(int32),
+			"username":      ctx.Value(middlewares.UsernameKey).(string),
+			"email":         ctx.Value(middlewares.EmailKey).(string),
+			"idPermissions": ctx.Value(middlewares.PermissionKey).([]int32),
+			"sessionId":     ctx.Value(middlewares.SessionIdKey).(string),
+		})
+	}, sessionMiddleware.Authenticate)
+}
+
+func (sut *SessionMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *SessionMiddlewareTestSuite) serve(cookie *http.Cookie) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *SessionMiddlewareTestSuite) Test1AuthenticateWithoutCookieUnauthorized() {
+	sut.T().Log("Test1AuthenticateWithoutCookieUnauthorized")
+	statusCode, responseBody := sut.serve(nil)
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["field"], "message")
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *SessionMiddlewareTestSuite) Test2AuthenticateUnknownSessionUnauthorized() {
+	sut.T().Log("Test2AuthenticateUnknownSessionUnauthorized")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return("", redis.Nil)
+	statusCode, responseBody := sut.serve(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *SessionMiddlewareTestSuite) Test3AuthenticateRedisHelperGetInternalServerError() {
+	sut.T().Log("Test3AuthenticateRedisHelperGetInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return("", sut.errInternalServer)
+	statusCode, responseBody := sut.serve(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *SessionMiddlewareTestSuite) Test4AuthenticateMalformedSessionUnauthorized() {
+	sut.T().Log("Test4AuthenticateMalformedSessionUnauthorized")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return("not a json", nil)
+	statusCode, responseBody := sut.serve(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *SessionMiddlewareTestSuite) Test5AuthenticateAbsoluteLifetimeReachedUnauthorized() {
+	sut.T().Log("Test5AuthenticateAbsoluteLifetimeReachedUnauthorized")
+	createdAt := time.Now().Add(-25 * time.Hour).UnixMilli()
+	session := `{"email":"email@email.com","id":1,"idPermissions":[1,2],"username":"username","createdAt":` + strconv.FormatInt(createdAt, 10) + `}`
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return(session, nil)
+	sut.redisHelperMock.Mock.On("Del", sut.client, mock.Anything, sut.sessionId).Return(int64(1), nil)
+	statusCode, responseBody := sut.serve(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+	sut.redisHelperMock.Mock.AssertCalled(sut.T(), "Del", sut.client, mock.Anything, sut.sessionId)
+}
+
+func (sut *SessionMiddlewareTestSuite) Test6AuthenticateRedisHelperExpireInternalServerError() {
+	sut.T().Log("Test6AuthenticateRedisHelperExpireInternalServerError")
+	session := `{"email":"email@email.com","id":1,"idPermissions":[1,2],"username":"username","createdAt":` + strconv.FormatInt(time.Now().UnixMilli(), 10) + `}`
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return(session, nil)
+	sut.redisHelperMock.Mock.On("Expire", sut.client, mock.Anything, sut.sessionId, 30*time.Minute).Return(false, sut.errInternalServer)
+	statusCode, responseBody := sut.serve(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *SessionMiddlewareTestSuite) Test7AuthenticateSuccess() {
+	sut.T().Log("Test7AuthenticateSuccess")
+	createdAt := time.Now().Add(-(24*time.Hour - 10*time.Minute))
+	session := `{"email":"email@email.com","id":1,"idPermissions":[1,2],"username":"username","createdAt":` + strconv.FormatInt(createdAt.UnixMilli(), 10) + `}`
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Get", sut.client, mock.Anything, sut.sessionId).Return(session, nil)
+	sut.redisHelperMock.Mock.On("Expire", sut.client, mock.Anything, sut.sessionId, mock.AnythingOfType("time.Duration")).Return(true, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+	req.AddCookie(&http.Cookie{Name: "sessionId", Value: sut.sessionId})
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	sut.Equal(response.StatusCode, http.StatusOK)
+
+	// the ttl is capped by the absolute lifetime, not by the 30 minutes idle timeout
+	ttl := sut.redisHelperMock.Mock.Calls[1].Arguments.Get(3).(time.Duration)
+	sut.LessOrEqual(ttl, 10*time.Minute)
+	sut.Greater(ttl, 9*time.Minute)
+	cookies := response.Cookies()
+	sut.Equal(cookies[0].Name, "sessionId")
+	sut.Equal(cookies[0].MaxAge, int(ttl.Seconds()))
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var responseBody map[string]interface{}
+	json.Unmarshal(body, &responseBody)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.Equal(responseBody["username"], "username")
+	sut.Equal(responseBody["email"], "email@email.com")
+	sut.Equal(responseBody["idPermissions"], []interface{}{float64(1), float64(2)})
+	sut.Equal(responseBody["sessionId"], sut.sessionId)
+}
+
+func (sut *SessionMiddlewareTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *SessionMiddlewareTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *SessionMiddlewareTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,176 @@
+package middlewares_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenMiddlewareTestSuite struct {
+	suite.Suite
+	redisUtilMock         *mockutils.RedisUtilMock
+	redisHelperMock       *mockhelpers.RedisHelperMock
+	jwtHelperMock         *mockhelpers.JwtHelperMock
+	tokenFamilyHelperMock *mockhelpers.TokenFamilyHelperMock
+	client                *redis.Client
+	errInternalServer     error
+	accessToken           string
+	accessTokenClaims     helpers.AccessTokenClaims
+	e                     *echo.Echo
+}
+
+func TestTokenMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenMiddlewareTestSuite))
+}
+
+func (sut *TokenMiddlewareTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.client = &redis.Client{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.accessToken = "accessToken"
+}
+
+func (sut *TokenMiddlewareTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.accessTokenClaims = helpers.AccessTokenClaims{
+		Type:          "access",
+		FamilyId:      "familyId",
+		Username:      "username",
+		Email:         "email@email.com",
+		IdPermissions: []int32{1, 2},
+		TwoFactor:     true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      "accessTokenId",
+			Subject: "1",
+		},
+	}
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.jwtHelperMock = new(mockhelpers.JwtHelperMock)
+	sut.tokenFamilyHelperMock = new(mockhelpers.TokenFamilyHelperMock)
+	sessionMiddleware := middlewares.NewSessionTokenOrApiKeyMiddleware(
+		middlewares.NewSessionMiddleware(sut.redisUtilMock, sut.redisHelperMock),
+		middlewares.NewTokenMiddleware(sut.redisUtilMock, sut.jwtHelperMock, sut.tokenFamilyHelperMock),
+		middlewares.NewApiKeyMiddleware(new(mockutils.PostgresUtilMock), new(mockrepositories.ApiKeyRepositoryMock)),
+	)
+	sut.e = echo.New()
+	sut.e.Use(middlewares.SetRequestId)
+	sut.e.GET("/api/v1/test", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"id":            ctx.Value(middlewares.IdKey).(int32),
+			"username":      ctx.Value(middlewares.UsernameKey).(string),
+			"email":         ctx.Value(middlewares.EmailKey).(string),
+			"idPermissions": ctx.Value(middlewares.PermissionKey).([]int32),
+			"sessionId":     ctx.Value(middlewares.SessionIdKey).(string),
+			"tokenId":       ctx.Value(middlewares.TokenIdKey).(string),
+			"twoFactor":     ctx.Value(middlewares.TwoFactorKey).(bool),
+		})
+	}, sessionMiddleware.Authenticate)
+}
+
+func (sut *TokenMiddlewareTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *TokenMiddlewareTestSuite) serve(authorization string) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *TokenMiddlewareTestSuite) Test1AuthenticateNotBearerUnauthorized() {
+	sut.T().Log("Test1AuthenticateNotBearerUnauthorized")
+	statusCode, responseBody := sut.serve("Basic dXNlcjpwYXNz")
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["field"], "message")
+	sut.Equal(errorMessage0["message"], "unauthorized")
+	sut.jwtHelperMock.Mock.AssertNotCalled(sut.T(), "ParseAccessToken", mock.Anything)
+}
+
+func (sut *TokenMiddlewareTestSuite) Test2AuthenticateInvalidAccessTokenUnauthorized() {
+	sut.T().Log("Test2AuthenticateInvalidAccessTokenUnauthorized")
+	sut.jwtHelperMock.Mock.On("ParseAccessToken", sut.accessToken).Return(helpers.AccessTokenClaims{}, errors.New("token is expired"))
+	statusCode, responseBody := sut.serve("Bearer " + sut.accessToken)
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *TokenMiddlewareTestSuite) Test3AuthenticateTokenFamilyHelperExistsInternalServerError() {
+	sut.T().Log("Test3AuthenticateTokenFamilyHelperExistsInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseAccessToken", sut.accessToken).Return(sut.accessTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Exists", sut.client, mock.Anything, "familyId").Return(false, sut.errInternalServer)
+	statusCode, responseBody := sut.serve("Bearer " + sut.accessToken)
+	sut.Equal(statusCode, http.StatusInternalServerError)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "internal server error")
+}
+
+func (sut *TokenMiddlewareTestSuite) Test4AuthenticateRevokedTokenFamilyUnauthorized() {
+	sut.T().Log("Test4AuthenticateRevokedTokenFamilyUnauthorized")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseAccessToken", sut.accessToken).Return(sut.accessTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Exists", sut.client, mock.Anything, "familyId").Return(false, nil)
+	statusCode, responseBody := sut.serve("Bearer " + sut.accessToken)
+	sut.Equal(statusCode, http.StatusUnauthorized)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "unauthorized")
+}
+
+func (sut *TokenMiddlewareTestSuite) Test5AuthenticateSuccess() {
+	sut.T().Log("Test5AuthenticateSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseAccessToken", sut.accessToken).Return(sut.accessTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Exists", sut.client, mock.Anything, "familyId").Return(true, nil)
+	statusCode, responseBody := sut.serve("Bearer " + sut.accessToken)
+	sut.Equal(statusCode, http.StatusOK)
+	sut.Equal(responseBody["id"], float64(1))
+	sut.Equal(responseBody["username"], "username")
+	sut.Equal(responseBody["email"], "email@email.com")
+	sut.Equal(responseBody["idPermissions"], []interface{}{float64(1), float64(2)})
+	sut.Equal(responseBody["sessionId"], "tokenFamily:familyId")
+	sut.Equal(responseBody["tokenId"], "accessTokenId")
+	sut.Equal(responseBody["twoFactor"], true)
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Get", sut.client, mock.Anything, mock.Anything)
+}
+
+func (sut *TokenMiddlewareTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *TokenMiddlewareTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *TokenMiddlewareTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,23 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type AccountDeletionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *AccountDeletionRepositoryMock) FindUserIdsDue(pool *pgxpool.Pool, ctx context.Context, deletionRequestedBefore int64) (userIds []int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, deletionRequestedBefore)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
+
+func (repository *AccountDeletionRepositoryMock) Anonymise(tx pgx.Tx, ctx context.Context, userId int32, deletionRequestedBefore int64, deletedAt int64) (anonymised bool, err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, deletionRequestedBefore, deletedAt)
+	return arguments.Bool(0), arguments.Error(1)
+}
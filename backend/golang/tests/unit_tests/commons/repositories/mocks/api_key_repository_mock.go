@@ -0,0 +1,23 @@
+package mockrepositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ApiKeyRepositoryMock) FindActiveByKeyHash(pool *pgxpool.Pool, ctx context.Context, keyHash string, now int64) (apiKey models.ApiKey, err error) {
+	arguments := repository.Mock.Called(pool, ctx, keyHash, now)
+	return arguments.Get(0).(models.ApiKey), arguments.Error(1)
+}
+
+func (repository *ApiKeyRepositoryMock) UpdateLastUsedAt(pool *pgxpool.Pool, ctx context.Context, id int32, lastUsedAt int64) (err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, lastUsedAt)
+	return arguments.Error(0)
+}
@@ -0,0 +1,33 @@
+package mockrepositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type DataExportRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *DataExportRepositoryMock) FindAllPending(pool *pgxpool.Pool, ctx context.Context) (dataExports []models.DataExport, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.DataExport), arguments.Error(1)
+}
+
+func (repository *DataExportRepositoryMock) UpdateReady(pool *pgxpool.Pool, ctx context.Context, id int32, archive []byte, completedAt int64, expiresAt int64) (err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, archive, completedAt, expiresAt)
+	return arguments.Error(0)
+}
+
+func (repository *DataExportRepositoryMock) UpdateFailed(pool *pgxpool.Pool, ctx context.Context, id int32, completedAt int64) (err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, completedAt)
+	return arguments.Error(0)
+}
+
+func (repository *DataExportRepositoryMock) UpdateExpired(pool *pgxpool.Pool, ctx context.Context, now int64) (err error) {
+	arguments := repository.Mock.Called(pool, ctx, now)
+	return arguments.Error(0)
+}
@@ -0,0 +1,18 @@
+package mockrepositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PermissionRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.Permission), arguments.Error(1)
+}
@@ -0,0 +1,58 @@
+package mockrepositories
+
+import (
+	"backend-golang/commons/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type PersonalDataRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PersonalDataRepositoryMock) FindUserById(pool *pgxpool.Pool, ctx context.Context, userId int32) (user models.PersonalDataUser, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).(models.PersonalDataUser), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindPermissionsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissions []string, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]string), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindRolesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []string, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]string), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) CountUnusedRecoveryCodesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (count int, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindIdentitiesByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (identities []models.PersonalDataIdentity, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.PersonalDataIdentity), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindApiKeysByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (apiKeys []models.PersonalDataApiKey, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.PersonalDataApiKey), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindAuthEventsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32, email string) (authEvents []models.PersonalDataAuthEvent, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId, email)
+	return arguments.Get(0).([]models.PersonalDataAuthEvent), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindPermissionAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (permissionAudits []models.PersonalDataPermissionAudit, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.PersonalDataPermissionAudit), arguments.Error(1)
+}
+
+func (repository *PersonalDataRepositoryMock) FindRoleAuditsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roleAudits []models.PersonalDataRoleAudit, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.PersonalDataRoleAudit), arguments.Error(1)
+}
@@ -0,0 +1,22 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type TwoFactorRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *TwoFactorRepositoryMock) UpdateTotpLastUsedStep(pool *pgxpool.Pool, ctx context.Context, userId int32, step int64) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId, step)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *TwoFactorRepositoryMock) UseRecoveryCode(pool *pgxpool.Pool, ctx context.Context, userId int32, codeHash string, usedAt int64) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId, codeHash, usedAt)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
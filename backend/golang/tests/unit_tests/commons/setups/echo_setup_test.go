@@ -0,0 +1,103 @@
+package setups_test
+
+import (
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type EchoSetupTestSuite struct {
+	suite.Suite
+	postgresUtilMock *mockutils.PostgresUtilMock
+	redisUtilMock    *mockutils.RedisUtilMock
+	e                *echo.Echo
+}
+
+func TestEchoSetupTestSuite(t *testing.T) {
+	suite.Run(t, new(EchoSetupTestSuite))
+}
+
+func (sut *EchoSetupTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+}
+
+func (sut *EchoSetupTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.e = setups.SetEcho(sut.postgresUtilMock, sut.redisUtilMock, setups.SetValidator(), new(mockhelpers.PasswordHasherMock), new(mockhelpers.UuidHelperMock), new(mockhelpers.RedisHelperMock), new(mockhelpers.SessionRegistryHelperMock), new(mockhelpers.TokenHelperMock), new(mockutils.MailerMock), new(mockhelpers.EmailVerificationHelperMock), new(mockhelpers.TwoFactorHelperMock), new(mockhelpers.JwtHelperMock), new(mockhelpers.TokenFamilyHelperMock), new(mockhelpers.CsrfTokenHelperMock), new(mockhelpers.AuthEventHelperMock), new(mockhelpers.OidcHelperMock), new(mockhelpers.PersonalDataHelperMock))
+}
+
+func (sut *EchoSetupTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *EchoSetupTestSuite) serveWithApiKey(method string, target string) (statusCode int, responseBody map[string]interface{}) {
+	req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middlewares.ApiKeyHeader, "ek_key")
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	response := rec.Result()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	json.Unmarshal(body, &responseBody)
+	return response.StatusCode, responseBody
+}
+
+func (sut *EchoSetupTestSuite) Test1FindAllDataExportsWithApiKeyForbidden() {
+	sut.T().Log("Test1FindAllDataExportsWithApiKeyForbidden")
+	statusCode, responseBody := sut.serveWithApiKey(http.MethodGet, "/api/v1/users/me/data-exports")
+	sut.Equal(statusCode, http.StatusForbidden)
+	sut.Equal(responseBody["data"], nil)
+	errorMessage0, _ := responseBody["errors"].([]interface{})[0].(map[string]interface{})
+	sut.Equal(errorMessage0["message"], "forbidden")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "GetPool")
+}
+
+func (sut *EchoSetupTestSuite) Test2SelfServiceRoutesWithApiKeyForbidden() {
+	sut.T().Log("Test2SelfServiceRoutesWithApiKeyForbidden")
+	routes := [][2]string{
+		{http.MethodPost, "/api/v1/users/me/data-exports"},
+		{http.MethodGet, "/api/v1/users/me/data-exports/1/archive"},
+		{http.MethodPost, "/api/v1/users/me/deletion"},
+		{http.MethodGet, "/api/v1/users/me"},
+		{http.MethodPatch, "/api/v1/users/me"},
+		{http.MethodGet, "/api/v1/users/sessions"},
+		{http.MethodDelete, "/api/v1/users/sessions/1"},
+		{http.MethodPut, "/api/v1/users/password"},
+		{http.MethodPost, "/api/v1/users/2fa/enroll"},
+		{http.MethodGet, "/api/v1/users/csrf"},
+	}
+	for _, route := range routes {
+		statusCode, _ := sut.serveWithApiKey(route[0], route[1])
+		sut.Equal(statusCode, http.StatusForbidden, route[0]+" "+route[1])
+	}
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "GetPool")
+	sut.redisUtilMock.Mock.AssertNotCalled(sut.T(), "GetClient")
+}
+
+func (sut *EchoSetupTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *EchoSetupTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *EchoSetupTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
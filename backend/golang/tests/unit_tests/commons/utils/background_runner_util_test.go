@@ -0,0 +1,95 @@
+package utils_test
+
+import (
+	"backend-golang/commons/utils"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type contextKey string
+
+type BackgroundRunnerTestSuite struct {
+	suite.Suite
+	mutex  sync.Mutex
+	errs   []error
+	runner *utils.BackgroundRunnerImplementation
+}
+
+func TestBackgroundRunnerTestSuite(t *testing.T) {
+	suite.Run(t, new(BackgroundRunnerTestSuite))
+}
+
+func (sut *BackgroundRunnerTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+}
+
+func (sut *BackgroundRunnerTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.errs = nil
+	sut.runner = utils.NewBackgroundRunner(1, func(ctx context.Context, err error) {
+		sut.mutex.Lock()
+		defer sut.mutex.Unlock()
+		sut.errs = append(sut.errs, errors.New(err.Error()+" of "+ctx.Value(contextKey("requestId")).(string)))
+	})
+}
+
+func (sut *BackgroundRunnerTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *BackgroundRunnerTestSuite) Test1RunCanceledRequestSuccess() {
+	sut.T().Log("Test1RunCanceledRequestSuccess")
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey("requestId"), "requestId"))
+	var jobErr error
+	sut.runner.Run(ctx, "job", func(ctx context.Context) error {
+		jobErr = ctx.Err()
+		return errors.New("job failed")
+	})
+	cancel()
+	sut.runner.Close()
+	sut.Nil(jobErr)
+	sut.Equal(sut.errs, []error{errors.New("job failed of requestId")})
+}
+
+func (sut *BackgroundRunnerTestSuite) Test2RunQueueFullDropped() {
+	sut.T().Log("Test2RunQueueFullDropped")
+	ctx := context.WithValue(context.Background(), contextKey("requestId"), "requestId")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ran := 0
+	sut.runner.Run(ctx, "first", func(ctx context.Context) error {
+		close(started)
+		<-release
+		ran++
+		return nil
+	})
+	<-started
+	sut.runner.Run(ctx, "second", func(ctx context.Context) error {
+		ran++
+		return nil
+	})
+	sut.runner.Run(ctx, "third", func(ctx context.Context) error {
+		ran++
+		return nil
+	})
+	close(release)
+	sut.runner.Close()
+	sut.Equal(ran, 2)
+	sut.Equal(sut.errs, []error{errors.New("background queue is full, dropped third of requestId")})
+}
+
+func (sut *BackgroundRunnerTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *BackgroundRunnerTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *BackgroundRunnerTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,148 @@
+package utils_test
+
+import (
+	"backend-golang/commons/utils"
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SmtpMailerTestSuite struct {
+	suite.Suite
+	listener net.Listener
+	mutex    sync.Mutex
+	received []string
+	mail     utils.Mail
+}
+
+func TestSmtpMailerTestSuite(t *testing.T) {
+	suite.Run(t, new(SmtpMailerTestSuite))
+}
+
+func (sut *SmtpMailerTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.mail = utils.Mail{To: "email@email.com", Subject: "Subject", Body: "Body"}
+}
+
+func (sut *SmtpMailerTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	sut.Nil(err)
+	sut.listener = listener
+	sut.received = nil
+}
+
+func (sut *SmtpMailerTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *SmtpMailerTestSuite) newMailer(timeout time.Duration) *utils.SmtpMailerImplementation {
+	return &utils.SmtpMailerImplementation{
+		Addr:    sut.listener.Addr().String(),
+		From:    "noreply@email.com",
+		Timeout: timeout,
+	}
+}
+
+// serveSmtp answers one connection like a mail server without extensions and keeps every command it gets
+func (sut *SmtpMailerTestSuite) serveSmtp() {
+	conn, err := sut.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	conn.Write([]byte("220 localhost ready\r\n"))
+	data := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		sut.mutex.Lock()
+		sut.received = append(sut.received, line)
+		sut.mutex.Unlock()
+		switch {
+		case data && line == ".":
+			data = false
+			conn.Write([]byte("250 queued\r\n"))
+		case data:
+		case strings.HasPrefix(line, "DATA"):
+			data = true
+			conn.Write([]byte("354 go ahead\r\n"))
+		case strings.HasPrefix(line, "QUIT"):
+			conn.Write([]byte("221 bye\r\n"))
+			return
+		default:
+			conn.Write([]byte("250 ok\r\n"))
+		}
+	}
+}
+
+// serveSilent accepts one connection and never answers it
+func (sut *SmtpMailerTestSuite) serveSilent(release chan struct{}) {
+	conn, err := sut.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	<-release
+}
+
+func (sut *SmtpMailerTestSuite) Test1SendSuccess() {
+	sut.T().Log("Test1SendSuccess")
+	go sut.serveSmtp()
+	err := sut.newMailer(time.Second).Send(context.Background(), sut.mail)
+	sut.Nil(err)
+	sut.mutex.Lock()
+	defer sut.mutex.Unlock()
+	sut.Contains(sut.received, "MAIL FROM:<noreply@email.com>")
+	sut.Contains(sut.received, "RCPT TO:<email@email.com>")
+	sut.Contains(sut.received, "Subject: Subject")
+	sut.Contains(sut.received, "Body")
+	sut.Equal(sut.received[len(sut.received)-1], "QUIT")
+}
+
+func (sut *SmtpMailerTestSuite) Test2SendSilentServerTimeout() {
+	sut.T().Log("Test2SendSilentServerTimeout")
+	release := make(chan struct{})
+	defer close(release)
+	go sut.serveSilent(release)
+	start := time.Now()
+	err := sut.newMailer(100*time.Millisecond).Send(context.Background(), sut.mail)
+	sut.NotNil(err)
+	sut.Less(time.Since(start), time.Second)
+}
+
+func (sut *SmtpMailerTestSuite) Test3SendCanceledContextCanceled() {
+	sut.T().Log("Test3SendCanceledContextCanceled")
+	release := make(chan struct{})
+	defer close(release)
+	go sut.serveSilent(release)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := sut.newMailer(time.Minute).Send(ctx, sut.mail)
+	sut.Equal(err, context.Canceled)
+	sut.Less(time.Since(start), time.Second)
+}
+
+func (sut *SmtpMailerTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *SmtpMailerTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+	sut.listener.Close()
+}
+
+func (sut *SmtpMailerTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,15 @@
+package mockutils
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BackgroundRunnerMock struct {
+	Mock mock.Mock
+}
+
+func (runner *BackgroundRunnerMock) Run(ctx context.Context, name string, job func(ctx context.Context) error) {
+	runner.Mock.Called(ctx, name, job)
+}
@@ -0,0 +1,17 @@
+package mockutils
+
+import (
+	"backend-golang/commons/utils"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MailerMock struct {
+	Mock mock.Mock
+}
+
+func (mailer *MailerMock) Send(ctx context.Context, mail utils.Mail) (err error) {
+	arguments := mailer.Mock.Called(ctx, mail)
+	return arguments.Error(0)
+}
@@ -0,0 +1,65 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/categories/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type CategoryRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *CategoryRepositoryMock) LockTree(tx pgx.Tx, ctx context.Context) (err error) {
+	arguments := repository.Mock.Called(tx, ctx)
+	return arguments.Error(0)
+}
+
+func (repository *CategoryRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context) (categories []models.Category, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (category models.Category, err error) {
+	arguments := repository.Mock.Called(pool, ctx, slug)
+	return arguments.Get(0).(models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) FindBreadcrumbBySlug(pool *pgxpool.Pool, ctx context.Context, slug string) (categories []models.Category, err error) {
+	arguments := repository.Mock.Called(pool, ctx, slug)
+	return arguments.Get(0).([]models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) FindById(tx pgx.Tx, ctx context.Context, id int32) (category models.Category, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).(models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) FindIdsByParentId(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4) (ids []int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, parentId)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) Create(tx pgx.Tx, ctx context.Context, parentId pgtype.Int4, name string, slug string, createdAt int64) (category models.Category, err error) {
+	arguments := repository.Mock.Called(tx, ctx, parentId, name, slug, createdAt)
+	return arguments.Get(0).(models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) UpdatePaths(tx pgx.Tx, ctx context.Context, oldPath string, newPath string) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, oldPath, newPath)
+	return arguments.Error(0)
+}
+
+func (repository *CategoryRepositoryMock) UpdateParent(tx pgx.Tx, ctx context.Context, id int32, parentId pgtype.Int4, updatedAt int64) (category models.Category, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id, parentId, updatedAt)
+	return arguments.Get(0).(models.Category), arguments.Error(1)
+}
+
+func (repository *CategoryRepositoryMock) UpdatePositions(tx pgx.Tx, ctx context.Context, ids []int32, updatedAt int64) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, ids, updatedAt)
+	return arguments.Error(0)
+}
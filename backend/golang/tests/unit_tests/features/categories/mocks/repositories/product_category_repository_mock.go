@@ -0,0 +1,22 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProductCategoryRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ProductCategoryRepositoryMock) DeleteAllByProductId(tx pgx.Tx, ctx context.Context, productId int32) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, productId)
+	return arguments.Error(0)
+}
+
+func (repository *ProductCategoryRepositoryMock) CreateAll(tx pgx.Tx, ctx context.Context, productId int32, categoryIds []int32) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, productId, categoryIds)
+	return arguments.Error(0)
+}
@@ -0,0 +1,24 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/categories/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProductRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ProductRepositoryMock) CountById(tx pgx.Tx, ctx context.Context, id int32) (count int, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) FindAllByCategoryPath(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error) {
+	arguments := repository.Mock.Called(pool, ctx, productFilter)
+	return arguments.Get(0).([]models.Product), arguments.Error(1)
+}
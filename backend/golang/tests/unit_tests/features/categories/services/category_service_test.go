@@ -0,0 +1,378 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/categories/models"
+	"backend-golang/features/categories/services"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/categories/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CategoryServiceTestSuite struct {
+	suite.Suite
+	ctx                           context.Context
+	postgresUtilMock              *mockutils.PostgresUtilMock
+	validate                      *validator.Validate
+	categoryRepositoryMock        *mockrepositories.CategoryRepositoryMock
+	productRepositoryMock         *mockrepositories.ProductRepositoryMock
+	productCategoryRepositoryMock *mockrepositories.ProductCategoryRepositoryMock
+	pool                          *pgxpool.Pool
+	tx                            pgx.Tx
+	errTimeout                    error
+	errInternalServer             error
+	coffee                        models.Category
+	tea                           models.Category
+	beans                         models.Category
+	arabica                       models.Category
+	categoryService               services.CategoryService
+}
+
+func TestCategoryServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CategoryServiceTestSuite))
+}
+
+func (sut *CategoryServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *CategoryServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.coffee = toCategory(1, 0, "coffee", "/1/", 0)
+	sut.tea = toCategory(2, 0, "tea", "/2/", 1)
+	sut.beans = toCategory(3, 1, "beans", "/1/3/", 0)
+	sut.arabica = toCategory(4, 3, "arabica", "/1/3/4/", 0)
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.categoryRepositoryMock = new(mockrepositories.CategoryRepositoryMock)
+	sut.productRepositoryMock = new(mockrepositories.ProductRepositoryMock)
+	sut.productCategoryRepositoryMock = new(mockrepositories.ProductCategoryRepositoryMock)
+	sut.categoryService = services.NewCategoryService(sut.postgresUtilMock, sut.validate, sut.categoryRepositoryMock, sut.productRepositoryMock, sut.productCategoryRepositoryMock)
+}
+
+func (sut *CategoryServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func toCategory(id int32, parentId int32, slug string, path string, position int32) models.Category {
+	return models.Category{
+		Id:       pgtype.Int4{Valid: true, Int32: id},
+		ParentId: pgtype.Int4{Valid: parentId > 0, Int32: parentId},
+		Name:     pgtype.Text{Valid: true, String: slug},
+		Slug:     pgtype.Text{Valid: true, String: slug},
+		Path:     pgtype.Text{Valid: true, String: path},
+		Position: pgtype.Int4{Valid: true, Int32: position},
+	}
+}
+
+func (sut *CategoryServiceTestSuite) Test01FindTreeCategoryRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test01FindTreeCategoryRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return([]models.Category{}, sut.errTimeout)
+	httpCode, response := sut.categoryService.FindTree(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *CategoryServiceTestSuite) Test02FindTreeSuccess() {
+	sut.T().Log("Test02FindTreeSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return([]models.Category{sut.coffee, sut.beans, sut.arabica, sut.tea}, nil)
+	httpCode, response := sut.categoryService.FindTree(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	arabica := &models.CategoryTreeResponse{Id: 4, Name: "arabica", Slug: "arabica", Children: []*models.CategoryTreeResponse{}}
+	beans := &models.CategoryTreeResponse{Id: 3, Name: "beans", Slug: "beans", Children: []*models.CategoryTreeResponse{arabica}}
+	sut.Equal(response.Data, []*models.CategoryTreeResponse{
+		{Id: 1, Name: "coffee", Slug: "coffee", Children: []*models.CategoryTreeResponse{beans}},
+		{Id: 2, Name: "tea", Slug: "tea", Children: []*models.CategoryTreeResponse{}},
+	})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test03FindBreadcrumbNotFound() {
+	sut.T().Log("Test03FindBreadcrumbNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindBreadcrumbBySlug", sut.pool, sut.ctx, "green").Return([]models.Category{}, nil)
+	httpCode, response := sut.categoryService.FindBreadcrumb(sut.ctx, "green")
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "category not found")
+}
+
+func (sut *CategoryServiceTestSuite) Test04FindBreadcrumbSuccess() {
+	sut.T().Log("Test04FindBreadcrumbSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindBreadcrumbBySlug", sut.pool, sut.ctx, "arabica").Return([]models.Category{sut.coffee, sut.beans, sut.arabica}, nil)
+	httpCode, response := sut.categoryService.FindBreadcrumb(sut.ctx, "arabica")
+	sut.Equal(httpCode, http.StatusOK)
+	categoryResponses, _ := response.Data.([]models.CategoryResponse)
+	sut.Equal(len(categoryResponses), 3)
+	sut.Nil(categoryResponses[0].ParentId)
+	sut.Equal(categoryResponses[2].Slug, "arabica")
+	sut.Equal(*categoryResponses[2].ParentId, int32(3))
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test05FindAllProductsCategoryRepositoryFindBySlugNotFound() {
+	sut.T().Log("Test05FindAllProductsCategoryRepositoryFindBySlugNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindBySlug", sut.pool, sut.ctx, "green").Return(models.Category{}, pgx.ErrNoRows)
+	httpCode, response := sut.categoryService.FindAllProducts(sut.ctx, "green", models.FindAllProductRequest{})
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "category not found")
+	sut.productRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindAllByCategoryPath", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *CategoryServiceTestSuite) Test06FindAllProductsIncludesDescendantsByPath() {
+	sut.T().Log("Test06FindAllProductsIncludesDescendantsByPath")
+	product := models.Product{
+		Id:       pgtype.Int4{Valid: true, Int32: 2},
+		Name:     pgtype.Text{Valid: true, String: "Arabica Beans"},
+		Slug:     pgtype.Text{Valid: true, String: "arabica-beans"},
+		Price:    pgtype.Int8{Valid: true, Int64: 1299},
+		Currency: pgtype.Text{Valid: true, String: "USD"},
+	}
+	product2 := product
+	product2.Id = pgtype.Int4{Valid: true, Int32: 1}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.categoryRepositoryMock.Mock.On("FindBySlug", sut.pool, sut.ctx, "coffee").Return(sut.coffee, nil)
+	sut.productRepositoryMock.Mock.On("FindAllByCategoryPath", sut.pool, sut.ctx, models.ProductFilter{Path: "/1/", Limit: 2}).Return([]models.Product{product, product2}, nil)
+	httpCode, response := sut.categoryService.FindAllProducts(sut.ctx, "coffee", models.FindAllProductRequest{Limit: 1})
+	sut.Equal(httpCode, http.StatusOK)
+	findAllProductResponse, _ := response.Data.(models.FindAllProductResponse)
+	sut.Equal(len(findAllProductResponse.Products), 1)
+	sut.Equal(findAllProductResponse.Products[0].Slug, "arabica-beans")
+	sut.NotEqual(findAllProductResponse.NextCursor, "")
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test07CreateValidationError() {
+	sut.T().Log("Test07CreateValidationError")
+	httpCode, response := sut.categoryService.Create(sut.ctx, models.CategoryRequest{Name: "Green Coffee", Slug: "green coffee"})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "slug")
+	sut.Equal(errorMessages[0].Message, "please use only lowercase letter and number separated by single -")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "BeginTx", sut.ctx, pgx.TxOptions{})
+}
+
+func (sut *CategoryServiceTestSuite) Test08CreateCategoryRepositoryCreateParentNotFound() {
+	sut.T().Log("Test08CreateCategoryRepositoryCreateParentNotFound")
+	errForeignKeyViolation := &pgconn.PgError{Code: "23503", ConstraintName: "categories_parent_id_fkey"}
+	categoryRequest := models.CategoryRequest{Name: "Green Coffee", Slug: "green-coffee", ParentId: 9}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, pgtype.Int4{Valid: true, Int32: 9}, "Green Coffee", "green-coffee", mock.AnythingOfType("int64")).Return(models.Category{}, errForeignKeyViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errForeignKeyViolation).Return(nil)
+	httpCode, response := sut.categoryService.Create(sut.ctx, categoryRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "parentId")
+	sut.Equal(errorMessages[0].Message, "parent category not found")
+}
+
+func (sut *CategoryServiceTestSuite) Test09CreateCategoryRepositoryCreateUniqueViolation() {
+	sut.T().Log("Test09CreateCategoryRepositoryCreateUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "categories_slug_key"}
+	categoryRequest := models.CategoryRequest{Name: "Coffee", Slug: "coffee"}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, pgtype.Int4{}, "Coffee", "coffee", mock.AnythingOfType("int64")).Return(models.Category{}, errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	httpCode, response := sut.categoryService.Create(sut.ctx, categoryRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "slug")
+	sut.Equal(errorMessages[0].Message, "slug already exists")
+}
+
+func (sut *CategoryServiceTestSuite) Test10CreateSuccess() {
+	sut.T().Log("Test10CreateSuccess")
+	categoryRequest := models.CategoryRequest{Name: "beans", Slug: "beans", ParentId: 1}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, pgtype.Int4{Valid: true, Int32: 1}, "beans", "beans", mock.AnythingOfType("int64")).Return(sut.beans, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.Create(sut.ctx, categoryRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	parentId := int32(1)
+	sut.Equal(response.Data, models.CategoryResponse{Id: 3, ParentId: &parentId, Name: "beans", Slug: "beans", Position: 0})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test11MoveCategoryRepositoryFindByIdNotFound() {
+	sut.T().Log("Test11MoveCategoryRepositoryFindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(9)).Return(models.Category{}, pgx.ErrNoRows)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, pgx.ErrNoRows).Return(nil)
+	httpCode, response := sut.categoryService.Move(sut.ctx, 9, models.MoveCategoryRequest{ParentId: 2})
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "category not found")
+}
+
+func (sut *CategoryServiceTestSuite) Test12MoveUnderOwnDescendant() {
+	sut.T().Log("Test12MoveUnderOwnDescendant")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(1)).Return(sut.coffee, nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(4)).Return(sut.arabica, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.categoryService.Move(sut.ctx, 1, models.MoveCategoryRequest{ParentId: 4})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "parentId")
+	sut.Equal(errorMessages[0].Message, "cannot move a category under itself or its descendants")
+	sut.categoryRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdatePaths", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *CategoryServiceTestSuite) Test13MoveSameParentChangesNothing() {
+	sut.T().Log("Test13MoveSameParentChangesNothing")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(2)).Return(sut.tea, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.Move(sut.ctx, 2, models.MoveCategoryRequest{})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, models.CategoryResponse{Id: 2, Name: "tea", Slug: "tea", Position: 1})
+	sut.categoryRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdatePaths", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.categoryRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *CategoryServiceTestSuite) Test14MoveSubtreeSuccess() {
+	sut.T().Log("Test14MoveSubtreeSuccess")
+	moved := toCategory(3, 2, "beans", "/2/3/", 0)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(3)).Return(sut.beans, nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(2)).Return(sut.tea, nil)
+	sut.categoryRepositoryMock.Mock.On("UpdatePaths", sut.tx, sut.ctx, "/1/3/", "/2/3/").Return(nil)
+	sut.categoryRepositoryMock.Mock.On("UpdateParent", sut.tx, sut.ctx, int32(3), pgtype.Int4{Valid: true, Int32: 2}, mock.AnythingOfType("int64")).Return(moved, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.Move(sut.ctx, 3, models.MoveCategoryRequest{ParentId: 2})
+	sut.Equal(httpCode, http.StatusOK)
+	parentId := int32(2)
+	sut.Equal(response.Data, models.CategoryResponse{Id: 3, ParentId: &parentId, Name: "beans", Slug: "beans", Position: 0})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test15MoveToRootSuccess() {
+	sut.T().Log("Test15MoveToRootSuccess")
+	moved := toCategory(4, 0, "arabica", "/4/", 2)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindById", sut.tx, sut.ctx, int32(4)).Return(sut.arabica, nil)
+	sut.categoryRepositoryMock.Mock.On("UpdatePaths", sut.tx, sut.ctx, "/1/3/4/", "/4/").Return(nil)
+	sut.categoryRepositoryMock.Mock.On("UpdateParent", sut.tx, sut.ctx, int32(4), pgtype.Int4{}, mock.AnythingOfType("int64")).Return(moved, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.Move(sut.ctx, 4, models.MoveCategoryRequest{})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, models.CategoryResponse{Id: 4, Name: "arabica", Slug: "arabica", Position: 2})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test16ReorderNotEveryChild() {
+	sut.T().Log("Test16ReorderNotEveryChild")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindIdsByParentId", sut.tx, sut.ctx, pgtype.Int4{}).Return([]int32{1, 2}, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.categoryService.Reorder(sut.ctx, models.ReorderCategoryRequest{CategoryIds: []int32{2, 3}})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "categoryIds")
+	sut.Equal(errorMessages[0].Message, "please input every child category of the parent exactly once")
+	sut.categoryRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdatePositions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *CategoryServiceTestSuite) Test17ReorderSuccess() {
+	sut.T().Log("Test17ReorderSuccess")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.categoryRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.categoryRepositoryMock.Mock.On("FindIdsByParentId", sut.tx, sut.ctx, pgtype.Int4{}).Return([]int32{1, 2}, nil)
+	sut.categoryRepositoryMock.Mock.On("UpdatePositions", sut.tx, sut.ctx, []int32{2, 1}, mock.AnythingOfType("int64")).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.Reorder(sut.ctx, models.ReorderCategoryRequest{CategoryIds: []int32{2, 1}})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully reorder categories"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *CategoryServiceTestSuite) Test18UpdateProductCategoriesProductNotFound() {
+	sut.T().Log("Test18UpdateProductCategoriesProductNotFound")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.productRepositoryMock.Mock.On("CountById", sut.tx, sut.ctx, int32(9)).Return(0, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.categoryService.UpdateProductCategories(sut.ctx, 9, models.ProductCategoryRequest{CategoryIds: []int32{3}})
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "product not found")
+	sut.productCategoryRepositoryMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByProductId", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *CategoryServiceTestSuite) Test19UpdateProductCategoriesCategoryNotFound() {
+	sut.T().Log("Test19UpdateProductCategoriesCategoryNotFound")
+	errForeignKeyViolation := &pgconn.PgError{Code: "23503", ConstraintName: "product_categories_category_id_fkey"}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.productRepositoryMock.Mock.On("CountById", sut.tx, sut.ctx, int32(1)).Return(1, nil)
+	sut.productCategoryRepositoryMock.Mock.On("DeleteAllByProductId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.productCategoryRepositoryMock.Mock.On("CreateAll", sut.tx, sut.ctx, int32(1), []int32{3, 9}).Return(errForeignKeyViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errForeignKeyViolation).Return(nil)
+	httpCode, response := sut.categoryService.UpdateProductCategories(sut.ctx, 1, models.ProductCategoryRequest{CategoryIds: []int32{3, 9}})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "categoryIds")
+	sut.Equal(errorMessages[0].Message, "category not found")
+}
+
+func (sut *CategoryServiceTestSuite) Test20UpdateProductCategoriesSuccess() {
+	sut.T().Log("Test20UpdateProductCategoriesSuccess")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.productRepositoryMock.Mock.On("CountById", sut.tx, sut.ctx, int32(1)).Return(1, nil)
+	sut.productCategoryRepositoryMock.Mock.On("DeleteAllByProductId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.productCategoryRepositoryMock.Mock.On("CreateAll", sut.tx, sut.ctx, int32(1), []int32{3, 4}).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.categoryService.UpdateProductCategories(sut.ctx, 1, models.ProductCategoryRequest{CategoryIds: []int32{3, 4}})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update product categories"})
+	sut.Equal(response.Errors, nil)
+}
@@ -0,0 +1,43 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/products/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProductRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ProductRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context, productFilter models.ProductFilter) (products []models.Product, err error) {
+	arguments := repository.Mock.Called(pool, ctx, productFilter)
+	return arguments.Get(0).([]models.Product), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (product models.Product, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.Product), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) FindBySlug(pool *pgxpool.Pool, ctx context.Context, slug string, status string) (product models.Product, err error) {
+	arguments := repository.Mock.Called(pool, ctx, slug, status)
+	return arguments.Get(0).(models.Product), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) Create(pool *pgxpool.Pool, ctx context.Context, productRequest models.ProductRequest, createdAt int64) (id int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, productRequest, createdAt)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) Update(pool *pgxpool.Pool, ctx context.Context, id int32, productRequest models.ProductRequest, updatedAt int64) (product models.Product, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, productRequest, updatedAt)
+	return arguments.Get(0).(models.Product), arguments.Error(1)
+}
+
+func (repository *ProductRepositoryMock) Archive(pool *pgxpool.Pool, ctx context.Context, id int32, archivedAt int64) (count int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, archivedAt)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
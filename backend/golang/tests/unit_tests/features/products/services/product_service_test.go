@@ -0,0 +1,326 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/products/models"
+	"backend-golang/features/products/services"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/products/mocks/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProductServiceTestSuite struct {
+	suite.Suite
+	ctx                   context.Context
+	postgresUtilMock      *mockutils.PostgresUtilMock
+	validate              *validator.Validate
+	productRepositoryMock *mockrepositories.ProductRepositoryMock
+	pool                  *pgxpool.Pool
+	errTimeout            error
+	errInternalServer     error
+	product               models.Product
+	productRequest        models.ProductRequest
+	productService        services.ProductService
+}
+
+func TestProductServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ProductServiceTestSuite))
+}
+
+func (sut *ProductServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *ProductServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.product = models.Product{
+		Id:          pgtype.Int4{Valid: true, Int32: 1},
+		Name:        pgtype.Text{Valid: true, String: "Coffee Beans"},
+		Slug:        pgtype.Text{Valid: true, String: "coffee-beans"},
+		Description: pgtype.Text{Valid: true, String: "roasted arabica"},
+		Price:       pgtype.Int8{Valid: true, Int64: 1299},
+		Currency:    pgtype.Text{Valid: true, String: "USD"},
+		Status:      pgtype.Text{Valid: true, String: models.ProductStatusActive},
+		CreatedAt:   pgtype.Int8{Valid: true, Int64: 1695095017000},
+		UpdatedAt:   pgtype.Int8{Valid: true, Int64: 1695095017000},
+	}
+	sut.productRequest = models.ProductRequest{
+		Name:        "Coffee Beans",
+		Slug:        "coffee-beans",
+		Description: "roasted arabica",
+		Price:       1299,
+		Currency:    "USD",
+		Status:      models.ProductStatusActive,
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.productRepositoryMock = new(mockrepositories.ProductRepositoryMock)
+	sut.productService = services.NewProductService(sut.postgresUtilMock, sut.validate, sut.productRepositoryMock)
+}
+
+func (sut *ProductServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *ProductServiceTestSuite) Test01FindAllActiveIgnoresRequestStatus() {
+	sut.T().Log("Test01FindAllActiveIgnoresRequestStatus")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, models.ProductFilter{Status: models.ProductStatusActive, Limit: 21}).Return([]models.Product{sut.product}, nil)
+	httpCode, response := sut.productService.FindAllActive(sut.ctx, models.FindAllProductRequest{Status: models.ProductStatusDraft})
+	sut.Equal(httpCode, http.StatusOK)
+	findAllProductResponse, _ := response.Data.(models.FindAllProductResponse)
+	sut.Equal(len(findAllProductResponse.Products), 1)
+	sut.Equal(findAllProductResponse.NextCursor, "")
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test02FindAllValidationError() {
+	sut.T().Log("Test02FindAllValidationError")
+	httpCode, response := sut.productService.FindAll(sut.ctx, models.FindAllProductRequest{Status: "SOLD", Limit: 101})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "status")
+	sut.Equal(errorMessages[0].Message, "please input one of DRAFT ACTIVE ARCHIVED")
+	sut.Equal(errorMessages[1].Field, "limit")
+	sut.Equal(errorMessages[1].Message, "please input less than equal to 100")
+}
+
+func (sut *ProductServiceTestSuite) Test03FindAllInvalidCursor() {
+	sut.T().Log("Test03FindAllInvalidCursor")
+	httpCode, response := sut.productService.FindAll(sut.ctx, models.FindAllProductRequest{Cursor: "!"})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "cursor")
+	sut.Equal(errorMessages[0].Message, "cursor is invalid")
+}
+
+func (sut *ProductServiceTestSuite) Test04FindAllProductRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test04FindAllProductRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, mock.Anything).Return([]models.Product{}, sut.errTimeout)
+	httpCode, response := sut.productService.FindAll(sut.ctx, models.FindAllProductRequest{})
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *ProductServiceTestSuite) Test05FindAllNextPage() {
+	sut.T().Log("Test05FindAllNextPage")
+	product2 := sut.product
+	product2.Id = pgtype.Int4{Valid: true, Int32: 2}
+	product3 := sut.product
+	product3.Id = pgtype.Int4{Valid: true, Int32: 3}
+	cursor := base64.RawURLEncoding.EncodeToString([]byte("4"))
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, models.ProductFilter{Status: models.ProductStatusDraft, BeforeId: 4, Limit: 3}).Return([]models.Product{product3, product2, sut.product}, nil)
+	httpCode, response := sut.productService.FindAll(sut.ctx, models.FindAllProductRequest{Status: models.ProductStatusDraft, Cursor: cursor, Limit: 2})
+	sut.Equal(httpCode, http.StatusOK)
+	findAllProductResponse, _ := response.Data.(models.FindAllProductResponse)
+	sut.Equal(len(findAllProductResponse.Products), 2)
+	sut.Equal(findAllProductResponse.Products[1].Id, int32(2))
+	sut.Equal(findAllProductResponse.NextCursor, base64.RawURLEncoding.EncodeToString([]byte("2")))
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test06FindActiveBySlugNotFound() {
+	sut.T().Log("Test06FindActiveBySlugNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindBySlug", sut.pool, sut.ctx, "coffee-beans", models.ProductStatusActive).Return(models.Product{}, pgx.ErrNoRows)
+	httpCode, response := sut.productService.FindActiveBySlug(sut.ctx, "coffee-beans")
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "product not found")
+}
+
+func (sut *ProductServiceTestSuite) Test07FindActiveBySlugSuccess() {
+	sut.T().Log("Test07FindActiveBySlugSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindBySlug", sut.pool, sut.ctx, "coffee-beans", models.ProductStatusActive).Return(sut.product, nil)
+	httpCode, response := sut.productService.FindActiveBySlug(sut.ctx, "coffee-beans")
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, models.ProductResponse{
+		Id:          1,
+		Name:        "Coffee Beans",
+		Slug:        "coffee-beans",
+		Description: "roasted arabica",
+		Price:       1299,
+		Currency:    "USD",
+		Status:      models.ProductStatusActive,
+		CreatedAt:   1695095017000,
+		UpdatedAt:   1695095017000,
+	})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test08FindByIdProductRepositoryFindByIdNotFound() {
+	sut.T().Log("Test08FindByIdProductRepositoryFindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(models.Product{}, pgx.ErrNoRows)
+	httpCode, response := sut.productService.FindById(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "product not found")
+}
+
+func (sut *ProductServiceTestSuite) Test09FindByIdArchivedSuccess() {
+	sut.T().Log("Test09FindByIdArchivedSuccess")
+	sut.product.Status = pgtype.Text{Valid: true, String: models.ProductStatusArchived}
+	sut.product.ArchivedAt = pgtype.Int8{Valid: true, Int64: 1695095018000}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.product, nil)
+	httpCode, response := sut.productService.FindById(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusOK)
+	productResponse, _ := response.Data.(models.ProductResponse)
+	sut.Equal(productResponse.Status, models.ProductStatusArchived)
+	sut.Equal(*productResponse.ArchivedAt, int64(1695095018000))
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test10CreateValidationError() {
+	sut.T().Log("Test10CreateValidationError")
+	sut.productRequest.Slug = "Coffee Beans"
+	sut.productRequest.Price = -1
+	sut.productRequest.Currency = "XYZ"
+	sut.productRequest.Status = models.ProductStatusArchived
+	httpCode, response := sut.productService.Create(sut.ctx, sut.productRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages, []helpers.ErrorMessage{
+		{Field: "slug", Message: "please use only lowercase letter and number separated by single -"},
+		{Field: "price", Message: "please input greater than equal to 0"},
+		{Field: "currency", Message: "please input an iso 4217 currency code"},
+		{Field: "status", Message: "please input one of DRAFT ACTIVE"},
+	})
+	sut.productRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ProductServiceTestSuite) Test11CreateProductRepositoryCreateUniqueViolation() {
+	sut.T().Log("Test11CreateProductRepositoryCreateUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "products_slug_key"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, sut.productRequest, mock.AnythingOfType("int64")).Return(int32(0), errUniqueViolation)
+	httpCode, response := sut.productService.Create(sut.ctx, sut.productRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "slug")
+	sut.Equal(errorMessages[0].Message, "slug already exists")
+}
+
+func (sut *ProductServiceTestSuite) Test12CreateSuccess() {
+	sut.T().Log("Test12CreateSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, sut.productRequest, mock.AnythingOfType("int64")).Return(int32(1), nil)
+	httpCode, response := sut.productService.Create(sut.ctx, sut.productRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	productResponse, _ := response.Data.(models.ProductResponse)
+	sut.Equal(productResponse.Id, int32(1))
+	sut.Equal(productResponse.Slug, "coffee-beans")
+	sut.Equal(productResponse.CreatedAt, productResponse.UpdatedAt)
+	sut.Nil(productResponse.ArchivedAt)
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test13UpdateProductRepositoryUpdateNotFound() {
+	sut.T().Log("Test13UpdateProductRepositoryUpdateNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Update", sut.pool, sut.ctx, int32(1), sut.productRequest, mock.AnythingOfType("int64")).Return(models.Product{}, pgx.ErrNoRows)
+	httpCode, response := sut.productService.Update(sut.ctx, 1, sut.productRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "product not found")
+}
+
+func (sut *ProductServiceTestSuite) Test14UpdateProductRepositoryUpdateUniqueViolation() {
+	sut.T().Log("Test14UpdateProductRepositoryUpdateUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "products_slug_key"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Update", sut.pool, sut.ctx, int32(1), sut.productRequest, mock.AnythingOfType("int64")).Return(models.Product{}, errUniqueViolation)
+	httpCode, response := sut.productService.Update(sut.ctx, 1, sut.productRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "slug")
+	sut.Equal(errorMessages[0].Message, "slug already exists")
+}
+
+func (sut *ProductServiceTestSuite) Test15UpdateSuccess() {
+	sut.T().Log("Test15UpdateSuccess")
+	sut.productRequest.Price = 1499
+	sut.product.Price = pgtype.Int8{Valid: true, Int64: 1499}
+	sut.product.UpdatedAt = pgtype.Int8{Valid: true, Int64: 1695095019000}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Update", sut.pool, sut.ctx, int32(1), sut.productRequest, mock.AnythingOfType("int64")).Return(sut.product, nil)
+	httpCode, response := sut.productService.Update(sut.ctx, 1, sut.productRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	productResponse, _ := response.Data.(models.ProductResponse)
+	sut.Equal(productResponse.Price, int64(1499))
+	sut.Equal(productResponse.CreatedAt, int64(1695095017000))
+	sut.Equal(productResponse.UpdatedAt, int64(1695095019000))
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProductServiceTestSuite) Test16ArchiveProductRepositoryArchiveInternalServerError() {
+	sut.T().Log("Test16ArchiveProductRepositoryArchiveInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Archive", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(0), sut.errInternalServer)
+	httpCode, response := sut.productService.Archive(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *ProductServiceTestSuite) Test17ArchiveNotFound() {
+	sut.T().Log("Test17ArchiveNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Archive", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(0), nil)
+	httpCode, response := sut.productService.Archive(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "product not found")
+}
+
+func (sut *ProductServiceTestSuite) Test18ArchiveSuccess() {
+	sut.T().Log("Test18ArchiveSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.productRepositoryMock.Mock.On("Archive", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(1), nil)
+	httpCode, response := sut.productService.Archive(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully archive product"})
+	sut.Equal(response.Errors, nil)
+}
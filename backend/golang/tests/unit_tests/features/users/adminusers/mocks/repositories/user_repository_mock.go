@@ -0,0 +1,33 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/adminusers/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context, userFilter models.UserFilter) (users []models.User, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userFilter)
+	return arguments.Get(0).([]models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) Disable(pool *pgxpool.Pool, ctx context.Context, id int32, disabledAt int64) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, disabledAt)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) Enable(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,282 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/adminusers/models"
+	"backend-golang/features/users/adminusers/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/adminusers/mocks/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminUserServiceTestSuite struct {
+	suite.Suite
+	ctx                          context.Context
+	postgresUtilMock             *mockutils.PostgresUtilMock
+	redisUtilMock                *mockutils.RedisUtilMock
+	validate                     *validator.Validate
+	userRepositoryMock           *mockrepositories.UserRepositoryMock
+	userPermissionRepositoryMock *mockrepositories.UserPermissionRepositoryMock
+	sessionRegistryHelperMock    *mockhelpers.SessionRegistryHelperMock
+	pool                         *pgxpool.Pool
+	client                       *redis.Client
+	errTimeout                   error
+	errInternalServer            error
+	adminId                      int32
+	userId                       int32
+	users                        []models.User
+	userPermissions              []models.UserPermission
+	adminUserService             services.AdminUserService
+}
+
+func TestAdminUserTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminUserServiceTestSuite))
+}
+
+func (sut *AdminUserServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.adminId = 1
+	sut.userId = 2
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, sut.adminId)
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *AdminUserServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.users = []models.User{
+		{
+			Id:              pgtype.Int4{Int32: 3, Valid: true},
+			Username:        pgtype.Text{String: "username3", Valid: true},
+			Email:           pgtype.Text{String: "email3@email.com", Valid: true},
+			CreatedAt:       pgtype.Int8{Int64: 1719496855216, Valid: true},
+			EmailVerifiedAt: pgtype.Int8{Int64: 1719496855216, Valid: true},
+		},
+		{
+			Id:         pgtype.Int4{Int32: 2, Valid: true},
+			Username:   pgtype.Text{String: "username2", Valid: true},
+			Email:      pgtype.Text{String: "email2@email.com", Valid: true},
+			CreatedAt:  pgtype.Int8{Int64: 1719496855000, Valid: true},
+			DisabledAt: pgtype.Int8{Int64: 1719496900000, Valid: true},
+		},
+	}
+	sut.userPermissions = []models.UserPermission{
+		{PermissionId: pgtype.Int4{Int32: 1, Valid: true}, Permission: pgtype.Text{String: "ADMINISTRATOR", Valid: true}},
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.userPermissionRepositoryMock = new(mockrepositories.UserPermissionRepositoryMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.adminUserService = services.NewAdminUserService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.userPermissionRepositoryMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *AdminUserServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *AdminUserServiceTestSuite) Test01FindAllValidationError() {
+	sut.T().Log("Test01FindAllValidationError")
+	findAllUserRequest := models.FindAllUserRequest{Limit: 101}
+	httpCode, response := sut.adminUserService.FindAll(sut.ctx, findAllUserRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "limit")
+	sut.Equal(errorMessages[0].Message, "please input less than equal to 100")
+}
+
+func (sut *AdminUserServiceTestSuite) Test02FindAllInvalidCursor() {
+	sut.T().Log("Test02FindAllInvalidCursor")
+	findAllUserRequest := models.FindAllUserRequest{Cursor: base64.RawURLEncoding.EncodeToString([]byte("abc"))}
+	httpCode, response := sut.adminUserService.FindAll(sut.ctx, findAllUserRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "cursor")
+	sut.Equal(errorMessages[0].Message, "cursor is invalid")
+}
+
+func (sut *AdminUserServiceTestSuite) Test03FindAllUserRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test03FindAllUserRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, mock.Anything).Return([]models.User{}, sut.errTimeout)
+	httpCode, response := sut.adminUserService.FindAll(sut.ctx, models.FindAllUserRequest{})
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *AdminUserServiceTestSuite) Test04FindAllDefaultLimitLastPage() {
+	sut.T().Log("Test04FindAllDefaultLimitLastPage")
+	userFilter := models.UserFilter{Email: "email", Limit: 21}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, userFilter).Return(sut.users, nil)
+	httpCode, response := sut.adminUserService.FindAll(sut.ctx, models.FindAllUserRequest{Email: "email"})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	findAllUserResponse, _ := response.Data.(models.FindAllUserResponse)
+	sut.Equal(len(findAllUserResponse.Users), 2)
+	sut.Equal(findAllUserResponse.NextCursor, "")
+	sut.Equal(findAllUserResponse.Users[0].Id, int32(3))
+	sut.Equal(findAllUserResponse.Users[0].EmailVerified, true)
+	sut.Equal(findAllUserResponse.Users[1].Disabled, true)
+	sut.Equal(findAllUserResponse.Users[1].DisabledAt, int64(1719496900000))
+}
+
+func (sut *AdminUserServiceTestSuite) Test05FindAllWithCursorHasNextPage() {
+	sut.T().Log("Test05FindAllWithCursorHasNextPage")
+	cursor := base64.RawURLEncoding.EncodeToString([]byte("4"))
+	userFilter := models.UserFilter{BeforeId: 4, Limit: 2}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, userFilter).Return(sut.users, nil)
+	httpCode, response := sut.adminUserService.FindAll(sut.ctx, models.FindAllUserRequest{Cursor: cursor, Limit: 1})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	findAllUserResponse, _ := response.Data.(models.FindAllUserResponse)
+	sut.Equal(len(findAllUserResponse.Users), 1)
+	sut.Equal(findAllUserResponse.Users[0].Id, int32(3))
+	sut.Equal(findAllUserResponse.NextCursor, base64.RawURLEncoding.EncodeToString([]byte("3")))
+}
+
+func (sut *AdminUserServiceTestSuite) Test06FindByIdNotFound() {
+	sut.T().Log("Test06FindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, sut.userId).Return(models.User{}, pgx.ErrNoRows)
+	httpCode, response := sut.adminUserService.FindById(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *AdminUserServiceTestSuite) Test07FindByIdUserPermissionRepositoryFindByUserIdInternalServerError() {
+	sut.T().Log("Test07FindByIdUserPermissionRepositoryFindByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, sut.userId).Return(sut.users[1], nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.userId).Return([]models.UserPermission{}, sut.errInternalServer)
+	httpCode, response := sut.adminUserService.FindById(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *AdminUserServiceTestSuite) Test08FindByIdSuccess() {
+	sut.T().Log("Test08FindByIdSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, sut.userId).Return(sut.users[1], nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.userId).Return(sut.userPermissions, nil)
+	httpCode, response := sut.adminUserService.FindById(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	userDetailResponse, _ := response.Data.(models.UserDetailResponse)
+	sut.Equal(userDetailResponse.Id, sut.userId)
+	sut.Equal(userDetailResponse.Username, "username2")
+	sut.Equal(len(userDetailResponse.Permissions), 1)
+	sut.Equal(userDetailResponse.Permissions[0].Permission, "ADMINISTRATOR")
+}
+
+func (sut *AdminUserServiceTestSuite) Test09DisableOwnAccount() {
+	sut.T().Log("Test09DisableOwnAccount")
+	httpCode, response := sut.adminUserService.Disable(sut.ctx, sut.adminId)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot disable your own account")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "Disable", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *AdminUserServiceTestSuite) Test10DisableNotFound() {
+	sut.T().Log("Test10DisableNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("Disable", sut.pool, sut.ctx, sut.userId, mock.Anything).Return(int64(0), nil)
+	httpCode, response := sut.adminUserService.Disable(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user not found")
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByUserId", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *AdminUserServiceTestSuite) Test11DisableSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test11DisableSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("Disable", sut.pool, sut.ctx, sut.userId, mock.Anything).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "").Return(sut.errInternalServer)
+	httpCode, response := sut.adminUserService.Disable(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *AdminUserServiceTestSuite) Test12DisableSuccess() {
+	sut.T().Log("Test12DisableSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("Disable", sut.pool, sut.ctx, sut.userId, mock.Anything).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "").Return(nil)
+	httpCode, response := sut.adminUserService.Disable(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully disable user")
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "")
+}
+
+func (sut *AdminUserServiceTestSuite) Test13EnableNotFound() {
+	sut.T().Log("Test13EnableNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("Enable", sut.pool, sut.ctx, sut.userId).Return(int64(0), nil)
+	httpCode, response := sut.adminUserService.Enable(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *AdminUserServiceTestSuite) Test14EnableSuccess() {
+	sut.T().Log("Test14EnableSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("Enable", sut.pool, sut.ctx, sut.userId).Return(int64(1), nil)
+	httpCode, response := sut.adminUserService.Enable(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully enable user")
+}
+
+func (sut *AdminUserServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *AdminUserServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *AdminUserServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
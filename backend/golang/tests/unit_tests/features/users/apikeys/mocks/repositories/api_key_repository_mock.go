@@ -0,0 +1,34 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/apikeys/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ApiKeyRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context) (apiKeys []models.ApiKey, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.ApiKey), arguments.Error(1)
+}
+
+func (repository *ApiKeyRepositoryMock) Create(tx pgx.Tx, ctx context.Context, apiKey models.ApiKey) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, apiKey)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *ApiKeyRepositoryMock) CreatePermissions(tx pgx.Tx, ctx context.Context, apiKeyId int32, permissionIds []int32) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, apiKeyId, permissionIds)
+	return arguments.Error(0)
+}
+
+func (repository *ApiKeyRepositoryMock) Revoke(pool *pgxpool.Pool, ctx context.Context, id int32, revokedAt int64) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, revokedAt)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,17 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserPermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserPermissionRepositoryMock) FindPermissionIdsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (idPermissions []int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
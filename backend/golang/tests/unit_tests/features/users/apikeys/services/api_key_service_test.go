@@ -0,0 +1,273 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/apikeys/models"
+	"backend-golang/features/users/apikeys/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/apikeys/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiKeyServiceTestSuite struct {
+	suite.Suite
+	ctx                          context.Context
+	postgresUtilMock             *mockutils.PostgresUtilMock
+	validate                     *validator.Validate
+	apiKeyRepositoryMock         *mockrepositories.ApiKeyRepositoryMock
+	userPermissionRepositoryMock *mockrepositories.UserPermissionRepositoryMock
+	permissionRepositoryMock     *mockrepositories.PermissionRepositoryMock
+	tokenHelperMock              *mockhelpers.TokenHelperMock
+	pool                         *pgxpool.Pool
+	tx                           pgx.Tx
+	errTimeout                   error
+	errInternalServer            error
+	administrator                models.Permission
+	apiKeyRequest                models.ApiKeyRequest
+	apiKeyService                services.ApiKeyService
+}
+
+func TestApiKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyServiceTestSuite))
+}
+
+func (sut *ApiKeyServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+	sut.administrator = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}}
+}
+
+func (sut *ApiKeyServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.apiKeyRequest = models.ApiKeyRequest{
+		Name:          "warehouse",
+		UserId:        2,
+		PermissionIds: []int32{3, 4},
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.apiKeyRepositoryMock = new(mockrepositories.ApiKeyRepositoryMock)
+	sut.userPermissionRepositoryMock = new(mockrepositories.UserPermissionRepositoryMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.tokenHelperMock = new(mockhelpers.TokenHelperMock)
+	sut.apiKeyService = services.NewApiKeyService(sut.postgresUtilMock, sut.validate, sut.apiKeyRepositoryMock, sut.userPermissionRepositoryMock, sut.permissionRepositoryMock, sut.tokenHelperMock)
+}
+
+func (sut *ApiKeyServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *ApiKeyServiceTestSuite) mockScopeHeld() {
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, int32(2)).Return([]int32{2, 3, 4}, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return("0123456789abcdef", nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+}
+
+func (sut *ApiKeyServiceTestSuite) Test01FindAllApiKeyRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test01FindAllApiKeyRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return([]models.ApiKey{}, sut.errTimeout)
+	httpCode, response := sut.apiKeyService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test02FindAllSuccess() {
+	sut.T().Log("Test02FindAllSuccess")
+	apiKeys := []models.ApiKey{
+		{
+			Id:            pgtype.Int4{Valid: true, Int32: 2},
+			UserId:        pgtype.Int4{Valid: true, Int32: 2},
+			Name:          pgtype.Text{Valid: true, String: "reporting"},
+			KeyPrefix:     pgtype.Text{Valid: true, String: "ek_01234567"},
+			PermissionIds: []int32{3},
+			CreatedBy:     pgtype.Int4{Valid: true, Int32: 1},
+			CreatedAt:     pgtype.Int8{Valid: true, Int64: 1719496855216},
+			ExpiresAt:     pgtype.Int8{Valid: true, Int64: 1719496955216},
+			RevokedAt:     pgtype.Int8{Valid: true, Int64: 1719496900000},
+		},
+		{
+			Id:            pgtype.Int4{Valid: true, Int32: 1},
+			UserId:        pgtype.Int4{Valid: true, Int32: 2},
+			Name:          pgtype.Text{Valid: true, String: "warehouse"},
+			KeyPrefix:     pgtype.Text{Valid: true, String: "ek_89abcdef"},
+			PermissionIds: []int32{3, 4},
+			CreatedBy:     pgtype.Int4{Valid: true, Int32: 1},
+			CreatedAt:     pgtype.Int8{Valid: true, Int64: 1719496855000},
+			LastUsedAt:    pgtype.Int8{Valid: true, Int64: 1719496856000},
+		},
+	}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return(apiKeys, nil)
+	httpCode, response := sut.apiKeyService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	apiKeyResponses, _ := response.Data.([]models.ApiKeyResponse)
+	sut.Equal(len(apiKeyResponses), 2)
+	sut.Equal(apiKeyResponses[0].KeyPrefix, "ek_01234567")
+	sut.Equal(*apiKeyResponses[0].ExpiresAt, int64(1719496955216))
+	sut.Equal(*apiKeyResponses[0].RevokedAt, int64(1719496900000))
+	sut.Nil(apiKeyResponses[0].LastUsedAt)
+	sut.Equal(apiKeyResponses[1].PermissionIds, []int32{3, 4})
+	sut.Nil(apiKeyResponses[1].ExpiresAt)
+	sut.Equal(*apiKeyResponses[1].LastUsedAt, int64(1719496856000))
+}
+
+func (sut *ApiKeyServiceTestSuite) Test03CreateValidationError() {
+	sut.T().Log("Test03CreateValidationError")
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, models.ApiKeyRequest{ExpiresAt: -1})
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "name")
+	sut.Equal(errorMessages[0].Message, "is required")
+	sut.Equal(errorMessages[1].Field, "userId")
+	sut.Equal(errorMessages[1].Message, "is required")
+	sut.Equal(errorMessages[2].Field, "permissionIds")
+	sut.Equal(errorMessages[2].Message, "is required")
+	sut.Equal(errorMessages[3].Field, "expiresAt")
+	sut.Equal(errorMessages[3].Message, "please input greater than equal to 0")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test04CreateExpiresAtInThePastBadRequest() {
+	sut.T().Log("Test04CreateExpiresAtInThePastBadRequest")
+	sut.apiKeyRequest.ExpiresAt = time.Now().Add(-time.Minute).UnixMilli()
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "expiresAt")
+	sut.Equal(errorMessages[0].Message, "please input a time in the future")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test05CreateAdministratorBadRequest() {
+	sut.T().Log("Test05CreateAdministratorBadRequest")
+	sut.apiKeyRequest.PermissionIds = []int32{1, 3}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "permissionIds")
+	sut.Equal(errorMessages[0].Message, "an api key cannot hold ADMINISTRATOR")
+	sut.userPermissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindPermissionIdsByUserId", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ApiKeyServiceTestSuite) Test06CreatePermissionNotHeldByUserBadRequest() {
+	sut.T().Log("Test06CreatePermissionNotHeldByUserBadRequest")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, int32(2)).Return([]int32{3}, nil)
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "permissionIds")
+	sut.Equal(errorMessages[0].Message, "the user does not hold permission 4")
+	sut.tokenHelperMock.Mock.AssertNotCalled(sut.T(), "Generate")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test07CreateApiKeyRepositoryCreateUserNotFoundBadRequest() {
+	sut.T().Log("Test07CreateApiKeyRepositoryCreateUserNotFoundBadRequest")
+	errForeignKeyViolation := &pgconn.PgError{Code: "23503"}
+	sut.mockScopeHeld()
+	sut.apiKeyRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(int32(0), errForeignKeyViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errForeignKeyViolation).Return(nil)
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "userId")
+	sut.Equal(errorMessages[0].Message, "user not found")
+	sut.apiKeyRepositoryMock.Mock.AssertNotCalled(sut.T(), "CreatePermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ApiKeyServiceTestSuite) Test08CreateApiKeyRepositoryCreatePermissionsInternalServerError() {
+	sut.T().Log("Test08CreateApiKeyRepositoryCreatePermissionsInternalServerError")
+	sut.mockScopeHeld()
+	sut.apiKeyRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(int32(1), nil)
+	sut.apiKeyRepositoryMock.Mock.On("CreatePermissions", sut.tx, sut.ctx, int32(1), []int32{3, 4}).Return(sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test09CreateSuccess() {
+	sut.T().Log("Test09CreateSuccess")
+	sut.apiKeyRequest.ExpiresAt = time.Now().Add(time.Hour).UnixMilli()
+	sut.mockScopeHeld()
+	sut.apiKeyRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(func(apiKey models.ApiKey) bool {
+		return apiKey.UserId.Int32 == 2 && apiKey.Name.String == "warehouse" && apiKey.KeyPrefix.String == "ek_01234567" &&
+			apiKey.KeyHash.String == helpers.ToTokenHash("ek_0123456789abcdef") && apiKey.CreatedBy.Int32 == 1 &&
+			apiKey.ExpiresAt.Valid && apiKey.ExpiresAt.Int64 == sut.apiKeyRequest.ExpiresAt
+	})).Return(int32(1), nil)
+	sut.apiKeyRepositoryMock.Mock.On("CreatePermissions", sut.tx, sut.ctx, int32(1), []int32{3, 4}).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.apiKeyService.Create(sut.ctx, sut.apiKeyRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	createApiKeyResponse, _ := response.Data.(models.CreateApiKeyResponse)
+	sut.Equal(createApiKeyResponse.Key, "ek_0123456789abcdef")
+	sut.Equal(createApiKeyResponse.ApiKey.Id, int32(1))
+	sut.Equal(createApiKeyResponse.ApiKey.KeyPrefix, "ek_01234567")
+	sut.Equal(createApiKeyResponse.ApiKey.PermissionIds, []int32{3, 4})
+	sut.Equal(*createApiKeyResponse.ApiKey.ExpiresAt, sut.apiKeyRequest.ExpiresAt)
+	sut.Nil(createApiKeyResponse.ApiKey.LastUsedAt)
+}
+
+func (sut *ApiKeyServiceTestSuite) Test10RevokeNotFound() {
+	sut.T().Log("Test10RevokeNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("Revoke", sut.pool, sut.ctx, int32(1), mock.Anything).Return(int64(0), nil)
+	httpCode, response := sut.apiKeyService.Revoke(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusNotFound)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "api key not found")
+}
+
+func (sut *ApiKeyServiceTestSuite) Test11RevokeSuccess() {
+	sut.T().Log("Test11RevokeSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.apiKeyRepositoryMock.Mock.On("Revoke", sut.pool, sut.ctx, int32(1), mock.Anything).Return(int64(1), nil)
+	httpCode, response := sut.apiKeyService.Revoke(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully revoke api key"})
+}
+
+func (sut *ApiKeyServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *ApiKeyServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *ApiKeyServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,18 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/authevents/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuthEventRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *AuthEventRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context, authEventFilter models.AuthEventFilter) (authEvents []models.AuthEvent, err error) {
+	arguments := repository.Mock.Called(pool, ctx, authEventFilter)
+	return arguments.Get(0).([]models.AuthEvent), arguments.Error(1)
+}
@@ -0,0 +1,171 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/authevents/models"
+	"backend-golang/features/users/authevents/services"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/authevents/mocks/repositories"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthEventServiceTestSuite struct {
+	suite.Suite
+	ctx                     context.Context
+	postgresUtilMock        *mockutils.PostgresUtilMock
+	validate                *validator.Validate
+	authEventRepositoryMock *mockrepositories.AuthEventRepositoryMock
+	pool                    *pgxpool.Pool
+	errTimeout              error
+	errInternalServer       error
+	authEvents              []models.AuthEvent
+	authEventService        services.AuthEventService
+}
+
+func TestAuthEventTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthEventServiceTestSuite))
+}
+
+func (sut *AuthEventServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *AuthEventServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.authEvents = []models.AuthEvent{
+		{
+			Id:        pgtype.Int4{Int32: 3, Valid: true},
+			EventType: pgtype.Text{String: helpers.LoginSucceededEvent, Valid: true},
+			UserId:    pgtype.Int4{Int32: 2, Valid: true},
+			Email:     pgtype.Text{String: "email2@email.com", Valid: true},
+			Ip:        pgtype.Text{String: "127.0.0.1", Valid: true},
+			UserAgent: pgtype.Text{String: "curl/8.5.0", Valid: true},
+			RequestId: pgtype.Text{String: "requestId3", Valid: true},
+			CreatedAt: pgtype.Int8{Int64: 1719496855216, Valid: true},
+		},
+		{
+			Id:        pgtype.Int4{Int32: 2, Valid: true},
+			EventType: pgtype.Text{String: helpers.LoginFailedEvent, Valid: true},
+			Email:     pgtype.Text{String: "unknown@email.com", Valid: true},
+			Ip:        pgtype.Text{String: "127.0.0.1", Valid: true},
+			UserAgent: pgtype.Text{String: "curl/8.5.0", Valid: true},
+			RequestId: pgtype.Text{String: "requestId2", Valid: true},
+			CreatedAt: pgtype.Int8{Int64: 1719496855000, Valid: true},
+		},
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.authEventRepositoryMock = new(mockrepositories.AuthEventRepositoryMock)
+	sut.authEventService = services.NewAuthEventService(sut.postgresUtilMock, sut.validate, sut.authEventRepositoryMock)
+}
+
+func (sut *AuthEventServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *AuthEventServiceTestSuite) Test01FindAllValidationError() {
+	sut.T().Log("Test01FindAllValidationError")
+	findAllAuthEventRequest := models.FindAllAuthEventRequest{EventType: "LOGOUT", Limit: 101}
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, findAllAuthEventRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "eventType")
+	sut.Equal(errorMessages[0].Message, "please input one of LOGIN_SUCCEEDED LOGIN_FAILED LOGIN_LOCKED LOGIN_DISABLED LOGIN_UNVERIFIED TWO_FACTOR_CHALLENGED TWO_FACTOR_FAILED")
+	sut.Equal(errorMessages[1].Field, "limit")
+	sut.Equal(errorMessages[1].Message, "please input less than equal to 100")
+}
+
+func (sut *AuthEventServiceTestSuite) Test02FindAllInvalidCursor() {
+	sut.T().Log("Test02FindAllInvalidCursor")
+	findAllAuthEventRequest := models.FindAllAuthEventRequest{Cursor: base64.RawURLEncoding.EncodeToString([]byte("-1"))}
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, findAllAuthEventRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "cursor")
+	sut.Equal(errorMessages[0].Message, "cursor is invalid")
+}
+
+func (sut *AuthEventServiceTestSuite) Test03FindAllAuthEventRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test03FindAllAuthEventRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.authEventRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, mock.Anything).Return([]models.AuthEvent{}, sut.errTimeout)
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, models.FindAllAuthEventRequest{})
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *AuthEventServiceTestSuite) Test04FindAllAuthEventRepositoryFindAllInternalServerError() {
+	sut.T().Log("Test04FindAllAuthEventRepositoryFindAllInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.authEventRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, mock.Anything).Return([]models.AuthEvent{}, sut.errInternalServer)
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, models.FindAllAuthEventRequest{})
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *AuthEventServiceTestSuite) Test05FindAllDefaultLimitLastPage() {
+	sut.T().Log("Test05FindAllDefaultLimitLastPage")
+	authEventFilter := models.AuthEventFilter{Ip: "127.0.0.1", CreatedFrom: 1719496855000, Limit: 21}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.authEventRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, authEventFilter).Return(sut.authEvents, nil)
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, models.FindAllAuthEventRequest{Ip: "127.0.0.1", CreatedFrom: 1719496855000})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	findAllAuthEventResponse, _ := response.Data.(models.FindAllAuthEventResponse)
+	sut.Equal(len(findAllAuthEventResponse.AuthEvents), 2)
+	sut.Equal(findAllAuthEventResponse.NextCursor, "")
+	sut.Equal(findAllAuthEventResponse.AuthEvents[0].EventType, helpers.LoginSucceededEvent)
+	sut.Equal(findAllAuthEventResponse.AuthEvents[0].UserId, int32(2))
+	sut.Equal(findAllAuthEventResponse.AuthEvents[1].UserId, int32(0))
+	sut.Equal(findAllAuthEventResponse.AuthEvents[1].Email, "unknown@email.com")
+}
+
+func (sut *AuthEventServiceTestSuite) Test06FindAllWithCursorHasNextPage() {
+	sut.T().Log("Test06FindAllWithCursorHasNextPage")
+	cursor := base64.RawURLEncoding.EncodeToString([]byte("4"))
+	authEventFilter := models.AuthEventFilter{EventType: helpers.LoginFailedEvent, UserId: 2, BeforeId: 4, Limit: 2}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.authEventRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx, authEventFilter).Return(sut.authEvents, nil)
+	httpCode, response := sut.authEventService.FindAll(sut.ctx, models.FindAllAuthEventRequest{EventType: helpers.LoginFailedEvent, UserId: 2, Cursor: cursor, Limit: 1})
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	findAllAuthEventResponse, _ := response.Data.(models.FindAllAuthEventResponse)
+	sut.Equal(len(findAllAuthEventResponse.AuthEvents), 1)
+	sut.Equal(findAllAuthEventResponse.AuthEvents[0].Id, int32(3))
+	sut.Equal(findAllAuthEventResponse.NextCursor, base64.RawURLEncoding.EncodeToString([]byte("3")))
+}
+
+func (sut *AuthEventServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *AuthEventServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *AuthEventServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
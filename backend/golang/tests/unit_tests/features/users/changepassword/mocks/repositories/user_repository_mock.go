@@ -0,0 +1,22 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRepositoryMock) FindPasswordByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (password string, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).(string), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) UpdatePassword(tx pgx.Tx, ctx context.Context, id int32, password string) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id, password)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,244 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/changepassword/models"
+	"backend-golang/features/users/changepassword/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/changepassword/mocks/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChangePasswordServiceTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	postgresUtilMock          *mockutils.PostgresUtilMock
+	redisUtilMock             *mockutils.RedisUtilMock
+	validate                  *validator.Validate
+	userRepositoryMock        *mockrepositories.UserRepositoryMock
+	passwordHasherMock        *mockhelpers.PasswordHasherMock
+	uuidHelperMock            *mockhelpers.UuidHelperMock
+	redisHelperMock           *mockhelpers.RedisHelperMock
+	sessionRegistryHelperMock *mockhelpers.SessionRegistryHelperMock
+	tx                        pgx.Tx
+	client                    *redis.Client
+	errTimeout                error
+	errInternalServer         error
+	changePasswordRequest     models.ChangePasswordRequest
+	password                  string
+	sessionId                 string
+	userAgent                 string
+	ip                        string
+	changePasswordService     services.ChangePasswordService
+}
+
+func TestChangePasswordTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangePasswordServiceTestSuite))
+}
+
+func (sut *ChangePasswordServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.ctx = context.WithValue(sut.ctx, middlewares.UsernameKey, "username")
+	sut.ctx = context.WithValue(sut.ctx, middlewares.EmailKey, "email@email.com")
+	sut.ctx = context.WithValue(sut.ctx, middlewares.PermissionKey, []int32{1})
+	sut.ctx = context.WithValue(sut.ctx, middlewares.SessionIdKey, "sessionId")
+	sut.validate = setups.SetValidator()
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *ChangePasswordServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.changePasswordRequest = models.ChangePasswordRequest{
+		Currentpassword: "password@A1",
+		Password:        "password@A2",
+		Confirmpassword: "password@A2",
+	}
+	sut.password = "$2a$10$MvEM5qcQFk39jC/3fYzJzOIy7M/xQiGv/PAkkoarCMgsx/rO0UaPG"
+	sut.sessionId = "newSessionId"
+	sut.userAgent = "userAgent"
+	sut.ip = "127.0.0.1"
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.uuidHelperMock = new(mockhelpers.UuidHelperMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.changePasswordService = services.NewChangePasswordService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.passwordHasherMock, sut.uuidHelperMock, sut.redisHelperMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *ChangePasswordServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *ChangePasswordServiceTestSuite) matchSession(session string) bool {
+	var sessionValue helpers.Session
+	err := json.Unmarshal([]byte(session), &sessionValue)
+	return err == nil && sessionValue.Id == 1 && sessionValue.Username == "username" && sessionValue.Email == "email@email.com" && len(sessionValue.IdPermissions) == 1 && sessionValue.CreatedAt > 0
+}
+
+func (sut *ChangePasswordServiceTestSuite) mockUpdatePassword(ctx context.Context) {
+	sut.postgresUtilMock.Mock.On("BeginTx", ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordByIdForUpdate", sut.tx, ctx, int32(1)).Return(sut.password, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.password, sut.changePasswordRequest.Currentpassword).Return(nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.changePasswordRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("UpdatePassword", sut.tx, ctx, int32(1), "password").Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test01ChangePasswordValidationError() {
+	sut.T().Log("Test01ChangePasswordValidationError")
+	sut.changePasswordRequest.Confirmpassword = "password@A3"
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "confirmpassword")
+	sut.Equal(errorMessages[0].Message, "please input the same value as password")
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test02ChangePasswordSamePasswordValidationError() {
+	sut.T().Log("Test02ChangePasswordSamePasswordValidationError")
+	sut.changePasswordRequest.Password = sut.changePasswordRequest.Currentpassword
+	sut.changePasswordRequest.Confirmpassword = sut.changePasswordRequest.Currentpassword
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "password")
+	sut.Equal(errorMessages[0].Message, "please input a different value from currentpassword")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "BeginTx", sut.ctx, pgx.TxOptions{})
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test03ChangePasswordUserRepositoryFindPasswordByIdForUpdateTimeoutError() {
+	sut.T().Log("Test03ChangePasswordUserRepositoryFindPasswordByIdForUpdateTimeoutError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return("", sut.errTimeout)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errTimeout).Return(nil)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test04ChangePasswordWrongCurrentPasswordBadRequest() {
+	sut.T().Log("Test04ChangePasswordWrongCurrentPasswordBadRequest")
+	sut.changePasswordRequest.Currentpassword = "password@A0"
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.password, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.password, sut.changePasswordRequest.Currentpassword).Return(helpers.ErrPasswordMismatch)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "currentpassword")
+	sut.Equal(errorMessages[0].Message, "wrong current password")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdatePassword", sut.tx, sut.ctx, int32(1), mock.Anything)
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test05ChangePasswordCommitOrRollbackInternalServerError() {
+	sut.T().Log("Test05ChangePasswordCommitOrRollbackInternalServerError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.password, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.password, sut.changePasswordRequest.Currentpassword).Return(nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.changePasswordRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("UpdatePassword", sut.tx, sut.ctx, int32(1), "password").Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(sut.errInternalServer)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+	sut.uuidHelperMock.Mock.AssertNotCalled(sut.T(), "String")
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test06ChangePasswordSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test06ChangePasswordSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.mockUpdatePassword(sut.ctx)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, int32(1), mock.AnythingOfType("helpers.SessionInfo"), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(1), sut.sessionId).Return(sut.errInternalServer)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test07ChangePasswordSuccess() {
+	sut.T().Log("Test07ChangePasswordSuccess")
+	sut.mockUpdatePassword(sut.ctx)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, int32(1), mock.MatchedBy(func(sessionInfo helpers.SessionInfo) bool {
+		return sessionInfo.SessionId == sut.sessionId && sessionInfo.UserAgent == sut.userAgent && sessionInfo.Ip == sut.ip
+	}), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(1), sut.sessionId).Return(nil)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(sut.ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully change password"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ChangePasswordServiceTestSuite) Test08ChangePasswordWithAccessTokenSuccess() {
+	sut.T().Log("Test08ChangePasswordWithAccessTokenSuccess")
+	ctx := context.WithValue(sut.ctx, middlewares.SessionIdKey, "tokenFamily:familyId")
+	ctx = context.WithValue(ctx, middlewares.TokenIdKey, "accessTokenId")
+	sut.mockUpdatePassword(ctx)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, ctx, int32(1), "tokenFamily:familyId").Return(nil)
+	sessionId, httpCode, response := sut.changePasswordService.ChangePassword(ctx, sut.changePasswordRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully change password"})
+	sut.Equal(response.Errors, nil)
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Set", sut.client, ctx, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ChangePasswordServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *ChangePasswordServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *ChangePasswordServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/features/users/csrf/models"
+	"backend-golang/features/users/csrf/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type CsrfServiceTestSuite struct {
+	suite.Suite
+	ctx                 context.Context
+	redisUtilMock       *mockutils.RedisUtilMock
+	tokenHelperMock     *mockhelpers.TokenHelperMock
+	csrfTokenHelperMock *mockhelpers.CsrfTokenHelperMock
+	client              *redis.Client
+	errTimeout          error
+	errInternalServer   error
+	sessionId           string
+	csrfService         services.CsrfService
+}
+
+func TestCsrfTestSuite(t *testing.T) {
+	suite.Run(t, new(CsrfServiceTestSuite))
+}
+
+func (sut *CsrfServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.sessionId = "sessionId"
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.SessionIdKey, sut.sessionId)
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *CsrfServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.tokenHelperMock = new(mockhelpers.TokenHelperMock)
+	sut.csrfTokenHelperMock = new(mockhelpers.CsrfTokenHelperMock)
+	sut.csrfService = services.NewCsrfService(sut.redisUtilMock, sut.tokenHelperMock, sut.csrfTokenHelperMock)
+}
+
+func (sut *CsrfServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *CsrfServiceTestSuite) Test1IssueTokenHelperGenerateInternalServerError() {
+	sut.T().Log("Test1IssueTokenHelperGenerateInternalServerError")
+	sut.tokenHelperMock.Mock.On("Generate").Return("", sut.errInternalServer)
+	httpCode, response := sut.csrfService.Issue(sut.ctx)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *CsrfServiceTestSuite) Test2IssueCsrfTokenHelperFindOrCreateTimeoutError() {
+	sut.T().Log("Test2IssueCsrfTokenHelperFindOrCreateTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.tokenHelperMock.Mock.On("Generate").Return("csrfToken", nil)
+	sut.csrfTokenHelperMock.Mock.On("FindOrCreate", sut.client, sut.ctx, sut.sessionId, "csrfToken", 24*time.Hour).Return("", sut.errTimeout)
+	httpCode, response := sut.csrfService.Issue(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *CsrfServiceTestSuite) Test3IssueNewTokenSuccess() {
+	sut.T().Log("Test3IssueNewTokenSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.tokenHelperMock.Mock.On("Generate").Return("csrfToken", nil)
+	sut.csrfTokenHelperMock.Mock.On("FindOrCreate", sut.client, sut.ctx, sut.sessionId, "csrfToken", 24*time.Hour).Return("csrfToken", nil)
+	httpCode, response := sut.csrfService.Issue(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.CsrfTokenResponse{CsrfToken: "csrfToken"})
+}
+
+func (sut *CsrfServiceTestSuite) Test4IssueExistingTokenSuccess() {
+	sut.T().Log("Test4IssueExistingTokenSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.tokenHelperMock.Mock.On("Generate").Return("newCsrfToken", nil)
+	sut.csrfTokenHelperMock.Mock.On("FindOrCreate", sut.client, sut.ctx, sut.sessionId, "newCsrfToken", 24*time.Hour).Return("csrfToken", nil)
+	httpCode, response := sut.csrfService.Issue(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.CsrfTokenResponse{CsrfToken: "csrfToken"})
+}
+
+func (sut *CsrfServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *CsrfServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *CsrfServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
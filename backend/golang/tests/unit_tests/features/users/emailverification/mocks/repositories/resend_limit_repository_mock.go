@@ -0,0 +1,18 @@
+package mockrepositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type ResendLimitRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ResendLimitRepositoryMock) Acquire(client *redis.Client, ctx context.Context, email string, interval time.Duration) (retryAfter time.Duration, err error) {
+	arguments := repository.Mock.Called(client, ctx, email, interval)
+	return arguments.Get(0).(time.Duration), arguments.Error(1)
+}
@@ -0,0 +1,229 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/emailverification/models"
+	"backend-golang/features/users/emailverification/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/emailverification/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailVerificationServiceTestSuite struct {
+	suite.Suite
+	ctx                             context.Context
+	postgresUtilMock                *mockutils.PostgresUtilMock
+	redisUtilMock                   *mockutils.RedisUtilMock
+	validate                        *validator.Validate
+	userRepositoryMock              *mockrepositories.UserRepositoryMock
+	resendLimitRepositoryMock       *mockrepositories.ResendLimitRepositoryMock
+	emailVerificationHelperMock     *mockhelpers.EmailVerificationHelperMock
+	pool                            *pgxpool.Pool
+	client                          *redis.Client
+	errTimeout                      error
+	errInternalServer               error
+	confirmEmailVerificationRequest models.ConfirmEmailVerificationRequest
+	resendEmailVerificationRequest  models.ResendEmailVerificationRequest
+	emailVerificationToken          helpers.EmailVerificationToken
+	user                            models.User
+	emailVerificationService        services.EmailVerificationService
+}
+
+func TestEmailVerificationTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationServiceTestSuite))
+}
+
+func (sut *EmailVerificationServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *EmailVerificationServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.confirmEmailVerificationRequest = models.ConfirmEmailVerificationRequest{
+		Token: "token",
+	}
+	sut.resendEmailVerificationRequest = models.ResendEmailVerificationRequest{
+		Email: "email@email.com",
+	}
+	sut.emailVerificationToken = helpers.EmailVerificationToken{
+		UserId: 1,
+		Email:  "email@email.com",
+	}
+	sut.user = models.User{
+		Id:       pgtype.Int4{Valid: true, Int32: 1},
+		Username: pgtype.Text{Valid: true, String: "username"},
+		Email:    pgtype.Text{Valid: true, String: "email@email.com"},
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.resendLimitRepositoryMock = new(mockrepositories.ResendLimitRepositoryMock)
+	sut.emailVerificationHelperMock = new(mockhelpers.EmailVerificationHelperMock)
+	sut.emailVerificationService = services.NewEmailVerificationService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.resendLimitRepositoryMock, sut.emailVerificationHelperMock)
+}
+
+func (sut *EmailVerificationServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test01ConfirmValidationError() {
+	sut.T().Log("Test01ConfirmValidationError")
+	sut.confirmEmailVerificationRequest.Token = ""
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test02ConfirmEmailVerificationHelperFindAndDeleteTimeoutError() {
+	sut.T().Log("Test02ConfirmEmailVerificationHelperFindAndDeleteTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.confirmEmailVerificationRequest.Token).Return(helpers.EmailVerificationToken{}, sut.errTimeout)
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test03ConfirmEmailVerificationHelperFindAndDeleteBadRequestInvalidToken() {
+	sut.T().Log("Test03ConfirmEmailVerificationHelperFindAndDeleteBadRequestInvalidToken")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.confirmEmailVerificationRequest.Token).Return(helpers.EmailVerificationToken{}, redis.Nil)
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "token is invalid or expired")
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test04ConfirmUserRepositoryUpdateEmailVerifiedAtInternalServerError() {
+	sut.T().Log("Test04ConfirmUserRepositoryUpdateEmailVerifiedAtInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.confirmEmailVerificationRequest.Token).Return(sut.emailVerificationToken, nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdateEmailVerifiedAt", sut.pool, sut.ctx, sut.emailVerificationToken.UserId, sut.emailVerificationToken.Email, mock.AnythingOfType("int64")).Return(int64(0), sut.errInternalServer)
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test05ConfirmUserRepositoryUpdateEmailVerifiedAtEmailChangedBadRequest() {
+	sut.T().Log("Test05ConfirmUserRepositoryUpdateEmailVerifiedAtEmailChangedBadRequest")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.confirmEmailVerificationRequest.Token).Return(sut.emailVerificationToken, nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdateEmailVerifiedAt", sut.pool, sut.ctx, sut.emailVerificationToken.UserId, sut.emailVerificationToken.Email, mock.AnythingOfType("int64")).Return(int64(0), nil)
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "token is invalid or expired")
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test06ConfirmSuccess() {
+	sut.T().Log("Test06ConfirmSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.confirmEmailVerificationRequest.Token).Return(sut.emailVerificationToken, nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdateEmailVerifiedAt", sut.pool, sut.ctx, sut.emailVerificationToken.UserId, sut.emailVerificationToken.Email, mock.AnythingOfType("int64")).Return(int64(1), nil)
+	httpCode, response := sut.emailVerificationService.Confirm(sut.ctx, sut.confirmEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully verify email"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test07ResendResendLimitRepositoryAcquireTooManyRequests() {
+	sut.T().Log("Test07ResendResendLimitRepositoryAcquireTooManyRequests")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.resendLimitRepositoryMock.Mock.On("Acquire", sut.client, sut.ctx, sut.resendEmailVerificationRequest.Email, time.Minute).Return(40*time.Second, nil)
+	retryAfter, httpCode, response := sut.emailVerificationService.Resend(sut.ctx, sut.resendEmailVerificationRequest)
+	sut.Equal(retryAfter, 40)
+	sut.Equal(httpCode, http.StatusTooManyRequests)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "please wait before asking for another verification email")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByEmail", sut.pool, sut.ctx, sut.resendEmailVerificationRequest.Email)
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test08ResendUserRepositoryFindByEmailNotFoundSameResponse() {
+	sut.T().Log("Test08ResendUserRepositoryFindByEmailNotFoundSameResponse")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.resendLimitRepositoryMock.Mock.On("Acquire", sut.client, sut.ctx, sut.resendEmailVerificationRequest.Email, time.Minute).Return(time.Duration(0), nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.resendEmailVerificationRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	retryAfter, httpCode, response := sut.emailVerificationService.Resend(sut.ctx, sut.resendEmailVerificationRequest)
+	sut.Equal(retryAfter, 0)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered and not verified yet, a verification link has been sent"})
+	sut.emailVerificationHelperMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test09ResendAlreadyVerifiedSameResponse() {
+	sut.T().Log("Test09ResendAlreadyVerifiedSameResponse")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.resendLimitRepositoryMock.Mock.On("Acquire", sut.client, sut.ctx, sut.resendEmailVerificationRequest.Email, time.Minute).Return(time.Duration(0), nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.user.EmailVerifiedAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.resendEmailVerificationRequest.Email).Return(sut.user, nil)
+	_, httpCode, response := sut.emailVerificationService.Resend(sut.ctx, sut.resendEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered and not verified yet, a verification link has been sent"})
+	sut.emailVerificationHelperMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *EmailVerificationServiceTestSuite) Test10ResendSuccess() {
+	sut.T().Log("Test10ResendSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.resendLimitRepositoryMock.Mock.On("Acquire", sut.client, sut.ctx, sut.resendEmailVerificationRequest.Email, time.Minute).Return(time.Duration(0), nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.resendEmailVerificationRequest.Email).Return(sut.user, nil)
+	sut.emailVerificationHelperMock.Mock.On("Send", sut.client, sut.ctx, sut.user.Id.Int32, sut.user.Username.String, sut.user.Email.String).Return(nil)
+	_, httpCode, response := sut.emailVerificationService.Resend(sut.ctx, sut.resendEmailVerificationRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered and not verified yet, a verification link has been sent"})
+	sut.emailVerificationHelperMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *EmailVerificationServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *EmailVerificationServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *EmailVerificationServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/login/controllers"
+	"backend-golang/features/users/login/models"
+	mockservices "backend-golang/tests/unit_tests/features/users/login/mocks/services"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginControllerTestSuite struct {
+	suite.Suite
+	loginServiceMock *mockservices.LoginServiceMock
+	loginController  controllers.LoginController
+	loginRequest     models.LoginRequest
+	e                *echo.Echo
+}
+
+func TestLoginControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginControllerTestSuite))
+}
+
+func (sut *LoginControllerTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	os.Setenv("ECOMMERCEV2_COOKIE_SECURE", "false")
+	sut.loginRequest = models.LoginRequest{Email: "email@email.com", Password: "password@A1"}
+}
+
+func (sut *LoginControllerTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.loginServiceMock = new(mockservices.LoginServiceMock)
+	sut.loginController = controllers.NewLoginController(sut.loginServiceMock)
+	sut.e = echo.New()
+	sut.e.POST("/api/v1/users/login", sut.loginController.Login)
+}
+
+func (sut *LoginControllerTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *LoginControllerTestSuite) serveLogin() (response *http.Response) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/login", strings.NewReader(`{"email":"email@email.com","password":"password@A1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	sut.e.ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func (sut *LoginControllerTestSuite) Test1LoginWrongPasswordSetsNoCookie() {
+	sut.T().Log("Test1LoginWrongPasswordSetsNoCookie")
+	httpCode, response := helpers.ToResponseError(errors.New("wrong email or password"), "requestId", http.StatusBadRequest, "wrong email or password")
+	sut.loginServiceMock.Mock.On("Login", mock.Anything, sut.loginRequest, mock.Anything, mock.Anything).Return("", 0, httpCode, response)
+	result := sut.serveLogin()
+	sut.Equal(result.StatusCode, http.StatusBadRequest)
+	sut.Equal(result.Header.Get(echo.HeaderSetCookie), "")
+}
+
+func (sut *LoginControllerTestSuite) Test2LoginTooManyAttemptsSetsNoCookie() {
+	sut.T().Log("Test2LoginTooManyAttemptsSetsNoCookie")
+	httpCode, response := helpers.ToResponseError(errors.New("login locked"), "requestId", http.StatusTooManyRequests, "too many failed login attempts, please try again later")
+	sut.loginServiceMock.Mock.On("Login", mock.Anything, sut.loginRequest, mock.Anything, mock.Anything).Return("", 60, httpCode, response)
+	result := sut.serveLogin()
+	sut.Equal(result.StatusCode, http.StatusTooManyRequests)
+	sut.Equal(result.Header.Get("Retry-After"), "60")
+	sut.Equal(result.Header.Get(echo.HeaderSetCookie), "")
+}
+
+func (sut *LoginControllerTestSuite) Test3LoginTwoFactorRequiredSetsNoCookie() {
+	sut.T().Log("Test3LoginTwoFactorRequiredSetsNoCookie")
+	sut.loginServiceMock.Mock.On("Login", mock.Anything, sut.loginRequest, mock.Anything, mock.Anything).Return("", 0, http.StatusOK, helpers.Response{Data: helpers.ResponseMessage{Message: "two-factor authentication required"}, Errors: nil})
+	result := sut.serveLogin()
+	sut.Equal(result.StatusCode, http.StatusOK)
+	sut.Equal(result.Header.Get(echo.HeaderSetCookie), "")
+}
+
+func (sut *LoginControllerTestSuite) Test4LoginSuccessSetsSessionCookie() {
+	sut.T().Log("Test4LoginSuccessSetsSessionCookie")
+	sut.loginServiceMock.Mock.On("Login", mock.Anything, sut.loginRequest, mock.Anything, mock.Anything).Return("sessionId", 0, http.StatusOK, helpers.Response{Data: helpers.ResponseMessage{Message: "successfully login"}, Errors: nil})
+	result := sut.serveLogin()
+	sut.Equal(result.StatusCode, http.StatusOK)
+	cookies := result.Cookies()
+	sut.Equal(len(cookies), 1)
+	sut.Equal(cookies[0].Name, helpers.SessionCookieName)
+	sut.Equal(cookies[0].Value, "sessionId")
+}
+
+func (sut *LoginControllerTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *LoginControllerTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *LoginControllerTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
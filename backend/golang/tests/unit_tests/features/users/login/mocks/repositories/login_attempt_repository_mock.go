@@ -0,0 +1,33 @@
+package mockrepositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type LoginAttemptRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *LoginAttemptRepositoryMock) FindLockTtl(client *redis.Client, ctx context.Context, key string) (ttl time.Duration, err error) {
+	arguments := repository.Mock.Called(client, ctx, key)
+	return arguments.Get(0).(time.Duration), arguments.Error(1)
+}
+
+func (repository *LoginAttemptRepositoryMock) IncrementFailure(client *redis.Client, ctx context.Context, key string, window time.Duration) (failures int64, err error) {
+	arguments := repository.Mock.Called(client, ctx, key, window)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *LoginAttemptRepositoryMock) Lock(client *redis.Client, ctx context.Context, key string, duration time.Duration) (err error) {
+	arguments := repository.Mock.Called(client, ctx, key, duration)
+	return arguments.Error(0)
+}
+
+func (repository *LoginAttemptRepositoryMock) Reset(client *redis.Client, ctx context.Context, keys ...string) (err error) {
+	arguments := repository.Mock.Called(client, ctx, keys)
+	return arguments.Error(0)
+}
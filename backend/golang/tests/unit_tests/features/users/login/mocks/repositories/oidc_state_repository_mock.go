@@ -0,0 +1,24 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/login/models"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type OidcStateRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *OidcStateRepositoryMock) Create(client *redis.Client, ctx context.Context, stateHash string, oidcState models.OidcState, expiration time.Duration) (err error) {
+	arguments := repository.Mock.Called(client, ctx, stateHash, oidcState, expiration)
+	return arguments.Error(0)
+}
+
+func (repository *OidcStateRepositoryMock) FindAndDelete(client *redis.Client, ctx context.Context, stateHash string) (oidcState models.OidcState, err error) {
+	arguments := repository.Mock.Called(client, ctx, stateHash)
+	return arguments.Get(0).(models.OidcState), arguments.Error(1)
+}
@@ -0,0 +1,33 @@
+package mockrepositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type TwoFactorChallengeRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *TwoFactorChallengeRepositoryMock) Create(client *redis.Client, ctx context.Context, challengeHash string, userId int32, expiration time.Duration) (err error) {
+	arguments := repository.Mock.Called(client, ctx, challengeHash, userId, expiration)
+	return arguments.Error(0)
+}
+
+func (repository *TwoFactorChallengeRepositoryMock) FindUserId(client *redis.Client, ctx context.Context, challengeHash string) (userId int32, err error) {
+	arguments := repository.Mock.Called(client, ctx, challengeHash)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *TwoFactorChallengeRepositoryMock) IncrementAttempt(client *redis.Client, ctx context.Context, challengeHash string, expiration time.Duration) (attempts int64, err error) {
+	arguments := repository.Mock.Called(client, ctx, challengeHash, expiration)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *TwoFactorChallengeRepositoryMock) Delete(client *redis.Client, ctx context.Context, challengeHash string) (deleted bool, err error) {
+	arguments := repository.Mock.Called(client, ctx, challengeHash)
+	return arguments.Bool(0), arguments.Error(1)
+}
@@ -0,0 +1,24 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/login/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserIdentityRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserIdentityRepositoryMock) FindUserId(pool *pgxpool.Pool, ctx context.Context, provider string, subject string) (userId int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, provider, subject)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *UserIdentityRepositoryMock) Create(tx pgx.Tx, ctx context.Context, userIdentity models.UserIdentity) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, userIdentity)
+	return arguments.Error(0)
+}
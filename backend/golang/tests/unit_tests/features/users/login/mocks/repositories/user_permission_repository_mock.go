@@ -1,7 +1,6 @@
 package mockrepositories
 
 import (
-	"backend-golang/features/users/login/models"
 	"context"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,7 +11,7 @@ type UserPermissionRepositoryMock struct {
 	Mock mock.Mock
 }
 
-func (repository *UserPermissionRepositoryMock) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error) {
+func (repository *UserPermissionRepositoryMock) FindPermissionIdsByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (idPermissions []int32, err error) {
 	arguments := repository.Mock.Called(pool, ctx, userId)
-	return arguments.Get(0).([]models.UserPermission), arguments.Error(1)
+	return arguments.Get(0).([]int32), arguments.Error(1)
 }
@@ -4,6 +4,7 @@ import (
 	"backend-golang/features/users/login/models"
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,3 +17,18 @@ func (repository *UserRepositoryMock) FindByEmail(pool *pgxpool.Pool, ctx contex
 	arguments := repository.Mock.Called(pool, ctx, email)
 	return arguments.Get(0).(models.User), arguments.Error(1)
 }
+
+func (repository *UserRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) RehashPassword(pool *pgxpool.Pool, ctx context.Context, id int32, currentPassword string, password string) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, currentPassword, password)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, user)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
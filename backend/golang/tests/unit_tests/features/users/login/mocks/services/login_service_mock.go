@@ -0,0 +1,43 @@
+package mockservices
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/features/users/login/models"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoginServiceMock struct {
+	Mock mock.Mock
+}
+
+func (service *LoginServiceMock) Login(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (sessionId string, retryAfter int, httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, loginRequest, userAgent, ip)
+	return arguments.String(0), arguments.Int(1), arguments.Int(2), arguments.Get(3).(helpers.Response)
+}
+
+func (service *LoginServiceMock) VerifyTwoFactor(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, verifyTwoFactorRequest, userAgent, ip)
+	return arguments.String(0), arguments.Int(1), arguments.Get(2).(helpers.Response)
+}
+
+func (service *LoginServiceMock) LoginWithToken(ctx context.Context, loginRequest models.LoginRequest, userAgent string, ip string) (retryAfter int, httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, loginRequest, userAgent, ip)
+	return arguments.Int(0), arguments.Int(1), arguments.Get(2).(helpers.Response)
+}
+
+func (service *LoginServiceMock) VerifyTwoFactorWithToken(ctx context.Context, verifyTwoFactorRequest models.VerifyTwoFactorRequest, userAgent string, ip string) (httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, verifyTwoFactorRequest, userAgent, ip)
+	return arguments.Int(0), arguments.Get(1).(helpers.Response)
+}
+
+func (service *LoginServiceMock) AuthorizeOidc(ctx context.Context, provider string) (state string, httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, provider)
+	return arguments.String(0), arguments.Int(1), arguments.Get(2).(helpers.Response)
+}
+
+func (service *LoginServiceMock) LoginWithOidc(ctx context.Context, provider string, oidcLoginRequest models.OidcLoginRequest, stateCookie string, userAgent string, ip string) (sessionId string, httpCode int, response helpers.Response) {
+	arguments := service.Mock.Called(ctx, provider, oidcLoginRequest, stateCookie, userAgent, ip)
+	return arguments.String(0), arguments.Int(1), arguments.Get(2).(helpers.Response)
+}
@@ -3,45 +3,80 @@ package services_test
 import (
 	"backend-golang/commons/helpers"
 	"backend-golang/commons/middlewares"
+	commonmodels "backend-golang/commons/models"
 	"backend-golang/commons/setups"
 	"backend-golang/features/users/login/models"
 	"backend-golang/features/users/login/services"
 	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockcommonrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
 	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
 	mockrepositories "backend-golang/tests/unit_tests/features/users/login/mocks/repositories"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"os"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type LoginServiceTestSuite struct {
 	suite.Suite
-	ctx                          context.Context
-	loginRequest                 models.LoginRequest
-	postgresUtilMock             *mockutils.PostgresUtilMock
-	redisUtilMock                *mockutils.RedisUtilMock
-	validate                     *validator.Validate
-	userRepositoryMock           *mockrepositories.UserRepositoryMock
-	userPermissionRepositoryMock *mockrepositories.UserPermissionRepositoryMock
-	uuidHelperMock               *mockhelpers.UuidHelperMock
-	redisHelperMock              *mockhelpers.RedisHelperMock
-	client                       *redis.Client
-	pool                         *pgxpool.Pool
-	errTimeout                   error
-	errInternalServer            error
-	user                         models.User
-	sessionId                    string
-	loginService                 services.LoginService
+	ctx                              context.Context
+	loginRequest                     models.LoginRequest
+	postgresUtilMock                 *mockutils.PostgresUtilMock
+	redisUtilMock                    *mockutils.RedisUtilMock
+	validate                         *validator.Validate
+	userRepositoryMock               *mockrepositories.UserRepositoryMock
+	userPermissionRepositoryMock     *mockrepositories.UserPermissionRepositoryMock
+	loginAttemptRepositoryMock       *mockrepositories.LoginAttemptRepositoryMock
+	twoFactorChallengeRepositoryMock *mockrepositories.TwoFactorChallengeRepositoryMock
+	twoFactorRepositoryMock          *mockcommonrepositories.TwoFactorRepositoryMock
+	uuidHelperMock                   *mockhelpers.UuidHelperMock
+	redisHelperMock                  *mockhelpers.RedisHelperMock
+	sessionRegistryHelperMock        *mockhelpers.SessionRegistryHelperMock
+	twoFactorHelperMock              *mockhelpers.TwoFactorHelperMock
+	jwtHelperMock                    *mockhelpers.JwtHelperMock
+	tokenFamilyHelperMock            *mockhelpers.TokenFamilyHelperMock
+	passwordHasherMock               *mockhelpers.PasswordHasherMock
+	authEventHelperMock              *mockhelpers.AuthEventHelperMock
+	oidcStateRepositoryMock          *mockrepositories.OidcStateRepositoryMock
+	userIdentityRepositoryMock       *mockrepositories.UserIdentityRepositoryMock
+	tokenHelperMock                  *mockhelpers.TokenHelperMock
+	oidcHelperMock                   *mockhelpers.OidcHelperMock
+	client                           *redis.Client
+	pool                             *pgxpool.Pool
+	tx                               pgx.Tx
+	errTimeout                       error
+	errInternalServer                error
+	user                             models.User
+	sessionId                        string
+	userAgent                        string
+	ip                               string
+	emailFailureKey                  string
+	ipFailureKey                     string
+	emailLockKey                     string
+	ipLockKey                        string
+	verifyTwoFactorRequest           models.VerifyTwoFactorRequest
+	challengeHash                    string
+	oidcProvider                     helpers.OidcProvider
+	oidcLoginRequest                 models.OidcLoginRequest
+	oidcState                        models.OidcState
+	oidcClaims                       helpers.OidcClaims
+	stateHash                        string
+	loginService                     services.LoginService
 }
 
 func TestLoginTestSuite(t *testing.T) {
@@ -56,6 +91,33 @@ func (sut *LoginServiceTestSuite) SetupSuite() {
 	sut.errTimeout = context.Canceled
 	sut.errInternalServer = errors.New("internal server error")
 	sut.sessionId = "sessionId"
+	sut.userAgent = "Mozilla/5.0"
+	sut.ip = "127.0.0.1"
+	sut.emailFailureKey = "loginFailures:email:email@email.com"
+	sut.ipFailureKey = "loginFailures:ip:127.0.0.1"
+	sut.emailLockKey = "loginLock:email:email@email.com"
+	sut.ipLockKey = "loginLock:ip:127.0.0.1"
+	sut.challengeHash = helpers.ToTokenHash("challengeId")
+	sut.tx = &pgxpool.Tx{}
+	sut.oidcProvider = helpers.OidcProvider{
+		Name:             "stub",
+		Issuer:           "https://stub.example.com",
+		ClientId:         "clientId",
+		ClientSecret:     "clientSecret",
+		AuthorizationUrl: "https://stub.example.com/authorize",
+		TokenUrl:         "https://stub.example.com/token",
+		JwksUrl:          "https://stub.example.com/jwks",
+		RedirectUrl:      "https://shop.example.com/oidc/stub",
+	}
+	os.Setenv("ECOMMERCEV2_OIDC_PROVIDERS", "stub")
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_ISSUER", sut.oidcProvider.Issuer)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_CLIENT_ID", sut.oidcProvider.ClientId)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_CLIENT_SECRET", sut.oidcProvider.ClientSecret)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_AUTHORIZATION_URL", sut.oidcProvider.AuthorizationUrl)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_TOKEN_URL", sut.oidcProvider.TokenUrl)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_JWKS_URL", sut.oidcProvider.JwksUrl)
+	os.Setenv("ECOMMERCEV2_OIDC_STUB_REDIRECT_URL", sut.oidcProvider.RedirectUrl)
+	sut.stateHash = helpers.ToTokenHash("state")
 }
 
 func (sut *LoginServiceTestSuite) SetupTest() {
@@ -71,6 +133,10 @@ func (sut *LoginServiceTestSuite) SetupTest() {
 		Password:  pgtype.Text{Valid: true, String: "$2a$10$MvEM5qcQFk39jC/3fYzJzOIy7M/xQiGv/PAkkoarCMgsx/rO0UaPG"},
 		CreatedAt: pgtype.Int8{Valid: true, Int64: 1719496855216},
 	}
+	sut.verifyTwoFactorRequest = models.VerifyTwoFactorRequest{
+		ChallengeId: "challengeId",
+		Code:        "123456",
+	}
 	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
 	sut.redisUtilMock = new(mockutils.RedisUtilMock)
 	sut.validate = validator.New()
@@ -79,9 +145,100 @@ func (sut *LoginServiceTestSuite) SetupTest() {
 	setups.TelephoneValidator(sut.validate)
 	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
 	sut.userPermissionRepositoryMock = new(mockrepositories.UserPermissionRepositoryMock)
+	sut.loginAttemptRepositoryMock = new(mockrepositories.LoginAttemptRepositoryMock)
+	sut.twoFactorChallengeRepositoryMock = new(mockrepositories.TwoFactorChallengeRepositoryMock)
+	sut.twoFactorRepositoryMock = new(mockcommonrepositories.TwoFactorRepositoryMock)
 	sut.uuidHelperMock = new(mockhelpers.UuidHelperMock)
 	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
-	sut.loginService = services.NewLoginService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.userPermissionRepositoryMock, sut.uuidHelperMock, sut.redisHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.twoFactorHelperMock = new(mockhelpers.TwoFactorHelperMock)
+	sut.jwtHelperMock = new(mockhelpers.JwtHelperMock)
+	sut.tokenFamilyHelperMock = new(mockhelpers.TokenFamilyHelperMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.authEventHelperMock = new(mockhelpers.AuthEventHelperMock)
+	sut.authEventHelperMock.Mock.On("Record", sut.pool, sut.ctx, mock.Anything).Return()
+	sut.oidcStateRepositoryMock = new(mockrepositories.OidcStateRepositoryMock)
+	sut.userIdentityRepositoryMock = new(mockrepositories.UserIdentityRepositoryMock)
+	sut.tokenHelperMock = new(mockhelpers.TokenHelperMock)
+	sut.oidcHelperMock = new(mockhelpers.OidcHelperMock)
+	sut.oidcLoginRequest = models.OidcLoginRequest{
+		Code:  "code",
+		State: "state",
+	}
+	sut.oidcState = models.OidcState{
+		Provider:     "stub",
+		Nonce:        "nonce",
+		CodeVerifier: "codeVerifier",
+	}
+	sut.oidcClaims = helpers.OidcClaims{
+		Nonce:         "nonce",
+		Email:         "Email@email.com",
+		EmailVerified: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   sut.oidcProvider.Issuer,
+			Subject:  "subject",
+			Audience: jwt.ClaimStrings{sut.oidcProvider.ClientId},
+		},
+	}
+	sut.loginService = services.NewLoginService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.userPermissionRepositoryMock, sut.loginAttemptRepositoryMock, sut.twoFactorChallengeRepositoryMock, sut.oidcStateRepositoryMock, sut.userIdentityRepositoryMock, sut.twoFactorRepositoryMock, sut.uuidHelperMock, sut.redisHelperMock, sut.sessionRegistryHelperMock, sut.twoFactorHelperMock, sut.jwtHelperMock, sut.tokenFamilyHelperMock, sut.passwordHasherMock, sut.authEventHelperMock, sut.tokenHelperMock, sut.oidcHelperMock)
+}
+
+func (sut *LoginServiceTestSuite) mockLoginAttemptNotLocked() {
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.loginAttemptRepositoryMock.Mock.On("FindLockTtl", sut.client, sut.ctx, sut.emailLockKey).Return(time.Duration(-2), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("FindLockTtl", sut.client, sut.ctx, sut.ipLockKey).Return(time.Duration(-2), nil)
+}
+
+func (sut *LoginServiceTestSuite) mockPasswordMatches() {
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.loginRequest.Password).Return(nil)
+	sut.passwordHasherMock.Mock.On("NeedsRehash", sut.user.Password.String).Return(false, nil)
+}
+
+func (sut *LoginServiceTestSuite) matchSession(value interface{}) bool {
+	var session helpers.Session
+	err := json.Unmarshal([]byte(value.(string)), &session)
+	return err == nil && session.Id == 1 && session.Username == "username" && session.Email == "email@email.com" && session.IdPermissions == nil && session.CreatedAt > 0 && !session.TwoFactor
+}
+
+func (sut *LoginServiceTestSuite) matchTwoFactorSession(value interface{}) bool {
+	var session helpers.Session
+	err := json.Unmarshal([]byte(value.(string)), &session)
+	return err == nil && session.Id == 1 && session.Username == "username" && session.Email == "email@email.com" && session.CreatedAt > 0 && session.TwoFactor
+}
+
+func (sut *LoginServiceTestSuite) enableTotp() {
+	sut.user.TotpSecret = pgtype.Text{Valid: true, String: "c2VhbGVkIHRvdHAgc2VjcmV0"}
+	sut.user.TotpEnabledAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+}
+
+// mockTotpCodeAccepted lets the code of the request through as an unused totp code
+func (sut *LoginServiceTestSuite) mockTotpCodeAccepted() {
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.user.TotpSecret.String).Return("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", sut.verifyTwoFactorRequest.Code, mock.Anything).Return(int64(57305000), true)
+	sut.twoFactorRepositoryMock.Mock.On("UpdateTotpLastUsedStep", sut.pool, sut.ctx, int32(1), int64(57305000)).Return(int64(1), nil)
+}
+
+func (sut *LoginServiceTestSuite) matchAuthEvent(eventType string, userId int32, email string) func(commonmodels.AuthEvent) bool {
+	return func(authEvent commonmodels.AuthEvent) bool {
+		return authEvent.EventType == eventType && authEvent.UserId == userId && authEvent.Email == email && authEvent.Ip == sut.ip && authEvent.UserAgent == sut.userAgent && authEvent.RequestId == sut.ctx.Value(middlewares.RequestIdKey).(string) && authEvent.CreatedAt > 0
+	}
+}
+
+func (sut *LoginServiceTestSuite) matchSessionInfo(sessionInfo helpers.SessionInfo) bool {
+	return sessionInfo.SessionId == sut.sessionId && sessionInfo.UserAgent == sut.userAgent && sessionInfo.Ip == sut.ip && sessionInfo.CreatedAt > 0
+}
+
+func (sut *LoginServiceTestSuite) mockOidcStateFound() {
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.oidcStateRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.stateHash).Return(sut.oidcState, nil)
+	sut.oidcHelperMock.Mock.On("ExchangeCode", sut.ctx, sut.oidcProvider, "code", "codeVerifier").Return("idToken", nil)
+}
+
+func (sut *LoginServiceTestSuite) mockOidcSession() {
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
 }
 
 func (sut *LoginServiceTestSuite) BeforeTest(suiteName, testName string) {
@@ -91,7 +248,7 @@ func (sut *LoginServiceTestSuite) BeforeTest(suiteName, testName string) {
 func (sut *LoginServiceTestSuite) Test01LoginRedisRepositoryDelWithSessionIdTimeoutError() {
 	sut.T().Log("Test01LoginRedisRepositoryDelWithSessionIdTimeoutError")
 	sut.loginRequest = models.LoginRequest{}
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
@@ -104,9 +261,10 @@ func (sut *LoginServiceTestSuite) Test01LoginRedisRepositoryDelWithSessionIdTime
 
 func (sut *LoginServiceTestSuite) Test02LoginUserRepositoryFindByEmailTimeoutError() {
 	sut.T().Log("Test02LoginUserRepositoryFindByEmailTimeoutError")
+	sut.mockLoginAttemptNotLocked()
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(models.User{}, sut.errTimeout)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusRequestTimeout)
 	sut.Equal(response.Data, nil)
@@ -117,9 +275,10 @@ func (sut *LoginServiceTestSuite) Test02LoginUserRepositoryFindByEmailTimeoutErr
 
 func (sut *LoginServiceTestSuite) Test03LoginUserRepositoryFindByEmailInternalServerError() {
 	sut.T().Log("Test03LoginUserRepositoryFindByEmailInternalServerError")
+	sut.mockLoginAttemptNotLocked()
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(models.User{}, sut.errInternalServer)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusInternalServerError)
 	sut.Equal(response.Data, nil)
@@ -130,37 +289,51 @@ func (sut *LoginServiceTestSuite) Test03LoginUserRepositoryFindByEmailInternalSe
 
 func (sut *LoginServiceTestSuite) Test04LoginUserRepositoryFindByEmailBadRequestWrongEmailPassword() {
 	sut.T().Log("Test04LoginUserRepositoryFindByEmailBadRequestWrongEmailPassword")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.emailFailureKey, time.Hour).Return(int64(1), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.ipFailureKey, time.Hour).Return(int64(1), nil)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(models.User{}, pgx.ErrNoRows)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.passwordHasherMock.Mock.On("DummyHash").Return("dummyHash", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "dummyHash", sut.loginRequest.Password).Return(helpers.ErrPasswordMismatch)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
 	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
 	sut.Equal(errorMessages[0].Field, "message")
 	sut.Equal(errorMessages[0].Message, "wrong email or password")
+	sut.passwordHasherMock.Mock.AssertCalled(sut.T(), "Compare", "dummyHash", sut.loginRequest.Password)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginFailedEvent, 0, sut.loginRequest.Email)))
 }
 
-func (sut *LoginServiceTestSuite) Test05LoginBcryptCompareHashAndPasswordBadRequestWrongEmailPassword() {
-	sut.T().Log("Test05LoginBcryptCompareHashAndPasswordBadRequestWrongEmailPassword")
+func (sut *LoginServiceTestSuite) Test05LoginPasswordHasherCompareBadRequestWrongEmailPassword() {
+	sut.T().Log("Test05LoginPasswordHasherCompareBadRequestWrongEmailPassword")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.emailFailureKey, time.Hour).Return(int64(1), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.ipFailureKey, time.Hour).Return(int64(1), nil)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
-	sut.user.Password.String = ""
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.loginRequest.Password).Return(helpers.ErrPasswordMismatch)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusBadRequest)
 	sut.Equal(response.Data, nil)
 	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
 	sut.Equal(errorMessages[0].Field, "message")
 	sut.Equal(errorMessages[0].Message, "wrong email or password")
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginFailedEvent, sut.user.Id.Int32, sut.loginRequest.Email)))
 }
 
-func (sut *LoginServiceTestSuite) Test06LoginUserPermissionRepositoryFindByUserIdTimeoutError() {
-	sut.T().Log("Test06LoginUserPermissionRepositoryFindByUserIdTimeoutError")
+func (sut *LoginServiceTestSuite) Test06LoginUserPermissionRepositoryFindPermissionIdsByUserIdTimeoutError() {
+	sut.T().Log("Test06LoginUserPermissionRepositoryFindPermissionIdsByUserIdTimeoutError")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]models.UserPermission{}, sut.errTimeout)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32{}, sut.errTimeout)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusRequestTimeout)
 	sut.Equal(response.Data, nil)
@@ -169,12 +342,15 @@ func (sut *LoginServiceTestSuite) Test06LoginUserPermissionRepositoryFindByUserI
 	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
 }
 
-func (sut *LoginServiceTestSuite) Test07LoginUserPermissionRepositoryFindByUserIdInternalServerError() {
-	sut.T().Log("Test07LoginUserPermissionRepositoryFindByUserIdInternalServerError")
+func (sut *LoginServiceTestSuite) Test07LoginUserPermissionRepositoryFindPermissionIdsByUserIdInternalServerError() {
+	sut.T().Log("Test07LoginUserPermissionRepositoryFindPermissionIdsByUserIdInternalServerError")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]models.UserPermission{}, sut.errInternalServer)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32{}, sut.errInternalServer)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, "")
 	sut.Equal(httpCode, http.StatusInternalServerError)
 	sut.Equal(response.Data, nil)
@@ -183,16 +359,39 @@ func (sut *LoginServiceTestSuite) Test07LoginUserPermissionRepositoryFindByUserI
 	sut.Equal(errorMessages[0].Message, "internal server error")
 }
 
-func (sut *LoginServiceTestSuite) Test08LoginRedisRepositorySetTimeoutError() {
-	sut.T().Log("Test08LoginRedisRepositorySetTimeoutError")
+func (sut *LoginServiceTestSuite) Test08LoginSessionRegistryHelperRegisterInternalServerError() {
+	sut.T().Log("Test08LoginSessionRegistryHelperRegisterInternalServerError")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
 	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]models.UserPermission{}, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
 	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
-	session := `{"email":"email@email.com","id":1,"idPermissions":null,"username":"username"}`
-	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, session, time.Duration(0)).Return("", sut.errTimeout)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(sut.errInternalServer)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *LoginServiceTestSuite) Test09LoginRedisRepositorySetTimeoutError() {
+	sut.T().Log("Test09LoginRedisRepositorySetTimeoutError")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", sut.errTimeout)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, sut.sessionId)
 	sut.Equal(httpCode, http.StatusRequestTimeout)
 	sut.Equal(response.Data, nil)
@@ -201,16 +400,19 @@ func (sut *LoginServiceTestSuite) Test08LoginRedisRepositorySetTimeoutError() {
 	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
 }
 
-func (sut *LoginServiceTestSuite) Test09LoginRedisRepositorySetInternalServerError() {
-	sut.T().Log("Test09LoginRedisRepositorySetInternalServerError")
+func (sut *LoginServiceTestSuite) Test10LoginRedisRepositorySetInternalServerError() {
+	sut.T().Log("Test10LoginRedisRepositorySetInternalServerError")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
 	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]models.UserPermission{}, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
 	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
-	session := `{"email":"email@email.com","id":1,"idPermissions":null,"username":"username"}`
-	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, session, time.Duration(0)).Return("", sut.errInternalServer)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", sut.errInternalServer)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
 	sut.Equal(sessionId, sut.sessionId)
 	sut.Equal(httpCode, http.StatusInternalServerError)
 	sut.Equal(response.Data, nil)
@@ -219,21 +421,722 @@ func (sut *LoginServiceTestSuite) Test09LoginRedisRepositorySetInternalServerErr
 	sut.Equal(errorMessages[0].Message, "internal server error")
 }
 
-func (sut *LoginServiceTestSuite) Test10LoginSuccess() {
-	sut.T().Log("Test13LoginSuccess")
+func (sut *LoginServiceTestSuite) Test11LoginSuccess() {
+	sut.T().Log("Test11LoginSuccess")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully login")
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginSucceededEvent, sut.user.Id.Int32, sut.user.Email.String)))
+}
+
+func (sut *LoginServiceTestSuite) Test12LoginLoginAttemptRepositoryFindLockTtlTooManyRequests() {
+	sut.T().Log("Test12LoginLoginAttemptRepositoryFindLockTtlTooManyRequests")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.loginAttemptRepositoryMock.Mock.On("FindLockTtl", sut.client, sut.ctx, sut.emailLockKey).Return(90*time.Second+time.Millisecond, nil)
+	sut.loginAttemptRepositoryMock.Mock.On("FindLockTtl", sut.client, sut.ctx, sut.ipLockKey).Return(time.Duration(-2), nil)
+	sessionId, retryAfter, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(retryAfter, 91)
+	sut.Equal(httpCode, http.StatusTooManyRequests)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "too many failed login attempts, please try again later")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginLockedEvent, 0, sut.loginRequest.Email)))
+}
+
+func (sut *LoginServiceTestSuite) Test13LoginLoginAttemptRepositoryIncrementFailureInternalServerError() {
+	sut.T().Log("Test13LoginLoginAttemptRepositoryIncrementFailureInternalServerError")
+	sut.mockLoginAttemptNotLocked()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	sut.passwordHasherMock.Mock.On("DummyHash").Return("dummyHash", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "dummyHash", sut.loginRequest.Password).Return(helpers.ErrPasswordMismatch)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.emailFailureKey, time.Hour).Return(int64(0), sut.errInternalServer)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *LoginServiceTestSuite) Test14LoginTooManyFailuresLocksWithBackoff() {
+	sut.T().Log("Test14LoginTooManyFailuresLocksWithBackoff")
+	sut.mockLoginAttemptNotLocked()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.loginRequest.Password).Return(helpers.ErrPasswordMismatch)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.emailFailureKey, time.Hour).Return(int64(7), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, sut.ipFailureKey, time.Hour).Return(int64(7), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("Lock", sut.client, sut.ctx, sut.emailLockKey, 2*time.Minute).Return(nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "wrong email or password")
+	sut.loginAttemptRepositoryMock.Mock.AssertCalled(sut.T(), "Lock", sut.client, sut.ctx, sut.emailLockKey, 2*time.Minute)
+	sut.loginAttemptRepositoryMock.Mock.AssertNotCalled(sut.T(), "Lock", sut.client, sut.ctx, sut.ipLockKey, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test15LoginEmailVerificationPolicyRequiredForbidden() {
+	sut.T().Log("Test15LoginEmailVerificationPolicyRequiredForbidden")
+	sut.T().Setenv("ECOMMERCEV2_EMAIL_VERIFICATION_POLICY", "required")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "please verify your email before logging in, check your inbox for the verification link")
+	sut.userPermissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32)
+}
+
+func (sut *LoginServiceTestSuite) Test16LoginEmailVerificationPolicyGraceOverForbidden() {
+	sut.T().Log("Test16LoginEmailVerificationPolicyGraceOverForbidden")
+	sut.T().Setenv("ECOMMERCEV2_EMAIL_VERIFICATION_POLICY", "grace")
+	sut.T().Setenv("ECOMMERCEV2_EMAIL_VERIFICATION_GRACE_PERIOD", "60")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.user.CreatedAt.Int64 = time.Now().Add(-61 * time.Minute).UnixMilli()
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusForbidden)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "please verify your email before logging in, check your inbox for the verification link")
+}
+
+func (sut *LoginServiceTestSuite) Test17LoginEmailVerificationPolicyRequiredVerifiedSuccess() {
+	sut.T().Log("Test17LoginEmailVerificationPolicyRequiredVerifiedSuccess")
+	sut.T().Setenv("ECOMMERCEV2_EMAIL_VERIFICATION_POLICY", "required")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.user.EmailVerifiedAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *LoginServiceTestSuite) Test18LoginTwoFactorEnabledChallenge() {
+	sut.T().Log("Test18LoginTwoFactorEnabledChallenge")
+	sut.enableTotp()
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.uuidHelperMock.Mock.On("String").Return("challengeId")
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Create", sut.client, sut.ctx, sut.challengeHash, sut.user.Id.Int32, 5*time.Minute).Return(nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.TwoFactorChallengeResponse{Message: "two-factor authentication required", ChallengeId: "challengeId"})
+	sut.userPermissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32)
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Set", sut.client, sut.ctx, mock.Anything, mock.Anything, mock.Anything)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.TwoFactorChallengedEvent, sut.user.Id.Int32, sut.user.Email.String)))
+}
+
+func (sut *LoginServiceTestSuite) Test19VerifyTwoFactorValidationError() {
+	sut.T().Log("Test19VerifyTwoFactorValidationError")
+	sut.verifyTwoFactorRequest = models.VerifyTwoFactorRequest{}
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "challengeId")
+	sut.Equal(errorMessages[0].Message, "is required")
+	sut.Equal(errorMessages[1].Field, "code")
+	sut.Equal(errorMessages[1].Message, "is required")
+}
+
+func (sut *LoginServiceTestSuite) Test20VerifyTwoFactorChallengeExpiredBadRequest() {
+	sut.T().Log("Test20VerifyTwoFactorChallengeExpiredBadRequest")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(0), redis.Nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "challengeId")
+	sut.Equal(errorMessages[0].Message, "challenge is invalid or expired")
+}
+
+func (sut *LoginServiceTestSuite) Test21VerifyTwoFactorTooManyAttemptsBadRequest() {
+	sut.T().Log("Test21VerifyTwoFactorTooManyAttemptsBadRequest")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(6), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Delete", sut.client, sut.ctx, sut.challengeHash).Return(true, nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "challengeId")
+	sut.Equal(errorMessages[0].Message, "challenge is invalid or expired")
+	sut.twoFactorChallengeRepositoryMock.Mock.AssertCalled(sut.T(), "Delete", sut.client, sut.ctx, sut.challengeHash)
+	sut.twoFactorHelperMock.Mock.AssertNotCalled(sut.T(), "DecryptSecret", mock.Anything)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.TwoFactorFailedEvent, 1, "")))
+}
+
+func (sut *LoginServiceTestSuite) Test22VerifyTwoFactorWrongCodeBadRequest() {
+	sut.T().Log("Test22VerifyTwoFactorWrongCodeBadRequest")
+	sut.enableTotp()
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.user.TotpSecret.String).Return("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", sut.verifyTwoFactorRequest.Code, mock.Anything).Return(int64(0), false)
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", sut.verifyTwoFactorRequest.Code).Return("recoveryCodeHash")
+	sut.twoFactorRepositoryMock.Mock.On("UseRecoveryCode", sut.pool, sut.ctx, int32(1), "recoveryCodeHash", mock.AnythingOfType("int64")).Return(int64(0), nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "code is invalid")
+	sut.twoFactorChallengeRepositoryMock.Mock.AssertNotCalled(sut.T(), "Delete", sut.client, sut.ctx, sut.challengeHash)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.TwoFactorFailedEvent, sut.user.Id.Int32, sut.user.Email.String)))
+}
+
+func (sut *LoginServiceTestSuite) Test23VerifyTwoFactorChallengeAlreadyUsedBadRequest() {
+	sut.T().Log("Test23VerifyTwoFactorChallengeAlreadyUsedBadRequest")
+	sut.enableTotp()
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.mockTotpCodeAccepted()
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Delete", sut.client, sut.ctx, sut.challengeHash).Return(false, nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "challengeId")
+	sut.Equal(errorMessages[0].Message, "challenge is invalid or expired")
+	sut.uuidHelperMock.Mock.AssertNotCalled(sut.T(), "String")
+}
+
+func (sut *LoginServiceTestSuite) Test24VerifyTwoFactorSuccess() {
+	sut.T().Log("Test24VerifyTwoFactorSuccess")
+	sut.enableTotp()
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.mockTotpCodeAccepted()
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Delete", sut.client, sut.ctx, sut.challengeHash).Return(true, nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchTwoFactorSession), 30*time.Minute).Return("", nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully login"})
+}
+
+func (sut *LoginServiceTestSuite) Test25LoginWithTokenSuccess() {
+	sut.T().Log("Test25LoginWithTokenSuccess")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32{3}, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("familyId").Once()
+	sut.uuidHelperMock.Mock.On("String").Return("refreshTokenId").Once()
+	sut.uuidHelperMock.Mock.On("String").Return("accessTokenId").Once()
+	sut.jwtHelperMock.Mock.On("SignAccessToken", mock.MatchedBy(func(claims helpers.AccessTokenClaims) bool {
+		return claims.ID == "accessTokenId" && claims.FamilyId == "familyId" && claims.Subject == "1" && claims.Email == "email@email.com" && len(claims.IdPermissions) == 1 && !claims.TwoFactor && claims.ExpiresAt.Sub(claims.IssuedAt.Time) == 15*time.Minute
+	})).Return("accessToken", nil)
+	sut.jwtHelperMock.Mock.On("SignRefreshToken", mock.MatchedBy(func(claims helpers.RefreshTokenClaims) bool {
+		return claims.ID == "refreshTokenId" && claims.FamilyId == "familyId" && claims.Subject == "1"
+	})).Return("refreshToken", nil)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(func(sessionInfo helpers.SessionInfo) bool {
+		return sessionInfo.SessionId == "tokenFamily:familyId" && sessionInfo.UserAgent == sut.userAgent && sessionInfo.Ip == sut.ip
+	}), 24*time.Hour).Return(nil)
+	sut.tokenFamilyHelperMock.Mock.On("Create", sut.client, sut.ctx, "familyId", "refreshTokenId", mock.MatchedBy(func(session helpers.Session) bool {
+		return session.Id == 1 && session.Username == "username" && len(session.IdPermissions) == 1 && session.CreatedAt > 0
+	}), 24*time.Hour).Return(nil)
+	retryAfter, httpCode, response := sut.loginService.LoginWithToken(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(retryAfter, 0)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, helpers.TokenResponse{AccessToken: "accessToken", RefreshToken: "refreshToken", TokenType: "Bearer", ExpiresIn: 15 * 60})
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Set", sut.client, sut.ctx, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test26LoginWithTokenTwoFactorEnabledChallenge() {
+	sut.T().Log("Test26LoginWithTokenTwoFactorEnabledChallenge")
+	sut.enableTotp()
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.uuidHelperMock.Mock.On("String").Return("challengeId")
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Create", sut.client, sut.ctx, sut.challengeHash, sut.user.Id.Int32, 5*time.Minute).Return(nil)
+	_, httpCode, response := sut.loginService.LoginWithToken(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, models.TwoFactorChallengeResponse{Message: "two-factor authentication required", ChallengeId: "challengeId"})
+	sut.jwtHelperMock.Mock.AssertNotCalled(sut.T(), "SignAccessToken", mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test27VerifyTwoFactorWithTokenTokenFamilyHelperCreateInternalServerError() {
+	sut.T().Log("Test27VerifyTwoFactorWithTokenTokenFamilyHelperCreateInternalServerError")
+	sut.enableTotp()
 	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
 	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.mockTotpCodeAccepted()
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Delete", sut.client, sut.ctx, sut.challengeHash).Return(true, nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return("familyId")
+	sut.jwtHelperMock.Mock.On("SignAccessToken", mock.MatchedBy(func(claims helpers.AccessTokenClaims) bool {
+		return claims.TwoFactor
+	})).Return("accessToken", nil)
+	sut.jwtHelperMock.Mock.On("SignRefreshToken", mock.Anything).Return("refreshToken", nil)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.AnythingOfType("helpers.SessionInfo"), 24*time.Hour).Return(nil)
+	sut.tokenFamilyHelperMock.Mock.On("Create", sut.client, sut.ctx, "familyId", "familyId", mock.AnythingOfType("helpers.Session"), 24*time.Hour).Return(sut.errInternalServer)
+	httpCode, response := sut.loginService.VerifyTwoFactorWithToken(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *LoginServiceTestSuite) Test28LoginNeedsRehashSuccess() {
+	sut.T().Log("Test28LoginNeedsRehashSuccess")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.loginRequest.Password).Return(nil)
+	sut.passwordHasherMock.Mock.On("NeedsRehash", sut.user.Password.String).Return(true, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.loginRequest.Password).Return("newPassword", nil)
+	sut.userRepositoryMock.Mock.On("RehashPassword", sut.pool, sut.ctx, sut.user.Id.Int32, sut.user.Password.String, "newPassword").Return(int64(1), nil)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.userRepositoryMock.Mock.AssertCalled(sut.T(), "RehashPassword", sut.pool, sut.ctx, sut.user.Id.Int32, sut.user.Password.String, "newPassword")
+}
+
+func (sut *LoginServiceTestSuite) Test29LoginRehashPasswordInternalServerErrorStillSuccess() {
+	sut.T().Log("Test29LoginRehashPasswordInternalServerErrorStillSuccess")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
 	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
-	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]models.UserPermission{}, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.loginRequest.Password).Return(nil)
+	sut.passwordHasherMock.Mock.On("NeedsRehash", sut.user.Password.String).Return(true, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.loginRequest.Password).Return("newPassword", nil)
+	sut.userRepositoryMock.Mock.On("RehashPassword", sut.pool, sut.ctx, sut.user.Id.Int32, sut.user.Password.String, "newPassword").Return(int64(0), sut.errInternalServer)
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
 	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
-	session := `{"email":"email@email.com","id":1,"idPermissions":null,"username":"username"}`
-	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, session, time.Duration(0)).Return("", nil)
-	sessionId, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully login")
+}
+
+func (sut *LoginServiceTestSuite) Test30LoginUnknownEmailComparesDummyHash() {
+	sut.T().Log("Test30LoginUnknownEmailComparesDummyHash")
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, mock.Anything, time.Hour).Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	sut.passwordHasherMock.Mock.On("DummyHash").Return("dummyHash", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "dummyHash", sut.loginRequest.Password).Return(helpers.ErrPasswordMismatch).Once()
+	_, _, httpCode, _ := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.passwordHasherMock.Mock.AssertExpectations(sut.T())
+	sut.passwordHasherMock.Mock.AssertNumberOfCalls(sut.T(), "Compare", 1)
+}
+
+func (sut *LoginServiceTestSuite) Test31LoginDisabledForbidden() {
+	sut.T().Log("Test31LoginDisabledForbidden")
+	sut.user.DisabledAt = pgtype.Int8{Valid: true, Int64: 1719496900000}
+	sut.mockLoginAttemptNotLocked()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "account is disabled")
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginDisabledEvent, sut.user.Id.Int32, sut.loginRequest.Email)))
+}
+
+func (sut *LoginServiceTestSuite) Test32VerifyTwoFactorDisabledBadRequest() {
+	sut.T().Log("Test32VerifyTwoFactorDisabledBadRequest")
+	sut.enableTotp()
+	sut.user.DisabledAt = pgtype.Int8{Valid: true, Int64: 1719496900000}
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("FindUserId", sut.client, sut.ctx, sut.challengeHash).Return(int32(1), nil)
+	sut.twoFactorChallengeRepositoryMock.Mock.On("IncrementAttempt", sut.client, sut.ctx, sut.challengeHash, 5*time.Minute).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sessionId, httpCode, response := sut.loginService.VerifyTwoFactor(sut.ctx, sut.verifyTwoFactorRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "challengeId")
+	sut.Equal(errorMessages[0].Message, "challenge is invalid or expired")
+	sut.twoFactorHelperMock.Mock.AssertNotCalled(sut.T(), "DecryptSecret", mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test33AuthorizeOidcProviderNotFound() {
+	sut.T().Log("Test33AuthorizeOidcProviderNotFound")
+	state, httpCode, response := sut.loginService.AuthorizeOidc(sut.ctx, "unknown")
+	sut.Equal(state, "")
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "identity provider not found")
+}
+
+func (sut *LoginServiceTestSuite) Test34AuthorizeOidcOidcStateRepositoryCreateInternalServerError() {
+	sut.T().Log("Test34AuthorizeOidcOidcStateRepositoryCreateInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.tokenHelperMock.Mock.On("Generate").Return("state", nil).Once()
+	sut.tokenHelperMock.Mock.On("Generate").Return("nonce", nil).Once()
+	sut.tokenHelperMock.Mock.On("Generate").Return("codeVerifier", nil).Once()
+	sut.oidcStateRepositoryMock.Mock.On("Create", sut.client, sut.ctx, sut.stateHash, sut.oidcState, 10*time.Minute).Return(sut.errInternalServer)
+	state, httpCode, response := sut.loginService.AuthorizeOidc(sut.ctx, "stub")
+	sut.Equal(state, "")
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *LoginServiceTestSuite) Test35AuthorizeOidcSuccess() {
+	sut.T().Log("Test35AuthorizeOidcSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.tokenHelperMock.Mock.On("Generate").Return("state", nil).Once()
+	sut.tokenHelperMock.Mock.On("Generate").Return("nonce", nil).Once()
+	sut.tokenHelperMock.Mock.On("Generate").Return("codeVerifier", nil).Once()
+	sut.oidcStateRepositoryMock.Mock.On("Create", sut.client, sut.ctx, sut.stateHash, sut.oidcState, 10*time.Minute).Return(nil)
+	state, httpCode, response := sut.loginService.AuthorizeOidc(sut.ctx, "stub")
+	sut.Equal(state, "state")
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	oidcAuthorizationResponse, _ := response.Data.(models.OidcAuthorizationResponse)
+	sut.Equal(oidcAuthorizationResponse.AuthorizationUrl, helpers.ToOidcAuthorizationUrl(sut.oidcProvider, "state", "nonce", "codeVerifier"))
+}
+
+func (sut *LoginServiceTestSuite) Test36LoginWithOidcValidationError() {
+	sut.T().Log("Test36LoginWithOidcValidationError")
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", models.OidcLoginRequest{}, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "is required")
+	sut.Equal(errorMessages[1].Field, "state")
+	sut.Equal(errorMessages[1].Message, "is required")
+}
+
+func (sut *LoginServiceTestSuite) Test37LoginWithOidcStateCookieMismatchBadRequest() {
+	sut.T().Log("Test37LoginWithOidcStateCookieMismatchBadRequest")
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "otherState", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "state")
+	sut.Equal(errorMessages[0].Message, "state is invalid or expired")
+	sut.oidcStateRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindAndDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test38LoginWithOidcOidcStateRepositoryFindAndDeleteExpiredBadRequest() {
+	sut.T().Log("Test38LoginWithOidcOidcStateRepositoryFindAndDeleteExpiredBadRequest")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.oidcStateRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.stateHash).Return(models.OidcState{}, redis.Nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "state")
+	sut.Equal(errorMessages[0].Message, "state is invalid or expired")
+	sut.oidcHelperMock.Mock.AssertNotCalled(sut.T(), "ExchangeCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test39LoginWithOidcOidcHelperExchangeCodeRejectedBadRequest() {
+	sut.T().Log("Test39LoginWithOidcOidcHelperExchangeCodeRejectedBadRequest")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.oidcStateRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, sut.stateHash).Return(sut.oidcState, nil)
+	sut.oidcHelperMock.Mock.On("ExchangeCode", sut.ctx, sut.oidcProvider, "code", "codeVerifier").Return("", errors.Join(helpers.ErrOidcRejected, errors.New("invalid_grant")))
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "sign in with the identity provider failed, please try again")
+}
+
+func (sut *LoginServiceTestSuite) Test40LoginWithOidcOidcHelperVerifyIdTokenTimeoutError() {
+	sut.T().Log("Test40LoginWithOidcOidcHelperVerifyIdTokenTimeoutError")
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(helpers.OidcClaims{}, sut.errTimeout)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *LoginServiceTestSuite) Test41LoginWithOidcLinkedUserSuccess() {
+	sut.T().Log("Test41LoginWithOidcLinkedUserSuccess")
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.mockOidcSession()
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
 	sut.Equal(sessionId, sut.sessionId)
 	sut.Equal(httpCode, http.StatusOK)
 	sut.Equal(response.Errors, nil)
 	responseMessage, _ := response.Data.(helpers.ResponseMessage)
 	sut.Equal(responseMessage.Message, "successfully login")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByEmail", mock.Anything, mock.Anything, mock.Anything)
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginSucceededEvent, sut.user.Id.Int32, sut.user.Email.String)))
+}
+
+func (sut *LoginServiceTestSuite) Test42LoginWithOidcEmailNotVerifiedForbidden() {
+	sut.T().Log("Test42LoginWithOidcEmailNotVerifiedForbidden")
+	sut.oidcClaims.EmailVerified = false
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(0), pgx.ErrNoRows)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusForbidden)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "the identity provider did not share a verified email")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test43LoginWithOidcExistingUserEmailNotVerifiedConflict() {
+	sut.T().Log("Test43LoginWithOidcExistingUserEmailNotVerifiedConflict")
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(0), pgx.ErrNoRows)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, "email@email.com").Return(sut.user, nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusConflict)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "an account with this email already exists, log in with its password and verify the email first")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "BeginTx", sut.ctx, pgx.TxOptions{})
+}
+
+func (sut *LoginServiceTestSuite) Test44LoginWithOidcExistingUserTwoFactorEnabledChallenge() {
+	sut.T().Log("Test44LoginWithOidcExistingUserTwoFactorEnabledChallenge")
+	sut.enableTotp()
+	sut.user.EmailVerifiedAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(0), pgx.ErrNoRows)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, "email@email.com").Return(sut.user, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userIdentityRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(func(userIdentity models.UserIdentity) bool {
+		return userIdentity.UserId == 1 && userIdentity.Provider == "stub" && userIdentity.Subject == "subject" && userIdentity.CreatedAt > 0
+	})).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.uuidHelperMock.Mock.On("String").Return("challengeId")
+	sut.twoFactorChallengeRepositoryMock.Mock.On("Create", sut.client, sut.ctx, sut.challengeHash, sut.user.Id.Int32, 5*time.Minute).Return(nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.TwoFactorChallengeResponse{Message: "two-factor authentication required", ChallengeId: "challengeId"})
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+	sut.redisHelperMock.Mock.AssertNotCalled(sut.T(), "Set", sut.client, sut.ctx, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *LoginServiceTestSuite) Test45LoginWithOidcNewUserSuccess() {
+	sut.T().Log("Test45LoginWithOidcNewUserSuccess")
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(0), pgx.ErrNoRows)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, "email@email.com").Return(models.User{}, pgx.ErrNoRows)
+	sut.tokenHelperMock.Mock.On("Generate").Return("password", nil)
+	sut.passwordHasherMock.Mock.On("Hash", "password").Return("hash", nil)
+	sut.mockOidcSession()
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(func(user models.User) bool {
+		return user.Username.String == "user_sessionId" && user.Email.String == "email@email.com" && user.Password.String == "hash" && user.CreatedAt.Int64 > 0 && user.EmailVerifiedAt.Valid
+	})).Return(int32(1), nil)
+	sut.userIdentityRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(func(value interface{}) bool {
+		var session helpers.Session
+		err := json.Unmarshal([]byte(value.(string)), &session)
+		return err == nil && session.Id == 1 && session.Username == "user_sessionId" && session.Email == "email@email.com" && !session.TwoFactor
+	}), 30*time.Minute).Return("", nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully login")
+}
+
+func (sut *LoginServiceTestSuite) Test46LoginWithOidcUserIdentityRepositoryCreateUniqueViolationConflict() {
+	sut.T().Log("Test46LoginWithOidcUserIdentityRepositoryCreateUniqueViolationConflict")
+	errUniqueViolation := &pgconn.PgError{Code: "23505"}
+	sut.user.EmailVerifiedAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(0), pgx.ErrNoRows)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, "email@email.com").Return(sut.user, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userIdentityRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusConflict)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "sign in is already in progress, please try again")
+}
+
+func (sut *LoginServiceTestSuite) Test47LoginWithOidcDisabledForbidden() {
+	sut.T().Log("Test47LoginWithOidcDisabledForbidden")
+	sut.user.DisabledAt = pgtype.Int8{Valid: true, Int64: 1719496900000}
+	sut.mockOidcStateFound()
+	sut.oidcHelperMock.Mock.On("VerifyIdToken", sut.ctx, sut.oidcProvider, "idToken", "nonce").Return(sut.oidcClaims, nil)
+	sut.userIdentityRepositoryMock.Mock.On("FindUserId", sut.pool, sut.ctx, "stub", "subject").Return(int32(1), nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sessionId, httpCode, response := sut.loginService.LoginWithOidc(sut.ctx, "stub", sut.oidcLoginRequest, "state", sut.userAgent, sut.ip)
+	sut.Equal(sessionId, "")
+	sut.Equal(httpCode, http.StatusForbidden)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "account is disabled")
+	sut.authEventHelperMock.Mock.AssertCalled(sut.T(), "Record", sut.pool, sut.ctx, mock.MatchedBy(sut.matchAuthEvent(helpers.LoginDisabledEvent, sut.user.Id.Int32, sut.user.Email.String)))
+}
+
+func (sut *LoginServiceTestSuite) Test48LoginMixedCaseEmailSuccess() {
+	sut.T().Log("Test48LoginMixedCaseEmailSuccess")
+	sut.loginRequest.Email = "Email@EMAIL.com"
+	sut.mockLoginAttemptNotLocked()
+	sut.loginAttemptRepositoryMock.Mock.On("Reset", sut.client, sut.ctx, []string{sut.emailFailureKey}).Return(nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, "email@email.com").Return(sut.user, nil)
+	sut.mockPasswordMatches()
+	sut.userPermissionRepositoryMock.Mock.On("FindPermissionIdsByUserId", sut.pool, sut.ctx, sut.user.Id.Int32).Return([]int32(nil), nil)
+	sut.uuidHelperMock.Mock.On("String").Return(sut.sessionId)
+	sut.sessionRegistryHelperMock.Mock.On("Register", sut.client, sut.ctx, sut.user.Id.Int32, mock.MatchedBy(sut.matchSessionInfo), 24*time.Hour).Return(nil)
+	sut.redisHelperMock.Mock.On("Set", sut.client, sut.ctx, sut.sessionId, mock.MatchedBy(sut.matchSession), 30*time.Minute).Return("", nil)
+	sessionId, _, httpCode, response := sut.loginService.Login(sut.ctx, sut.loginRequest, sut.userAgent, sut.ip)
+	sut.Equal(sessionId, sut.sessionId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByEmail", sut.pool, sut.ctx, "Email@EMAIL.com")
+}
+
+// Test49 runs the real password hasher with a low cost, the median time of a login with an unknown email must stay
+// within 20% of the median time of a login with a registered email and a wrong password
+func (sut *LoginServiceTestSuite) Test49LoginUnknownEmailTakesAsLongAsWrongPassword() {
+	sut.T().Log("Test49LoginUnknownEmailTakesAsLongAsWrongPassword")
+	passwordHasher := helpers.NewPasswordHasher(helpers.PasswordHashConfig{Algorithm: helpers.PasswordHashAlgorithmBcrypt, BcryptCost: 8})
+	password, err := passwordHasher.Hash("password@A2")
+	sut.Nil(err)
+	sut.user.Password.String = password
+	unknownLoginRequest := models.LoginRequest{Email: "unknown@email.com", Password: sut.loginRequest.Password}
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.loginAttemptRepositoryMock.Mock.On("FindLockTtl", sut.client, sut.ctx, mock.Anything).Return(time.Duration(-2), nil)
+	sut.loginAttemptRepositoryMock.Mock.On("IncrementFailure", sut.client, sut.ctx, mock.Anything, time.Hour).Return(int64(1), nil)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.loginRequest.Email).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, unknownLoginRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	loginService := services.NewLoginService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.userPermissionRepositoryMock, sut.loginAttemptRepositoryMock, sut.twoFactorChallengeRepositoryMock, sut.oidcStateRepositoryMock, sut.userIdentityRepositoryMock, sut.twoFactorRepositoryMock, sut.uuidHelperMock, sut.redisHelperMock, sut.sessionRegistryHelperMock, sut.twoFactorHelperMock, sut.jwtHelperMock, sut.tokenFamilyHelperMock, passwordHasher, sut.authEventHelperMock, sut.tokenHelperMock, sut.oidcHelperMock)
+	measure := func(loginRequest models.LoginRequest) time.Duration {
+		start := time.Now()
+		_, _, httpCode, _ := loginService.Login(sut.ctx, loginRequest, sut.userAgent, sut.ip)
+		sut.Equal(httpCode, http.StatusBadRequest)
+		return time.Since(start)
+	}
+	median := func(durations []time.Duration) time.Duration {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		return durations[len(durations)/2]
+	}
+
+	// the first unknown email makes the dummy hash, it is left out like any other warm up
+	measure(sut.loginRequest)
+	measure(unknownLoginRequest)
+	var wrongPasswordDurations, unknownEmailDurations []time.Duration
+	for i := 0; i < 25; i++ {
+		wrongPasswordDurations = append(wrongPasswordDurations, measure(sut.loginRequest))
+		unknownEmailDurations = append(unknownEmailDurations, measure(unknownLoginRequest))
+	}
+	wrongPasswordMedian := median(wrongPasswordDurations)
+	unknownEmailMedian := median(unknownEmailDurations)
+	difference := wrongPasswordMedian - unknownEmailMedian
+	if difference < 0 {
+		difference = -difference
+	}
+	sut.T().Log("wrong password median " + wrongPasswordMedian.String() + ", unknown email median " + unknownEmailMedian.String())
+	sut.LessOrEqual(difference, wrongPasswordMedian/5)
 }
 
 func (sut *LoginServiceTestSuite) AfterTest(suiteName, testName string) {
@@ -246,4 +1149,12 @@ func (sut *LoginServiceTestSuite) TearDownTest() {
 
 func (sut *LoginServiceTestSuite) TearDownSuite() {
 	sut.T().Log("TearDownSuite")
+	os.Unsetenv("ECOMMERCEV2_OIDC_PROVIDERS")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_ISSUER")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_CLIENT_ID")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_CLIENT_SECRET")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_AUTHORIZATION_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_TOKEN_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_JWKS_URL")
+	os.Unsetenv("ECOMMERCEV2_OIDC_STUB_REDIRECT_URL")
 }
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/features/users/logout/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type LogoutServiceTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	redisUtilMock             *mockutils.RedisUtilMock
+	redisHelperMock           *mockhelpers.RedisHelperMock
+	sessionRegistryHelperMock *mockhelpers.SessionRegistryHelperMock
+	client                    *redis.Client
+	errTimeout                error
+	errInternalServer         error
+	sessionId                 string
+	sessionCtx                context.Context
+	logoutService             services.LogoutService
+}
+
+func TestLogoutTestSuite(t *testing.T) {
+	suite.Run(t, new(LogoutServiceTestSuite))
+}
+
+func (sut *LogoutServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *LogoutServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.sessionId = "sessionId"
+	sut.sessionCtx = context.WithValue(sut.ctx, middlewares.SessionIdKey, sut.sessionId)
+	sut.sessionCtx = context.WithValue(sut.sessionCtx, middlewares.IdKey, int32(1))
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.logoutService = services.NewLogoutService(sut.redisUtilMock, sut.redisHelperMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *LogoutServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *LogoutServiceTestSuite) Test1LogoutRedisHelperDelTimeoutError() {
+	sut.T().Log("Test1LogoutRedisHelperDelTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Del", sut.client, sut.sessionCtx, sut.sessionId).Return(int64(0), sut.errTimeout)
+	httpCode, response := sut.logoutService.Logout(sut.sessionCtx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *LogoutServiceTestSuite) Test2LogoutSessionRegistryHelperUnregisterInternalServerError() {
+	sut.T().Log("Test2LogoutSessionRegistryHelperUnregisterInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Del", sut.client, sut.sessionCtx, sut.sessionId).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("Unregister", sut.client, sut.sessionCtx, int32(1), sut.sessionId).Return(sut.errInternalServer)
+	httpCode, response := sut.logoutService.Logout(sut.sessionCtx)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *LogoutServiceTestSuite) Test3LogoutWithoutSessionSuccess() {
+	sut.T().Log("Test3LogoutWithoutSessionSuccess")
+	httpCode, response := sut.logoutService.Logout(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully logout")
+	sut.redisUtilMock.Mock.AssertNotCalled(sut.T(), "GetClient")
+}
+
+func (sut *LogoutServiceTestSuite) Test4LogoutSuccess() {
+	sut.T().Log("Test4LogoutSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.redisHelperMock.Mock.On("Del", sut.client, sut.sessionCtx, sut.sessionId).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("Unregister", sut.client, sut.sessionCtx, int32(1), sut.sessionId).Return(nil)
+	httpCode, response := sut.logoutService.Logout(sut.sessionCtx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully logout")
+}
+
+func (sut *LogoutServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *LogoutServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *LogoutServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,23 @@
+package mockrepositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type PasswordResetTokenRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PasswordResetTokenRepositoryMock) Create(client *redis.Client, ctx context.Context, tokenHash string, userId int32, expiration time.Duration) (err error) {
+	arguments := repository.Mock.Called(client, ctx, tokenHash, userId, expiration)
+	return arguments.Error(0)
+}
+
+func (repository *PasswordResetTokenRepositoryMock) FindAndDeleteUserId(client *redis.Client, ctx context.Context, tokenHash string) (userId int32, err error) {
+	arguments := repository.Mock.Called(client, ctx, tokenHash)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
@@ -0,0 +1,317 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/passwordreset/models"
+	"backend-golang/features/users/passwordreset/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/passwordreset/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordResetServiceTestSuite struct {
+	suite.Suite
+	ctx                              context.Context
+	postgresUtilMock                 *mockutils.PostgresUtilMock
+	redisUtilMock                    *mockutils.RedisUtilMock
+	mailerMock                       *mockutils.MailerMock
+	validate                         *validator.Validate
+	userRepositoryMock               *mockrepositories.UserRepositoryMock
+	passwordResetTokenRepositoryMock *mockrepositories.PasswordResetTokenRepositoryMock
+	passwordHasherMock               *mockhelpers.PasswordHasherMock
+	tokenHelperMock                  *mockhelpers.TokenHelperMock
+	sessionRegistryHelperMock        *mockhelpers.SessionRegistryHelperMock
+	pool                             *pgxpool.Pool
+	client                           *redis.Client
+	errTimeout                       error
+	errInternalServer                error
+	forgotPasswordRequest            models.ForgotPasswordRequest
+	resetPasswordRequest             models.ResetPasswordRequest
+	user                             models.User
+	token                            string
+	passwordResetService             services.PasswordResetService
+}
+
+func TestPasswordResetTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetServiceTestSuite))
+}
+
+func (sut *PasswordResetServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *PasswordResetServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.forgotPasswordRequest = models.ForgotPasswordRequest{
+		Email: "email@email.com",
+	}
+	sut.resetPasswordRequest = models.ResetPasswordRequest{
+		Token:           "token",
+		Password:        "password@A1",
+		Confirmpassword: "password@A1",
+	}
+	sut.user = models.User{
+		Id:       pgtype.Int4{Valid: true, Int32: 1},
+		Username: pgtype.Text{Valid: true, String: "username"},
+		Email:    pgtype.Text{Valid: true, String: "email@email.com"},
+	}
+	sut.token = "token"
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.mailerMock = new(mockutils.MailerMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.passwordResetTokenRepositoryMock = new(mockrepositories.PasswordResetTokenRepositoryMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.tokenHelperMock = new(mockhelpers.TokenHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.passwordResetService = services.NewPasswordResetService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.passwordResetTokenRepositoryMock, sut.passwordHasherMock, sut.tokenHelperMock, sut.sessionRegistryHelperMock, sut.mailerMock)
+}
+
+func (sut *PasswordResetServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *PasswordResetServiceTestSuite) Test01ForgotValidationError() {
+	sut.T().Log("Test01ForgotValidationError")
+	sut.forgotPasswordRequest.Email = "email"
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "email")
+	sut.Equal(errorMessages[0].Message, "please input a correct email format ")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test02ForgotUserRepositoryFindByEmailTimeoutError() {
+	sut.T().Log("Test02ForgotUserRepositoryFindByEmailTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(models.User{}, sut.errTimeout)
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test03ForgotUserRepositoryFindByEmailNotFoundSameResponse() {
+	sut.T().Log("Test03ForgotUserRepositoryFindByEmailNotFoundSameResponse")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered, a password reset link has been sent"})
+	sut.Equal(response.Errors, nil)
+	sut.tokenHelperMock.Mock.AssertNotCalled(sut.T(), "Generate")
+	sut.mailerMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (sut *PasswordResetServiceTestSuite) Test04ForgotPasswordResetTokenRepositoryCreateErrorSameResponse() {
+	sut.T().Log("Test04ForgotPasswordResetTokenRepositoryCreateErrorSameResponse")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(sut.user, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.user.Id.Int32, 30*time.Minute).Return(sut.errInternalServer)
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered, a password reset link has been sent"})
+	sut.Equal(response.Errors, nil)
+	sut.mailerMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (sut *PasswordResetServiceTestSuite) Test05ForgotMailerSendErrorSameResponse() {
+	sut.T().Log("Test05ForgotMailerSendErrorSameResponse")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(sut.user, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.user.Id.Int32, 30*time.Minute).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.Anything).Return(sut.errInternalServer)
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered, a password reset link has been sent"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *PasswordResetServiceTestSuite) Test06ForgotSuccess() {
+	sut.T().Log("Test06ForgotSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(sut.user, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.user.Id.Int32, 30*time.Minute).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.user.Email.String && strings.Contains(mail.Body, "?token="+sut.token)
+	})).Return(nil)
+	httpCode, response := sut.passwordResetService.Forgot(sut.ctx, sut.forgotPasswordRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "if the email is registered, a password reset link has been sent"})
+	sut.Equal(response.Errors, nil)
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *PasswordResetServiceTestSuite) Test07ResetValidationError() {
+	sut.T().Log("Test07ResetValidationError")
+	sut.resetPasswordRequest.Confirmpassword = "password@A2"
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "confirmpassword")
+	sut.Equal(errorMessages[0].Message, "please input the same value as password")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test08ResetPasswordResetTokenRepositoryFindAndDeleteUserIdTimeoutError() {
+	sut.T().Log("Test08ResetPasswordResetTokenRepositoryFindAndDeleteUserIdTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("FindAndDeleteUserId", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(int32(0), sut.errTimeout)
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test09ResetPasswordResetTokenRepositoryFindAndDeleteUserIdBadRequestInvalidToken() {
+	sut.T().Log("Test09ResetPasswordResetTokenRepositoryFindAndDeleteUserIdBadRequestInvalidToken")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("FindAndDeleteUserId", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(int32(0), redis.Nil)
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "token is invalid or expired")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test10ResetUserRepositoryUpdatePasswordInternalServerError() {
+	sut.T().Log("Test10ResetUserRepositoryUpdatePasswordInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("FindAndDeleteUserId", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.user.Id.Int32, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.resetPasswordRequest.Password).Return("password", nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdatePassword", sut.pool, sut.ctx, sut.user.Id.Int32, "password").Return(int64(0), sut.errInternalServer)
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, sut.user.Id.Int32, "")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test11ResetSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test11ResetSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("FindAndDeleteUserId", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.user.Id.Int32, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.resetPasswordRequest.Password).Return("password", nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdatePassword", sut.pool, sut.ctx, sut.user.Id.Int32, "password").Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.user.Id.Int32, "").Return(sut.errInternalServer)
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *PasswordResetServiceTestSuite) Test12ResetSuccess() {
+	sut.T().Log("Test12ResetSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("FindAndDeleteUserId", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.user.Id.Int32, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.resetPasswordRequest.Password).Return("password", nil)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("UpdatePassword", sut.pool, sut.ctx, sut.user.Id.Int32, "password").Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.user.Id.Int32, "").Return(nil)
+	httpCode, response := sut.passwordResetService.Reset(sut.ctx, sut.resetPasswordRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully reset password"})
+	sut.Equal(response.Errors, nil)
+}
+
+// Test13 sends the mail through the background mailer to an smtp server which takes 10ms, the median time of a request for
+// an unknown email must stay within a fifth of that from the median time of a request for a registered email
+func (sut *PasswordResetServiceTestSuite) Test13ForgotUnknownEmailTakesAsLongAsRegisteredEmail() {
+	sut.T().Log("Test13ForgotUnknownEmailTakesAsLongAsRegisteredEmail")
+	smtpDelay := 10 * time.Millisecond
+	unknownForgotPasswordRequest := models.ForgotPasswordRequest{Email: "unknown@email.com"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, sut.forgotPasswordRequest.Email).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindByEmail", sut.pool, sut.ctx, unknownForgotPasswordRequest.Email).Return(models.User{}, pgx.ErrNoRows)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.passwordResetTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.user.Id.Int32, 30*time.Minute).Return(nil)
+	sut.mailerMock.Mock.On("Send", mock.Anything, mock.AnythingOfType("utils.Mail")).After(smtpDelay).Return(nil)
+	backgroundRunner := utils.NewBackgroundRunner(64, func(ctx context.Context, err error) {
+		sut.Nil(err)
+	})
+	passwordResetService := services.NewPasswordResetService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.passwordResetTokenRepositoryMock, sut.passwordHasherMock, sut.tokenHelperMock, sut.sessionRegistryHelperMock, utils.NewBackgroundMailer(sut.mailerMock, backgroundRunner))
+	measure := func(forgotPasswordRequest models.ForgotPasswordRequest) time.Duration {
+		start := time.Now()
+		httpCode, _ := passwordResetService.Forgot(sut.ctx, forgotPasswordRequest)
+		sut.Equal(httpCode, http.StatusOK)
+		return time.Since(start)
+	}
+	median := func(durations []time.Duration) time.Duration {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		return durations[len(durations)/2]
+	}
+
+	measure(sut.forgotPasswordRequest)
+	measure(unknownForgotPasswordRequest)
+	var registeredEmailDurations, unknownEmailDurations []time.Duration
+	for i := 0; i < 25; i++ {
+		registeredEmailDurations = append(registeredEmailDurations, measure(sut.forgotPasswordRequest))
+		unknownEmailDurations = append(unknownEmailDurations, measure(unknownForgotPasswordRequest))
+	}
+	backgroundRunner.Close()
+	registeredEmailMedian := median(registeredEmailDurations)
+	unknownEmailMedian := median(unknownEmailDurations)
+	difference := registeredEmailMedian - unknownEmailMedian
+	if difference < 0 {
+		difference = -difference
+	}
+	sut.T().Log("registered email median " + registeredEmailMedian.String() + ", unknown email median " + unknownEmailMedian.String())
+	sut.LessOrEqual(difference, smtpDelay/5)
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 26)
+}
+
+func (sut *PasswordResetServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *PasswordResetServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *PasswordResetServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,43 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PermissionRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context) (permissions []models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.Permission), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.Permission), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, permission)
+	return arguments.Get(0).(models.Permission), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) Create(pool *pgxpool.Pool, ctx context.Context, permission string) (id int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, permission)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) Update(pool *pgxpool.Pool, ctx context.Context, id int32, permission string) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, permission)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
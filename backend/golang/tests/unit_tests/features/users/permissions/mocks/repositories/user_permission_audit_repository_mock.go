@@ -0,0 +1,18 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserPermissionAuditRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserPermissionAuditRepositoryMock) Create(tx pgx.Tx, ctx context.Context, userPermissionAudit models.UserPermissionAudit) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, userPermissionAudit)
+	return arguments.Error(0)
+}
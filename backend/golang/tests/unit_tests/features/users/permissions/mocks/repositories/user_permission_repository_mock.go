@@ -0,0 +1,29 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/permissions/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserPermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserPermissionRepositoryMock) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (userPermissions []models.UserPermission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.UserPermission), arguments.Error(1)
+}
+
+func (repository *UserPermissionRepositoryMock) Create(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, permissionId)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *UserPermissionRepositoryMock) Delete(tx pgx.Tx, ctx context.Context, userId int32, permissionId int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, permissionId)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/services"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/permissions/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+)
+
+type PermissionServiceTestSuite struct {
+	suite.Suite
+	ctx                      context.Context
+	postgresUtilMock         *mockutils.PostgresUtilMock
+	validate                 *validator.Validate
+	permissionRepositoryMock *mockrepositories.PermissionRepositoryMock
+	pool                     *pgxpool.Pool
+	errTimeout               error
+	errInternalServer        error
+	permissions              []models.Permission
+	permissionRequest        models.PermissionRequest
+	permissionService        services.PermissionService
+}
+
+func TestPermissionTestSuite(t *testing.T) {
+	suite.Run(t, new(PermissionServiceTestSuite))
+}
+
+func (sut *PermissionServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *PermissionServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.permissions = []models.Permission{
+		{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 6}, Permission: pgtype.Text{Valid: true, String: "READ_PRODUCT"}},
+	}
+	sut.permissionRequest = models.PermissionRequest{
+		Permission: "CREATE_PRODUCT",
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.permissionService = services.NewPermissionService(sut.postgresUtilMock, sut.validate, sut.permissionRepositoryMock)
+}
+
+func (sut *PermissionServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *PermissionServiceTestSuite) Test01FindAllPermissionRepositoryFindAllTimeoutError() {
+	sut.T().Log("Test01FindAllPermissionRepositoryFindAllTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return([]models.Permission{}, sut.errTimeout)
+	httpCode, response := sut.permissionService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *PermissionServiceTestSuite) Test02FindAllSuccess() {
+	sut.T().Log("Test02FindAllSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return(sut.permissions, nil)
+	httpCode, response := sut.permissionService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	permissionResponses, _ := response.Data.([]models.PermissionResponse)
+	sut.Equal(len(permissionResponses), 2)
+	sut.Equal(permissionResponses[1].Id, int32(6))
+	sut.Equal(permissionResponses[1].Permission, "READ_PRODUCT")
+}
+
+func (sut *PermissionServiceTestSuite) Test03FindByIdNotFound() {
+	sut.T().Log("Test03FindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(7)).Return(models.Permission{}, pgx.ErrNoRows)
+	httpCode, response := sut.permissionService.FindById(sut.ctx, 7)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "permission not found")
+}
+
+func (sut *PermissionServiceTestSuite) Test04CreateValidationError() {
+	sut.T().Log("Test04CreateValidationError")
+	sut.permissionRequest = models.PermissionRequest{}
+	httpCode, response := sut.permissionService.Create(sut.ctx, sut.permissionRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "permission")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *PermissionServiceTestSuite) Test05CreatePermissionExists() {
+	sut.T().Log("Test05CreatePermissionExists")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, sut.permissionRequest.Permission).Return(int32(0), &pgconn.PgError{Code: "23505"})
+	httpCode, response := sut.permissionService.Create(sut.ctx, sut.permissionRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "permission")
+	sut.Equal(errorMessages[0].Message, "permission already exists")
+}
+
+func (sut *PermissionServiceTestSuite) Test06CreateSuccess() {
+	sut.T().Log("Test06CreateSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, sut.permissionRequest.Permission).Return(int32(7), nil)
+	httpCode, response := sut.permissionService.Create(sut.ctx, sut.permissionRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.PermissionResponse{Id: 7, Permission: "CREATE_PRODUCT"})
+}
+
+func (sut *PermissionServiceTestSuite) Test07UpdateBuiltInPermissionBadRequest() {
+	sut.T().Log("Test07UpdateBuiltInPermissionBadRequest")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.permissions[0], nil)
+	httpCode, response := sut.permissionService.Update(sut.ctx, 1, sut.permissionRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "built-in permission cannot be changed")
+	sut.permissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "Update", sut.pool, sut.ctx, int32(1), sut.permissionRequest.Permission)
+}
+
+func (sut *PermissionServiceTestSuite) Test08UpdateSuccess() {
+	sut.T().Log("Test08UpdateSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.permissions[1], nil)
+	sut.permissionRepositoryMock.Mock.On("Update", sut.pool, sut.ctx, int32(6), sut.permissionRequest.Permission).Return(int64(1), nil)
+	httpCode, response := sut.permissionService.Update(sut.ctx, 6, sut.permissionRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.PermissionResponse{Id: 6, Permission: "CREATE_PRODUCT"})
+}
+
+func (sut *PermissionServiceTestSuite) Test09DeleteStillGrantedBadRequest() {
+	sut.T().Log("Test09DeleteStillGrantedBadRequest")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.permissions[1], nil)
+	sut.permissionRepositoryMock.Mock.On("Delete", sut.pool, sut.ctx, int32(6)).Return(int64(0), &pgconn.PgError{Code: "23503"})
+	httpCode, response := sut.permissionService.Delete(sut.ctx, 6)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "permission is still granted, revoke it first")
+}
+
+func (sut *PermissionServiceTestSuite) Test10DeleteSuccess() {
+	sut.T().Log("Test10DeleteSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.permissions[1], nil)
+	sut.permissionRepositoryMock.Mock.On("Delete", sut.pool, sut.ctx, int32(6)).Return(int64(1), nil)
+	httpCode, response := sut.permissionService.Delete(sut.ctx, 6)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully delete permission")
+}
+
+func (sut *PermissionServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *PermissionServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *PermissionServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
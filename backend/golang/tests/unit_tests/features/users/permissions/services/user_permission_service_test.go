@@ -0,0 +1,299 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/permissions/models"
+	"backend-golang/features/users/permissions/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/permissions/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserPermissionServiceTestSuite struct {
+	suite.Suite
+	ctx                               context.Context
+	postgresUtilMock                  *mockutils.PostgresUtilMock
+	redisUtilMock                     *mockutils.RedisUtilMock
+	validate                          *validator.Validate
+	permissionRepositoryMock          *mockrepositories.PermissionRepositoryMock
+	userPermissionRepositoryMock      *mockrepositories.UserPermissionRepositoryMock
+	userPermissionAuditRepositoryMock *mockrepositories.UserPermissionAuditRepositoryMock
+	sessionRegistryHelperMock         *mockhelpers.SessionRegistryHelperMock
+	pool                              *pgxpool.Pool
+	tx                                pgx.Tx
+	client                            *redis.Client
+	errInternalServer                 error
+	requestId                         string
+	actorId                           int32
+	userId                            int32
+	administrator                     models.Permission
+	readProduct                       models.Permission
+	createProduct                     models.Permission
+	grantPermissionRequest            models.GrantPermissionRequest
+	userPermissionService             services.UserPermissionService
+}
+
+func TestUserPermissionTestSuite(t *testing.T) {
+	suite.Run(t, new(UserPermissionServiceTestSuite))
+}
+
+func (sut *UserPermissionServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.requestId = uuid.New().String()
+	sut.actorId = 1
+	sut.userId = 2
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.administrator = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}}
+	sut.readProduct = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 6}, Permission: pgtype.Text{Valid: true, String: "READ_PRODUCT"}}
+	sut.createProduct = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 7}, Permission: pgtype.Text{Valid: true, String: "CREATE_PRODUCT"}}
+}
+
+func (sut *UserPermissionServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.setSession([]int32{2, 6}, false)
+	sut.grantPermissionRequest = models.GrantPermissionRequest{
+		PermissionId: 6,
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.userPermissionRepositoryMock = new(mockrepositories.UserPermissionRepositoryMock)
+	sut.userPermissionAuditRepositoryMock = new(mockrepositories.UserPermissionAuditRepositoryMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.userPermissionService = services.NewUserPermissionService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.permissionRepositoryMock, sut.userPermissionRepositoryMock, sut.userPermissionAuditRepositoryMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *UserPermissionServiceTestSuite) setSession(idPermissions []int32, twoFactor bool) {
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, sut.requestId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, sut.actorId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.PermissionKey, idPermissions)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.TwoFactorKey, twoFactor)
+}
+
+func (sut *UserPermissionServiceTestSuite) matchUserPermissionAudit(action string, permissionId int32) func(models.UserPermissionAudit) bool {
+	return func(userPermissionAudit models.UserPermissionAudit) bool {
+		return userPermissionAudit.UserId.Int32 == sut.userId && userPermissionAudit.PermissionId.Int32 == permissionId && userPermissionAudit.Action.String == action && userPermissionAudit.ActorId.Int32 == sut.actorId && userPermissionAudit.RequestId.String == sut.requestId && userPermissionAudit.CreatedAt.Int64 > 0
+	}
+}
+
+func (sut *UserPermissionServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *UserPermissionServiceTestSuite) Test01FindAllByUserIdSuccess() {
+	sut.T().Log("Test01FindAllByUserIdSuccess")
+	userPermissions := []models.UserPermission{{PermissionId: sut.readProduct.Id, Permission: sut.readProduct.Permission}}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userPermissionRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.userId).Return(userPermissions, nil)
+	httpCode, response := sut.userPermissionService.FindAllByUserId(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, []models.PermissionResponse{{Id: 6, Permission: "READ_PRODUCT"}})
+}
+
+func (sut *UserPermissionServiceTestSuite) Test02GrantValidationError() {
+	sut.T().Log("Test02GrantValidationError")
+	sut.grantPermissionRequest = models.GrantPermissionRequest{}
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "permissionId")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test03GrantPermissionNotFound() {
+	sut.T().Log("Test03GrantPermissionNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(models.Permission{}, pgx.ErrNoRows)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "permission not found")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test04GrantPermissionNotOwnedForbidden() {
+	sut.T().Log("Test04GrantPermissionNotOwnedForbidden")
+	sut.grantPermissionRequest.PermissionId = 7
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(7)).Return(sut.createProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot grant or revoke a permission you do not have")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "BeginTx", sut.ctx, pgx.TxOptions{})
+}
+
+func (sut *UserPermissionServiceTestSuite) Test05GrantAdministratorWithoutTwoFactorForbidden() {
+	sut.T().Log("Test05GrantAdministratorWithoutTwoFactorForbidden")
+	sut.setSession([]int32{1, 2}, false)
+	sut.grantPermissionRequest.PermissionId = 1
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.administrator, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot grant or revoke a permission you do not have")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test06GrantAlreadyGrantedBadRequest() {
+	sut.T().Log("Test06GrantAlreadyGrantedBadRequest")
+	errUniqueViolation := &pgconn.PgError{Code: "23505"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int32(0), errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user already has this permission")
+	sut.userPermissionAuditRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, mock.Anything)
+}
+
+func (sut *UserPermissionServiceTestSuite) Test07GrantUserNotFound() {
+	sut.T().Log("Test07GrantUserNotFound")
+	errForeignKeyViolation := &pgconn.PgError{Code: "23503"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int32(0), errForeignKeyViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errForeignKeyViolation).Return(nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test08GrantUserPermissionAuditRepositoryCreateInternalServerError() {
+	sut.T().Log("Test08GrantUserPermissionAuditRepositoryCreateInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int32(3), nil)
+	sut.userPermissionAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+	sut.postgresUtilMock.Mock.AssertCalled(sut.T(), "CommitOrRollback", sut.tx, sut.errInternalServer)
+}
+
+func (sut *UserPermissionServiceTestSuite) Test09GrantSuccess() {
+	sut.T().Log("Test09GrantSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int32(3), nil)
+	sut.userPermissionAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserPermissionAudit(models.GrantAction, 6))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully grant permission")
+	sut.permissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test10GrantNotOwnedAdministratorWithTwoFactorSuccess() {
+	sut.T().Log("Test10GrantNotOwnedAdministratorWithTwoFactorSuccess")
+	sut.setSession([]int32{1}, true)
+	sut.grantPermissionRequest.PermissionId = 7
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(7)).Return(sut.createProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(7)).Return(int32(3), nil)
+	sut.userPermissionAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserPermissionAudit(models.GrantAction, 7))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.userPermissionService.Grant(sut.ctx, sut.userId, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *UserPermissionServiceTestSuite) Test11RevokeNotGrantedNotFound() {
+	sut.T().Log("Test11RevokeNotGrantedNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Delete", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int64(0), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.userPermissionService.Revoke(sut.ctx, sut.userId, 6)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user does not have this permission")
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByUserId", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *UserPermissionServiceTestSuite) Test12RevokeSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test12RevokeSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Delete", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int64(1), nil)
+	sut.userPermissionAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.Anything).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "").Return(sut.errInternalServer)
+	httpCode, response := sut.userPermissionService.Revoke(sut.ctx, sut.userId, 6)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *UserPermissionServiceTestSuite) Test13RevokeSuccess() {
+	sut.T().Log("Test13RevokeSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userPermissionRepositoryMock.Mock.On("Delete", sut.tx, sut.ctx, sut.userId, int32(6)).Return(int64(1), nil)
+	sut.userPermissionAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserPermissionAudit(models.RevokeAction, 6))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "").Return(nil)
+	httpCode, response := sut.userPermissionService.Revoke(sut.ctx, sut.userId, 6)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke permission")
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "")
+}
+
+func (sut *UserPermissionServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *UserPermissionServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *UserPermissionServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
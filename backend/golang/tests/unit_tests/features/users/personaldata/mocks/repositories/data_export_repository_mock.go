@@ -0,0 +1,28 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/personaldata/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type DataExportRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *DataExportRepositoryMock) Create(pool *pgxpool.Pool, ctx context.Context, userId int32, createdAt int64) (id int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId, createdAt)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *DataExportRepositoryMock) FindAllByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (dataExports []models.DataExport, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.DataExport), arguments.Error(1)
+}
+
+func (repository *DataExportRepositoryMock) FindArchiveById(pool *pgxpool.Pool, ctx context.Context, id int32, userId int32, now int64) (archive []byte, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, userId, now)
+	return arguments.Get(0).([]byte), arguments.Error(1)
+}
@@ -0,0 +1,319 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/personaldata/models"
+	"backend-golang/features/users/personaldata/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/personaldata/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PersonalDataServiceTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	postgresUtilMock          *mockutils.PostgresUtilMock
+	redisUtilMock             *mockutils.RedisUtilMock
+	validate                  *validator.Validate
+	userRepositoryMock        *mockrepositories.UserRepositoryMock
+	dataExportRepositoryMock  *mockrepositories.DataExportRepositoryMock
+	passwordHasherMock        *mockhelpers.PasswordHasherMock
+	sessionRegistryHelperMock *mockhelpers.SessionRegistryHelperMock
+	personalDataHelperMock    *mockhelpers.PersonalDataHelperMock
+	pool                      *pgxpool.Pool
+	client                    *redis.Client
+	errTimeout                error
+	errInternalServer         error
+	user                      models.User
+	deleteAccountRequest      models.DeleteAccountRequest
+	personalDataService       services.PersonalDataService
+}
+
+func TestPersonalDataServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PersonalDataServiceTestSuite))
+}
+
+func (sut *PersonalDataServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *PersonalDataServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.user = models.User{
+		Id:       pgtype.Int4{Valid: true, Int32: 1},
+		Password: pgtype.Text{Valid: true, String: "hash"},
+	}
+	sut.deleteAccountRequest = models.DeleteAccountRequest{
+		Password: "password",
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.dataExportRepositoryMock = new(mockrepositories.DataExportRepositoryMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.personalDataHelperMock = new(mockhelpers.PersonalDataHelperMock)
+	sut.personalDataService = services.NewPersonalDataService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.dataExportRepositoryMock, sut.passwordHasherMock, sut.sessionRegistryHelperMock, sut.personalDataHelperMock)
+}
+
+func (sut *PersonalDataServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test01CreateDataExportDataExportRepositoryCreateTimeoutError() {
+	sut.T().Log("Test01CreateDataExportDataExportRepositoryCreateTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int32(0), sut.errTimeout)
+	httpCode, response := sut.personalDataService.CreateDataExport(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+	sut.personalDataHelperMock.Mock.AssertNotCalled(sut.T(), "Export", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test02CreateDataExportDataExportRepositoryCreateUniqueViolation() {
+	sut.T().Log("Test02CreateDataExportDataExportRepositoryCreateUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "data_export_pending_unique"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int32(0), errUniqueViolation)
+	httpCode, response := sut.personalDataService.CreateDataExport(sut.ctx)
+	sut.Equal(httpCode, http.StatusConflict)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "a data export is already being written")
+	sut.personalDataHelperMock.Mock.AssertNotCalled(sut.T(), "Export", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test03CreateDataExportSuccess() {
+	sut.T().Log("Test03CreateDataExportSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int32(2), nil)
+	sut.personalDataHelperMock.Mock.On("Export", sut.ctx, int32(2), int32(1))
+	httpCode, response := sut.personalDataService.CreateDataExport(sut.ctx)
+	sut.Equal(httpCode, http.StatusAccepted)
+	dataExportResponse, _ := response.Data.(models.DataExportResponse)
+	sut.Equal(dataExportResponse.Id, int32(2))
+	sut.Equal(dataExportResponse.Status, helpers.DataExportStatusPending)
+	sut.Nil(dataExportResponse.CompletedAt)
+	sut.Equal(response.Errors, nil)
+	sut.personalDataHelperMock.Mock.AssertNumberOfCalls(sut.T(), "Export", 1)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test04FindAllDataExportsDataExportRepositoryFindAllByUserIdInternalServerError() {
+	sut.T().Log("Test04FindAllDataExportsDataExportRepositoryFindAllByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("FindAllByUserId", sut.pool, sut.ctx, int32(1)).Return([]models.DataExport{}, sut.errInternalServer)
+	httpCode, response := sut.personalDataService.FindAllDataExports(sut.ctx)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *PersonalDataServiceTestSuite) Test05FindAllDataExportsSuccess() {
+	sut.T().Log("Test05FindAllDataExportsSuccess")
+	dataExports := []models.DataExport{
+		{
+			Id:          pgtype.Int4{Valid: true, Int32: 2},
+			Status:      pgtype.Text{Valid: true, String: helpers.DataExportStatusReady},
+			CreatedAt:   pgtype.Int8{Valid: true, Int64: 1695095017},
+			CompletedAt: pgtype.Int8{Valid: true, Int64: 1695095018},
+			ExpiresAt:   pgtype.Int8{Valid: true, Int64: 1695699818},
+		},
+		{
+			Id:        pgtype.Int4{Valid: true, Int32: 1},
+			Status:    pgtype.Text{Valid: true, String: helpers.DataExportStatusPending},
+			CreatedAt: pgtype.Int8{Valid: true, Int64: 1695095000},
+		},
+	}
+	completedAt := int64(1695095018)
+	expiresAt := int64(1695699818)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("FindAllByUserId", sut.pool, sut.ctx, int32(1)).Return(dataExports, nil)
+	httpCode, response := sut.personalDataService.FindAllDataExports(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, []models.DataExportResponse{
+		{Id: 2, Status: helpers.DataExportStatusReady, CreatedAt: 1695095017, CompletedAt: &completedAt, ExpiresAt: &expiresAt},
+		{Id: 1, Status: helpers.DataExportStatusPending, CreatedAt: 1695095000},
+	})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test06FindDataExportArchiveDataExportRepositoryFindArchiveByIdNotFound() {
+	sut.T().Log("Test06FindDataExportArchiveDataExportRepositoryFindArchiveByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("FindArchiveById", sut.pool, sut.ctx, int32(2), int32(1), mock.AnythingOfType("int64")).Return([]byte(nil), pgx.ErrNoRows)
+	archive, httpCode, response := sut.personalDataService.FindDataExportArchive(sut.ctx, 2)
+	sut.Nil(archive)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "data export not found")
+}
+
+func (sut *PersonalDataServiceTestSuite) Test07FindDataExportArchiveSuccess() {
+	sut.T().Log("Test07FindDataExportArchiveSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.dataExportRepositoryMock.Mock.On("FindArchiveById", sut.pool, sut.ctx, int32(2), int32(1), mock.AnythingOfType("int64")).Return([]byte(`{"user":{"id":1}}`), nil)
+	archive, httpCode, response := sut.personalDataService.FindDataExportArchive(sut.ctx, 2)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(archive, []byte(`{"user":{"id":1}}`))
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test08RequestAccountDeletionNoPasswordLoginTooOldBadRequest() {
+	sut.T().Log("Test08RequestAccountDeletionNoPasswordLoginTooOldBadRequest")
+	ctx := context.WithValue(sut.ctx, middlewares.AuthenticatedAtKey, time.Now().Add(-10*time.Minute).UnixMilli())
+	sut.deleteAccountRequest.Password = ""
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, ctx, int32(1)).Return(sut.user, nil)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "password")
+	sut.Equal(errorMessages[0].Message, "please input the password or log in again")
+	sut.passwordHasherMock.Mock.AssertNotCalled(sut.T(), "Compare", mock.Anything, mock.Anything)
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateDeletionRequestedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test09RequestAccountDeletionUserRepositoryFindByIdNotFound() {
+	sut.T().Log("Test09RequestAccountDeletionUserRepositoryFindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(models.User{}, pgx.ErrNoRows)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(sut.ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *PersonalDataServiceTestSuite) Test10RequestAccountDeletionPasswordHasherCompareWrongPassword() {
+	sut.T().Log("Test10RequestAccountDeletionPasswordHasherCompareWrongPassword")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.deleteAccountRequest.Password).Return(errors.New("mismatched hash and password"))
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(sut.ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "password")
+	sut.Equal(errorMessages[0].Message, "wrong password")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateDeletionRequestedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByUserId", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test11RequestAccountDeletionSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test11RequestAccountDeletionSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.deleteAccountRequest.Password).Return(nil)
+	sut.userRepositoryMock.Mock.On("UpdateDeletionRequestedAt", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(1), "").Return(sut.errInternalServer)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(sut.ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *PersonalDataServiceTestSuite) Test12RequestAccountDeletionAlreadyRequestedKeepsFirstRequest() {
+	sut.T().Log("Test12RequestAccountDeletionAlreadyRequestedKeepsFirstRequest")
+	sut.user.DeletionRequestedAt = pgtype.Int8{Valid: true, Int64: 1695095017000}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.deleteAccountRequest.Password).Return(nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(1), "").Return(nil)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(sut.ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusAccepted)
+	sut.Equal(response.Data, models.AccountDeletionResponse{DeleteAt: 1695095017000 + (30 * 24 * time.Hour).Milliseconds()})
+	sut.Equal(response.Errors, nil)
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateDeletionRequestedAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test13RequestAccountDeletionSuccess() {
+	sut.T().Log("Test13RequestAccountDeletionSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.deleteAccountRequest.Password).Return(nil)
+	sut.userRepositoryMock.Mock.On("UpdateDeletionRequestedAt", sut.pool, sut.ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(1), "").Return(nil)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(sut.ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusAccepted)
+	accountDeletionResponse, _ := response.Data.(models.AccountDeletionResponse)
+	sut.Greater(accountDeletionResponse.DeleteAt, time.Now().Add(29*24*time.Hour).UnixMilli())
+	sut.Equal(response.Errors, nil)
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "DeleteAllByUserId", 1)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test14CancelAccountDeletionUserRepositoryDeleteDeletionRequestedAtNotFound() {
+	sut.T().Log("Test14CancelAccountDeletionUserRepositoryDeleteDeletionRequestedAtNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("DeleteDeletionRequestedAt", sut.pool, sut.ctx, int32(1)).Return(int64(0), nil)
+	httpCode, response := sut.personalDataService.CancelAccountDeletion(sut.ctx)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "account deletion not found")
+}
+
+func (sut *PersonalDataServiceTestSuite) Test15CancelAccountDeletionSuccess() {
+	sut.T().Log("Test15CancelAccountDeletionSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("DeleteDeletionRequestedAt", sut.pool, sut.ctx, int32(1)).Return(int64(1), nil)
+	httpCode, response := sut.personalDataService.CancelAccountDeletion(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully cancel account deletion"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *PersonalDataServiceTestSuite) Test16RequestAccountDeletionNoPasswordRecentLoginSuccess() {
+	sut.T().Log("Test16RequestAccountDeletionNoPasswordRecentLoginSuccess")
+	ctx := context.WithValue(sut.ctx, middlewares.AuthenticatedAtKey, time.Now().Add(-time.Minute).UnixMilli())
+	sut.deleteAccountRequest.Password = ""
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateDeletionRequestedAt", sut.pool, ctx, int32(1), mock.AnythingOfType("int64")).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, ctx, int32(1), "").Return(nil)
+	httpCode, response := sut.personalDataService.RequestAccountDeletion(ctx, sut.deleteAccountRequest)
+	sut.Equal(httpCode, http.StatusAccepted)
+	sut.Equal(response.Errors, nil)
+	sut.passwordHasherMock.Mock.AssertNotCalled(sut.T(), "Compare", mock.Anything, mock.Anything)
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "DeleteAllByUserId", 1)
+}
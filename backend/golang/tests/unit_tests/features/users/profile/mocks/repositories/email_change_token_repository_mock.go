@@ -0,0 +1,24 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/profile/models"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+)
+
+type EmailChangeTokenRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *EmailChangeTokenRepositoryMock) Create(client *redis.Client, ctx context.Context, tokenHash string, emailChangeToken models.EmailChangeToken, expiration time.Duration) (err error) {
+	arguments := repository.Mock.Called(client, ctx, tokenHash, emailChangeToken, expiration)
+	return arguments.Error(0)
+}
+
+func (repository *EmailChangeTokenRepositoryMock) FindAndDelete(client *redis.Client, ctx context.Context, tokenHash string) (emailChangeToken models.EmailChangeToken, err error) {
+	arguments := repository.Mock.Called(client, ctx, tokenHash)
+	return arguments.Get(0).(models.EmailChangeToken), arguments.Error(1)
+}
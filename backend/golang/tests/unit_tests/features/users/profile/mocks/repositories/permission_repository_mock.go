@@ -0,0 +1,17 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PermissionRepositoryMock) FindPermissionsByIds(pool *pgxpool.Pool, ctx context.Context, ids []int32) (permissions []string, err error) {
+	arguments := repository.Mock.Called(pool, ctx, ids)
+	return arguments.Get(0).([]string), arguments.Error(1)
+}
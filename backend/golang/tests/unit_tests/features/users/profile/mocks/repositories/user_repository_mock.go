@@ -0,0 +1,38 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/profile/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) FindPasswordById(pool *pgxpool.Pool, ctx context.Context, id int32) (password string, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.String(0), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) CountByEmail(pool *pgxpool.Pool, ctx context.Context, email string) (count int, err error) {
+	arguments := repository.Mock.Called(pool, ctx, email)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) UpdateUsername(pool *pgxpool.Pool, ctx context.Context, id int32, username string) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, username)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) UpdateEmail(pool *pgxpool.Pool, ctx context.Context, id int32, email string, emailVerifiedAt int64) (username string, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id, email, emailVerifiedAt)
+	return arguments.String(0), arguments.Error(1)
+}
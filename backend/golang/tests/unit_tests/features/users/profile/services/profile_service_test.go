@@ -0,0 +1,399 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/profile/models"
+	"backend-golang/features/users/profile/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/profile/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProfileServiceTestSuite struct {
+	suite.Suite
+	ctx                            context.Context
+	postgresUtilMock               *mockutils.PostgresUtilMock
+	redisUtilMock                  *mockutils.RedisUtilMock
+	mailerMock                     *mockutils.MailerMock
+	validate                       *validator.Validate
+	userRepositoryMock             *mockrepositories.UserRepositoryMock
+	permissionRepositoryMock       *mockrepositories.PermissionRepositoryMock
+	emailChangeTokenRepositoryMock *mockrepositories.EmailChangeTokenRepositoryMock
+	passwordHasherMock             *mockhelpers.PasswordHasherMock
+	tokenHelperMock                *mockhelpers.TokenHelperMock
+	sessionRegistryHelperMock      *mockhelpers.SessionRegistryHelperMock
+	pool                           *pgxpool.Pool
+	client                         *redis.Client
+	errTimeout                     error
+	errInternalServer              error
+	idPermissions                  []int32
+	user                           models.User
+	updateProfileRequest           models.UpdateProfileRequest
+	confirmEmailChangeRequest      models.ConfirmEmailChangeRequest
+	emailChangeToken               models.EmailChangeToken
+	token                          string
+	profileService                 services.ProfileService
+}
+
+func TestProfileServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileServiceTestSuite))
+}
+
+func (sut *ProfileServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.idPermissions = []int32{1, 2}
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.ctx = context.WithValue(sut.ctx, middlewares.PermissionKey, sut.idPermissions)
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *ProfileServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.user = models.User{
+		Id:        pgtype.Int4{Valid: true, Int32: 1},
+		Username:  pgtype.Text{Valid: true, String: "username"},
+		Email:     pgtype.Text{Valid: true, String: "email@email.com"},
+		CreatedAt: pgtype.Int8{Valid: true, Int64: 1695095017},
+	}
+	sut.updateProfileRequest = models.UpdateProfileRequest{
+		Username: "username2",
+	}
+	sut.confirmEmailChangeRequest = models.ConfirmEmailChangeRequest{
+		Token: "token",
+	}
+	sut.emailChangeToken = models.EmailChangeToken{
+		UserId: 1,
+		Email:  "email2@email.com",
+	}
+	sut.token = "token"
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.mailerMock = new(mockutils.MailerMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.emailChangeTokenRepositoryMock = new(mockrepositories.EmailChangeTokenRepositoryMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.tokenHelperMock = new(mockhelpers.TokenHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.profileService = services.NewProfileService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.permissionRepositoryMock, sut.emailChangeTokenRepositoryMock, sut.passwordHasherMock, sut.tokenHelperMock, sut.sessionRegistryHelperMock, sut.mailerMock)
+}
+
+func (sut *ProfileServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *ProfileServiceTestSuite) Test01FindUserRepositoryFindByIdTimeoutError() {
+	sut.T().Log("Test01FindUserRepositoryFindByIdTimeoutError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(models.User{}, sut.errTimeout)
+	httpCode, response := sut.profileService.Find(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *ProfileServiceTestSuite) Test02FindUserRepositoryFindByIdNotFound() {
+	sut.T().Log("Test02FindUserRepositoryFindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(models.User{}, pgx.ErrNoRows)
+	httpCode, response := sut.profileService.Find(sut.ctx)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *ProfileServiceTestSuite) Test03FindPermissionRepositoryFindPermissionsByIdsInternalServerError() {
+	sut.T().Log("Test03FindPermissionRepositoryFindPermissionsByIdsInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.permissionRepositoryMock.Mock.On("FindPermissionsByIds", sut.pool, sut.ctx, sut.idPermissions).Return([]string{}, sut.errInternalServer)
+	httpCode, response := sut.profileService.Find(sut.ctx)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *ProfileServiceTestSuite) Test04FindSuccess() {
+	sut.T().Log("Test04FindSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.permissionRepositoryMock.Mock.On("FindPermissionsByIds", sut.pool, sut.ctx, sut.idPermissions).Return([]string{"ADMINISTRATOR", "USER"}, nil)
+	httpCode, response := sut.profileService.Find(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, models.ProfileResponse{
+		Id:          1,
+		Username:    "username",
+		Email:       "email@email.com",
+		CreatedAt:   1695095017,
+		Permissions: []string{"ADMINISTRATOR", "USER"},
+	})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *ProfileServiceTestSuite) Test05UpdateValidationError() {
+	sut.T().Log("Test05UpdateValidationError")
+	sut.updateProfileRequest.Username = "user name"
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "please use only uppercase and lowercase letter and number and min 5 and max 8 alphanumeric")
+}
+
+func (sut *ProfileServiceTestSuite) Test06UpdateNothingToUpdateValidationError() {
+	sut.T().Log("Test06UpdateNothingToUpdateValidationError")
+	sut.updateProfileRequest.Username = ""
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "please input the username or the email")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ProfileServiceTestSuite) Test07UpdateUserRepositoryUpdateUsernameUniqueViolation() {
+	sut.T().Log("Test07UpdateUserRepositoryUpdateUsernameUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "users_username_key"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateUsername", sut.pool, sut.ctx, int32(1), sut.updateProfileRequest.Username).Return(int64(0), errUniqueViolation)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "username already exists")
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "UpdateProfileByUserId", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ProfileServiceTestSuite) Test08UpdateSessionRegistryHelperUpdateProfileByUserIdInternalServerError() {
+	sut.T().Log("Test08UpdateSessionRegistryHelperUpdateProfileByUserIdInternalServerError")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateUsername", sut.pool, sut.ctx, int32(1), sut.updateProfileRequest.Username).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("UpdateProfileByUserId", sut.client, sut.ctx, int32(1), sut.updateProfileRequest.Username, sut.user.Email.String).Return(sut.errInternalServer)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *ProfileServiceTestSuite) Test09UpdateUsernameSuccess() {
+	sut.T().Log("Test09UpdateUsernameSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateUsername", sut.pool, sut.ctx, int32(1), sut.updateProfileRequest.Username).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("UpdateProfileByUserId", sut.client, sut.ctx, int32(1), sut.updateProfileRequest.Username, sut.user.Email.String).Return(nil)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update profile"})
+	sut.Equal(response.Errors, nil)
+	sut.tokenHelperMock.Mock.AssertNotCalled(sut.T(), "Generate")
+}
+
+func (sut *ProfileServiceTestSuite) Test10UpdateEmailTakenSameResponse() {
+	sut.T().Log("Test10UpdateEmailTakenSameResponse")
+	sut.updateProfileRequest = models.UpdateProfileRequest{Email: "email2@email.com", Currentpassword: "password"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordById", sut.pool, sut.ctx, int32(1)).Return("hashedPassword", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "hashedPassword", "password").Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.user.Email.String && strings.Contains(mail.Body, sut.updateProfileRequest.Email)
+	})).Return(nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.pool, sut.ctx, sut.updateProfileRequest.Email).Return(1, nil)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update profile, open the link sent to the new email to change it"})
+	sut.Equal(response.Errors, nil)
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateUsername", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.tokenHelperMock.Mock.AssertNotCalled(sut.T(), "Generate")
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *ProfileServiceTestSuite) Test11UpdateEmailChangeTokenRepositoryCreateErrorSameResponse() {
+	sut.T().Log("Test11UpdateEmailChangeTokenRepositoryCreateErrorSameResponse")
+	sut.updateProfileRequest = models.UpdateProfileRequest{Email: "email2@email.com", Currentpassword: "password"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordById", sut.pool, sut.ctx, int32(1)).Return("hashedPassword", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "hashedPassword", "password").Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.user.Email.String && strings.Contains(mail.Body, sut.updateProfileRequest.Email)
+	})).Return(nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.pool, sut.ctx, sut.updateProfileRequest.Email).Return(0, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.emailChangeTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.emailChangeToken, time.Hour).Return(sut.errInternalServer)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update profile, open the link sent to the new email to change it"})
+	sut.Equal(response.Errors, nil)
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *ProfileServiceTestSuite) Test12UpdateUsernameAndEmailSuccess() {
+	sut.T().Log("Test12UpdateUsernameAndEmailSuccess")
+	sut.updateProfileRequest.Email = "email2@email.com"
+	sut.updateProfileRequest.Currentpassword = "password"
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordById", sut.pool, sut.ctx, int32(1)).Return("hashedPassword", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "hashedPassword", "password").Return(nil)
+	sut.userRepositoryMock.Mock.On("UpdateUsername", sut.pool, sut.ctx, int32(1), sut.updateProfileRequest.Username).Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("UpdateProfileByUserId", sut.client, sut.ctx, int32(1), sut.updateProfileRequest.Username, sut.user.Email.String).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.user.Email.String && strings.HasPrefix(mail.Body, "Hi "+sut.updateProfileRequest.Username+",") && strings.Contains(mail.Body, sut.updateProfileRequest.Email)
+	})).Return(nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.pool, sut.ctx, sut.updateProfileRequest.Email).Return(0, nil)
+	sut.tokenHelperMock.Mock.On("Generate").Return(sut.token, nil)
+	sut.emailChangeTokenRepositoryMock.Mock.On("Create", sut.client, sut.ctx, helpers.ToTokenHash(sut.token), sut.emailChangeToken, time.Hour).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.updateProfileRequest.Email && strings.HasPrefix(mail.Body, "Hi "+sut.updateProfileRequest.Username+",") && strings.Contains(mail.Body, "?token="+sut.token)
+	})).Return(nil)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update profile, open the link sent to the new email to change it"})
+	sut.Equal(response.Errors, nil)
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 2)
+}
+
+func (sut *ProfileServiceTestSuite) Test13ConfirmEmailChangeEmailChangeTokenRepositoryFindAndDeleteNotFound() {
+	sut.T().Log("Test13ConfirmEmailChangeEmailChangeTokenRepositoryFindAndDeleteNotFound")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailChangeTokenRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(models.EmailChangeToken{}, redis.Nil)
+	httpCode, response := sut.profileService.ConfirmEmailChange(sut.ctx, sut.confirmEmailChangeRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "token is invalid or expired")
+}
+
+func (sut *ProfileServiceTestSuite) Test14ConfirmEmailChangeUserRepositoryUpdateEmailUniqueViolation() {
+	sut.T().Log("Test14ConfirmEmailChangeUserRepositoryUpdateEmailUniqueViolation")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.emailChangeTokenRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.emailChangeToken, nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateEmail", sut.pool, sut.ctx, int32(1), sut.emailChangeToken.Email, mock.AnythingOfType("int64")).Return("", errUniqueViolation)
+	httpCode, response := sut.profileService.ConfirmEmailChange(sut.ctx, sut.confirmEmailChangeRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "email already exists")
+}
+
+func (sut *ProfileServiceTestSuite) Test15ConfirmEmailChangeUserRepositoryUpdateEmailUserDeleted() {
+	sut.T().Log("Test15ConfirmEmailChangeUserRepositoryUpdateEmailUserDeleted")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.emailChangeTokenRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.emailChangeToken, nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateEmail", sut.pool, sut.ctx, int32(1), sut.emailChangeToken.Email, mock.AnythingOfType("int64")).Return("", pgx.ErrNoRows)
+	httpCode, response := sut.profileService.ConfirmEmailChange(sut.ctx, sut.confirmEmailChangeRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "token")
+	sut.Equal(errorMessages[0].Message, "token is invalid or expired")
+}
+
+func (sut *ProfileServiceTestSuite) Test16ConfirmEmailChangeSuccess() {
+	sut.T().Log("Test16ConfirmEmailChangeSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.emailChangeTokenRepositoryMock.Mock.On("FindAndDelete", sut.client, sut.ctx, helpers.ToTokenHash(sut.token)).Return(sut.emailChangeToken, nil)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("UpdateEmail", sut.pool, sut.ctx, int32(1), sut.emailChangeToken.Email, mock.AnythingOfType("int64")).Return("username", nil)
+	sut.sessionRegistryHelperMock.Mock.On("UpdateProfileByUserId", sut.client, sut.ctx, int32(1), "username", sut.emailChangeToken.Email).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.user.Email.String && strings.Contains(mail.Body, sut.emailChangeToken.Email)
+	})).Return(nil)
+	httpCode, response := sut.profileService.ConfirmEmailChange(sut.ctx, sut.confirmEmailChangeRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully change email"})
+	sut.Equal(response.Errors, nil)
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "UpdateProfileByUserId", 1)
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *ProfileServiceTestSuite) Test17UpdateEmailWithoutCurrentPasswordValidationError() {
+	sut.T().Log("Test17UpdateEmailWithoutCurrentPasswordValidationError")
+	sut.updateProfileRequest = models.UpdateProfileRequest{Email: "email2@email.com"}
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "currentpassword")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindById", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *ProfileServiceTestSuite) Test18UpdateEmailWrongCurrentPassword() {
+	sut.T().Log("Test18UpdateEmailWrongCurrentPassword")
+	sut.updateProfileRequest.Email = "email2@email.com"
+	sut.updateProfileRequest.Currentpassword = "password"
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.userRepositoryMock.Mock.On("FindPasswordById", sut.pool, sut.ctx, int32(1)).Return("hashedPassword", nil)
+	sut.passwordHasherMock.Mock.On("Compare", "hashedPassword", "password").Return(sut.errInternalServer)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "currentpassword")
+	sut.Equal(errorMessages[0].Message, "wrong current password")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateUsername", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	sut.mailerMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (sut *ProfileServiceTestSuite) Test19UpdateSameEmailNoPasswordCheck() {
+	sut.T().Log("Test19UpdateSameEmailNoPasswordCheck")
+	sut.updateProfileRequest = models.UpdateProfileRequest{Email: "Email@Email.com", Currentpassword: "password"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	httpCode, response := sut.profileService.Update(sut.ctx, sut.updateProfileRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully update profile"})
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindPasswordById", mock.Anything, mock.Anything, mock.Anything)
+	sut.mailerMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,28 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/register/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRepositoryMock) CountByUsername(tx pgx.Tx, ctx context.Context, username string) (count int, err error) {
+	arguments := repository.Mock.Called(tx, ctx, username)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) CountByEmail(tx pgx.Tx, ctx context.Context, email string) (count int, err error) {
+	arguments := repository.Mock.Called(tx, ctx, email)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) Create(tx pgx.Tx, ctx context.Context, user models.User) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, user)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
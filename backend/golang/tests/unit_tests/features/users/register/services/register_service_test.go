@@ -0,0 +1,366 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/commons/utils"
+	"backend-golang/features/users/register/models"
+	"backend-golang/features/users/register/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/register/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegisterServiceTestSuite struct {
+	suite.Suite
+	ctx                         context.Context
+	registerRequest             models.RegisterRequest
+	postgresUtilMock            *mockutils.PostgresUtilMock
+	redisUtilMock               *mockutils.RedisUtilMock
+	validate                    *validator.Validate
+	userRepositoryMock          *mockrepositories.UserRepositoryMock
+	passwordHasherMock          *mockhelpers.PasswordHasherMock
+	emailVerificationHelperMock *mockhelpers.EmailVerificationHelperMock
+	mailerMock                  *mockutils.MailerMock
+	tx                          pgx.Tx
+	client                      *redis.Client
+	errTimeout                  error
+	errInternalServer           error
+	registerService             services.RegisterService
+}
+
+func TestRegisterTestSuite(t *testing.T) {
+	suite.Run(t, new(RegisterServiceTestSuite))
+}
+
+func (sut *RegisterServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *RegisterServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.registerRequest = models.RegisterRequest{
+		Username:        "username",
+		Email:           "email@email.com",
+		Password:        "password@A1",
+		Confirmpassword: "password@A1",
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.validate = validator.New()
+	setups.UsernameValidator(sut.validate)
+	setups.PasswordValidator(sut.validate)
+	setups.TelephoneValidator(sut.validate)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.emailVerificationHelperMock = new(mockhelpers.EmailVerificationHelperMock)
+	sut.mailerMock = new(mockutils.MailerMock)
+	sut.registerService = services.NewRegisterService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, sut.passwordHasherMock, sut.emailVerificationHelperMock, sut.mailerMock)
+}
+
+func (sut *RegisterServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterServiceTestSuite) Test01RegisterValidationError() {
+	sut.T().Log("Test01RegisterValidationError")
+	sut.registerRequest = models.RegisterRequest{}
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "is required")
+	sut.Equal(errorMessages[1].Field, "email")
+	sut.Equal(errorMessages[1].Message, "is required")
+	sut.Equal(errorMessages[2].Field, "password")
+	sut.Equal(errorMessages[2].Message, "is required")
+	sut.Equal(errorMessages[3].Field, "confirmpassword")
+	sut.Equal(errorMessages[3].Message, "is required")
+}
+
+func (sut *RegisterServiceTestSuite) Test02RegisterConfirmpasswordValidationError() {
+	sut.T().Log("Test02RegisterConfirmpasswordValidationError")
+	sut.registerRequest.Confirmpassword = "password@A2"
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "confirmpassword")
+	sut.Equal(errorMessages[0].Message, "please input the same value as password")
+}
+
+func (sut *RegisterServiceTestSuite) Test03RegisterBeginTxInternalServerError() {
+	sut.T().Log("Test03RegisterBeginTxInternalServerError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, sut.errInternalServer)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *RegisterServiceTestSuite) Test04RegisterUserRepositoryCountByUsernameTimeoutError() {
+	sut.T().Log("Test04RegisterUserRepositoryCountByUsernameTimeoutError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, sut.errTimeout)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errTimeout).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *RegisterServiceTestSuite) Test05RegisterUserRepositoryCountByEmailInternalServerError() {
+	sut.T().Log("Test05RegisterUserRepositoryCountByEmailInternalServerError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *RegisterServiceTestSuite) Test06RegisterUsernameAlreadyExistsBadRequest() {
+	sut.T().Log("Test06RegisterUsernameAlreadyExistsBadRequest")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(1, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(len(errorMessages), 1)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "username already exists")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email)
+}
+
+func (sut *RegisterServiceTestSuite) Test07RegisterPasswordHasherHashInternalServerError() {
+	sut.T().Log("Test07RegisterPasswordHasherHashInternalServerError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("", sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *RegisterServiceTestSuite) Test08RegisterUserRepositoryCreateUniqueViolationSameResponse() {
+	sut.T().Log("Test08RegisterUserRepositoryCreateUniqueViolationSameResponse")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(0), errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.AnythingOfType("utils.Mail")).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully register")
+	sut.mailerMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *RegisterServiceTestSuite) Test09RegisterCommitOrRollbackInternalServerError() {
+	sut.T().Log("Test09RegisterCommitOrRollbackInternalServerError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(sut.errInternalServer)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+	sut.emailVerificationHelperMock.Mock.AssertNotCalled(sut.T(), "Send", sut.client, sut.ctx, int32(1), sut.registerRequest.Username, sut.registerRequest.Email)
+}
+
+func (sut *RegisterServiceTestSuite) Test10RegisterEmailVerificationHelperSendErrorStillCreated() {
+	sut.T().Log("Test10RegisterEmailVerificationHelperSendErrorStillCreated")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("Send", sut.client, sut.ctx, int32(1), sut.registerRequest.Username, sut.registerRequest.Email).Return(sut.errInternalServer)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully register")
+}
+
+func (sut *RegisterServiceTestSuite) Test11RegisterSuccess() {
+	sut.T().Log("Test11RegisterSuccess")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("Send", sut.client, sut.ctx, int32(1), sut.registerRequest.Username, sut.registerRequest.Email).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully register")
+	sut.emailVerificationHelperMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+func (sut *RegisterServiceTestSuite) Test12RegisterEmailAlreadyExistsSameResponse() {
+	sut.T().Log("Test12RegisterEmailAlreadyExistsSameResponse")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(1, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.MatchedBy(func(mail utils.Mail) bool {
+		return mail.To == sut.registerRequest.Email && mail.Subject == "Someone tried to register with your email"
+	})).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully register")
+	sut.passwordHasherMock.Mock.AssertNumberOfCalls(sut.T(), "Hash", 1)
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, mock.Anything)
+	sut.emailVerificationHelperMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *RegisterServiceTestSuite) Test13RegisterUserRepositoryCreateUsernameUniqueViolationBadRequest() {
+	sut.T().Log("Test13RegisterUserRepositoryCreateUsernameUniqueViolationBadRequest")
+	errUniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "users_username_key"}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(0), errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "username")
+	sut.Equal(errorMessages[0].Message, "username already exists")
+	sut.mailerMock.Mock.AssertNotCalled(sut.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (sut *RegisterServiceTestSuite) Test14RegisterMixedCaseEmailSuccess() {
+	sut.T().Log("Test14RegisterMixedCaseEmailSuccess")
+	email := sut.registerRequest.Email
+	sut.registerRequest.Email = strings.ToUpper(email)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, email).Return(0, nil)
+	sut.passwordHasherMock.Mock.On("Hash", sut.registerRequest.Password).Return("password", nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(func(user models.User) bool {
+		return user.Email.String == email
+	})).Return(int32(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("Send", sut.client, sut.ctx, int32(1), sut.registerRequest.Username, email).Return(nil)
+	httpCode, response := sut.registerService.Register(sut.ctx, sut.registerRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	sut.emailVerificationHelperMock.Mock.AssertNumberOfCalls(sut.T(), "Send", 1)
+}
+
+// Test15 runs the real password hasher with a low cost, the median time of a registration with a taken email must stay
+// within 20% of the median time of a registration with a new email
+func (sut *RegisterServiceTestSuite) Test15RegisterEmailExistsTakesAsLongAsNewEmail() {
+	sut.T().Log("Test15RegisterEmailExistsTakesAsLongAsNewEmail")
+	passwordHasher := helpers.NewPasswordHasher(helpers.PasswordHashConfig{Algorithm: helpers.PasswordHashAlgorithmBcrypt, BcryptCost: 8})
+	takenRegisterRequest := sut.registerRequest
+	takenRegisterRequest.Email = "taken@email.com"
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("CountByUsername", sut.tx, sut.ctx, sut.registerRequest.Username).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, sut.registerRequest.Email).Return(0, nil)
+	sut.userRepositoryMock.Mock.On("CountByEmail", sut.tx, sut.ctx, takenRegisterRequest.Email).Return(1, nil)
+	sut.userRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.AnythingOfType("models.User")).Return(int32(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.emailVerificationHelperMock.Mock.On("Send", sut.client, sut.ctx, int32(1), sut.registerRequest.Username, sut.registerRequest.Email).Return(nil)
+	sut.mailerMock.Mock.On("Send", sut.ctx, mock.AnythingOfType("utils.Mail")).Return(nil)
+	registerService := services.NewRegisterService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.userRepositoryMock, passwordHasher, sut.emailVerificationHelperMock, sut.mailerMock)
+	measure := func(registerRequest models.RegisterRequest) time.Duration {
+		start := time.Now()
+		httpCode, _ := registerService.Register(sut.ctx, registerRequest)
+		sut.Equal(httpCode, http.StatusCreated)
+		return time.Since(start)
+	}
+	median := func(durations []time.Duration) time.Duration {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		return durations[len(durations)/2]
+	}
+
+	measure(sut.registerRequest)
+	measure(takenRegisterRequest)
+	var newEmailDurations, takenEmailDurations []time.Duration
+	for i := 0; i < 25; i++ {
+		newEmailDurations = append(newEmailDurations, measure(sut.registerRequest))
+		takenEmailDurations = append(takenEmailDurations, measure(takenRegisterRequest))
+	}
+	newEmailMedian := median(newEmailDurations)
+	takenEmailMedian := median(takenEmailDurations)
+	difference := newEmailMedian - takenEmailMedian
+	if difference < 0 {
+		difference = -difference
+	}
+	sut.T().Log("new email median " + newEmailMedian.String() + ", taken email median " + takenEmailMedian.String())
+	sut.LessOrEqual(difference, newEmailMedian/5)
+}
+
+func (sut *RegisterServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *RegisterServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *RegisterServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
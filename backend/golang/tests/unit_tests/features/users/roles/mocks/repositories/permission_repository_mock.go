@@ -0,0 +1,23 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type PermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PermissionRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (permission models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.Permission), arguments.Error(1)
+}
+
+func (repository *PermissionRepositoryMock) FindByPermission(pool *pgxpool.Pool, ctx context.Context, permission string) (foundPermission models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, permission)
+	return arguments.Get(0).(models.Permission), arguments.Error(1)
+}
@@ -0,0 +1,34 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type RolePermissionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *RolePermissionRepositoryMock) FindByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (permissions []models.Permission, err error) {
+	arguments := repository.Mock.Called(pool, ctx, roleId)
+	return arguments.Get(0).([]models.Permission), arguments.Error(1)
+}
+
+func (repository *RolePermissionRepositoryMock) FindEffectivePermissionIdsByRoleId(tx pgx.Tx, ctx context.Context, roleId int32) (idPermissions []int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, roleId)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
+
+func (repository *RolePermissionRepositoryMock) Create(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (id int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, roleId, permissionId)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *RolePermissionRepositoryMock) Delete(pool *pgxpool.Pool, ctx context.Context, roleId int32, permissionId int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, roleId, permissionId)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
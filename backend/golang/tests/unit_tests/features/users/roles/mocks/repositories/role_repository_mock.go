@@ -0,0 +1,55 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type RoleRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *RoleRepositoryMock) LockTree(tx pgx.Tx, ctx context.Context) (err error) {
+	arguments := repository.Mock.Called(tx, ctx)
+	return arguments.Error(0)
+}
+
+func (repository *RoleRepositoryMock) FindAll(pool *pgxpool.Pool, ctx context.Context) (roles []models.Role, err error) {
+	arguments := repository.Mock.Called(pool, ctx)
+	return arguments.Get(0).([]models.Role), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (role models.Role, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.Role), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (role models.Role, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).(models.Role), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) FindAncestorIds(tx pgx.Tx, ctx context.Context, id int32) (ancestorIds []int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) Create(tx pgx.Tx, ctx context.Context, role string, parentId pgtype.Int4) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, role, parentId)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) Update(tx pgx.Tx, ctx context.Context, id int32, role string, parentId pgtype.Int4) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id, role, parentId)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *RoleRepositoryMock) Delete(pool *pgxpool.Pool, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
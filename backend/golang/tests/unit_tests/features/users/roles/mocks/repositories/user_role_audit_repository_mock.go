@@ -0,0 +1,18 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRoleAuditRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRoleAuditRepositoryMock) Create(tx pgx.Tx, ctx context.Context, userRoleAudit models.UserRoleAudit) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, userRoleAudit)
+	return arguments.Error(0)
+}
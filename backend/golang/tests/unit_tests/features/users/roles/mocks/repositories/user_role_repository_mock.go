@@ -0,0 +1,34 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/roles/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRoleRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRoleRepositoryMock) FindByUserId(pool *pgxpool.Pool, ctx context.Context, userId int32) (roles []models.Role, err error) {
+	arguments := repository.Mock.Called(pool, ctx, userId)
+	return arguments.Get(0).([]models.Role), arguments.Error(1)
+}
+
+func (repository *UserRoleRepositoryMock) FindUserIdsByRoleId(pool *pgxpool.Pool, ctx context.Context, roleId int32) (userIds []int32, err error) {
+	arguments := repository.Mock.Called(pool, ctx, roleId)
+	return arguments.Get(0).([]int32), arguments.Error(1)
+}
+
+func (repository *UserRoleRepositoryMock) Create(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (id int32, err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, roleId)
+	return arguments.Get(0).(int32), arguments.Error(1)
+}
+
+func (repository *UserRoleRepositoryMock) Delete(tx pgx.Tx, ctx context.Context, userId int32, roleId int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, roleId)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,388 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/roles/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleServiceTestSuite struct {
+	suite.Suite
+	ctx                          context.Context
+	postgresUtilMock             *mockutils.PostgresUtilMock
+	redisUtilMock                *mockutils.RedisUtilMock
+	validate                     *validator.Validate
+	roleRepositoryMock           *mockrepositories.RoleRepositoryMock
+	rolePermissionRepositoryMock *mockrepositories.RolePermissionRepositoryMock
+	permissionRepositoryMock     *mockrepositories.PermissionRepositoryMock
+	userRoleRepositoryMock       *mockrepositories.UserRoleRepositoryMock
+	sessionRegistryHelperMock    *mockhelpers.SessionRegistryHelperMock
+	pool                         *pgxpool.Pool
+	client                       *redis.Client
+	tx                           pgx.Tx
+	errInternalServer            error
+	requestId                    string
+	administrator                models.Permission
+	readProduct                  models.Permission
+	createProduct                models.Permission
+	roles                        []models.Role
+	roleRequest                  models.RoleRequest
+	grantPermissionRequest       models.GrantPermissionRequest
+	roleService                  services.RoleService
+}
+
+func TestRoleTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleServiceTestSuite))
+}
+
+func (sut *RoleServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.requestId = uuid.New().String()
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.errInternalServer = errors.New("internal server error")
+	sut.administrator = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}}
+	sut.readProduct = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 6}, Permission: pgtype.Text{Valid: true, String: "READ_PRODUCT"}}
+	sut.createProduct = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 7}, Permission: pgtype.Text{Valid: true, String: "CREATE_PRODUCT"}}
+}
+
+func (sut *RoleServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.setSession([]int32{2, 6}, false)
+	sut.roles = []models.Role{
+		{Id: pgtype.Int4{Valid: true, Int32: 1}, Role: pgtype.Text{Valid: true, String: "SUPPORT"}},
+		{Id: pgtype.Int4{Valid: true, Int32: 2}, Role: pgtype.Text{Valid: true, String: "CATALOG"}, ParentId: pgtype.Int4{Valid: true, Int32: 1}},
+	}
+	sut.roleRequest = models.RoleRequest{
+		Role:     "WAREHOUSE",
+		ParentId: 1,
+	}
+	sut.grantPermissionRequest = models.GrantPermissionRequest{
+		PermissionId: 6,
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.roleRepositoryMock = new(mockrepositories.RoleRepositoryMock)
+	sut.rolePermissionRepositoryMock = new(mockrepositories.RolePermissionRepositoryMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.userRoleRepositoryMock = new(mockrepositories.UserRoleRepositoryMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.roleService = services.NewRoleService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.roleRepositoryMock, sut.rolePermissionRepositoryMock, sut.permissionRepositoryMock, sut.userRoleRepositoryMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *RoleServiceTestSuite) setSession(idPermissions []int32, twoFactor bool) {
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, sut.requestId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.ctx = context.WithValue(sut.ctx, middlewares.PermissionKey, idPermissions)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.TwoFactorKey, twoFactor)
+}
+
+func (sut *RoleServiceTestSuite) mockLockTree() {
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.roleRepositoryMock.Mock.On("LockTree", sut.tx, sut.ctx).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+}
+
+func (sut *RoleServiceTestSuite) mockParentOwned(parentId int32) {
+	sut.roleRepositoryMock.Mock.On("FindAncestorIds", sut.tx, sut.ctx, parentId).Return([]int32{parentId}, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("FindEffectivePermissionIdsByRoleId", sut.tx, sut.ctx, parentId).Return([]int32{6}, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+}
+
+func (sut *RoleServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *RoleServiceTestSuite) Test01FindAllSuccess() {
+	sut.T().Log("Test01FindAllSuccess")
+	parentId := int32(1)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.roleRepositoryMock.Mock.On("FindAll", sut.pool, sut.ctx).Return(sut.roles, nil)
+	httpCode, response := sut.roleService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, []models.RoleResponse{{Id: 1, Role: "SUPPORT"}, {Id: 2, Role: "CATALOG", ParentId: &parentId}})
+}
+
+func (sut *RoleServiceTestSuite) Test02FindByIdNotFound() {
+	sut.T().Log("Test02FindByIdNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.roleRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(3)).Return(models.Role{}, pgx.ErrNoRows)
+	httpCode, response := sut.roleService.FindById(sut.ctx, 3)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "role not found")
+}
+
+func (sut *RoleServiceTestSuite) Test03FindByIdSuccess() {
+	sut.T().Log("Test03FindByIdSuccess")
+	parentId := int32(1)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.roleRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(2)).Return(sut.roles[1], nil)
+	sut.rolePermissionRepositoryMock.Mock.On("FindByRoleId", sut.pool, sut.ctx, int32(2)).Return([]models.Permission{sut.readProduct}, nil)
+	httpCode, response := sut.roleService.FindById(sut.ctx, 2)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.RoleDetailResponse{Id: 2, Role: "CATALOG", ParentId: &parentId, Permissions: []models.PermissionResponse{{Id: 6, Permission: "READ_PRODUCT"}}})
+}
+
+func (sut *RoleServiceTestSuite) Test04CreateValidationError() {
+	sut.T().Log("Test04CreateValidationError")
+	sut.roleRequest = models.RoleRequest{}
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "role")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *RoleServiceTestSuite) Test05CreateRoleExists() {
+	sut.T().Log("Test05CreateRoleExists")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.mockParentOwned(1)
+	sut.roleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, "WAREHOUSE", pgtype.Int4{Valid: true, Int32: 1}).Return(int32(0), &pgconn.PgError{Code: "23505"})
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "role")
+	sut.Equal(errorMessages[0].Message, "role already exists")
+}
+
+func (sut *RoleServiceTestSuite) Test06CreateParentNotFound() {
+	sut.T().Log("Test06CreateParentNotFound")
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindAncestorIds", sut.tx, sut.ctx, int32(1)).Return([]int32{}, nil)
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "parentId")
+	sut.Equal(errorMessages[0].Message, "parent role not found")
+	sut.roleRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, "WAREHOUSE", pgtype.Int4{Valid: true, Int32: 1})
+}
+
+func (sut *RoleServiceTestSuite) Test07CreateWithoutParentSuccess() {
+	sut.T().Log("Test07CreateWithoutParentSuccess")
+	sut.roleRequest.ParentId = 0
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, "WAREHOUSE", pgtype.Int4{Valid: false, Int32: 0}).Return(int32(3), nil)
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.RoleResponse{Id: 3, Role: "WAREHOUSE"})
+}
+
+func (sut *RoleServiceTestSuite) Test08UpdateParentCycleBadRequest() {
+	sut.T().Log("Test08UpdateParentCycleBadRequest")
+	sut.roleRequest = models.RoleRequest{Role: "SUPPORT", ParentId: 2}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.roles[0], nil)
+	sut.roleRepositoryMock.Mock.On("FindAncestorIds", sut.tx, sut.ctx, int32(2)).Return([]int32{2, 1}, nil)
+	httpCode, response := sut.roleService.Update(sut.ctx, 1, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "parentId")
+	sut.Equal(errorMessages[0].Message, "parent role cannot inherit from this role")
+	sut.roleRepositoryMock.Mock.AssertNotCalled(sut.T(), "Update", sut.tx, sut.ctx, int32(1), "SUPPORT", pgtype.Int4{Valid: true, Int32: 2})
+}
+
+func (sut *RoleServiceTestSuite) Test09UpdateParentNotOwnedForbidden() {
+	sut.T().Log("Test09UpdateParentNotOwnedForbidden")
+	sut.roleRequest = models.RoleRequest{Role: "WAREHOUSE", ParentId: 2}
+	warehouse := models.Role{Id: pgtype.Int4{Valid: true, Int32: 3}, Role: pgtype.Text{Valid: true, String: "WAREHOUSE"}}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(3)).Return(warehouse, nil)
+	sut.roleRepositoryMock.Mock.On("FindAncestorIds", sut.tx, sut.ctx, int32(2)).Return([]int32{2, 1}, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("FindEffectivePermissionIdsByRoleId", sut.tx, sut.ctx, int32(2)).Return([]int32{6, 7}, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.roleService.Update(sut.ctx, 3, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot inherit from a role with permissions you do not have")
+}
+
+func (sut *RoleServiceTestSuite) Test10UpdateParentRemovedLogsOutUsers() {
+	sut.T().Log("Test10UpdateParentRemovedLogsOutUsers")
+	sut.roleRequest = models.RoleRequest{Role: "CATALOG"}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(2)).Return(sut.roles[1], nil)
+	sut.roleRepositoryMock.Mock.On("Update", sut.tx, sut.ctx, int32(2), "CATALOG", pgtype.Int4{Valid: false, Int32: 0}).Return(int64(1), nil)
+	sut.userRoleRepositoryMock.Mock.On("FindUserIdsByRoleId", sut.pool, sut.ctx, int32(2)).Return([]int32{4, 5}, nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, mock.Anything, "").Return(nil)
+	httpCode, response := sut.roleService.Update(sut.ctx, 2, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.RoleResponse{Id: 2, Role: "CATALOG"})
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, int32(4), "")
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, int32(5), "")
+}
+
+func (sut *RoleServiceTestSuite) Test11UpdateSameParentSuccess() {
+	sut.T().Log("Test11UpdateSameParentSuccess")
+	parentId := int32(1)
+	sut.roleRequest = models.RoleRequest{Role: "CATALOG_TEAM", ParentId: 1}
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(2)).Return(sut.roles[1], nil)
+	sut.roleRepositoryMock.Mock.On("Update", sut.tx, sut.ctx, int32(2), "CATALOG_TEAM", pgtype.Int4{Valid: true, Int32: 1}).Return(int64(1), nil)
+	httpCode, response := sut.roleService.Update(sut.ctx, 2, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.RoleResponse{Id: 2, Role: "CATALOG_TEAM", ParentId: &parentId})
+	sut.roleRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindAncestorIds", sut.tx, sut.ctx, int32(1))
+	sut.userRoleRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindUserIdsByRoleId", sut.pool, sut.ctx, int32(2))
+}
+
+func (sut *RoleServiceTestSuite) Test12DeleteStillInUseBadRequest() {
+	sut.T().Log("Test12DeleteStillInUseBadRequest")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.roleRepositoryMock.Mock.On("Delete", sut.pool, sut.ctx, int32(1)).Return(int64(0), &pgconn.PgError{Code: "23503"})
+	httpCode, response := sut.roleService.Delete(sut.ctx, 1)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "role is still assigned or inherited, unassign it first")
+}
+
+func (sut *RoleServiceTestSuite) Test13GrantPermissionNotOwnedForbidden() {
+	sut.T().Log("Test13GrantPermissionNotOwnedForbidden")
+	sut.grantPermissionRequest.PermissionId = 7
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(7)).Return(sut.createProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.roleService.GrantPermission(sut.ctx, 2, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot grant or revoke a permission you do not have")
+	sut.rolePermissionRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.pool, sut.ctx, int32(2), int32(7))
+}
+
+func (sut *RoleServiceTestSuite) Test14GrantPermissionRoleNotFound() {
+	sut.T().Log("Test14GrantPermissionRoleNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, int32(3), int32(6)).Return(int32(0), &pgconn.PgError{Code: "23503"})
+	httpCode, response := sut.roleService.GrantPermission(sut.ctx, 3, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "role not found")
+}
+
+func (sut *RoleServiceTestSuite) Test15GrantPermissionAdministratorWithTwoFactorSuccess() {
+	sut.T().Log("Test15GrantPermissionAdministratorWithTwoFactorSuccess")
+	sut.setSession([]int32{1}, true)
+	sut.grantPermissionRequest.PermissionId = 7
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(7)).Return(sut.createProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("Create", sut.pool, sut.ctx, int32(2), int32(7)).Return(int32(4), nil)
+	httpCode, response := sut.roleService.GrantPermission(sut.ctx, 2, sut.grantPermissionRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully grant permission")
+}
+
+func (sut *RoleServiceTestSuite) Test16RevokePermissionNotGrantedNotFound() {
+	sut.T().Log("Test16RevokePermissionNotGrantedNotFound")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("Delete", sut.pool, sut.ctx, int32(2), int32(6)).Return(int64(0), nil)
+	httpCode, response := sut.roleService.RevokePermission(sut.ctx, 2, 6)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "role does not have this permission")
+	sut.userRoleRepositoryMock.Mock.AssertNotCalled(sut.T(), "FindUserIdsByRoleId", sut.pool, sut.ctx, int32(2))
+}
+
+func (sut *RoleServiceTestSuite) Test17RevokePermissionSuccess() {
+	sut.T().Log("Test17RevokePermissionSuccess")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.permissionRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(6)).Return(sut.readProduct, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("Delete", sut.pool, sut.ctx, int32(1), int32(6)).Return(int64(1), nil)
+	sut.userRoleRepositoryMock.Mock.On("FindUserIdsByRoleId", sut.pool, sut.ctx, int32(1)).Return([]int32{4, 5}, nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, mock.Anything, "").Return(nil)
+	httpCode, response := sut.roleService.RevokePermission(sut.ctx, 1, 6)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke permission")
+	sut.sessionRegistryHelperMock.Mock.AssertNumberOfCalls(sut.T(), "DeleteAllByUserId", 2)
+}
+
+func (sut *RoleServiceTestSuite) Test18CreateParentNotOwnedForbidden() {
+	sut.T().Log("Test18CreateParentNotOwnedForbidden")
+	sut.roleRequest.ParentId = 2
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.roleRepositoryMock.Mock.On("FindAncestorIds", sut.tx, sut.ctx, int32(2)).Return([]int32{2, 1}, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("FindEffectivePermissionIdsByRoleId", sut.tx, sut.ctx, int32(2)).Return([]int32{6, 7}, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot inherit from a role with permissions you do not have")
+	sut.roleRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, "WAREHOUSE", pgtype.Int4{Valid: true, Int32: 2})
+}
+
+func (sut *RoleServiceTestSuite) Test19CreateWithParentSuccess() {
+	sut.T().Log("Test19CreateWithParentSuccess")
+	parentId := int32(1)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.mockLockTree()
+	sut.mockParentOwned(1)
+	sut.roleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, "WAREHOUSE", pgtype.Int4{Valid: true, Int32: 1}).Return(int32(3), nil)
+	httpCode, response := sut.roleService.Create(sut.ctx, sut.roleRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, models.RoleResponse{Id: 3, Role: "WAREHOUSE", ParentId: &parentId})
+	sut.roleRepositoryMock.Mock.AssertCalled(sut.T(), "LockTree", sut.tx, sut.ctx)
+}
+
+func (sut *RoleServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *RoleServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *RoleServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
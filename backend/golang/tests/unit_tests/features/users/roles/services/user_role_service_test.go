@@ -0,0 +1,265 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/roles/models"
+	"backend-golang/features/users/roles/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/roles/mocks/repositories"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserRoleServiceTestSuite struct {
+	suite.Suite
+	ctx                          context.Context
+	postgresUtilMock             *mockutils.PostgresUtilMock
+	redisUtilMock                *mockutils.RedisUtilMock
+	validate                     *validator.Validate
+	roleRepositoryMock           *mockrepositories.RoleRepositoryMock
+	rolePermissionRepositoryMock *mockrepositories.RolePermissionRepositoryMock
+	permissionRepositoryMock     *mockrepositories.PermissionRepositoryMock
+	userRoleRepositoryMock       *mockrepositories.UserRoleRepositoryMock
+	userRoleAuditRepositoryMock  *mockrepositories.UserRoleAuditRepositoryMock
+	sessionRegistryHelperMock    *mockhelpers.SessionRegistryHelperMock
+	pool                         *pgxpool.Pool
+	tx                           pgx.Tx
+	client                       *redis.Client
+	requestId                    string
+	actorId                      int32
+	userId                       int32
+	administrator                models.Permission
+	catalog                      models.Role
+	assignRoleRequest            models.AssignRoleRequest
+	userRoleService              services.UserRoleService
+}
+
+func TestUserRoleTestSuite(t *testing.T) {
+	suite.Run(t, new(UserRoleServiceTestSuite))
+}
+
+func (sut *UserRoleServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.requestId = uuid.New().String()
+	sut.actorId = 1
+	sut.userId = 2
+	sut.validate = setups.SetValidator()
+	sut.pool = &pgxpool.Pool{}
+	sut.tx = &pgxpool.Tx{}
+	sut.client = &redis.Client{}
+	sut.administrator = models.Permission{Id: pgtype.Int4{Valid: true, Int32: 1}, Permission: pgtype.Text{Valid: true, String: "ADMINISTRATOR"}}
+	sut.catalog = models.Role{Id: pgtype.Int4{Valid: true, Int32: 2}, Role: pgtype.Text{Valid: true, String: "CATALOG"}, ParentId: pgtype.Int4{Valid: true, Int32: 1}}
+}
+
+func (sut *UserRoleServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.setSession([]int32{2, 6, 7}, false)
+	sut.assignRoleRequest = models.AssignRoleRequest{
+		RoleId: 2,
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.roleRepositoryMock = new(mockrepositories.RoleRepositoryMock)
+	sut.rolePermissionRepositoryMock = new(mockrepositories.RolePermissionRepositoryMock)
+	sut.permissionRepositoryMock = new(mockrepositories.PermissionRepositoryMock)
+	sut.userRoleRepositoryMock = new(mockrepositories.UserRoleRepositoryMock)
+	sut.userRoleAuditRepositoryMock = new(mockrepositories.UserRoleAuditRepositoryMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.userRoleService = services.NewUserRoleService(sut.postgresUtilMock, sut.redisUtilMock, sut.validate, sut.roleRepositoryMock, sut.rolePermissionRepositoryMock, sut.permissionRepositoryMock, sut.userRoleRepositoryMock, sut.userRoleAuditRepositoryMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *UserRoleServiceTestSuite) setSession(idPermissions []int32, twoFactor bool) {
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, sut.requestId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, sut.actorId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.PermissionKey, idPermissions)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.TwoFactorKey, twoFactor)
+}
+
+func (sut *UserRoleServiceTestSuite) mockRoleManageable(idPermissions []int32) {
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(2)).Return(sut.catalog, nil)
+	sut.rolePermissionRepositoryMock.Mock.On("FindEffectivePermissionIdsByRoleId", sut.tx, sut.ctx, int32(2)).Return(idPermissions, nil)
+	sut.permissionRepositoryMock.Mock.On("FindByPermission", sut.pool, sut.ctx, "ADMINISTRATOR").Return(sut.administrator, nil)
+}
+
+func (sut *UserRoleServiceTestSuite) matchUserRoleAudit(action string) func(models.UserRoleAudit) bool {
+	return func(userRoleAudit models.UserRoleAudit) bool {
+		return userRoleAudit.UserId.Int32 == sut.userId && userRoleAudit.RoleId.Int32 == 2 && userRoleAudit.Action.String == action && userRoleAudit.ActorId.Int32 == sut.actorId && userRoleAudit.RequestId.String == sut.requestId && userRoleAudit.CreatedAt.Int64 > 0
+	}
+}
+
+func (sut *UserRoleServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *UserRoleServiceTestSuite) Test01FindAllByUserIdSuccess() {
+	sut.T().Log("Test01FindAllByUserIdSuccess")
+	parentId := int32(1)
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRoleRepositoryMock.Mock.On("FindByUserId", sut.pool, sut.ctx, sut.userId).Return([]models.Role{sut.catalog}, nil)
+	httpCode, response := sut.userRoleService.FindAllByUserId(sut.ctx, sut.userId)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sut.Equal(response.Data, []models.RoleResponse{{Id: 2, Role: "CATALOG", ParentId: &parentId}})
+}
+
+func (sut *UserRoleServiceTestSuite) Test02AssignValidationError() {
+	sut.T().Log("Test02AssignValidationError")
+	sut.assignRoleRequest = models.AssignRoleRequest{}
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "roleId")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *UserRoleServiceTestSuite) Test03AssignRoleNotFound() {
+	sut.T().Log("Test03AssignRoleNotFound")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.roleRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(2)).Return(models.Role{}, pgx.ErrNoRows)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "role not found")
+}
+
+func (sut *UserRoleServiceTestSuite) Test04AssignInheritedPermissionNotOwnedForbidden() {
+	sut.T().Log("Test04AssignInheritedPermissionNotOwnedForbidden")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.mockRoleManageable([]int32{6, 7, 8})
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot assign or unassign a role with permissions you do not have")
+	sut.userRoleRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, sut.userId, int32(2))
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "CommitOrRollback", sut.tx, nil)
+}
+
+func (sut *UserRoleServiceTestSuite) Test05AssignAdministratorRoleWithoutTwoFactorForbidden() {
+	sut.T().Log("Test05AssignAdministratorRoleWithoutTwoFactorForbidden")
+	sut.setSession([]int32{1, 6, 7}, false)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.mockRoleManageable([]int32{1, 6})
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusForbidden)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "cannot assign or unassign a role with permissions you do not have")
+}
+
+func (sut *UserRoleServiceTestSuite) Test06AssignAlreadyAssignedBadRequest() {
+	sut.T().Log("Test06AssignAlreadyAssignedBadRequest")
+	errUniqueViolation := &pgconn.PgError{Code: "23505"}
+	sut.mockRoleManageable([]int32{6, 7})
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int32(0), errUniqueViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errUniqueViolation).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user already has this role")
+	sut.userRoleAuditRepositoryMock.Mock.AssertNotCalled(sut.T(), "Create", sut.tx, sut.ctx, mock.Anything)
+}
+
+func (sut *UserRoleServiceTestSuite) Test07AssignUserNotFound() {
+	sut.T().Log("Test07AssignUserNotFound")
+	errForeignKeyViolation := &pgconn.PgError{Code: "23503"}
+	sut.mockRoleManageable([]int32{6, 7})
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int32(0), errForeignKeyViolation)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, errForeignKeyViolation).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusNotFound)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user not found")
+}
+
+func (sut *UserRoleServiceTestSuite) Test08AssignSuccess() {
+	sut.T().Log("Test08AssignSuccess")
+	sut.mockRoleManageable([]int32{6, 7})
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int32(3), nil)
+	sut.userRoleAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserRoleAudit(models.AssignAction))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully assign role")
+}
+
+func (sut *UserRoleServiceTestSuite) Test09AssignAdministratorWithTwoFactorSuccess() {
+	sut.T().Log("Test09AssignAdministratorWithTwoFactorSuccess")
+	sut.setSession([]int32{1}, true)
+	sut.mockRoleManageable([]int32{1, 6, 8})
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int32(3), nil)
+	sut.userRoleAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserRoleAudit(models.AssignAction))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.userRoleService.Assign(sut.ctx, sut.userId, sut.assignRoleRequest)
+	sut.Equal(httpCode, http.StatusCreated)
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *UserRoleServiceTestSuite) Test10UnassignNotAssignedNotFound() {
+	sut.T().Log("Test10UnassignNotAssignedNotFound")
+	sut.mockRoleManageable([]int32{6, 7})
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Delete", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int64(0), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.userRoleService.Unassign(sut.ctx, sut.userId, 2)
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "user does not have this role")
+	sut.sessionRegistryHelperMock.Mock.AssertNotCalled(sut.T(), "DeleteAllByUserId", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (sut *UserRoleServiceTestSuite) Test11UnassignSuccess() {
+	sut.T().Log("Test11UnassignSuccess")
+	sut.mockRoleManageable([]int32{6, 7})
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRoleRepositoryMock.Mock.On("Delete", sut.tx, sut.ctx, sut.userId, int32(2)).Return(int64(1), nil)
+	sut.userRoleAuditRepositoryMock.Mock.On("Create", sut.tx, sut.ctx, mock.MatchedBy(sut.matchUserRoleAudit(models.UnassignAction))).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "").Return(nil)
+	httpCode, response := sut.userRoleService.Unassign(sut.ctx, sut.userId, 2)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully unassign role")
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "DeleteAllByUserId", sut.client, sut.ctx, sut.userId, "")
+}
+
+func (sut *UserRoleServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *UserRoleServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *UserRoleServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
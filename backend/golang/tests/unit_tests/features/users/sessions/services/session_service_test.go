@@ -0,0 +1,175 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/features/users/sessions/models"
+	"backend-golang/features/users/sessions/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionServiceTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	redisUtilMock             *mockutils.RedisUtilMock
+	redisHelperMock           *mockhelpers.RedisHelperMock
+	sessionRegistryHelperMock *mockhelpers.SessionRegistryHelperMock
+	client                    *redis.Client
+	errTimeout                error
+	errInternalServer         error
+	userId                    int32
+	sessionId                 string
+	sessionInfos              []helpers.SessionInfo
+	sessionService            services.SessionService
+}
+
+func TestSessionTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionServiceTestSuite))
+}
+
+func (sut *SessionServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.userId = 1
+	sut.sessionId = "sessionId"
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, sut.userId)
+	sut.ctx = context.WithValue(sut.ctx, middlewares.SessionIdKey, sut.sessionId)
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+}
+
+func (sut *SessionServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.sessionInfos = []helpers.SessionInfo{
+		{SessionId: "otherSessionId", CreatedAt: 1719496855216, UserAgent: "curl/8.0", Ip: "10.0.0.1"},
+		{SessionId: sut.sessionId, CreatedAt: 1719496900000, UserAgent: "Mozilla/5.0", Ip: "127.0.0.1"},
+	}
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.redisHelperMock = new(mockhelpers.RedisHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.sessionService = services.NewSessionService(sut.redisUtilMock, sut.redisHelperMock, sut.sessionRegistryHelperMock)
+}
+
+func (sut *SessionServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *SessionServiceTestSuite) Test01FindAllSessionRegistryHelperFindAllByUserIdTimeoutError() {
+	sut.T().Log("Test01FindAllSessionRegistryHelperFindAllByUserIdTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, sut.ctx, sut.userId).Return([]helpers.SessionInfo{}, sut.errTimeout)
+	httpCode, response := sut.sessionService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *SessionServiceTestSuite) Test02FindAllSuccess() {
+	sut.T().Log("Test02FindAllSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, sut.ctx, sut.userId).Return(sut.sessionInfos, nil)
+	httpCode, response := sut.sessionService.FindAll(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	sessionResponses, _ := response.Data.([]models.SessionResponse)
+	sut.Equal(len(sessionResponses), 2)
+	sut.Equal(sessionResponses[0].Id, helpers.ToSessionPublicId(sut.sessionId))
+	sut.Equal(sessionResponses[0].Current, true)
+	sut.Equal(sessionResponses[0].UserAgent, "Mozilla/5.0")
+	sut.Equal(sessionResponses[1].Id, helpers.ToSessionPublicId("otherSessionId"))
+	sut.Equal(sessionResponses[1].Current, false)
+}
+
+func (sut *SessionServiceTestSuite) Test03DeleteNotFound() {
+	sut.T().Log("Test03DeleteNotFound")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, sut.ctx, sut.userId).Return(sut.sessionInfos, nil)
+	httpCode, response := sut.sessionService.Delete(sut.ctx, "unknown")
+	sut.Equal(httpCode, http.StatusNotFound)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "session not found")
+}
+
+func (sut *SessionServiceTestSuite) Test04DeleteRedisHelperDelInternalServerError() {
+	sut.T().Log("Test04DeleteRedisHelperDelInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, sut.ctx, sut.userId).Return(sut.sessionInfos, nil)
+	sut.redisHelperMock.Mock.On("Del", sut.client, sut.ctx, "otherSessionId").Return(int64(0), sut.errInternalServer)
+	httpCode, response := sut.sessionService.Delete(sut.ctx, helpers.ToSessionPublicId("otherSessionId"))
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *SessionServiceTestSuite) Test05DeleteSuccess() {
+	sut.T().Log("Test05DeleteSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("FindAllByUserId", sut.client, sut.ctx, sut.userId).Return(sut.sessionInfos, nil)
+	sut.redisHelperMock.Mock.On("Del", sut.client, sut.ctx, "otherSessionId").Return(int64(1), nil)
+	sut.sessionRegistryHelperMock.Mock.On("Unregister", sut.client, sut.ctx, sut.userId, "otherSessionId").Return(nil)
+	httpCode, response := sut.sessionService.Delete(sut.ctx, helpers.ToSessionPublicId("otherSessionId"))
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke session")
+}
+
+func (sut *SessionServiceTestSuite) Test06DeleteOthersSessionRegistryHelperDeleteAllByUserIdInternalServerError() {
+	sut.T().Log("Test06DeleteOthersSessionRegistryHelperDeleteAllByUserIdInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, sut.sessionId).Return(sut.errInternalServer)
+	httpCode, response := sut.sessionService.DeleteOthers(sut.ctx)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *SessionServiceTestSuite) Test07DeleteOthersSuccess() {
+	sut.T().Log("Test07DeleteOthersSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, sut.userId, sut.sessionId).Return(nil)
+	httpCode, response := sut.sessionService.DeleteOthers(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke other sessions")
+}
+
+func (sut *SessionServiceTestSuite) Test08DeleteAllByUserIdSuccess() {
+	sut.T().Log("Test08DeleteAllByUserIdSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.sessionRegistryHelperMock.Mock.On("DeleteAllByUserId", sut.client, sut.ctx, int32(2), "").Return(nil)
+	httpCode, response := sut.sessionService.DeleteAllByUserId(sut.ctx, 2)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke user sessions")
+}
+
+func (sut *SessionServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *SessionServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *SessionServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
@@ -0,0 +1,221 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/features/users/token/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenServiceTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	redisUtilMock             *mockutils.RedisUtilMock
+	uuidHelperMock            *mockhelpers.UuidHelperMock
+	sessionRegistryHelperMock *mockhelpers.SessionRegistryHelperMock
+	jwtHelperMock             *mockhelpers.JwtHelperMock
+	tokenFamilyHelperMock     *mockhelpers.TokenFamilyHelperMock
+	client                    *redis.Client
+	errTimeout                error
+	errInternalServer         error
+	refreshToken              string
+	refreshTokenClaims        helpers.RefreshTokenClaims
+	session                   helpers.Session
+	tokenService              services.TokenService
+}
+
+func TestTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenServiceTestSuite))
+}
+
+func (sut *TokenServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.client = &redis.Client{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+	sut.refreshToken = "refreshToken"
+}
+
+func (sut *TokenServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.refreshTokenClaims = helpers.RefreshTokenClaims{
+		Type:     "refresh",
+		FamilyId: "familyId",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      "refreshTokenId",
+			Subject: "1",
+		},
+	}
+	sut.session = helpers.Session{
+		Id:            1,
+		Username:      "username",
+		Email:         "email@email.com",
+		IdPermissions: []int32{3},
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+	sut.redisUtilMock = new(mockutils.RedisUtilMock)
+	sut.uuidHelperMock = new(mockhelpers.UuidHelperMock)
+	sut.sessionRegistryHelperMock = new(mockhelpers.SessionRegistryHelperMock)
+	sut.jwtHelperMock = new(mockhelpers.JwtHelperMock)
+	sut.tokenFamilyHelperMock = new(mockhelpers.TokenFamilyHelperMock)
+	sut.tokenService = services.NewTokenService(sut.redisUtilMock, sut.uuidHelperMock, sut.sessionRegistryHelperMock, sut.jwtHelperMock, sut.tokenFamilyHelperMock)
+}
+
+func (sut *TokenServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *TokenServiceTestSuite) Test01RefreshInvalidRefreshToken() {
+	sut.T().Log("Test01RefreshInvalidRefreshToken")
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(helpers.RefreshTokenClaims{}, errors.New("token is expired"))
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusUnauthorized)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "refresh token is invalid or expired")
+}
+
+func (sut *TokenServiceTestSuite) Test02RefreshTokenFamilyHelperRotateTimeoutError() {
+	sut.T().Log("Test02RefreshTokenFamilyHelperRotateTimeoutError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("newRefreshTokenId")
+	sut.tokenFamilyHelperMock.Mock.On("Rotate", sut.client, sut.ctx, "familyId", "refreshTokenId", "newRefreshTokenId").Return(int64(0), sut.errTimeout)
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *TokenServiceTestSuite) Test03RefreshTokenFamilyNotFound() {
+	sut.T().Log("Test03RefreshTokenFamilyNotFound")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("newRefreshTokenId")
+	sut.tokenFamilyHelperMock.Mock.On("Rotate", sut.client, sut.ctx, "familyId", "refreshTokenId", "newRefreshTokenId").Return(helpers.TokenFamilyNotFound, nil)
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusUnauthorized)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "refresh token is invalid or expired")
+}
+
+func (sut *TokenServiceTestSuite) Test04RefreshTokenReused() {
+	sut.T().Log("Test04RefreshTokenReused")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("newRefreshTokenId")
+	sut.tokenFamilyHelperMock.Mock.On("Rotate", sut.client, sut.ctx, "familyId", "refreshTokenId", "newRefreshTokenId").Return(helpers.TokenFamilyReused, nil)
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusUnauthorized)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "refresh token is invalid or expired")
+	sut.tokenFamilyHelperMock.Mock.AssertNotCalled(sut.T(), "FindSession", sut.client, sut.ctx, "familyId")
+}
+
+func (sut *TokenServiceTestSuite) Test05RefreshTokenFamilyHelperFindSessionNotFound() {
+	sut.T().Log("Test05RefreshTokenFamilyHelperFindSessionNotFound")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("newRefreshTokenId")
+	sut.tokenFamilyHelperMock.Mock.On("Rotate", sut.client, sut.ctx, "familyId", "refreshTokenId", "newRefreshTokenId").Return(helpers.TokenFamilyRotated, nil)
+	sut.tokenFamilyHelperMock.Mock.On("FindSession", sut.client, sut.ctx, "familyId").Return(helpers.Session{}, redis.Nil)
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusUnauthorized)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "refresh token is invalid or expired")
+}
+
+func (sut *TokenServiceTestSuite) Test06RefreshSuccess() {
+	sut.T().Log("Test06RefreshSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.uuidHelperMock.Mock.On("String").Return("newRefreshTokenId").Once()
+	sut.uuidHelperMock.Mock.On("String").Return("newAccessTokenId").Once()
+	sut.tokenFamilyHelperMock.Mock.On("Rotate", sut.client, sut.ctx, "familyId", "refreshTokenId", "newRefreshTokenId").Return(helpers.TokenFamilyRotated, nil)
+	sut.tokenFamilyHelperMock.Mock.On("FindSession", sut.client, sut.ctx, "familyId").Return(sut.session, nil)
+	sut.jwtHelperMock.Mock.On("SignAccessToken", mock.MatchedBy(func(claims helpers.AccessTokenClaims) bool {
+		return claims.ID == "newAccessTokenId" && claims.FamilyId == "familyId" && claims.Subject == "1" && claims.Username == "username" && len(claims.IdPermissions) == 1
+	})).Return("newAccessToken", nil)
+	sut.jwtHelperMock.Mock.On("SignRefreshToken", mock.MatchedBy(func(claims helpers.RefreshTokenClaims) bool {
+		return claims.ID == "newRefreshTokenId" && claims.FamilyId == "familyId" && claims.Subject == "1"
+	})).Return("newRefreshToken", nil)
+	httpCode, response := sut.tokenService.Refresh(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	tokenResponse, _ := response.Data.(helpers.TokenResponse)
+	sut.Equal(tokenResponse.AccessToken, "newAccessToken")
+	sut.Equal(tokenResponse.RefreshToken, "newRefreshToken")
+	sut.Equal(tokenResponse.TokenType, "Bearer")
+}
+
+func (sut *TokenServiceTestSuite) Test07RevokeInvalidRefreshTokenSuccess() {
+	sut.T().Log("Test07RevokeInvalidRefreshTokenSuccess")
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(helpers.RefreshTokenClaims{}, errors.New("signature is invalid"))
+	httpCode, response := sut.tokenService.Revoke(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke token")
+	sut.tokenFamilyHelperMock.Mock.AssertNotCalled(sut.T(), "Delete", sut.client, sut.ctx, mock.Anything)
+}
+
+func (sut *TokenServiceTestSuite) Test08RevokeTokenFamilyHelperDeleteInternalServerError() {
+	sut.T().Log("Test08RevokeTokenFamilyHelperDeleteInternalServerError")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Delete", sut.client, sut.ctx, "familyId").Return(sut.errInternalServer)
+	httpCode, response := sut.tokenService.Revoke(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *TokenServiceTestSuite) Test09RevokeSuccess() {
+	sut.T().Log("Test09RevokeSuccess")
+	sut.redisUtilMock.Mock.On("GetClient").Return(sut.client)
+	sut.jwtHelperMock.Mock.On("ParseRefreshToken", sut.refreshToken).Return(sut.refreshTokenClaims, nil)
+	sut.tokenFamilyHelperMock.Mock.On("Delete", sut.client, sut.ctx, "familyId").Return(nil)
+	sut.sessionRegistryHelperMock.Mock.On("Unregister", sut.client, sut.ctx, int32(1), "tokenFamily:familyId").Return(nil)
+	httpCode, response := sut.tokenService.Revoke(sut.ctx, sut.refreshToken)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	responseMessage, _ := response.Data.(helpers.ResponseMessage)
+	sut.Equal(responseMessage.Message, "successfully revoke token")
+	sut.sessionRegistryHelperMock.Mock.AssertCalled(sut.T(), "Unregister", sut.client, sut.ctx, int32(1), "tokenFamily:familyId")
+}
+
+func (sut *TokenServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *TokenServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *TokenServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}
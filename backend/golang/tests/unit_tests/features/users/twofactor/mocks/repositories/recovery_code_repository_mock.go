@@ -0,0 +1,22 @@
+package mockrepositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+type RecoveryCodeRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *RecoveryCodeRepositoryMock) CreateMany(tx pgx.Tx, ctx context.Context, userId int32, codeHashes []string) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId, codeHashes)
+	return arguments.Error(0)
+}
+
+func (repository *RecoveryCodeRepositoryMock) DeleteByUserId(tx pgx.Tx, ctx context.Context, userId int32) (err error) {
+	arguments := repository.Mock.Called(tx, ctx, userId)
+	return arguments.Error(0)
+}
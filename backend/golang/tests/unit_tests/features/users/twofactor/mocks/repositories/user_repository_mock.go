@@ -0,0 +1,39 @@
+package mockrepositories
+
+import (
+	"backend-golang/features/users/twofactor/models"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+)
+
+type UserRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *UserRepositoryMock) FindById(pool *pgxpool.Pool, ctx context.Context, id int32) (user models.User, err error) {
+	arguments := repository.Mock.Called(pool, ctx, id)
+	return arguments.Get(0).(models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) FindByIdForUpdate(tx pgx.Tx, ctx context.Context, id int32) (user models.User, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).(models.User), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) UpdateTotpSecret(tx pgx.Tx, ctx context.Context, id int32, totpSecret string) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id, totpSecret)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) UpdateTotpEnabledAt(tx pgx.Tx, ctx context.Context, id int32, totpEnabledAt int64, totpLastUsedStep int64) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id, totpEnabledAt, totpLastUsedStep)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
+
+func (repository *UserRepositoryMock) DeleteTotp(tx pgx.Tx, ctx context.Context, id int32) (rowsAffected int64, err error) {
+	arguments := repository.Mock.Called(tx, ctx, id)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
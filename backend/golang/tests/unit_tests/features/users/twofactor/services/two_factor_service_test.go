@@ -0,0 +1,346 @@
+package services_test
+
+import (
+	"backend-golang/commons/helpers"
+	"backend-golang/commons/middlewares"
+	"backend-golang/commons/setups"
+	"backend-golang/features/users/twofactor/models"
+	"backend-golang/features/users/twofactor/services"
+	mockhelpers "backend-golang/tests/unit_tests/commons/helpers/mocks"
+	mockcommonrepositories "backend-golang/tests/unit_tests/commons/repositories/mocks"
+	mockutils "backend-golang/tests/unit_tests/commons/utils/mocks"
+	mockrepositories "backend-golang/tests/unit_tests/features/users/twofactor/mocks/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorServiceTestSuite struct {
+	suite.Suite
+	ctx                        context.Context
+	postgresUtilMock           *mockutils.PostgresUtilMock
+	validate                   *validator.Validate
+	userRepositoryMock         *mockrepositories.UserRepositoryMock
+	recoveryCodeRepositoryMock *mockrepositories.RecoveryCodeRepositoryMock
+	twoFactorRepositoryMock    *mockcommonrepositories.TwoFactorRepositoryMock
+	twoFactorHelperMock        *mockhelpers.TwoFactorHelperMock
+	passwordHasherMock         *mockhelpers.PasswordHasherMock
+	tx                         pgx.Tx
+	pool                       *pgxpool.Pool
+	errTimeout                 error
+	errInternalServer          error
+	user                       models.User
+	secret                     string
+	encryptedSecret            string
+	recoveryCodes              []string
+	confirmTwoFactorRequest    models.ConfirmTwoFactorRequest
+	disableTwoFactorRequest    models.DisableTwoFactorRequest
+	twoFactorService           services.TwoFactorService
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorServiceTestSuite))
+}
+
+func (sut *TwoFactorServiceTestSuite) SetupSuite() {
+	sut.T().Log("SetupSuite")
+	sut.ctx = context.WithValue(context.Background(), middlewares.RequestIdKey, uuid.New().String())
+	sut.ctx = context.WithValue(sut.ctx, middlewares.IdKey, int32(1))
+	sut.ctx = context.WithValue(sut.ctx, middlewares.EmailKey, "email@email.com")
+	sut.validate = setups.SetValidator()
+	sut.tx = &pgxpool.Tx{}
+	sut.pool = &pgxpool.Pool{}
+	sut.errTimeout = context.Canceled
+	sut.errInternalServer = errors.New("internal server error")
+	sut.secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	sut.encryptedSecret = "c2VhbGVkIHRvdHAgc2VjcmV0"
+	sut.recoveryCodes = []string{"abcd-efgh", "ijkl-mnop"}
+}
+
+func (sut *TwoFactorServiceTestSuite) SetupTest() {
+	sut.T().Log("SetupTest")
+	sut.user = models.User{
+		Id:       pgtype.Int4{Valid: true, Int32: 1},
+		Password: pgtype.Text{Valid: true, String: "$2a$10$MvEM5qcQFk39jC/3fYzJzOIy7M/xQiGv/PAkkoarCMgsx/rO0UaPG"},
+	}
+	sut.confirmTwoFactorRequest = models.ConfirmTwoFactorRequest{
+		Code: "123456",
+	}
+	sut.disableTwoFactorRequest = models.DisableTwoFactorRequest{
+		Password: "password@A1",
+		Code:     "123456",
+	}
+	sut.postgresUtilMock = new(mockutils.PostgresUtilMock)
+	sut.userRepositoryMock = new(mockrepositories.UserRepositoryMock)
+	sut.recoveryCodeRepositoryMock = new(mockrepositories.RecoveryCodeRepositoryMock)
+	sut.twoFactorRepositoryMock = new(mockcommonrepositories.TwoFactorRepositoryMock)
+	sut.twoFactorHelperMock = new(mockhelpers.TwoFactorHelperMock)
+	sut.passwordHasherMock = new(mockhelpers.PasswordHasherMock)
+	sut.twoFactorService = services.NewTwoFactorService(sut.postgresUtilMock, sut.validate, sut.userRepositoryMock, sut.recoveryCodeRepositoryMock, sut.twoFactorRepositoryMock, sut.twoFactorHelperMock, sut.passwordHasherMock)
+}
+
+func (sut *TwoFactorServiceTestSuite) BeforeTest(suiteName, testName string) {
+	sut.T().Log("BeforeTest: " + suiteName + " " + testName)
+}
+
+func (sut *TwoFactorServiceTestSuite) enableTotp() {
+	sut.user.TotpSecret = pgtype.Text{Valid: true, String: sut.encryptedSecret}
+	sut.user.TotpEnabledAt = pgtype.Int8{Valid: true, Int64: 1719496855216}
+}
+
+func (sut *TwoFactorServiceTestSuite) Test01EnrollUserRepositoryFindByIdForUpdateTimeoutError() {
+	sut.T().Log("Test01EnrollUserRepositoryFindByIdForUpdateTimeoutError")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(models.User{}, sut.errTimeout)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errTimeout).Return(nil)
+	httpCode, response := sut.twoFactorService.Enroll(sut.ctx)
+	sut.Equal(httpCode, http.StatusRequestTimeout)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "time out or user cancel the request")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test02EnrollAlreadyEnabledBadRequest() {
+	sut.T().Log("Test02EnrollAlreadyEnabledBadRequest")
+	sut.enableTotp()
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.twoFactorService.Enroll(sut.ctx)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "two-factor authentication is already enabled")
+	sut.twoFactorHelperMock.Mock.AssertNotCalled(sut.T(), "GenerateSecret")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test03EnrollSuccess() {
+	sut.T().Log("Test03EnrollSuccess")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.twoFactorHelperMock.Mock.On("GenerateSecret").Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("EncryptSecret", sut.secret).Return(sut.encryptedSecret, nil)
+	sut.userRepositoryMock.Mock.On("UpdateTotpSecret", sut.tx, sut.ctx, int32(1), sut.encryptedSecret).Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.twoFactorService.Enroll(sut.ctx)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	enrollTwoFactorResponse, _ := response.Data.(models.EnrollTwoFactorResponse)
+	sut.Equal(enrollTwoFactorResponse.Secret, sut.secret)
+	sut.True(strings.HasPrefix(enrollTwoFactorResponse.Uri, "otpauth://totp/EcommerceV2:email@email.com?"))
+	sut.True(strings.Contains(enrollTwoFactorResponse.Uri, "secret="+sut.secret))
+}
+
+func (sut *TwoFactorServiceTestSuite) Test04ConfirmValidationError() {
+	sut.T().Log("Test04ConfirmValidationError")
+	sut.confirmTwoFactorRequest = models.ConfirmTwoFactorRequest{}
+	httpCode, response := sut.twoFactorService.Confirm(sut.ctx, sut.confirmTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "is required")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test05ConfirmNotEnrolledBadRequest() {
+	sut.T().Log("Test05ConfirmNotEnrolledBadRequest")
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.twoFactorService.Confirm(sut.ctx, sut.confirmTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "please enroll two-factor authentication first")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test06ConfirmInvalidCodeBadRequest() {
+	sut.T().Log("Test06ConfirmInvalidCodeBadRequest")
+	sut.user.TotpSecret = pgtype.Text{Valid: true, String: sut.encryptedSecret}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.confirmTwoFactorRequest.Code, mock.Anything).Return(int64(0), false)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, mock.Anything).Return(nil)
+	httpCode, response := sut.twoFactorService.Confirm(sut.ctx, sut.confirmTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "code is invalid")
+	sut.userRepositoryMock.Mock.AssertNotCalled(sut.T(), "UpdateTotpEnabledAt", sut.tx, sut.ctx, int32(1), mock.Anything, mock.Anything)
+}
+
+func (sut *TwoFactorServiceTestSuite) Test07ConfirmRecoveryCodeRepositoryCreateManyInternalServerError() {
+	sut.T().Log("Test07ConfirmRecoveryCodeRepositoryCreateManyInternalServerError")
+	sut.user.TotpSecret = pgtype.Text{Valid: true, String: sut.encryptedSecret}
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.confirmTwoFactorRequest.Code, mock.Anything).Return(int64(57305000), true)
+	sut.twoFactorHelperMock.Mock.On("GenerateRecoveryCodes").Return(sut.recoveryCodes, nil)
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", mock.Anything).Return("hash")
+	sut.userRepositoryMock.Mock.On("UpdateTotpEnabledAt", sut.tx, sut.ctx, int32(1), mock.AnythingOfType("int64"), int64(57305000)).Return(int64(1), nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("DeleteByUserId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("CreateMany", sut.tx, sut.ctx, int32(1), mock.Anything).Return(sut.errInternalServer)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, sut.errInternalServer).Return(nil)
+	httpCode, response := sut.twoFactorService.Confirm(sut.ctx, sut.confirmTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusInternalServerError)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "internal server error")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test08ConfirmSuccess() {
+	sut.T().Log("Test08ConfirmSuccess")
+	sut.user.TotpSecret = pgtype.Text{Valid: true, String: sut.encryptedSecret}
+	codeHashes := []string{"hash of abcd-efgh", "hash of ijkl-mnop"}
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", "abcd-efgh").Return(codeHashes[0])
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", "ijkl-mnop").Return(codeHashes[1])
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("FindByIdForUpdate", sut.tx, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.confirmTwoFactorRequest.Code, mock.Anything).Return(int64(57305000), true)
+	sut.twoFactorHelperMock.Mock.On("GenerateRecoveryCodes").Return(sut.recoveryCodes, nil)
+	sut.userRepositoryMock.Mock.On("UpdateTotpEnabledAt", sut.tx, sut.ctx, int32(1), mock.AnythingOfType("int64"), int64(57305000)).Return(int64(1), nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("DeleteByUserId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("CreateMany", sut.tx, sut.ctx, int32(1), codeHashes).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.twoFactorService.Confirm(sut.ctx, sut.confirmTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Errors, nil)
+	recoveryCodesResponse, _ := response.Data.(models.RecoveryCodesResponse)
+	sut.Equal(recoveryCodesResponse.RecoveryCodes, sut.recoveryCodes)
+	sut.Equal(recoveryCodesResponse.Message, "successfully enable two-factor authentication, store the recovery codes somewhere safe, they are shown only once")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test09DisableNotEnabledBadRequest() {
+	sut.T().Log("Test09DisableNotEnabledBadRequest")
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "message")
+	sut.Equal(errorMessages[0].Message, "two-factor authentication is not enabled")
+}
+
+func (sut *TwoFactorServiceTestSuite) Test10DisableWrongPasswordBadRequest() {
+	sut.T().Log("Test10DisableWrongPasswordBadRequest")
+	sut.enableTotp()
+	sut.disableTwoFactorRequest.Password = "password@A2"
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.disableTwoFactorRequest.Password).Return(helpers.ErrPasswordMismatch)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "password")
+	sut.Equal(errorMessages[0].Message, "wrong password")
+	sut.twoFactorHelperMock.Mock.AssertNotCalled(sut.T(), "ValidateTotp", sut.secret, sut.disableTwoFactorRequest.Code, mock.Anything)
+}
+
+func (sut *TwoFactorServiceTestSuite) Test11DisableInvalidCodeBadRequest() {
+	sut.T().Log("Test11DisableInvalidCodeBadRequest")
+	sut.enableTotp()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.disableTwoFactorRequest.Password).Return(nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.disableTwoFactorRequest.Code, mock.Anything).Return(int64(0), false)
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", sut.disableTwoFactorRequest.Code).Return("hash of 123456")
+	sut.twoFactorRepositoryMock.Mock.On("UseRecoveryCode", sut.pool, sut.ctx, int32(1), "hash of 123456", mock.AnythingOfType("int64")).Return(int64(0), nil)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "code is invalid")
+	sut.postgresUtilMock.Mock.AssertNotCalled(sut.T(), "BeginTx", sut.ctx, pgx.TxOptions{})
+}
+
+func (sut *TwoFactorServiceTestSuite) Test12DisableSuccess() {
+	sut.T().Log("Test12DisableSuccess")
+	sut.enableTotp()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.disableTwoFactorRequest.Password).Return(nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.disableTwoFactorRequest.Code, mock.Anything).Return(int64(57305000), true)
+	sut.twoFactorRepositoryMock.Mock.On("UpdateTotpLastUsedStep", sut.pool, sut.ctx, int32(1), int64(57305000)).Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("DeleteTotp", sut.tx, sut.ctx, int32(1)).Return(int64(1), nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("DeleteByUserId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully disable two-factor authentication"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *TwoFactorServiceTestSuite) Test13DisableUsedTotpCodeBadRequest() {
+	sut.T().Log("Test13DisableUsedTotpCodeBadRequest")
+	sut.enableTotp()
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.disableTwoFactorRequest.Password).Return(nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.disableTwoFactorRequest.Code, mock.Anything).Return(int64(57305000), true)
+	sut.twoFactorRepositoryMock.Mock.On("UpdateTotpLastUsedStep", sut.pool, sut.ctx, int32(1), int64(57305000)).Return(int64(0), nil)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusBadRequest)
+	sut.Equal(response.Data, nil)
+	errorMessages, _ := response.Errors.([]helpers.ErrorMessage)
+	sut.Equal(errorMessages[0].Field, "code")
+	sut.Equal(errorMessages[0].Message, "code is invalid")
+	sut.twoFactorRepositoryMock.Mock.AssertNotCalled(sut.T(), "UseRecoveryCode", sut.pool, sut.ctx, int32(1), mock.Anything, mock.Anything)
+}
+
+func (sut *TwoFactorServiceTestSuite) Test14DisableRecoveryCodeSuccess() {
+	sut.T().Log("Test14DisableRecoveryCodeSuccess")
+	sut.enableTotp()
+	sut.disableTwoFactorRequest.Code = "abcd-efgh"
+	sut.postgresUtilMock.Mock.On("GetPool").Return(sut.pool)
+	sut.userRepositoryMock.Mock.On("FindById", sut.pool, sut.ctx, int32(1)).Return(sut.user, nil)
+	sut.passwordHasherMock.Mock.On("Compare", sut.user.Password.String, sut.disableTwoFactorRequest.Password).Return(nil)
+	sut.twoFactorHelperMock.Mock.On("DecryptSecret", sut.encryptedSecret).Return(sut.secret, nil)
+	sut.twoFactorHelperMock.Mock.On("ValidateTotp", sut.secret, sut.disableTwoFactorRequest.Code, mock.Anything).Return(int64(0), false)
+	sut.twoFactorHelperMock.Mock.On("ToRecoveryCodeHash", sut.disableTwoFactorRequest.Code).Return("hash of abcd-efgh")
+	sut.twoFactorRepositoryMock.Mock.On("UseRecoveryCode", sut.pool, sut.ctx, int32(1), "hash of abcd-efgh", mock.AnythingOfType("int64")).Return(int64(1), nil)
+	sut.postgresUtilMock.Mock.On("BeginTx", sut.ctx, pgx.TxOptions{}).Return(sut.tx, nil)
+	sut.userRepositoryMock.Mock.On("DeleteTotp", sut.tx, sut.ctx, int32(1)).Return(int64(1), nil)
+	sut.recoveryCodeRepositoryMock.Mock.On("DeleteByUserId", sut.tx, sut.ctx, int32(1)).Return(nil)
+	sut.postgresUtilMock.Mock.On("CommitOrRollback", sut.tx, nil).Return(nil)
+	httpCode, response := sut.twoFactorService.Disable(sut.ctx, sut.disableTwoFactorRequest)
+	sut.Equal(httpCode, http.StatusOK)
+	sut.Equal(response.Data, helpers.ResponseMessage{Message: "successfully disable two-factor authentication"})
+	sut.Equal(response.Errors, nil)
+}
+
+func (sut *TwoFactorServiceTestSuite) AfterTest(suiteName, testName string) {
+	sut.T().Log("AfterTest: " + suiteName + " " + testName)
+}
+
+func (sut *TwoFactorServiceTestSuite) TearDownTest() {
+	sut.T().Log("TearDownTest")
+}
+
+func (sut *TwoFactorServiceTestSuite) TearDownSuite() {
+	sut.T().Log("TearDownSuite")
+}